@@ -0,0 +1,73 @@
+// Package audit records security events in the shared audit_logs table owned
+// by auth-service, so events from every service end up in one place.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Event categories used across services.
+const (
+	CategoryAuthentication    = "authentication"
+	CategoryAuthorization     = "authorization"
+	CategoryAccountManagement = "account_management"
+	CategorySecurity          = "security"
+	CategoryImpersonation     = "impersonation" // support staff acting as a user
+)
+
+// Event is a single audit log entry.
+type Event struct {
+	UserID        *uuid.UUID
+	EventType     string
+	EventCategory string
+	Description   string
+	Metadata      map[string]interface{}
+	IPAddress     string
+	UserAgent     string
+	Success       bool
+	ErrorMessage  string
+	CreatedAt     time.Time
+}
+
+// Sink persists audit events.
+type Sink interface {
+	Record(ctx context.Context, event Event) error
+}
+
+type postgresSink struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresSink creates a sink writing to the audit_logs table.
+func NewPostgresSink(db *pgxpool.Pool) Sink {
+	return &postgresSink{db: db}
+}
+
+func (s *postgresSink) Record(ctx context.Context, event Event) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	var metadata []byte
+	if len(event.Metadata) > 0 {
+		var err error
+		if metadata, err = json.Marshal(event.Metadata); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		INSERT INTO audit_logs (user_id, event_type, event_category, description, metadata, ip_address, user_agent, success, error_message, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, NULLIF($6, ''), NULLIF($7, ''), $8, NULLIF($9, ''), $10)
+	`
+	_, err := s.db.Exec(ctx, query,
+		event.UserID, event.EventType, event.EventCategory, event.Description, metadata,
+		event.IPAddress, event.UserAgent, event.Success, event.ErrorMessage, event.CreatedAt,
+	)
+	return err
+}
@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// API key scopes. A key may only call the routes a service maps to one of its scopes.
+const (
+	ScopeListingsRead  = "listings:read"
+	ScopeListingsWrite = "listings:write"
+	ScopeLeadsRead     = "leads:read"
+)
+
+// Scopes lists every scope an API key can be issued with.
+var Scopes = []string{ScopeListingsRead, ScopeListingsWrite, ScopeLeadsRead}
+
+// API keys look like "exl_<prefix>_<secret>". The prefix is stored in clear to
+// find the key; only a SHA-256 hash of the whole key is stored.
+const (
+	apiKeyTag          = "exl_"
+	apiKeyPrefixLength = 8
+	apiKeySecretBytes  = 32
+	// apiKeyTouchInterval limits how often last_used_at is written for a busy key
+	apiKeyTouchInterval = time.Minute
+)
+
+// APIKeyRoutes maps "METHOD /route/template" (as registered with gin) to the
+// scope a key needs to call it. Routes not listed reject API keys.
+type APIKeyRoutes map[string]string
+
+// WithAPIKeys accepts "Authorization: ApiKey <key>" on the given routes, looking
+// keys up in the shared api_keys table. Keys belong to dealer accounts and stop
+// working when the account is suspended, deleted or no longer a dealer.
+func WithAPIKeys(db *pgxpool.Pool, routes APIKeyRoutes) Option {
+	return func(m *Middleware) {
+		m.apiKeys = &apiKeyVerifier{db: db, routes: routes}
+	}
+}
+
+// GenerateAPIKey returns a new random key and its lookup prefix.
+func GenerateAPIKey() (key, prefix string, err error) {
+	buf := make([]byte, apiKeyPrefixLength/2+apiKeySecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	prefix = hex.EncodeToString(buf[:apiKeyPrefixLength/2])
+	secret := base64.RawURLEncoding.EncodeToString(buf[apiKeyPrefixLength/2:])
+	return apiKeyTag + prefix + "_" + secret, prefix, nil
+}
+
+// HashAPIKey returns the hex SHA-256 of key, as stored in api_keys.key_hash.
+// Keys are long and random, so a fast hash is enough.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeyPrefix returns the lookup prefix of a well-formed key.
+func APIKeyPrefix(key string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, apiKeyTag)
+	if !ok {
+		return "", false
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(prefix) != apiKeyPrefixLength || secret == "" {
+		return "", false
+	}
+	return prefix, true
+}
+
+// IsAPIKey reports whether the request was authenticated with an API key.
+func IsAPIKey(c *gin.Context) bool {
+	claims := GetClaims(c)
+	return claims != nil && claims.APIKeyID != ""
+}
+
+type apiKeyVerifier struct {
+	db     *pgxpool.Pool
+	routes APIKeyRoutes
+}
+
+var errInvalidAPIKey = errors.New("Invalid or expired API key")
+
+// authenticate verifies an API key for the route being called and returns
+// claims describing the key's owner.
+func (v *apiKeyVerifier) authenticate(c *gin.Context, key string) (*Claims, int, string) {
+	scope, ok := v.routes[c.Request.Method+" "+c.FullPath()]
+	if !ok {
+		return nil, http.StatusForbidden, "This endpoint does not accept API keys"
+	}
+
+	claims, scopes, err := v.lookup(c.Request.Context(), key)
+	if err != nil {
+		return nil, http.StatusUnauthorized, err.Error()
+	}
+	if !hasScope(scopes, scope) {
+		return nil, http.StatusForbidden, "API key lacks the " + scope + " scope"
+	}
+
+	go v.touch(claims.APIKeyID)
+	return claims, 0, ""
+}
+
+func (v *apiKeyVerifier) lookup(ctx context.Context, key string) (*Claims, []string, error) {
+	prefix, ok := APIKeyPrefix(key)
+	if !ok {
+		return nil, nil, errInvalidAPIKey
+	}
+
+	var (
+		id, userID, keyHash, role, status string
+		scopes                            []string
+		expiresAt, suspendedUntil         *time.Time
+	)
+	err := v.db.QueryRow(ctx, `
+		SELECT k.id::text, k.user_id::text, k.key_hash, k.scopes, k.expires_at, u.role, u.status, u.suspended_until
+		FROM api_keys k
+		JOIN users u ON u.id = k.user_id
+		WHERE k.prefix = $1 AND k.revoked_at IS NULL AND u.deleted_at IS NULL
+	`, prefix).Scan(&id, &userID, &keyHash, &scopes, &expiresAt, &role, &status, &suspendedUntil)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, errInvalidAPIKey
+	}
+	if err != nil {
+		return nil, nil, errors.New("API key validation failed")
+	}
+
+	now := time.Now()
+	if subtle.ConstantTimeCompare([]byte(HashAPIKey(key)), []byte(keyHash)) != 1 {
+		return nil, nil, errInvalidAPIKey
+	}
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, nil, errInvalidAPIKey
+	}
+	switch {
+	case status == StatusDeleted:
+		return nil, nil, ErrAccountClosed
+	case IsSuspended(status, suspendedUntil, now):
+		return nil, nil, ErrAccountSuspended
+	case role != RoleDealer:
+		return nil, nil, errInvalidAPIKey
+	}
+
+	return &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: userID},
+		Role:             role,
+		APIKeyID:         id,
+		Scopes:           scopes,
+	}, scopes, nil
+}
+
+func (v *apiKeyVerifier) touch(id string) {
+	_, err := v.db.Exec(context.Background(), `
+		UPDATE api_keys SET last_used_at = NOW()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $2)
+	`, id, time.Now().Add(-apiKeyTouchInterval))
+	if err != nil {
+		log.Printf("auth: failed to record use of API key %s: %v", id, err)
+	}
+}
+
+func hasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatalf("GenerateAPIKey: %v", err)
+	}
+	if !strings.HasPrefix(key, "exl_"+prefix+"_") {
+		t.Errorf("key %q does not start with exl_%s_", key, prefix)
+	}
+
+	got, ok := APIKeyPrefix(key)
+	if !ok || got != prefix {
+		t.Errorf("APIKeyPrefix(generated key) = %q, %v; want %q, true", got, ok, prefix)
+	}
+
+	other, _, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatalf("GenerateAPIKey: %v", err)
+	}
+	if other == key {
+		t.Error("GenerateAPIKey returned the same key twice")
+	}
+}
+
+func TestAPIKeyPrefix(t *testing.T) {
+	tests := []struct {
+		name       string
+		key        string
+		wantPrefix string
+		wantOK     bool
+	}{
+		{"well formed", "exl_0a1b2c3d_c2VjcmV0", "0a1b2c3d", true},
+		{"secret may contain underscores", "exl_0a1b2c3d_sec_ret", "0a1b2c3d", true},
+		{"missing tag", "0a1b2c3d_c2VjcmV0", "", false},
+		{"wrong tag", "key_0a1b2c3d_c2VjcmV0", "", false},
+		{"prefix too short", "exl_0a1b2c_c2VjcmV0", "", false},
+		{"prefix too long", "exl_0a1b2c3d4e_c2VjcmV0", "", false},
+		{"no secret", "exl_0a1b2c3d_", "", false},
+		{"no separator", "exl_0a1b2c3dc2VjcmV0", "", false},
+		{"bearer token", "eyJhbGciOiJSUzI1NiJ9.e30.sig", "", false},
+		{"empty", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefix, ok := APIKeyPrefix(tt.key)
+			if prefix != tt.wantPrefix || ok != tt.wantOK {
+				t.Errorf("APIKeyPrefix(%q) = %q, %v; want %q, %v", tt.key, prefix, ok, tt.wantPrefix, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestHashAPIKey(t *testing.T) {
+	hash := HashAPIKey("exl_0a1b2c3d_c2VjcmV0")
+	if len(hash) != 64 {
+		t.Errorf("HashAPIKey returned %d characters, want 64", len(hash))
+	}
+	if hash != HashAPIKey("exl_0a1b2c3d_c2VjcmV0") {
+		t.Error("HashAPIKey is not deterministic")
+	}
+	if hash == HashAPIKey("exl_0a1b2c3d_c2VjcmV1") {
+		t.Error("different keys have the same hash")
+	}
+}
+
+func TestHasScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		scope  string
+		want   bool
+	}{
+		{"granted", []string{ScopeListingsRead, ScopeLeadsRead}, ScopeLeadsRead, true},
+		{"not granted", []string{ScopeLeadsRead}, ScopeListingsRead, false},
+		{"read does not imply write", []string{ScopeListingsRead}, ScopeListingsWrite, false},
+		{"no scopes", nil, ScopeListingsRead, false},
+		{"exact match only", []string{"listings:read:all"}, ScopeListingsRead, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasScope(tt.scopes, tt.scope); got != tt.want {
+				t.Errorf("hasScope(%v, %q) = %v, want %v", tt.scopes, tt.scope, got, tt.want)
+			}
+		})
+	}
+}
+
+// Requests are turned away before the key is looked up, so no database is needed
+func TestAPIKeyAuthenticateRejects(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	v := &apiKeyVerifier{routes: APIKeyRoutes{"GET /api/listings/:id": ScopeListingsRead}}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		key        string
+		wantStatus int
+		wantMsg    string
+	}{
+		{"route not mapped", http.MethodPost, "/api/listings/1", "exl_0a1b2c3d_c2VjcmV0", http.StatusForbidden, "does not accept API keys"},
+		{"malformed key", http.MethodGet, "/api/listings/1", "exl_short_c2VjcmV0", http.StatusUnauthorized, "Invalid or expired API key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var status int
+			var msg string
+			r := gin.New()
+			handler := func(c *gin.Context) {
+				_, status, msg = v.authenticate(c, tt.key)
+			}
+			r.GET("/api/listings/:id", handler)
+			r.POST("/api/listings/:id", handler)
+
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+			if status != tt.wantStatus || !strings.Contains(msg, tt.wantMsg) {
+				t.Errorf("authenticate = %d %q, want %d containing %q", status, msg, tt.wantStatus, tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var ErrUnauthenticated = errors.New("user ID not found in context")
+
+// GetUserID returns the authenticated user's ID.
+func GetUserID(c *gin.Context) (uuid.UUID, error) {
+	val, exists := c.Get(ContextUserID)
+	if !exists {
+		return uuid.Nil, ErrUnauthenticated
+	}
+	id, ok := val.(uuid.UUID)
+	if !ok {
+		return uuid.Nil, errors.New("invalid user ID type in context")
+	}
+	return id, nil
+}
+
+// GetOptionalUserID returns the authenticated user's ID, or nil for anonymous
+// requests let through by Optional.
+func GetOptionalUserID(c *gin.Context) *uuid.UUID {
+	id, err := GetUserID(c)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+// GetRole returns the authenticated user's role, or "" if there is none.
+func GetRole(c *gin.Context) string {
+	return c.GetString(ContextRole)
+}
+
+// IsPhoneVerified reports whether the authenticated user has a verified phone number.
+func IsPhoneVerified(c *gin.Context) bool {
+	claims := GetClaims(c)
+	return claims != nil && claims.PhoneVerified
+}
+
+// GetClaims returns the verified token claims, or nil if the request is anonymous.
+func GetClaims(c *gin.Context) *Claims {
+	val, exists := c.Get(ContextClaims)
+	if !exists {
+		return nil
+	}
+	claims, _ := val.(*Claims)
+	return claims
+}
+
+// GetToken returns the raw access token of the request.
+func GetToken(c *gin.Context) string {
+	return c.GetString(ContextToken)
+}
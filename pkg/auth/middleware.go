@@ -0,0 +1,206 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/audit"
+	"github.com/aselahemantha/exoticsLanka/pkg/jwks"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Context keys set by the middleware.
+const (
+	ContextUserID = "userID"
+	ContextRole   = "userRole"
+	ContextClaims = "claims"
+	ContextToken  = "token"
+)
+
+// TokenCheck performs extra validation on an already verified token, such as
+// auth-service's session revocation check. A non-nil error rejects the request.
+type TokenCheck func(ctx context.Context, token string, claims *Claims) error
+
+// Middleware authenticates requests carrying a Bearer access token, or an API
+// key on routes that accept them (see WithAPIKeys).
+type Middleware struct {
+	keyfunc jwt.Keyfunc
+	checks  []TokenCheck
+	sink    audit.Sink
+	service string
+	apiKeys *apiKeyVerifier
+}
+
+// Option configures a Middleware.
+type Option func(*Middleware)
+
+// WithTokenCheck adds a check run after the token signature and expiry are verified.
+func WithTokenCheck(check TokenCheck) Option {
+	return func(m *Middleware) {
+		m.checks = append(m.checks, check)
+	}
+}
+
+// NewMiddleware creates a middleware that verifies tokens with keyfunc, e.g.
+// (*jwks.Client).Keyfunc.
+func NewMiddleware(keyfunc jwt.Keyfunc, opts ...Option) *Middleware {
+	m := &Middleware{keyfunc: keyfunc}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Required rejects requests without a valid token.
+func (m *Middleware) Required() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if status, msg := m.authenticate(c); status != 0 {
+			response.Abort(c, status, msg)
+			return
+		}
+		m.next(c)
+	}
+}
+
+// Optional authenticates the request when a token is present and otherwise
+// continues anonymously. An invalid token is treated as no token.
+func (m *Middleware) Optional() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			_, _ = m.authenticate(c)
+		}
+		m.next(c)
+	}
+}
+
+// RequireRole is Required followed by a check that the user has one of roles.
+func (m *Middleware) RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if status, msg := m.authenticate(c); status != 0 {
+			response.Abort(c, status, msg)
+			return
+		}
+		if !hasRole(GetRole(c), roles) {
+			response.Abort(c, http.StatusForbidden, "Insufficient permissions")
+			return
+		}
+		m.next(c)
+	}
+}
+
+// RequireRole checks the role of a request already authenticated by Required.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasRole(GetRole(c), roles) {
+			response.Abort(c, http.StatusForbidden, "Insufficient permissions")
+			return
+		}
+		c.Next()
+	}
+}
+
+// TokenFromQuery lets clients that cannot set headers, such as browser
+// WebSocket and EventSource connections, send the access token in the given
+// query parameter. Use it only on those routes, before Required: URLs are
+// more likely than headers to end up in logs.
+func TokenFromQuery(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := c.Query(param); token != "" && c.GetHeader("Authorization") == "" {
+			c.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+		c.Next()
+	}
+}
+
+// Recheck runs the token checks again for a request authenticated earlier.
+// Long-lived connections call it periodically, so they end once the account
+// is suspended or deleted rather than when the token expires.
+func (m *Middleware) Recheck(c *gin.Context) error {
+	claims := GetClaims(c)
+	if claims == nil {
+		return errors.New("Request is not authenticated")
+	}
+	for _, check := range m.checks {
+		if err := check(c.Request.Context(), c.GetString(ContextToken), claims); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// authenticate verifies the request's token and populates the context. It
+// returns a non-zero status and message when the request must be rejected.
+func (m *Middleware) authenticate(c *gin.Context) (int, string) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		return http.StatusUnauthorized, "Authorization header required"
+	}
+
+	if key, ok := strings.CutPrefix(authHeader, "ApiKey "); ok {
+		return m.authenticateAPIKey(c, key)
+	}
+
+	tokenString, ok := strings.CutPrefix(authHeader, "Bearer ")
+	if !ok || tokenString == "" {
+		return http.StatusUnauthorized, "Invalid authorization header format"
+	}
+
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, m.keyfunc, jwt.WithValidMethods(jwks.SigningMethods))
+	if err != nil || !token.Valid {
+		return http.StatusUnauthorized, "Invalid or expired token"
+	}
+
+	userID, err := claims.UserID()
+	if err != nil {
+		return http.StatusUnauthorized, "Invalid user ID in token"
+	}
+
+	for _, check := range m.checks {
+		if err := check(c.Request.Context(), tokenString, claims); err != nil {
+			return http.StatusUnauthorized, err.Error()
+		}
+	}
+
+	c.Set(ContextUserID, userID)
+	c.Set(ContextRole, claims.Role)
+	c.Set(ContextClaims, claims)
+	c.Set(ContextToken, tokenString)
+	return 0, ""
+}
+
+// authenticateAPIKey verifies an API key and populates the context like a token
+// would. Token checks are not run; the key lookup checks the account itself.
+func (m *Middleware) authenticateAPIKey(c *gin.Context, key string) (int, string) {
+	if m.apiKeys == nil {
+		return http.StatusUnauthorized, "API keys are not accepted by this service"
+	}
+
+	claims, status, msg := m.apiKeys.authenticate(c, key)
+	if status != 0 {
+		return status, msg
+	}
+
+	userID, err := claims.UserID()
+	if err != nil {
+		return http.StatusUnauthorized, "Invalid API key"
+	}
+
+	c.Set(ContextUserID, userID)
+	c.Set(ContextRole, claims.Role)
+	c.Set(ContextClaims, claims)
+	return 0, ""
+}
+
+func hasRole(role string, roles []string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
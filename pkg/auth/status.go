@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Account statuses stored in users.status.
+const (
+	StatusPending   = "pending"
+	StatusActive    = "active"
+	StatusSuspended = "suspended"
+	StatusDeleted   = "deleted"
+)
+
+var (
+	// ErrAccountSuspended rejects tokens of suspended users.
+	ErrAccountSuspended = errors.New("Account suspended")
+	// ErrAccountClosed rejects tokens of deleted or unknown users.
+	ErrAccountClosed = errors.New("Account no longer exists")
+)
+
+// IsSuspended reports whether a suspension with the given end (nil for
+// indefinite) is still in force at now.
+func IsSuspended(status string, until *time.Time, now time.Time) bool {
+	return status == StatusSuspended && (until == nil || until.After(now))
+}
+
+// AccountStatusCheck returns a TokenCheck that looks the user up in the
+// shared users table on every request, so suspensions and deletions take
+// effect immediately rather than when the access token expires.
+func AccountStatusCheck(db *pgxpool.Pool) TokenCheck {
+	return func(ctx context.Context, _ string, claims *Claims) error {
+		// Service tokens don't belong to a user account
+		if claims.Role == RoleService {
+			return nil
+		}
+
+		var status string
+		var until *time.Time
+		err := db.QueryRow(ctx,
+			`SELECT status, suspended_until FROM users WHERE id = $1 AND deleted_at IS NULL`,
+			claims.Subject,
+		).Scan(&status, &until)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrAccountClosed
+		}
+		if err != nil {
+			return errors.New("Account validation failed")
+		}
+
+		switch {
+		case status == StatusDeleted:
+			return ErrAccountClosed
+		case IsSuspended(status, until, time.Now()):
+			return ErrAccountSuspended
+		}
+		return nil
+	}
+}
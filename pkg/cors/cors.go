@@ -0,0 +1,47 @@
+// Package cors provides the CORS middleware used by every service.
+package cors
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	allowHeaders = "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With"
+	allowMethods = "POST, OPTIONS, GET, PUT, PATCH, DELETE"
+)
+
+// New returns a CORS middleware. With no origins (or "*") any origin is
+// allowed; otherwise the request origin is echoed back only if it is listed.
+func New(origins ...string) gin.HandlerFunc {
+	allowAll := len(origins) == 0
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		origin = strings.TrimSpace(origin)
+		if origin == "*" {
+			allowAll = true
+		}
+		allowed[origin] = true
+	}
+
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		if allowAll {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else if origin := c.GetHeader("Origin"); allowed[origin] {
+			header.Set("Access-Control-Allow-Origin", origin)
+			header.Set("Access-Control-Allow-Credentials", "true")
+			header.Add("Vary", "Origin")
+		}
+		header.Set("Access-Control-Allow-Headers", allowHeaders)
+		header.Set("Access-Control-Allow-Methods", allowMethods)
+
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,180 @@
+package jwks
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// cacheTTL is how long a fetched key set is trusted before it is refreshed.
+	cacheTTL = 10 * time.Minute
+	// minRefreshInterval stops tokens with unknown key IDs from hammering auth-service.
+	minRefreshInterval = 30 * time.Second
+)
+
+// Client resolves token verification keys from auth-service's JWKS endpoint.
+// Keys are cached; when the endpoint cannot be reached the client falls back to
+// a local key file, which may hold either a JWKS document or a PEM public key.
+type Client struct {
+	url          string
+	fallbackFile string
+	httpClient   *http.Client
+
+	mu          sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	lastAttempt time.Time
+}
+
+// NewClient creates a JWKS client. Either url or fallbackFile may be empty, but
+// not both.
+func NewClient(url, fallbackFile string) *Client {
+	return &Client{
+		url:          url,
+		fallbackFile: fallbackFile,
+		httpClient:   &http.Client{Timeout: 5 * time.Second},
+		keys:         make(map[string]crypto.PublicKey),
+	}
+}
+
+// Keyfunc is a jwt.Keyfunc that returns the public key matching the token's kid.
+func (c *Client) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, fresh := c.lookup(kid)
+	if key != nil && fresh {
+		return key, nil
+	}
+
+	// Unknown kid (e.g. just after a key rotation) or stale cache
+	c.refresh()
+
+	if key, _ := c.lookup(kid); key != nil {
+		return key, nil
+	}
+	if key != nil {
+		// Keep verifying with the cached key if the refresh failed
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (c *Client) lookup(kid string) (crypto.PublicKey, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	key, ok := c.keys[kid]
+	if !ok {
+		// A PEM fallback key has no kid and is used for every token
+		key = c.keys[""]
+	}
+	return key, time.Since(c.fetchedAt) < cacheTTL
+}
+
+// refresh fetches the key set without holding the lock, so tokens whose keys
+// are cached keep verifying while auth-service is slow, and swaps it in after.
+// Callers arriving during a fetch return at once, as lastAttempt is already set.
+func (c *Client) refresh() {
+	c.mu.Lock()
+	if time.Since(c.lastAttempt) < minRefreshInterval {
+		c.mu.Unlock()
+		return
+	}
+	c.lastAttempt = time.Now()
+	c.mu.Unlock()
+
+	keys, err := c.fetch()
+	if err != nil {
+		log.Printf("jwks: failed to fetch %s: %v", c.url, err)
+		c.mu.RLock()
+		cached := len(c.keys) > 0
+		c.mu.RUnlock()
+		if cached {
+			return
+		}
+		if keys, err = c.loadFallback(); err != nil {
+			log.Printf("jwks: failed to load fallback key file: %v", err)
+			return
+		}
+	}
+
+	c.mu.Lock()
+	c.keys = keys
+	c.fetchedAt = time.Now()
+	c.mu.Unlock()
+}
+
+func (c *Client) fetch() (map[string]crypto.PublicKey, error) {
+	if c.url == "" {
+		return nil, errors.New("no JWKS URL configured")
+	}
+
+	resp, err := c.httpClient.Get(c.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var set Set
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, err
+	}
+	return decodeSet(set)
+}
+
+func (c *Client) loadFallback() (map[string]crypto.PublicKey, error) {
+	if c.fallbackFile == "" {
+		return nil, errors.New("no fallback key file configured")
+	}
+
+	data, err := os.ReadFile(c.fallbackFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if block, _ := pem.Decode(data); block != nil {
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]crypto.PublicKey{"": pub}, nil
+	}
+
+	var set Set
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	return decodeSet(set)
+}
+
+func decodeSet(set Set) (map[string]crypto.PublicKey, error) {
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		pub, err := k.PublicKey()
+		if err != nil {
+			// Skip keys we can't use rather than rejecting the whole set
+			log.Printf("jwks: skipping key %q: %v", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("key set contains no usable keys")
+	}
+	return keys, nil
+}
@@ -0,0 +1,46 @@
+// Package migrate applies a service's SQL migration files at startup.
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Run executes every .sql file in dir in lexical order. All services share one
+// database and run their migrations on every start, so each file must be
+// idempotent (CREATE ... IF NOT EXISTS, ADD COLUMN IF NOT EXISTS, ...).
+func Run(ctx context.Context, db *pgxpool.Pool, dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	var sqlFiles []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".sql") {
+			sqlFiles = append(sqlFiles, entry.Name())
+		}
+	}
+	sort.Strings(sqlFiles)
+
+	for _, file := range sqlFiles {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			return fmt.Errorf("failed to read migration file %s: %w", file, err)
+		}
+
+		if _, err := db.Exec(ctx, string(content)); err != nil {
+			return fmt.Errorf("failed to execute migration %s: %w", file, err)
+		}
+		log.Printf("Applied migration: %s", file)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package org
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Team changes made in auth-service that the services owning a dealer's
+// listings and conversations apply to their own tables.
+const (
+	// EventCreated moves the owner's own listings and leads into the new team
+	EventCreated = "organization_created"
+	// EventMemberRemoved hands the member's assigned listings and leads back to the owner
+	EventMemberRemoved = "member_removed"
+)
+
+// Consumers of events, each recording what it has applied separately.
+const (
+	ConsumerListings      = "listings"      // listings-service, car_listings
+	ConsumerConversations = "conversations" // messaging-service, conversations
+)
+
+var appliedColumns = map[string]string{
+	ConsumerListings:      "listings_applied_at",
+	ConsumerConversations: "conversations_applied_at",
+}
+
+// Event is a row of the organization_events table.
+type Event struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	Type           string
+	UserID         uuid.UUID // The owner for EventCreated, the member for EventMemberRemoved
+	OwnerID        uuid.UUID
+}
+
+// Execer is satisfied by pgx.Tx.
+type Execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// RecordEvent queues an event. Call it in the transaction that changes the
+// team, so the change and the event are saved together.
+func RecordEvent(ctx context.Context, tx Execer, event Event) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO organization_events (organization_id, type, user_id, owner_id) VALUES ($1, $2, $3, $4)
+	`, event.OrganizationID, event.Type, event.UserID, event.OwnerID)
+	return err
+}
+
+// ApplyEvents passes up to limit events the consumer hasn't applied yet to
+// apply, oldest first, and marks them applied. It runs in one transaction, so
+// an error leaves all of them to be retried; replicas skip each other's rows.
+func ApplyEvents(ctx context.Context, db *pgxpool.Pool, consumer string, limit int, apply func(ctx context.Context, tx pgx.Tx, event Event) error) (int, error) {
+	column, ok := appliedColumns[consumer]
+	if !ok {
+		return 0, fmt.Errorf("unknown organisation event consumer %q", consumer)
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, organization_id, type, user_id, owner_id FROM organization_events
+		WHERE `+column+` IS NULL
+		ORDER BY created_at
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`, limit)
+	if err != nil {
+		return 0, err
+	}
+	var events []Event
+	for rows.Next() {
+		var e Event
+		if err := rows.Scan(&e.ID, &e.OrganizationID, &e.Type, &e.UserID, &e.OwnerID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		events = append(events, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil || len(events) == 0 {
+		return 0, err
+	}
+
+	ids := make([]uuid.UUID, len(events))
+	for i, event := range events {
+		if err := apply(ctx, tx, event); err != nil {
+			return 0, fmt.Errorf("applying %s event %s: %w", event.Type, event.ID, err)
+		}
+		ids[i] = event.ID
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE organization_events SET `+column+` = NOW() WHERE id = ANY($1)`, ids); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
@@ -0,0 +1,73 @@
+// Package org looks up dealer organisation membership in the shared database.
+// Organisations are managed by auth-service; other services use this to decide
+// who on a dealer's team can see and act on the team's listings and leads.
+package org
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Member roles, from most to least privileged.
+const (
+	RoleOwner       = "owner"
+	RoleManager     = "manager"
+	RoleSalesperson = "salesperson"
+)
+
+// Membership is a user's place in an organisation. A user belongs to at most one.
+type Membership struct {
+	OrganizationID uuid.UUID
+	OwnerID        uuid.UUID
+	Role           string
+}
+
+// CanManage reports whether the member works with the whole team's listings
+// and conversations rather than only those assigned to them.
+func (m *Membership) CanManage() bool {
+	return m != nil && (m.Role == RoleOwner || m.Role == RoleManager)
+}
+
+// ID returns the organisation ID, or nil for a user without an organisation.
+func (m *Membership) ID() *uuid.UUID {
+	if m == nil {
+		return nil
+	}
+	return &m.OrganizationID
+}
+
+// Querier is satisfied by *pgxpool.Pool, *pgx.Conn and pgx.Tx.
+type Querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Lookup returns the user's membership, or nil if they are not in an organisation.
+func Lookup(ctx context.Context, db Querier, userID uuid.UUID) (*Membership, error) {
+	var m Membership
+	err := db.QueryRow(ctx, `
+		SELECT m.organization_id, o.owner_id, m.role
+		FROM organization_members m
+		JOIN organizations o ON o.id = m.organization_id
+		WHERE m.user_id = $1
+	`, userID).Scan(&m.OrganizationID, &m.OwnerID, &m.Role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// IsMember reports whether userID belongs to the organisation.
+func IsMember(ctx context.Context, db Querier, organizationID, userID uuid.UUID) (bool, error) {
+	var member bool
+	err := db.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM organization_members WHERE organization_id = $1 AND user_id = $2)`,
+		organizationID, userID,
+	).Scan(&member)
+	return member, err
+}
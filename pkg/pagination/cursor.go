@@ -0,0 +1,88 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrInvalidCursor is returned for a cursor that was not produced by EncodeCursor.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the position of the last row of a page in a result set ordered
+// by (created_at, id) descending.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// CursorParams are the normalised cursor and limit of a keyset-paginated request.
+type CursorParams struct {
+	Cursor *Cursor
+	Limit  int
+}
+
+// CursorPage is the metadata returned with a page of a keyset-paginated result.
+type CursorPage struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	HasMore    bool   `json:"hasMore"`
+}
+
+// EncodeCursor returns an opaque cursor pointing after the given row.
+func EncodeCursor(createdAt time.Time, id string) string {
+	raw := strconv.FormatInt(createdAt.UnixNano(), 10) + "|" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor produced by EncodeCursor.
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	nanos, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, ErrInvalidCursor
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &Cursor{CreatedAt: time.Unix(0, n).UTC(), ID: id}, nil
+}
+
+// CursorFromQuery reads the cursor and limit query parameters. The limit
+// defaults to defaultLimit and is capped at MaxLimit.
+func CursorFromQuery(c *gin.Context, defaultLimit int) (CursorParams, error) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	params := CursorParams{Limit: NewParams(1, limit, defaultLimit).Limit}
+
+	if s := c.Query("cursor"); s != "" {
+		cursor, err := DecodeCursor(s)
+		if err != nil {
+			return params, err
+		}
+		params.Cursor = cursor
+	}
+	return params, nil
+}
+
+// NewCursorPage builds the metadata for a page fetched with Limit+1 rows;
+// last returns the created_at and id of the i-th row. It reports how many
+// rows belong to the page.
+func NewCursorPage(p CursorParams, fetched int, last func(i int) (time.Time, string)) (CursorPage, int) {
+	page := CursorPage{Limit: p.Limit}
+	if fetched <= p.Limit {
+		return page, fetched
+	}
+
+	createdAt, id := last(p.Limit - 1)
+	page.HasMore = true
+	page.NextCursor = EncodeCursor(createdAt, id)
+	return page, p.Limit
+}
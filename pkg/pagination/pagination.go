@@ -0,0 +1,62 @@
+// Package pagination provides page/limit parsing and the pagination metadata
+// returned alongside list responses.
+package pagination
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxLimit caps the page size a client may request.
+const MaxLimit = 100
+
+// Params are the normalised page and limit of a list request.
+type Params struct {
+	Page  int
+	Limit int
+}
+
+// Pagination is the metadata returned with a page of results.
+type Pagination struct {
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"totalPages"`
+}
+
+// NewParams normalises page and limit: page defaults to 1, limit defaults to
+// defaultLimit and is capped at MaxLimit.
+func NewParams(page, limit, defaultLimit int) Params {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+	return Params{Page: page, Limit: limit}
+}
+
+// FromQuery reads the page and limit query parameters.
+func FromQuery(c *gin.Context, defaultLimit int) Params {
+	page, _ := strconv.Atoi(c.Query("page"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	return NewParams(page, limit, defaultLimit)
+}
+
+// Offset returns the number of rows to skip for the current page.
+func (p Params) Offset() int {
+	return (p.Page - 1) * p.Limit
+}
+
+// New builds the pagination metadata for a page of a result set of size total.
+func New(p Params, total int64) Pagination {
+	pagination := Pagination{Page: p.Page, Limit: p.Limit, Total: total}
+	if p.Limit > 0 {
+		pagination.TotalPages = int((total + int64(p.Limit) - 1) / int64(p.Limit))
+	}
+	return pagination
+}
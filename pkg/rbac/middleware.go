@@ -0,0 +1,95 @@
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/audit"
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Authorizer checks the permissions of authenticated requests and audits denials.
+type Authorizer struct {
+	policy  *Policy
+	sink    audit.Sink
+	service string
+}
+
+// NewAuthorizer creates an authorizer. service identifies the calling service in
+// audit events; sink may be nil to disable auditing.
+func NewAuthorizer(policy *Policy, sink audit.Sink, service string) *Authorizer {
+	return &Authorizer{policy: policy, sink: sink, service: service}
+}
+
+// MustLoadAuthorizer loads the role to permission mapping shared by all
+// services from policyFile, or the bundled default when it is empty, and
+// creates an authorizer for service. A service can't run without its policy,
+// so it exits if the policy fails to load.
+func MustLoadAuthorizer(policyFile string, sink audit.Sink, service string) *Authorizer {
+	policy, err := LoadPolicy(policyFile)
+	if err != nil {
+		log.Fatalf("Failed to load RBAC policy: %v", err)
+	}
+	return NewAuthorizer(policy, sink, service)
+}
+
+// Can reports whether the authenticated user of the request has perm.
+func (a *Authorizer) Can(c *gin.Context, perm Permission) bool {
+	return a.policy.Allows(auth.GetRole(c), perm)
+}
+
+// Require rejects requests whose user lacks perm. It must run after the auth
+// middleware has authenticated the request.
+func (a *Authorizer) Require(perm Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := auth.GetUserID(c)
+		if err != nil {
+			response.Abort(c, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		if !a.Can(c, perm) {
+			a.recordDenied(c, userID, perm)
+			response.Abort(c, http.StatusForbidden, "Insufficient permissions")
+			return
+		}
+		c.Next()
+	}
+}
+
+func (a *Authorizer) recordDenied(c *gin.Context, userID uuid.UUID, perm Permission) {
+	if a.sink == nil {
+		return
+	}
+
+	role := auth.GetRole(c)
+	event := audit.Event{
+		UserID:        &userID,
+		EventType:     "authorization_denied",
+		EventCategory: audit.CategoryAuthorization,
+		Description:   fmt.Sprintf("%s %s requires %s", c.Request.Method, c.FullPath(), perm),
+		Metadata: map[string]interface{}{
+			"service":    a.service,
+			"permission": string(perm),
+			"role":       role,
+			"method":     c.Request.Method,
+			"path":       c.Request.URL.Path,
+		},
+		IPAddress:    c.ClientIP(),
+		UserAgent:    c.Request.UserAgent(),
+		Success:      false,
+		ErrorMessage: "insufficient permissions",
+	}
+
+	// Don't let a slow audit write delay or fail the response
+	go func() {
+		if err := a.sink.Record(context.Background(), event); err != nil {
+			log.Printf("rbac: failed to audit denied request for user %s: %v", userID, err)
+		}
+	}()
+}
@@ -0,0 +1,47 @@
+// Package rbac maps roles to permissions and provides the middleware services
+// use to authorize requests by permission rather than by raw role strings.
+package rbac
+
+// Permission is an action a user may be allowed to perform, written as
+// "<resource>:<action>".
+type Permission string
+
+const (
+	ListingCreate   Permission = "listing:create"
+	ListingModerate Permission = "listing:moderate"
+
+	ReviewCreate   Permission = "review:create"
+	ReviewRespond  Permission = "review:respond"
+	ReviewModerate Permission = "review:moderate"
+
+	ReportCreate  Permission = "report:create"
+	ReportRead    Permission = "report:read"
+	ReportResolve Permission = "report:resolve"
+
+	InquiryRead    Permission = "inquiry:read"
+	InquiryRespond Permission = "inquiry:respond"
+
+	AnalyticsRead    Permission = "analytics:read"
+	AnalyticsRunJobs Permission = "analytics:run-jobs"
+
+	MessageSend      Permission = "message:send"
+	ImageUpload      Permission = "image:upload"
+	NotificationSend Permission = "notification:send"
+	// MessageModerate allows editing the scam phrases that hold messages for review
+	MessageModerate Permission = "message:moderate"
+
+	DealerVerify Permission = "dealer:verify"
+	AuditRead    Permission = "audit:read"
+	// APIKeyManage allows creating and revoking API keys for the caller's own account
+	APIKeyManage Permission = "apikey:manage"
+
+	UserManage Permission = "user:manage"
+	// UserManageAdmins additionally allows acting on admin accounts and granting admin roles
+	UserManageAdmins Permission = "user:manage-admins"
+	// UserImpersonate allows minting short-lived tokens that act as a non-admin user
+	UserImpersonate Permission = "user:impersonate"
+
+	FavoritesManage  Permission = "favorites:manage"
+	SearchesManage   Permission = "searches:manage"
+	ComparisonManage Permission = "comparison:manage"
+)
@@ -0,0 +1,68 @@
+package rbac
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+//go:embed policy.json
+var defaultPolicy []byte
+
+// Policy maps roles to the permissions they grant. A permission of "*" grants
+// everything and "<resource>:*" grants every action on a resource.
+type Policy struct {
+	roles map[string]map[Permission]bool
+}
+
+type policyFile struct {
+	Roles map[string][]Permission `json:"roles"`
+}
+
+// LoadPolicy reads the role to permission mapping from a JSON file. When path
+// is empty the default policy bundled with this package is used.
+func LoadPolicy(path string) (*Policy, error) {
+	data := defaultPolicy
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read RBAC policy: %w", err)
+		}
+	}
+	return ParsePolicy(data)
+}
+
+// ParsePolicy parses a JSON policy document.
+func ParsePolicy(data []byte) (*Policy, error) {
+	var file policyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid RBAC policy: %w", err)
+	}
+
+	p := &Policy{roles: make(map[string]map[Permission]bool, len(file.Roles))}
+	for role, perms := range file.Roles {
+		set := make(map[Permission]bool, len(perms))
+		for _, perm := range perms {
+			set[perm] = true
+		}
+		p.roles[role] = set
+	}
+	return p, nil
+}
+
+// Allows reports whether role grants perm.
+func (p *Policy) Allows(role string, perm Permission) bool {
+	perms, ok := p.roles[role]
+	if !ok {
+		return false
+	}
+	if perms["*"] || perms[perm] {
+		return true
+	}
+	if resource, _, found := strings.Cut(string(perm), ":"); found {
+		return perms[Permission(resource+":*")]
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+// Package response defines the error envelope shared by every service:
+//
+//	{"success": false, "error": "listing not found", "code": "NOT_FOUND"}
+//
+// The error message stays a plain string so existing clients keep working;
+// code is a stable machine-readable identifier.
+package response
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorBody is the JSON body written for every error response.
+type ErrorBody struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Code    string `json:"code"`
+}
+
+// Error writes an error response with a code derived from the HTTP status.
+func Error(c *gin.Context, status int, message string) {
+	c.JSON(status, body(status, "", message))
+}
+
+// ErrorWithCode writes an error response with an explicit code.
+func ErrorWithCode(c *gin.Context, status int, code, message string) {
+	c.JSON(status, body(status, code, message))
+}
+
+// Abort writes an error response and stops the handler chain. It is intended
+// for middleware.
+func Abort(c *gin.Context, status int, message string) {
+	c.AbortWithStatusJSON(status, body(status, "", message))
+}
+
+// AbortWithCode is Abort with an explicit code.
+func AbortWithCode(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, body(status, code, message))
+}
+
+func body(status int, code, message string) ErrorBody {
+	if code == "" {
+		code = CodeForStatus(status)
+	}
+	return ErrorBody{Success: false, Error: message, Code: code}
+}
+
+// CodeForStatus maps an HTTP status to its default code, e.g. 404 -> NOT_FOUND.
+func CodeForStatus(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "ERROR"
+	}
+	text = strings.ReplaceAll(text, "-", " ")
+	return strings.ToUpper(strings.Join(strings.Fields(text), "_"))
+}
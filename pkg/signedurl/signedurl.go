@@ -0,0 +1,50 @@
+// Package signedurl grants temporary access to a private file through a URL,
+// for clients that can't send an Authorization header (e.g. <img> tags). The
+// service that issues the URL and the one that serves it share a secret.
+package signedurl
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrExpired          = errors.New("link has expired")
+	ErrInvalidSignature = errors.New("invalid link signature")
+)
+
+// Sign returns path with the expiry and signature appended as query parameters.
+func Sign(secret []byte, path string, expires time.Time) string {
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	q := url.Values{}
+	q.Set("expires", exp)
+	q.Set("signature", signature(secret, path, exp))
+	return path + "?" + q.Encode()
+}
+
+// Verify checks the expiry and signature that Sign added to path.
+func Verify(secret []byte, path string, query url.Values, now time.Time) error {
+	exp := query.Get("expires")
+	unix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(query.Get("signature")), []byte(signature(secret, path, exp))) {
+		return ErrInvalidSignature
+	}
+	if !now.Before(time.Unix(unix, 0)) {
+		return ErrExpired
+	}
+	return nil
+}
+
+func signature(secret []byte, path, expires string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(path + "\n" + expires))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -10,8 +10,15 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/audit"
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/cors"
+	"github.com/aselahemantha/exoticsLanka/pkg/jwks"
+	"github.com/aselahemantha/exoticsLanka/pkg/migrate"
+	"github.com/aselahemantha/exoticsLanka/pkg/rbac"
 	"github.com/aselahemantha/exoticsLanka/services/analytics-service/internal/config"
 	"github.com/aselahemantha/exoticsLanka/services/analytics-service/internal/handler"
+	"github.com/aselahemantha/exoticsLanka/services/analytics-service/internal/jobs"
 	"github.com/aselahemantha/exoticsLanka/services/analytics-service/internal/repository"
 	"github.com/aselahemantha/exoticsLanka/services/analytics-service/internal/service"
 	"github.com/gin-gonic/gin"
@@ -38,7 +45,7 @@ func main() {
 	fmt.Println("Connected to PostgreSQL")
 
 	// 3. Run Migrations
-	if err := repository.RunMigrations(dbPool, "migrations"); err != nil {
+	if err := migrate.Run(context.Background(), dbPool, "migrations"); err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
@@ -47,40 +54,47 @@ func main() {
 	svc := service.NewService(repo)
 	h := handler.NewHandler(svc)
 
+	// Aggregates each seller's previous day, including response times
+	jobs.NewJobScheduler(svc).Start()
+
+	// Token verification keys, fetched from auth-service; suspended and deleted
+	// accounts are rejected straight away and impersonated requests are audited
+	auditSink := audit.NewPostgresSink(dbPool)
+	authMW := auth.NewMiddleware(
+		jwks.NewClient(cfg.JWKSURL, cfg.JWTPublicKeyFile).Keyfunc,
+		auth.WithTokenCheck(auth.AccountStatusCheck(dbPool)),
+		auth.WithImpersonationAudit(auditSink, "analytics-service"),
+	)
+
+	// Role to permission mapping shared by all services
+	authz := rbac.MustLoadAuthorizer(cfg.RBACPolicyFile, auditSink, "analytics-service")
+
 	// 5. Setup Router
 	router := gin.Default()
 
-	// CORS
-	router.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-		c.Next()
-	})
+	router.Use(cors.New())
 
 	api := router.Group("/api")
 
 	// Public Tracking Endpoint (Optional Auth support included in internal logic or add middleware)
 	// Track event logic supports extracting userId from header if present.
 	// We use OptionalAuthMiddleware here or handle it manually.
-	api.POST("/analytics/track", handler.OptionalAuthMiddleware(cfg), h.Track)
+	api.POST("/analytics/track", authMW.Optional(), h.Track)
+
+	// Public "usually replies within" badge shown on listing pages
+	api.GET("/analytics/sellers/:id/response-time", h.GetResponseBadge)
 
 	// Protected Dealer/Admin Routes
 	dealer := api.Group("/analytics")
-	dealer.Use(handler.AuthMiddleware(cfg))
+	dealer.Use(authMW.Required())
 	{
-		dealer.GET("/overview", h.GetOverview)
-		dealer.GET("/insights", h.GetInsights)
-		dealer.GET("/inventory", h.GetInventoryStats)
+		dealer.GET("/overview", authz.Require(rbac.AnalyticsRead), h.GetOverview)
+		dealer.GET("/insights", authz.Require(rbac.AnalyticsRead), h.GetInsights)
+		dealer.GET("/inventory", authz.Require(rbac.AnalyticsRead), h.GetInventoryStats)
+		dealer.GET("/organization", authz.Require(rbac.AnalyticsRead), h.GetOrganizationOverview)
 
-		// Admin/Testing Trigger
-		dealer.POST("/jobs/aggregate", h.TriggerAggregation) // In real app, restrict to Admin role
+		// Admin Trigger
+		dealer.POST("/jobs/aggregate", authz.Require(rbac.AnalyticsRunJobs), h.TriggerAggregation)
 	}
 
 	// 6. Start Server
@@ -34,6 +34,8 @@ type DealerAnalytics struct {
 	TotalRevenue        float64   `json:"totalRevenue"`
 	AvgResponseTimeMins *int      `json:"avgResponseTimeMins,omitempty"`
 	ResponseRate        *float64  `json:"responseRate,omitempty"`
+	ResponseLeads       int       `json:"responseLeads"` // Leads the response metrics are measured over
+	RepliedLeads        int       `json:"repliedLeads"`
 	InventoryCount      int       `json:"inventoryCount"`
 	InventoryValue      float64   `json:"inventoryValue"`
 	AvgHealthScore      int       `json:"avgHealthScore"`
@@ -41,6 +43,136 @@ type DealerAnalytics struct {
 	CreatedAt           time.Time `json:"createdAt"`
 }
 
+// MemberAnalytics is a dealer organisation member's activity on the listings
+// and conversations assigned to them, for one day or summed over a period.
+type MemberAnalytics struct {
+	OrganizationID uuid.UUID `json:"organizationId"`
+	MemberID       uuid.UUID `json:"memberId"`
+	MemberName     string    `json:"memberName,omitempty"`
+	Role           string    `json:"role,omitempty"`
+	Date           string    `json:"date,omitempty"` // YYYY-MM-DD, daily rows only
+	TotalViews     int       `json:"totalViews"`
+	UniqueViewers  int       `json:"uniqueViewers"`
+	TotalFavorites int       `json:"totalFavorites"`
+	TotalShares    int       `json:"totalShares"`
+	TotalLeads     int       `json:"totalLeads"`
+	TotalMessages  int       `json:"totalMessages"`
+	PhoneReveals   int       `json:"phoneReveals"`
+	InventoryCount int       `json:"inventoryCount"`
+	InventoryValue float64   `json:"inventoryValue"`
+}
+
+// Add adds other's metrics to m.
+func (m *MemberAnalytics) Add(other *MemberAnalytics) {
+	m.TotalViews += other.TotalViews
+	m.UniqueViewers += other.UniqueViewers
+	m.TotalFavorites += other.TotalFavorites
+	m.TotalShares += other.TotalShares
+	m.TotalLeads += other.TotalLeads
+	m.TotalMessages += other.TotalMessages
+	m.PhoneReveals += other.PhoneReveals
+	m.InventoryCount += other.InventoryCount
+	m.InventoryValue += other.InventoryValue
+}
+
+// SriLankaTime is the time zone business hours are kept in. Sri Lanka doesn't
+// observe daylight saving.
+var SriLankaTime = time.FixedZone("Asia/Colombo", 5*60*60+30*60)
+
+// BusinessWindow is a weekly period, in Sri Lanka time, when a seller answers
+// messages, as set with their auto-reply in messaging-service. Weekday 0 is Sunday.
+type BusinessWindow struct {
+	Weekday int    `json:"weekday"`
+	Start   string `json:"start"` // HH:MM
+	End     string `json:"end"`
+}
+
+// BusinessTime is how much of the time between from and to falls within the
+// windows. Without windows it is all of it.
+func BusinessTime(from, to time.Time, windows []BusinessWindow) time.Duration {
+	if !to.After(from) {
+		return 0
+	}
+	if len(windows) == 0 {
+		return to.Sub(from)
+	}
+
+	from, to = from.In(SriLankaTime), to.In(SriLankaTime)
+	var total time.Duration
+	for day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, SriLankaTime); day.Before(to); day = day.AddDate(0, 0, 1) {
+		for _, w := range windows {
+			if int(day.Weekday()) != w.Weekday {
+				continue
+			}
+			start, ok := clockTime(day, w.Start)
+			if !ok {
+				continue
+			}
+			end, ok := clockTime(day, w.End)
+			if !ok {
+				continue
+			}
+			if start.Before(from) {
+				start = from
+			}
+			if end.After(to) {
+				end = to
+			}
+			if end.After(start) {
+				total += end.Sub(start)
+			}
+		}
+	}
+	return total
+}
+
+// clockTime is the HH:MM time of day on the day
+func clockTime(day time.Time, hhmm string) (time.Time, bool) {
+	t, err := time.Parse("15:04", hhmm)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return day.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute), true
+}
+
+// LeadResponse is when the buyer first wrote in a conversation and when the
+// seller side first replied, if it has
+type LeadResponse struct {
+	FirstMessageAt time.Time
+	FirstReplyAt   *time.Time
+	BusinessHours  []BusinessWindow // The seller side's, if set
+}
+
+// ResponseMetrics are first-response figures over a set of leads. The average
+// only counts time within business hours; both are nil without leads.
+type ResponseMetrics struct {
+	Leads               int
+	Replied             int
+	AvgResponseTimeMins *int
+	ResponseRate        *float64 // Percent of leads replied to
+}
+
+// ResponseBadge is the "usually replies within" badge shown on listing pages
+type ResponseBadge struct {
+	SellerID            uuid.UUID `json:"sellerId"`
+	Available           bool      `json:"available"` // Enough replies to go on
+	Label               string    `json:"label,omitempty"`
+	AvgResponseTimeMins *int      `json:"avgResponseTimeMins,omitempty"`
+	ResponseRate        *float64  `json:"responseRate,omitempty"`
+	PeriodDays          int       `json:"periodDays"`
+}
+
+// Badges are worked out over the last BadgePeriodDays, and need at least
+// BadgeMinReplies replies
+const (
+	BadgePeriodDays = 30
+	BadgeMinReplies = 3
+)
+
+// ResponseRecheckDays is how many days back the daily job refreshes response
+// metrics, so leads answered after their day was aggregated are counted
+const ResponseRecheckDays = 7
+
 // Stats Objects for Dashboard API
 type DashboardStats struct {
 	Period      string          `json:"period"`
@@ -50,11 +182,23 @@ type DashboardStats struct {
 	Performance PerformStats    `json:"performance"`
 }
 
+// OrganizationDashboard is a dealer organisation's totals with a per-member
+// breakdown. Salespeople only see their own row and totals.
+type OrganizationDashboard struct {
+	Period         string            `json:"period"`
+	OrganizationID uuid.UUID         `json:"organizationId"`
+	Summary        SummaryStats      `json:"summary"`
+	Engagement     EngagementStats   `json:"engagement"`
+	Conversions    ConversionStats   `json:"conversions"`
+	Members        []MemberAnalytics `json:"members"`
+}
+
 type SummaryStats struct {
 	TotalInventory   int     `json:"totalInventory"`
 	TotalValue       float64 `json:"totalValue"`
 	AvgDaysToSell    int     `json:"avgDaysToSell"`
-	ResponseTimeAvg  int     `json:"responseTimeAvg"`
+	ResponseTimeAvg  int     `json:"responseTimeAvg"` // Minutes, within business hours
+	ResponseRate     float64 `json:"responseRate"`
 	ReviewScore      float64 `json:"reviewScore"`
 	DepreciationRate float64 `json:"inventoryDepreciation"`
 }
@@ -73,6 +217,7 @@ type ConversionStats struct {
 	TotalMessages  int     `json:"totalMessages"`
 	PhoneReveals   int     `json:"phoneReveals"`
 	TotalSales     int     `json:"totalSales"`
+	TotalRevenue   float64 `json:"totalRevenue"`
 	ConversionRate float64 `json:"conversionRate"`
 	LeadsChange    float64 `json:"leadsChange"`
 }
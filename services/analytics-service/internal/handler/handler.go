@@ -4,9 +4,12 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
 	"github.com/aselahemantha/exoticsLanka/services/analytics-service/internal/domain"
 	"github.com/aselahemantha/exoticsLanka/services/analytics-service/internal/service"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type Handler struct {
@@ -21,19 +24,19 @@ func NewHandler(service service.Service) *Handler {
 func (h *Handler) Track(c *gin.Context) {
 	var req domain.TrackEventRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	meta := make(map[string]interface{})
 	meta["ipAddress"] = c.ClientIP()
 	meta["userAgent"] = c.Request.UserAgent()
-	if uid, err := GetUserID(c); err == nil {
+	if uid, err := auth.GetUserID(c); err == nil {
 		meta["userId"] = uid
 	}
 
 	if err := h.service.TrackEvent(c.Request.Context(), req, meta); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -42,9 +45,9 @@ func (h *Handler) Track(c *gin.Context) {
 
 // GET /api/analytics/overview
 func (h *Handler) GetOverview(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -52,7 +55,7 @@ func (h *Handler) GetOverview(c *gin.Context) {
 
 	stats, err := h.service.GetDashboard(c.Request.Context(), userID, period)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -61,15 +64,15 @@ func (h *Handler) GetOverview(c *gin.Context) {
 
 // GET /api/analytics/insights
 func (h *Handler) GetInsights(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	insights, err := h.service.GenerateInsights(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -78,38 +81,78 @@ func (h *Handler) GetInsights(c *gin.Context) {
 
 // GET /api/analytics/inventory
 func (h *Handler) GetInventoryStats(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	data, err := h.service.GetInventoryPerformance(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": data})
 }
 
+// GET /api/analytics/organization?period=30d
+func (h *Handler) GetOrganizationOverview(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	period := c.DefaultQuery("period", "30d")
+
+	stats, err := h.service.GetOrganizationDashboard(c.Request.Context(), userID, period)
+	if err != nil {
+		if err.Error() == "you are not a member of a dealer organisation" {
+			response.Error(c, http.StatusForbidden, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": stats})
+}
+
+// GET /api/analytics/sellers/:id/response-time - public badge for listing pages
+func (h *Handler) GetResponseBadge(c *gin.Context) {
+	sellerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid seller ID")
+		return
+	}
+
+	badge, err := h.service.GetResponseBadge(c.Request.Context(), sellerID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": badge})
+}
+
 // POST /api/analytics/jobs/aggregate (Admin/Manual Trigger)
 func (h *Handler) TriggerAggregation(c *gin.Context) {
-	userID, err := GetUserID(c) // Technically should check if admin or if user wants to agg their own data
+	userID, err := auth.GetUserID(c) // Technically should check if admin or if user wants to agg their own data
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Default to today or provided date
 	date := c.Query("date")
 	if date == "" {
-		date = time.Now().Format("2006-01-02")
+		date = time.Now().In(domain.SriLankaTime).Format("2006-01-02")
 	}
 
 	err = h.service.RunDailyAggregation(c.Request.Context(), userID, date)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
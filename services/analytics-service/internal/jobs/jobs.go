@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/services/analytics-service/internal/service"
+)
+
+type JobScheduler struct {
+	svc service.Service
+}
+
+func NewJobScheduler(svc service.Service) *JobScheduler {
+	return &JobScheduler{svc: svc}
+}
+
+func (s *JobScheduler) Start() {
+	go s.runDailyJobs()
+}
+
+// runDailyJobs runs once at startup, so a restart doesn't skip a day, then
+// every 24 hours. Aggregation is an upsert, so running twice is harmless.
+func (s *JobScheduler) runDailyJobs() {
+	s.runDaily()
+
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.runDaily()
+	}
+}
+
+func (s *JobScheduler) runDaily() {
+	log.Println("Running daily jobs...")
+
+	if n, err := s.svc.RunScheduledAggregation(context.Background(), time.Now()); err != nil {
+		log.Printf("Error aggregating daily analytics: %v", err)
+	} else {
+		log.Printf("Aggregated daily analytics for %d sellers", n)
+	}
+}
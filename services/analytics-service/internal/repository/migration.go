@@ -1,43 +0,0 @@
-package repository
-
-import (
-	"context"
-	"fmt"
-	"os"
-	"path/filepath"
-	"sort"
-	"strings"
-
-	"github.com/jackc/pgx/v5/pgxpool"
-)
-
-func RunMigrations(db *pgxpool.Pool, migrationsDir string) error {
-	entries, err := os.ReadDir(migrationsDir)
-	if err != nil {
-		return fmt.Errorf("failed to read migrations directory: %v", err)
-	}
-
-	var sqlFiles []string
-	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".sql") {
-			sqlFiles = append(sqlFiles, entry.Name())
-		}
-	}
-
-	sort.Strings(sqlFiles)
-
-	for _, file := range sqlFiles {
-		content, err := os.ReadFile(filepath.Join(migrationsDir, file))
-		if err != nil {
-			return fmt.Errorf("failed to read migration file %s: %v", file, err)
-		}
-
-		_, err = db.Exec(context.Background(), string(content))
-		if err != nil {
-			return fmt.Errorf("failed to execute migration %s: %v", file, err)
-		}
-		fmt.Printf("Executed migration: %s\n", file)
-	}
-
-	return nil
-}
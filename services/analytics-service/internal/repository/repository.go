@@ -2,9 +2,12 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/org"
 	"github.com/aselahemantha/exoticsLanka/services/analytics-service/internal/domain"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -16,12 +19,23 @@ type Repository interface {
 	GetDailyEngagement(ctx context.Context, dealerID string, date string) (*domain.EngagementStats, error)
 	GetDailyConversions(ctx context.Context, dealerID string, date string) (*domain.ConversionStats, error)
 	GetInventoryMetrics(ctx context.Context, dealerID string) (*domain.SummaryStats, error)
+	GetDailyResponses(ctx context.Context, dealerID string, date string) ([]domain.LeadResponse, error)
+	GetActiveSellers(ctx context.Context, date string) ([]uuid.UUID, error)
+	GetSellersWithLeads(ctx context.Context, date string) ([]uuid.UUID, error)
 
 	// Analytics Storage
 	UpsertDealerAnalytics(ctx context.Context, analytics *domain.DealerAnalytics) error
+	UpsertResponseMetrics(ctx context.Context, dealerID uuid.UUID, date string, m *domain.ResponseMetrics) error
 
 	// Reporting
 	GetDealerAnalytics(ctx context.Context, dealerID string, periodDays int) ([]domain.DealerAnalytics, error)
+	GetResponseSummary(ctx context.Context, dealerID uuid.UUID, periodDays int) (*domain.ResponseMetrics, error)
+
+	// Dealer organisations (organizations are owned by auth-service, in the shared database)
+	GetMembership(ctx context.Context, userID uuid.UUID) (*org.Membership, error)
+	GetDailyMemberStats(ctx context.Context, orgID uuid.UUID, date string) ([]domain.MemberAnalytics, error)
+	UpsertMemberAnalytics(ctx context.Context, analytics *domain.MemberAnalytics) error
+	GetMemberAnalytics(ctx context.Context, orgID uuid.UUID, periodDays int) ([]domain.MemberAnalytics, error)
 }
 
 type postgresRepository struct {
@@ -32,6 +46,16 @@ func NewPostgresRepository(db *pgxpool.Pool) Repository {
 	return &postgresRepository{db: db}
 }
 
+// localDate is the Sri Lanka date of a timestamp column, so a day's analytics
+// cover the day as sellers live it. The shared tables store UTC timestamps
+// without a time zone.
+func localDate(column string) string {
+	return "((" + column + " AT TIME ZONE 'UTC') AT TIME ZONE 'Asia/Colombo')::date"
+}
+
+// localToday is the current date in Sri Lanka
+const localToday = "(NOW() AT TIME ZONE 'Asia/Colombo')::date"
+
 func (r *postgresRepository) TrackEvent(ctx context.Context, view *domain.ListingView) error {
 	_, err := r.db.Exec(ctx, `
 		INSERT INTO listing_views (
@@ -52,7 +76,7 @@ func (r *postgresRepository) GetDailyEngagement(ctx context.Context, dealerID st
 			COUNT(*) FILTER (WHERE event_type = 'share') as total_shares
 		FROM listing_views lv
 		JOIN car_listings cl ON lv.listing_id = cl.id
-		WHERE cl.user_id = $1 AND DATE(lv.created_at) = $2
+		WHERE cl.user_id = $1 AND `+localDate("lv.created_at")+` = $2
 	`, dealerID, date).Scan(&stats.TotalViews, &stats.UniqueViewers, &stats.TotalShares)
 	if err != nil {
 		return nil, err
@@ -63,7 +87,7 @@ func (r *postgresRepository) GetDailyEngagement(ctx context.Context, dealerID st
 		SELECT COUNT(*)
 		FROM favorites f
 		JOIN car_listings cl ON f.listing_id = cl.id
-		WHERE cl.user_id = $1 AND DATE(f.created_at) = $2
+		WHERE cl.user_id = $1 AND `+localDate("f.created_at")+` = $2
 	`, dealerID, date).Scan(&stats.TotalFavorites)
 	if err != nil {
 		return nil, err
@@ -79,7 +103,7 @@ func (r *postgresRepository) GetDailyConversions(ctx context.Context, dealerID s
 	err := r.db.QueryRow(ctx, `
 		SELECT COUNT(*)
 		FROM conversations
-		WHERE seller_id = $1 AND DATE(created_at) = $2
+		WHERE seller_id = $1 AND `+localDate("created_at")+` = $2
 	`, dealerID, date).Scan(&stats.TotalLeads)
 	if err != nil {
 		return nil, err
@@ -90,18 +114,29 @@ func (r *postgresRepository) GetDailyConversions(ctx context.Context, dealerID s
 		SELECT COUNT(*)
 		FROM messages m
 		JOIN conversations c ON m.conversation_id = c.id
-		WHERE c.seller_id = $1 AND DATE(m.created_at) = $2
+		WHERE c.seller_id = $1 AND `+localDate("m.created_at")+` = $2
 	`, dealerID, date).Scan(&stats.TotalMessages)
 	if err != nil {
 		return nil, err
 	}
 
+	// Sales are offers accepted in messaging-service (Shared DB). Revenue only
+	// counts offers in LKR, the currency listings are priced in.
+	err = r.db.QueryRow(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(amount) FILTER (WHERE currency = 'LKR'), 0)
+		FROM offers
+		WHERE seller_id = $1 AND status = 'accepted' AND `+localDate("responded_at")+` = $2
+	`, dealerID, date).Scan(&stats.TotalSales, &stats.TotalRevenue)
+	if err != nil {
+		return nil, err
+	}
+
 	// Phone Reveals (from listing_views events)
 	err = r.db.QueryRow(ctx, `
 		SELECT COUNT(*)
 		FROM listing_views lv
 		JOIN car_listings cl ON lv.listing_id = cl.id
-		WHERE cl.user_id = $1 AND DATE(lv.created_at) = $2 AND lv.event_type = 'phone_click'
+		WHERE cl.user_id = $1 AND `+localDate("lv.created_at")+` = $2 AND lv.event_type = 'phone_click'
 	`, dealerID, date).Scan(&stats.PhoneReveals)
 	if err != nil {
 		return nil, err
@@ -131,9 +166,10 @@ func (r *postgresRepository) UpsertDealerAnalytics(ctx context.Context, a *domai
 		INSERT INTO dealer_analytics (
 			dealer_id, date,
 			total_views, unique_viewers, total_clicks, total_favorites, total_shares,
-			total_leads, total_messages, phone_reveals,
-			inventory_count, inventory_value, avg_health_score, avg_days_listed
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			total_leads, total_messages, phone_reveals, total_sales, total_revenue,
+			inventory_count, inventory_value, avg_health_score, avg_days_listed,
+			avg_response_time_mins, response_rate, response_leads, replied_leads
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
 		ON CONFLICT (dealer_id, date) DO UPDATE SET
 			total_views = EXCLUDED.total_views,
 			unique_viewers = EXCLUDED.unique_viewers,
@@ -143,26 +179,126 @@ func (r *postgresRepository) UpsertDealerAnalytics(ctx context.Context, a *domai
 			total_leads = EXCLUDED.total_leads,
 			total_messages = EXCLUDED.total_messages,
 			phone_reveals = EXCLUDED.phone_reveals,
+			total_sales = EXCLUDED.total_sales,
+			total_revenue = EXCLUDED.total_revenue,
 			inventory_count = EXCLUDED.inventory_count,
 			inventory_value = EXCLUDED.inventory_value,
 			avg_health_score = EXCLUDED.avg_health_score,
-			avg_days_listed = EXCLUDED.avg_days_listed
+			avg_days_listed = EXCLUDED.avg_days_listed,
+			avg_response_time_mins = EXCLUDED.avg_response_time_mins,
+			response_rate = EXCLUDED.response_rate,
+			response_leads = EXCLUDED.response_leads,
+			replied_leads = EXCLUDED.replied_leads
 	`, a.DealerID, a.Date,
 		a.TotalViews, a.UniqueViewers, a.TotalClicks, a.TotalFavorites, a.TotalShares,
-		a.TotalLeads, a.TotalMessages, a.PhoneReveals,
-		a.InventoryCount, a.InventoryValue, a.AvgHealthScore, a.AvgDaysListed)
+		a.TotalLeads, a.TotalMessages, a.PhoneReveals, a.TotalSales, a.TotalRevenue,
+		a.InventoryCount, a.InventoryValue, a.AvgHealthScore, a.AvgDaysListed,
+		a.AvgResponseTimeMins, a.ResponseRate, a.ResponseLeads, a.RepliedLeads)
+	return err
+}
+
+// UpsertResponseMetrics refreshes only the response metrics of a day
+func (r *postgresRepository) UpsertResponseMetrics(ctx context.Context, dealerID uuid.UUID, date string, m *domain.ResponseMetrics) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO dealer_analytics (dealer_id, date, avg_response_time_mins, response_rate, response_leads, replied_leads)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (dealer_id, date) DO UPDATE SET
+			avg_response_time_mins = EXCLUDED.avg_response_time_mins,
+			response_rate = EXCLUDED.response_rate,
+			response_leads = EXCLUDED.response_leads,
+			replied_leads = EXCLUDED.replied_leads
+	`, dealerID, date, m.AvgResponseTimeMins, m.ResponseRate, m.Leads, m.Replied)
 	return err
 }
 
+// GetDailyResponses returns, for each conversation the seller received on the
+// date, when the buyer's first message reached the seller side and when their
+// first reply reached the buyer (messaging-service tables, in the shared DB).
+// A message held by screening counts from its release; auto-replies aren't
+// replies. Business hours are the seller side's auto-reply hours.
+func (r *postgresRepository) GetDailyResponses(ctx context.Context, dealerID string, date string) ([]domain.LeadResponse, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT f.first_at, rp.replied_at, COALESCE(ar.business_hours, '[]')
+		FROM conversations c
+		CROSS JOIN LATERAL (
+			SELECT MIN(m.delivered_at) AS first_at
+			FROM messages m
+			WHERE m.conversation_id = c.id AND m.sender_id = c.buyer_id AND m.moderation_status = 'visible'
+		) f
+		CROSS JOIN LATERAL (
+			SELECT MIN(m.delivered_at) AS replied_at
+			FROM messages m
+			WHERE m.conversation_id = c.id AND m.sender_id <> c.buyer_id
+			AND m.message_type <> 'auto_reply' AND m.moderation_status = 'visible'
+			AND m.delivered_at >= f.first_at
+		) rp
+		LEFT JOIN auto_replies ar ON ar.owner_id = COALESCE(c.organization_id, c.seller_id)
+		WHERE c.seller_id = $1 AND `+localDate("c.created_at")+` = $2 AND f.first_at IS NOT NULL
+	`, dealerID, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var leads []domain.LeadResponse
+	for rows.Next() {
+		var l domain.LeadResponse
+		var hours []byte
+		if err := rows.Scan(&l.FirstMessageAt, &l.FirstReplyAt, &hours); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(hours, &l.BusinessHours); err != nil {
+			return nil, err
+		}
+		leads = append(leads, l)
+	}
+	return leads, rows.Err()
+}
+
+// GetActiveSellers returns the sellers to aggregate for the date: those with
+// active listings or new conversations
+func (r *postgresRepository) GetActiveSellers(ctx context.Context, date string) ([]uuid.UUID, error) {
+	return r.ids(ctx, `
+		SELECT user_id FROM car_listings WHERE status = 'active'
+		UNION
+		SELECT seller_id FROM conversations WHERE `+localDate("created_at")+` = $1
+	`, date)
+}
+
+// GetSellersWithLeads returns the sellers who received conversations on the date
+func (r *postgresRepository) GetSellersWithLeads(ctx context.Context, date string) ([]uuid.UUID, error) {
+	return r.ids(ctx, "SELECT DISTINCT seller_id FROM conversations WHERE "+localDate("created_at")+" = $1", date)
+}
+
+func (r *postgresRepository) ids(ctx context.Context, query string, args ...any) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
 func (r *postgresRepository) GetDealerAnalytics(ctx context.Context, dealerID string, periodDays int) ([]domain.DealerAnalytics, error) {
 	query := `
 		SELECT 
 			id, dealer_id, date, 
 			total_views, unique_viewers, total_clicks, total_favorites, total_shares,
 			total_leads, total_messages, phone_reveals,
+			COALESCE(total_sales, 0), COALESCE(total_revenue, 0),
+			avg_response_time_mins, response_rate::float8, COALESCE(response_leads, 0), COALESCE(replied_leads, 0),
 			inventory_count, inventory_value, avg_health_score, avg_days_listed
 		FROM dealer_analytics
-		WHERE dealer_id = $1 AND date >= CURRENT_DATE - make_interval(days => $2)
+		WHERE dealer_id = $1 AND date >= `+localToday+` - make_interval(days => $2)
 		ORDER BY date ASC
 	`
 	rows, err := r.db.Query(ctx, query, dealerID, periodDays)
@@ -179,6 +315,8 @@ func (r *postgresRepository) GetDealerAnalytics(ctx context.Context, dealerID st
 			&da.ID, &da.DealerID, &date,
 			&da.TotalViews, &da.UniqueViewers, &da.TotalClicks, &da.TotalFavorites, &da.TotalShares,
 			&da.TotalLeads, &da.TotalMessages, &da.PhoneReveals,
+			&da.TotalSales, &da.TotalRevenue,
+			&da.AvgResponseTimeMins, &da.ResponseRate, &da.ResponseLeads, &da.RepliedLeads,
 			&da.InventoryCount, &da.InventoryValue, &da.AvgHealthScore, &da.AvgDaysListed,
 		)
 		if err != nil {
@@ -189,3 +327,214 @@ func (r *postgresRepository) GetDealerAnalytics(ctx context.Context, dealerID st
 	}
 	return results, nil
 }
+
+// GetResponseSummary combines the seller's daily response metrics over the
+// period, weighting each day's average by the replies it is made of
+func (r *postgresRepository) GetResponseSummary(ctx context.Context, dealerID uuid.UUID, periodDays int) (*domain.ResponseMetrics, error) {
+	var m domain.ResponseMetrics
+	var avg, rate *float64
+	err := r.db.QueryRow(ctx, `
+		SELECT
+			COALESCE(SUM(response_leads), 0),
+			COALESCE(SUM(replied_leads), 0),
+			(SUM(avg_response_time_mins::numeric * replied_leads)
+				/ NULLIF(SUM(replied_leads) FILTER (WHERE avg_response_time_mins IS NOT NULL), 0))::float8,
+			(SUM(replied_leads)::numeric * 100 / NULLIF(SUM(response_leads), 0))::float8
+		FROM dealer_analytics
+		WHERE dealer_id = $1 AND date >= `+localToday+` - make_interval(days => $2)
+	`, dealerID, periodDays).Scan(&m.Leads, &m.Replied, &avg, &rate)
+	if err != nil {
+		return nil, err
+	}
+	if avg != nil {
+		mins := int(*avg + 0.5)
+		m.AvgResponseTimeMins = &mins
+	}
+	if rate != nil {
+		rounded := float64(int(*rate*100+0.5)) / 100
+		m.ResponseRate = &rounded
+	}
+	return &m, nil
+}
+
+func (r *postgresRepository) GetMembership(ctx context.Context, userID uuid.UUID) (*org.Membership, error) {
+	return org.Lookup(ctx, r.db, userID)
+}
+
+// GetDailyMemberStats attributes a day's activity to organisation members by
+// who each listing and conversation is assigned to. Inventory is a snapshot.
+func (r *postgresRepository) GetDailyMemberStats(ctx context.Context, orgID uuid.UUID, date string) ([]domain.MemberAnalytics, error) {
+	rows, err := r.db.Query(ctx,
+		"SELECT user_id FROM organization_members WHERE organization_id = $1 ORDER BY joined_at", orgID)
+	if err != nil {
+		return nil, err
+	}
+	var members []domain.MemberAnalytics
+	byMember := map[uuid.UUID]int{}
+	for rows.Next() {
+		m := domain.MemberAnalytics{OrganizationID: orgID, Date: date}
+		if err := rows.Scan(&m.MemberID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		byMember[m.MemberID] = len(members)
+		members = append(members, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Each query returns (member, metrics...) and fills its columns into the member's row
+	queries := []struct {
+		sql  string
+		args []any
+		dest func(m *domain.MemberAnalytics) []any
+	}{
+		{`
+			SELECT cl.assigned_to,
+				COUNT(*),
+				COUNT(DISTINCT lv.session_id),
+				COUNT(*) FILTER (WHERE lv.event_type = 'share'),
+				COUNT(*) FILTER (WHERE lv.event_type = 'phone_click')
+			FROM listing_views lv
+			JOIN car_listings cl ON lv.listing_id = cl.id
+			WHERE cl.organization_id = $1 AND cl.assigned_to IS NOT NULL AND `+localDate("lv.created_at")+` = $2
+			GROUP BY cl.assigned_to
+		`, []any{orgID, date}, func(m *domain.MemberAnalytics) []any {
+			return []any{&m.TotalViews, &m.UniqueViewers, &m.TotalShares, &m.PhoneReveals}
+		}},
+		{`
+			SELECT cl.assigned_to, COUNT(*)
+			FROM favorites f
+			JOIN car_listings cl ON f.listing_id = cl.id
+			WHERE cl.organization_id = $1 AND cl.assigned_to IS NOT NULL AND `+localDate("f.created_at")+` = $2
+			GROUP BY cl.assigned_to
+		`, []any{orgID, date}, func(m *domain.MemberAnalytics) []any {
+			return []any{&m.TotalFavorites}
+		}},
+		{`
+			SELECT assigned_to, COUNT(*)
+			FROM conversations
+			WHERE organization_id = $1 AND assigned_to IS NOT NULL AND `+localDate("created_at")+` = $2
+			GROUP BY assigned_to
+		`, []any{orgID, date}, func(m *domain.MemberAnalytics) []any {
+			return []any{&m.TotalLeads}
+		}},
+		{`
+			SELECT c.assigned_to, COUNT(*)
+			FROM messages m
+			JOIN conversations c ON m.conversation_id = c.id
+			WHERE c.organization_id = $1 AND c.assigned_to IS NOT NULL AND `+localDate("m.created_at")+` = $2
+			GROUP BY c.assigned_to
+		`, []any{orgID, date}, func(m *domain.MemberAnalytics) []any {
+			return []any{&m.TotalMessages}
+		}},
+		{`
+			SELECT assigned_to, COUNT(*), COALESCE(SUM(price), 0)
+			FROM car_listings
+			WHERE organization_id = $1 AND assigned_to IS NOT NULL AND status = 'active'
+			GROUP BY assigned_to
+		`, []any{orgID}, func(m *domain.MemberAnalytics) []any {
+			return []any{&m.InventoryCount, &m.InventoryValue}
+		}},
+	}
+
+	for _, q := range queries {
+		if err := r.mergeMemberStats(ctx, q.sql, q.args, q.dest, members, byMember); err != nil {
+			return nil, err
+		}
+	}
+
+	return members, nil
+}
+
+// mergeMemberStats runs a query whose first column is a member ID, scans the
+// remaining columns with dest and adds them to that member's row.
+func (r *postgresRepository) mergeMemberStats(ctx context.Context, query string, args []any, dest func(m *domain.MemberAnalytics) []any, members []domain.MemberAnalytics, byMember map[uuid.UUID]int) error {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var memberID uuid.UUID
+		var row domain.MemberAnalytics
+		if err := rows.Scan(append([]any{&memberID}, dest(&row)...)...); err != nil {
+			return err
+		}
+		// Listings can stay assigned to someone who has since left
+		if i, ok := byMember[memberID]; ok {
+			members[i].Add(&row)
+		}
+	}
+	return rows.Err()
+}
+
+func (r *postgresRepository) UpsertMemberAnalytics(ctx context.Context, a *domain.MemberAnalytics) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO organization_member_analytics (
+			organization_id, member_id, date,
+			total_views, unique_viewers, total_favorites, total_shares,
+			total_leads, total_messages, phone_reveals,
+			inventory_count, inventory_value
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (organization_id, member_id, date) DO UPDATE SET
+			total_views = EXCLUDED.total_views,
+			unique_viewers = EXCLUDED.unique_viewers,
+			total_favorites = EXCLUDED.total_favorites,
+			total_shares = EXCLUDED.total_shares,
+			total_leads = EXCLUDED.total_leads,
+			total_messages = EXCLUDED.total_messages,
+			phone_reveals = EXCLUDED.phone_reveals,
+			inventory_count = EXCLUDED.inventory_count,
+			inventory_value = EXCLUDED.inventory_value
+	`, a.OrganizationID, a.MemberID, a.Date,
+		a.TotalViews, a.UniqueViewers, a.TotalFavorites, a.TotalShares,
+		a.TotalLeads, a.TotalMessages, a.PhoneReveals,
+		a.InventoryCount, a.InventoryValue)
+	return err
+}
+
+// GetMemberAnalytics sums each current member's daily rows over the period;
+// inventory is taken from their latest row rather than summed.
+func (r *postgresRepository) GetMemberAnalytics(ctx context.Context, orgID uuid.UUID, periodDays int) ([]domain.MemberAnalytics, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			om.user_id, u.name, om.role,
+			COALESCE(SUM(a.total_views), 0), COALESCE(SUM(a.unique_viewers), 0),
+			COALESCE(SUM(a.total_favorites), 0), COALESCE(SUM(a.total_shares), 0),
+			COALESCE(SUM(a.total_leads), 0), COALESCE(SUM(a.total_messages), 0), COALESCE(SUM(a.phone_reveals), 0),
+			COALESCE((ARRAY_AGG(a.inventory_count ORDER BY a.date DESC))[1], 0),
+			COALESCE((ARRAY_AGG(a.inventory_value ORDER BY a.date DESC))[1], 0)
+		FROM organization_members om
+		JOIN users u ON u.id = om.user_id
+		LEFT JOIN organization_member_analytics a
+			ON a.organization_id = om.organization_id AND a.member_id = om.user_id
+			AND a.date >= `+localToday+` - make_interval(days => $2)
+		WHERE om.organization_id = $1
+		GROUP BY om.user_id, u.name, om.role, om.joined_at
+		ORDER BY om.joined_at
+	`, orgID, periodDays)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []domain.MemberAnalytics
+	for rows.Next() {
+		m := domain.MemberAnalytics{OrganizationID: orgID}
+		err := rows.Scan(
+			&m.MemberID, &m.MemberName, &m.Role,
+			&m.TotalViews, &m.UniqueViewers, &m.TotalFavorites, &m.TotalShares,
+			&m.TotalLeads, &m.TotalMessages, &m.PhoneReveals,
+			&m.InventoryCount, &m.InventoryValue,
+		)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, m)
+	}
+	return results, rows.Err()
+}
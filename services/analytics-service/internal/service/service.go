@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"fmt"
+	"log"
+	"time"
 
 	"github.com/aselahemantha/exoticsLanka/services/analytics-service/internal/domain"
 	"github.com/aselahemantha/exoticsLanka/services/analytics-service/internal/repository"
@@ -15,6 +17,9 @@ type Service interface {
 	GenerateInsights(ctx context.Context, dealerID uuid.UUID) ([]domain.Insight, error)
 	GetInventoryPerformance(ctx context.Context, dealerID uuid.UUID) (map[string]interface{}, error)
 	RunDailyAggregation(ctx context.Context, dealerID uuid.UUID, date string) error
+	RunScheduledAggregation(ctx context.Context, now time.Time) (int, error)
+	GetResponseBadge(ctx context.Context, sellerID uuid.UUID) (*domain.ResponseBadge, error)
+	GetOrganizationDashboard(ctx context.Context, userID uuid.UUID, period string) (*domain.OrganizationDashboard, error)
 }
 
 type service struct {
@@ -48,17 +53,8 @@ func (s *service) TrackEvent(ctx context.Context, req domain.TrackEventRequest,
 }
 
 func (s *service) GetDashboard(ctx context.Context, dealerID uuid.UUID, period string) (*domain.DashboardStats, error) {
-	// Determine days
-	days := 30
-	if period == "7d" {
-		days = 7
-	}
-	if period == "90d" {
-		days = 90
-	}
-
 	// Fetch historical analytics
-	history, err := s.repo.GetDealerAnalytics(ctx, dealerID.String(), days)
+	history, err := s.repo.GetDealerAnalytics(ctx, dealerID.String(), periodDays(period))
 	if err != nil {
 		return nil, err
 	}
@@ -72,6 +68,8 @@ func (s *service) GetDashboard(ctx context.Context, dealerID uuid.UUID, period s
 	// Aggregate History
 	var totalViews, uniqueViewers, totalFavorites, totalShares int
 	var totalLeads, totalMessages, phoneReveals, totalSales int
+	var totalRevenue float64
+	var measured, answered, timedReplies, responseMins int
 	// For change calculation, we'd need previous period using separate query or slice logic.
 	// MVP: Simple sum.
 
@@ -84,6 +82,13 @@ func (s *service) GetDashboard(ctx context.Context, dealerID uuid.UUID, period s
 		totalMessages += day.TotalMessages
 		phoneReveals += day.PhoneReveals
 		totalSales += day.TotalSales
+		totalRevenue += day.TotalRevenue
+		measured += day.ResponseLeads
+		answered += day.RepliedLeads
+		if day.AvgResponseTimeMins != nil {
+			timedReplies += day.RepliedLeads
+			responseMins += *day.AvgResponseTimeMins * day.RepliedLeads
+		}
 	}
 
 	stats := &domain.DashboardStats{
@@ -101,6 +106,7 @@ func (s *service) GetDashboard(ctx context.Context, dealerID uuid.UUID, period s
 			TotalMessages: totalMessages,
 			PhoneReveals:  phoneReveals,
 			TotalSales:    totalSales,
+			TotalRevenue:  totalRevenue,
 		},
 		Performance: domain.PerformStats{
 			AvgHealthScore: int(invStats.ReviewScore), // Check mapping in repo
@@ -108,6 +114,14 @@ func (s *service) GetDashboard(ctx context.Context, dealerID uuid.UUID, period s
 		},
 	}
 
+	// Response time is weighted by each day's replies
+	if timedReplies > 0 {
+		stats.Summary.ResponseTimeAvg = (responseMins + timedReplies/2) / timedReplies
+	}
+	if measured > 0 {
+		stats.Summary.ResponseRate = float64(answered*10000/measured) / 100
+	}
+
 	return stats, nil
 }
 
@@ -130,7 +144,13 @@ func (s *service) RunDailyAggregation(ctx context.Context, dealerID uuid.UUID, d
 		return fmt.Errorf("inventory agg failed: %v", err)
 	}
 
-	// 4. Upsert
+	// 4. Get First Responses
+	resp, err := s.responseMetrics(ctx, dealerID, date)
+	if err != nil {
+		return fmt.Errorf("response agg failed: %v", err)
+	}
+
+	// 5. Upsert
 	analytics := &domain.DealerAnalytics{
 		DealerID:       dealerID,
 		Date:           date,
@@ -142,6 +162,13 @@ func (s *service) RunDailyAggregation(ctx context.Context, dealerID uuid.UUID, d
 		TotalLeads:    conv.TotalLeads,
 		TotalMessages: conv.TotalMessages,
 		PhoneReveals:  conv.PhoneReveals,
+		TotalSales:    conv.TotalSales,
+		TotalRevenue:  conv.TotalRevenue,
+
+		AvgResponseTimeMins: resp.AvgResponseTimeMins,
+		ResponseRate:        resp.ResponseRate,
+		ResponseLeads:       resp.Leads,
+		RepliedLeads:        resp.Replied,
 
 		InventoryCount: inv.TotalInventory,
 		InventoryValue: inv.TotalValue,
@@ -149,7 +176,190 @@ func (s *service) RunDailyAggregation(ctx context.Context, dealerID uuid.UUID, d
 		// AvgHealthScore: ... mapping ?
 	}
 
-	return s.repo.UpsertDealerAnalytics(ctx, analytics)
+	if err := s.repo.UpsertDealerAnalytics(ctx, analytics); err != nil {
+		return err
+	}
+
+	// 6. Members of a dealer organisation also refresh the team's per-member rows
+	membership, err := s.repo.GetMembership(ctx, dealerID)
+	if err != nil {
+		return fmt.Errorf("membership lookup failed: %v", err)
+	}
+	if membership == nil {
+		return nil
+	}
+	return s.aggregateOrganization(ctx, membership.OrganizationID, date)
+}
+
+// RunScheduledAggregation aggregates yesterday for every seller with active
+// listings or new conversations, and refreshes the response metrics of the
+// days before it, whose leads may have been answered since. It returns how
+// many sellers were aggregated for yesterday; a day that fails to refresh is
+// logged and skipped. Days are Sri Lanka dates, as the queries bucket them.
+func (s *service) RunScheduledAggregation(ctx context.Context, now time.Time) (int, error) {
+	now = now.In(domain.SriLankaTime)
+	yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+	sellers, err := s.repo.GetActiveSellers(ctx, yesterday)
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range sellers {
+		if err := s.RunDailyAggregation(ctx, id, yesterday); err != nil {
+			log.Printf("Error aggregating analytics of %s for %s: %v", id, yesterday, err)
+		}
+	}
+
+	for days := 2; days <= domain.ResponseRecheckDays; days++ {
+		date := now.AddDate(0, 0, -days).Format("2006-01-02")
+		leadSellers, err := s.repo.GetSellersWithLeads(ctx, date)
+		if err != nil {
+			log.Printf("Error loading sellers with leads for %s: %v", date, err)
+			continue
+		}
+		for _, id := range leadSellers {
+			m, err := s.responseMetrics(ctx, id, date)
+			if err == nil {
+				err = s.repo.UpsertResponseMetrics(ctx, id, date, m)
+			}
+			if err != nil {
+				log.Printf("Error refreshing response metrics of %s for %s: %v", id, date, err)
+			}
+		}
+	}
+	return len(sellers), nil
+}
+
+// responseMetrics works out how quickly, within business hours, and how often
+// the seller side answered the leads of the day
+func (s *service) responseMetrics(ctx context.Context, dealerID uuid.UUID, date string) (*domain.ResponseMetrics, error) {
+	leads, err := s.repo.GetDailyResponses(ctx, dealerID.String(), date)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &domain.ResponseMetrics{Leads: len(leads)}
+	var total time.Duration
+	for _, l := range leads {
+		if l.FirstReplyAt == nil {
+			continue
+		}
+		m.Replied++
+		total += domain.BusinessTime(l.FirstMessageAt, *l.FirstReplyAt, l.BusinessHours)
+	}
+	if m.Leads > 0 {
+		rate := float64(m.Replied*10000/m.Leads) / 100
+		m.ResponseRate = &rate
+	}
+	if m.Replied > 0 {
+		mins := int((total / time.Duration(m.Replied)).Round(time.Minute).Minutes())
+		m.AvgResponseTimeMins = &mins
+	}
+	return m, nil
+}
+
+// GetResponseBadge returns the seller's "usually replies within" badge for
+// listing pages, from the last domain.BadgePeriodDays of daily metrics
+func (s *service) GetResponseBadge(ctx context.Context, sellerID uuid.UUID) (*domain.ResponseBadge, error) {
+	summary, err := s.repo.GetResponseSummary(ctx, sellerID, domain.BadgePeriodDays)
+	if err != nil {
+		return nil, err
+	}
+
+	badge := &domain.ResponseBadge{SellerID: sellerID, PeriodDays: domain.BadgePeriodDays}
+	if summary.Replied < domain.BadgeMinReplies || summary.AvgResponseTimeMins == nil {
+		return badge, nil
+	}
+	badge.Available = true
+	badge.Label = responseLabel(*summary.AvgResponseTimeMins)
+	badge.AvgResponseTimeMins = summary.AvgResponseTimeMins
+	badge.ResponseRate = summary.ResponseRate
+	return badge, nil
+}
+
+// responseLabel describes a typical response time, rounded up to the hour or day
+func responseLabel(mins int) string {
+	const day = 24 * 60
+	switch {
+	case mins <= 60:
+		return "Usually replies within an hour"
+	case mins <= day:
+		return fmt.Sprintf("Usually replies within %d hours", (mins+59)/60)
+	default:
+		return fmt.Sprintf("Usually replies within %d days", (mins+day-1)/day)
+	}
+}
+
+func (s *service) aggregateOrganization(ctx context.Context, orgID uuid.UUID, date string) error {
+	members, err := s.repo.GetDailyMemberStats(ctx, orgID, date)
+	if err != nil {
+		return fmt.Errorf("organisation agg failed: %v", err)
+	}
+	for i := range members {
+		if err := s.repo.UpsertMemberAnalytics(ctx, &members[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetOrganizationDashboard returns the dealer organisation's totals and
+// per-member breakdown. Salespeople only see their own figures.
+func (s *service) GetOrganizationDashboard(ctx context.Context, userID uuid.UUID, period string) (*domain.OrganizationDashboard, error) {
+	membership, err := s.repo.GetMembership(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if membership == nil {
+		return nil, fmt.Errorf("you are not a member of a dealer organisation")
+	}
+
+	members, err := s.repo.GetMemberAnalytics(ctx, membership.OrganizationID, periodDays(period))
+	if err != nil {
+		return nil, err
+	}
+
+	visible := []domain.MemberAnalytics{}
+	var total domain.MemberAnalytics
+	for _, m := range members {
+		if !membership.CanManage() && m.MemberID != userID {
+			continue
+		}
+		visible = append(visible, m)
+		total.Add(&m)
+	}
+
+	return &domain.OrganizationDashboard{
+		Period:         period,
+		OrganizationID: membership.OrganizationID,
+		Summary: domain.SummaryStats{
+			TotalInventory: total.InventoryCount,
+			TotalValue:     total.InventoryValue,
+		},
+		Engagement: domain.EngagementStats{
+			TotalViews:     total.TotalViews,
+			UniqueViewers:  total.UniqueViewers,
+			TotalFavorites: total.TotalFavorites,
+			TotalShares:    total.TotalShares,
+		},
+		Conversions: domain.ConversionStats{
+			TotalLeads:    total.TotalLeads,
+			TotalMessages: total.TotalMessages,
+			PhoneReveals:  total.PhoneReveals,
+		},
+		Members: visible,
+	}, nil
+}
+
+// periodDays maps a dashboard period ("7d", "30d", "90d") to days, defaulting to 30
+func periodDays(period string) int {
+	switch period {
+	case "7d":
+		return 7
+	case "90d":
+		return 90
+	default:
+		return 30
+	}
 }
 
 func (s *service) GenerateInsights(ctx context.Context, dealerID uuid.UUID) ([]domain.Insight, error) {
@@ -0,0 +1,30 @@
+-- Dealer Organisation Member Analytics (Aggregated Daily)
+-- Activity on the listings and conversations assigned to each member; the
+-- organisation's totals are the sum over its members.
+CREATE TABLE IF NOT EXISTS organization_member_analytics (
+    id                      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id         UUID NOT NULL, -- References organizations(id)
+    member_id               UUID NOT NULL, -- References users(id)
+    date                    DATE NOT NULL,
+
+    -- Engagement Metrics
+    total_views             INT DEFAULT 0,
+    unique_viewers          INT DEFAULT 0,
+    total_favorites         INT DEFAULT 0,
+    total_shares            INT DEFAULT 0,
+
+    -- Conversion Metrics
+    total_leads             INT DEFAULT 0,
+    total_messages          INT DEFAULT 0,
+    phone_reveals           INT DEFAULT 0,
+
+    -- Inventory Metrics
+    inventory_count         INT DEFAULT 0,
+    inventory_value         DECIMAL(20, 2) DEFAULT 0,
+
+    created_at              TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(organization_id, member_id, date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_org_member_analytics_org_date ON organization_member_analytics(organization_id, date DESC);
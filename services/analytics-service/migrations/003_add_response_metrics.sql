@@ -0,0 +1,5 @@
+-- First-response metrics are measured over the conversations a seller received
+-- each day whose first buyer message reached them. Keeping the counts lets
+-- averages over a period be weighted by day.
+ALTER TABLE dealer_analytics ADD COLUMN IF NOT EXISTS response_leads INT DEFAULT 0;
+ALTER TABLE dealer_analytics ADD COLUMN IF NOT EXISTS replied_leads INT DEFAULT 0;
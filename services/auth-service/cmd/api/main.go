@@ -9,8 +9,18 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/audit"
+	"github.com/aselahemantha/exoticsLanka/pkg/cors"
+	"github.com/aselahemantha/exoticsLanka/pkg/migrate"
+	"github.com/aselahemantha/exoticsLanka/pkg/rbac"
 	"github.com/exoticsLanka/auth-service/internal/config"
 	"github.com/exoticsLanka/auth-service/internal/delivery/http"
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/exoticsLanka/auth-service/internal/jobs"
+	"github.com/exoticsLanka/auth-service/internal/keystore"
+	"github.com/exoticsLanka/auth-service/internal/notification"
+	"github.com/exoticsLanka/auth-service/internal/oauth"
+	"github.com/exoticsLanka/auth-service/internal/password"
 	"github.com/exoticsLanka/auth-service/internal/repository"
 	"github.com/exoticsLanka/auth-service/internal/usecase"
 	"github.com/gin-gonic/gin"
@@ -21,6 +31,9 @@ import (
 func main() {
 	// 1. Load Config
 	cfg := config.LoadConfig()
+	if cfg.OTPSecret == "" {
+		log.Fatal("OTP_SECRET is not set")
+	}
 
 	// 2. Connect to PostgreSQL
 	dbPool, err := pgxpool.New(context.Background(), cfg.DatabaseURL)
@@ -36,7 +49,7 @@ func main() {
 
 	// 2.1 Run Migrations
 	// Dockerfile places migrations in ./sql/migrations
-	if err := repository.RunMigrations(context.Background(), dbPool, "sql/migrations"); err != nil {
+	if err := migrate.Run(context.Background(), dbPool, "sql/migrations"); err != nil {
 		log.Printf("Warning: Failed to run migrations: %v", err)
 		// We don't Fatalf here because sometimes local dev paths differ,
 		// but in Prod it should ideally work or we assume it's fine if tables exist.
@@ -55,24 +68,80 @@ func main() {
 	}
 	log.Println("Connected to Redis")
 
-	// 4. Initialize Layers
+	// 4. Load JWT signing keys
+	keys, err := keystore.Load(cfg.JWTKeysDir, cfg.JWTActiveKID)
+	if err != nil {
+		log.Fatalf("Unable to load JWT signing keys: %v\n", err)
+	}
+
+	// 5. Load the breached password list used by the password policy
+	breached, err := password.LoadBreachList(cfg.BreachedPasswordsFile)
+	if err != nil {
+		log.Fatalf("Unable to load breached password list: %v\n", err)
+	}
+	log.Printf("Loaded %d breached password hashes", breached.Len())
+	passwordPolicy := password.NewPolicy(cfg.PasswordMinLength, cfg.PasswordMinClasses, breached)
+
+	// 6. Initialize Layers
 	userRepo := repository.NewPostgresUserRepository(dbPool)
 	auditRepo := repository.NewPostgresAuditRepository(dbPool)
 	sessionRepo := repository.NewRedisSessionRepository(rdb)
-
-	authUC := usecase.NewAuthUseCase(userRepo, sessionRepo, auditRepo, cfg)
+	refreshRepo := repository.NewRedisRefreshTokenRepository(rdb)
+	dealerRepo := repository.NewPostgresDealerApplicationRepository(dbPool)
+	identityRepo := repository.NewPostgresUserIdentityRepository(dbPool)
+	oauthStateRepo := repository.NewRedisOAuthStateRepository(rdb)
+	otpRepo := repository.NewRedisOTPRepository(rdb)
+	privacyRepo := repository.NewPostgresPrivacyRepository(dbPool)
+	apiKeyRepo := repository.NewPostgresAPIKeyRepository(dbPool)
+	orgRepo := repository.NewPostgresOrganizationRepository(dbPool)
+	smsSender := notification.NewClient(cfg.NotificationServiceURL, keys)
+
+	authUC := usecase.NewAuthUseCase(userRepo, sessionRepo, refreshRepo, auditRepo, passwordPolicy, keys, cfg)
+	dealerUC := usecase.NewDealerUseCase(dealerRepo, userRepo, sessionRepo, refreshRepo, auditRepo)
+	oauthUC := usecase.NewOAuthUseCase(userRepo, identityRepo, oauthStateRepo, sessionRepo, refreshRepo, auditRepo, keys, cfg, oauthProviders(cfg)...)
+	auditUC := usecase.NewAuditUseCase(auditRepo)
+	userAdminUC := usecase.NewUserAdminUseCase(userRepo, identityRepo, sessionRepo, refreshRepo, auditRepo, keys, cfg)
+	phoneUC := usecase.NewPhoneUseCase(userRepo, otpRepo, sessionRepo, refreshRepo, auditRepo, smsSender, keys, cfg)
+	privacyUC := usecase.NewPrivacyUseCase(privacyRepo, userRepo, sessionRepo, refreshRepo, auditRepo, cfg)
+	apiKeyUC := usecase.NewAPIKeyUseCase(apiKeyRepo, userRepo, auditRepo)
+	orgUC := usecase.NewOrganizationUseCase(orgRepo, userRepo, auditRepo)
 	authHandler := http.NewAuthHandler(authUC)
-	authMiddleware := http.NewAuthMiddleware(cfg, sessionRepo)
-
-	// 5. Setup Router
+	dealerHandler := http.NewDealerHandler(dealerUC)
+	oauthHandler := http.NewOAuthHandler(oauthUC)
+	phoneHandler := http.NewPhoneHandler(phoneUC)
+	auditHandler := http.NewAuditHandler(auditUC)
+	privacyHandler := http.NewPrivacyHandler(privacyUC)
+	apiKeyHandler := http.NewAPIKeyHandler(apiKeyUC)
+	orgHandler := http.NewOrganizationHandler(orgUC)
+	auditSink := audit.NewPostgresSink(dbPool)
+	authMiddleware := http.NewAuthMiddleware(keys, sessionRepo, dbPool, auditSink)
+	// Role to permission mapping shared by all services
+	authz := rbac.MustLoadAuthorizer(cfg.RBACPolicyFile, auditSink, "auth-service")
+	userAdminHandler := http.NewUserAdminHandler(userAdminUC, authz)
+	keysHandler := http.NewKeysHandler(keys)
+
+	// 7. Setup Router
 	router := gin.Default()
+	router.Use(cors.New())
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
+	keysHandler.RegisterRoutes(router)
 	authHandler.RegisterRoutes(router, authMiddleware)
-
-	// 6. Start Server
+	oauthHandler.RegisterRoutes(router, authMiddleware)
+	phoneHandler.RegisterRoutes(router, authMiddleware)
+	dealerHandler.RegisterRoutes(router, authMiddleware, authz)
+	auditHandler.RegisterRoutes(router, authMiddleware, authz)
+	userAdminHandler.RegisterRoutes(router, authMiddleware)
+	privacyHandler.RegisterRoutes(router, authMiddleware)
+	apiKeyHandler.RegisterRoutes(router, authMiddleware, authz)
+	orgHandler.RegisterRoutes(router, authMiddleware)
+
+	// 8. Start background jobs (account erasures, expired data exports)
+	jobs.NewJobScheduler(privacyUC).Start()
+
+	// 10. Start Server
 	srv := &netHttp.Server{
 		Addr:    ":" + cfg.Port,
 		Handler: router,
@@ -99,3 +168,19 @@ func main() {
 	}
 	log.Println("Server exiting")
 }
+
+// oauthProviders returns the social login providers that have been configured
+func oauthProviders(cfg *config.Config) []domain.OAuthProvider {
+	var providers []domain.OAuthProvider
+	if cfg.GoogleClientID != "" {
+		providers = append(providers, oauth.NewGoogleProvider(cfg.GoogleClientID, cfg.GoogleClientSecret, cfg.GoogleRedirectURL))
+	}
+	if cfg.FacebookClientID != "" {
+		providers = append(providers, oauth.NewFacebookProvider(cfg.FacebookClientID, cfg.FacebookClientSecret, cfg.FacebookRedirectURL))
+	}
+	if cfg.OAuthMockEnabled {
+		log.Println("Warning: mock OAuth provider enabled")
+		providers = append(providers, oauth.NewMockProvider(cfg.OAuthMockRedirectURL, cfg.OAuthMockSubject, cfg.OAuthMockEmail))
+	}
+	return providers
+}
@@ -3,6 +3,7 @@ module github.com/exoticsLanka/auth-service
 go 1.25.5
 
 require (
+	github.com/aselahemantha/exoticsLanka/pkg v0.0.0-00010101000000-000000000000
 	github.com/gin-gonic/gin v1.11.0
 	github.com/golang-jwt/jwt/v5 v5.3.0
 	github.com/google/uuid v1.6.0
@@ -10,6 +11,7 @@ require (
 	github.com/joho/godotenv v1.5.1
 	github.com/redis/go-redis/v9 v9.17.2
 	golang.org/x/crypto v0.46.0
+	golang.org/x/oauth2 v0.30.0
 )
 
 require (
@@ -49,3 +51,5 @@ require (
 	golang.org/x/tools v0.39.0 // indirect
 	google.golang.org/protobuf v1.36.9 // indirect
 )
+
+replace github.com/aselahemantha/exoticsLanka/pkg => ../../pkg
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 )
@@ -10,8 +11,39 @@ type Config struct {
 	Port             string
 	DatabaseURL      string
 	RedisURL         string
-	JWTSecret        string
+	JWTKeysDir       string
+	JWTActiveKID     string
 	JWTRefreshSecret string
+	RBACPolicyFile   string
+
+	// OAuth providers are enabled when their client ID is set
+	GoogleClientID       string
+	GoogleClientSecret   string
+	GoogleRedirectURL    string
+	FacebookClientID     string
+	FacebookClientSecret string
+	FacebookRedirectURL  string
+	// The mock provider signs everyone in as OAuthMockEmail, for local development and tests
+	OAuthMockEnabled     bool
+	OAuthMockEmail       string
+	OAuthMockSubject     string
+	OAuthMockRedirectURL string
+
+	// Phone OTP codes are sent through notification-service
+	NotificationServiceURL string
+	OTPSecret              string
+
+	// Personal data exports are written here and kept for a week
+	DataExportDir string
+	// Days between an erasure request and the account actually being erased
+	ErasureGraceDays int
+
+	// Password policy for new passwords; BreachedPasswordsFile adds SHA-1 hashes to the bundled list
+	PasswordMinLength     int
+	PasswordMinClasses    int
+	BreachedPasswordsFile string
+	// Existing hashes are upgraded to BcryptCost when their owner next signs in
+	BcryptCost int
 }
 
 func LoadConfig() *Config {
@@ -27,7 +59,47 @@ func LoadConfig() *Config {
 		Port:             port,
 		DatabaseURL:      os.Getenv("DATABASE_URL"),
 		RedisURL:         os.Getenv("REDIS_URL"),
-		JWTSecret:        os.Getenv("JWT_SECRET"),
+		JWTKeysDir:       os.Getenv("JWT_KEYS_DIR"),
+		JWTActiveKID:     os.Getenv("JWT_ACTIVE_KID"),
 		JWTRefreshSecret: os.Getenv("JWT_REFRESH_SECRET"),
+		RBACPolicyFile:   os.Getenv("RBAC_POLICY_FILE"),
+
+		GoogleClientID:       os.Getenv("GOOGLE_CLIENT_ID"),
+		GoogleClientSecret:   os.Getenv("GOOGLE_CLIENT_SECRET"),
+		GoogleRedirectURL:    os.Getenv("GOOGLE_REDIRECT_URL"),
+		FacebookClientID:     os.Getenv("FACEBOOK_CLIENT_ID"),
+		FacebookClientSecret: os.Getenv("FACEBOOK_CLIENT_SECRET"),
+		FacebookRedirectURL:  os.Getenv("FACEBOOK_REDIRECT_URL"),
+		OAuthMockEnabled:     os.Getenv("OAUTH_MOCK_ENABLED") == "true",
+		OAuthMockEmail:       getEnv("OAUTH_MOCK_EMAIL", "mock.user@example.com"),
+		OAuthMockSubject:     getEnv("OAUTH_MOCK_SUBJECT", "mock-user"),
+		OAuthMockRedirectURL: getEnv("OAUTH_MOCK_REDIRECT_URL", "http://localhost:"+port+"/api/auth/oauth/mock/callback"),
+
+		NotificationServiceURL: getEnv("NOTIFICATION_SERVICE_URL", "http://localhost:8092"),
+		OTPSecret:              os.Getenv("OTP_SECRET"),
+
+		DataExportDir:    getEnv("DATA_EXPORT_DIR", "data/exports"),
+		ErasureGraceDays: getEnvInt("ERASURE_GRACE_DAYS", 14),
+
+		PasswordMinLength:     getEnvInt("PASSWORD_MIN_LENGTH", 10),
+		PasswordMinClasses:    getEnvInt("PASSWORD_MIN_CLASSES", 3),
+		BreachedPasswordsFile: os.Getenv("BREACHED_PASSWORDS_FILE"),
+		BcryptCost:            getEnvInt("BCRYPT_COST", 12),
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
 	}
+	return fallback
 }
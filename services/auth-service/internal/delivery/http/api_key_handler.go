@@ -0,0 +1,118 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/rbac"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type APIKeyHandler struct {
+	apiKeyUseCase domain.APIKeyUseCase
+}
+
+func NewAPIKeyHandler(apiKeyUseCase domain.APIKeyUseCase) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUseCase: apiKeyUseCase,
+	}
+}
+
+func (h *APIKeyHandler) RegisterRoutes(router *gin.Engine, authMiddleware *auth.Middleware, authz *rbac.Authorizer) {
+	keys := router.Group("/api/auth/api-keys")
+	keys.Use(authMiddleware.Required(), authz.Require(rbac.APIKeyManage))
+	{
+		// Support staff impersonating a dealer must not walk away with a working key
+		keys.POST("", auth.BlockImpersonation(), h.Create)
+		keys.GET("", h.List)
+		keys.DELETE("/:id", h.Revoke)
+	}
+}
+
+// POST /api/auth/api-keys - the response is the only time the key is shown
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var req domain.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	req.UserID = userID
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	key, err := h.apiKeyUseCase.Create(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": key})
+}
+
+func (h *APIKeyHandler) List(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	keys, err := h.apiKeyUseCase.List(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": keys})
+}
+
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	keyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid API key ID")
+		return
+	}
+
+	err = h.apiKeyUseCase.Revoke(c.Request.Context(), &domain.RevokeAPIKeyRequest{
+		UserID:    userID,
+		KeyID:     keyID,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "API key revoked"})
+}
+
+func (h *APIKeyHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrAPIKeyNotFound), errors.Is(err, domain.ErrUserNotFound):
+		response.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidAPIKeyScope):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrAPIKeysDealerOnly):
+		response.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, domain.ErrAPIKeyLimitReached):
+		response.Error(c, http.StatusConflict, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,148 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
+	"github.com/aselahemantha/exoticsLanka/pkg/rbac"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AuditHandler struct {
+	auditUseCase domain.AuditUseCase
+}
+
+func NewAuditHandler(auditUseCase domain.AuditUseCase) *AuditHandler {
+	return &AuditHandler{
+		auditUseCase: auditUseCase,
+	}
+}
+
+func (h *AuditHandler) RegisterRoutes(router *gin.Engine, authMiddleware *auth.Middleware, authz *rbac.Authorizer) {
+	me := router.Group("/api/auth/me")
+	me.Use(authMiddleware.Required())
+	{
+		me.GET("/security-activity", h.SecurityActivity)
+	}
+
+	admin := router.Group("/api/admin/audit")
+	admin.Use(authMiddleware.Required(), authz.Require(rbac.AuditRead))
+	{
+		admin.GET("", h.List)
+	}
+}
+
+// GET /api/admin/audit?user_id=&event_type=&category=&success=&ip=&from=&to=&cursor=&limit=&format=csv
+func (h *AuditHandler) List(c *gin.Context) {
+	filter, err := auditFilterFromQuery(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		filename := fmt.Sprintf("audit-%s.csv", time.Now().UTC().Format("20060102-150405"))
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+		c.Status(http.StatusOK)
+
+		// Headers are already sent, so a failure can only cut the file short
+		if err := h.auditUseCase.ExportCSV(c.Request.Context(), filter, c.Writer); err != nil {
+			_ = c.Error(err)
+		}
+		return
+	}
+
+	logs, page, err := h.auditUseCase.List(c.Request.Context(), filter)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": logs, "pagination": page})
+}
+
+// GET /api/auth/me/security-activity - recent sign-ins and account changes of the current user
+func (h *AuditHandler) SecurityActivity(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	params, err := pagination.CursorFromQuery(c, 20)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	logs, page, err := h.auditUseCase.SecurityActivity(c.Request.Context(), userID, params)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": logs, "pagination": page})
+}
+
+func auditFilterFromQuery(c *gin.Context) (*domain.AuditFilter, error) {
+	params, err := pagination.CursorFromQuery(c, 50)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &domain.AuditFilter{
+		EventType: c.Query("event_type"),
+		IPAddress: c.Query("ip"),
+		Page:      params,
+	}
+
+	if s := c.Query("user_id"); s != "" {
+		userID, err := uuid.Parse(s)
+		if err != nil {
+			return nil, errors.New("invalid user_id")
+		}
+		filter.UserID = &userID
+	}
+	if s := c.Query("category"); s != "" {
+		filter.Categories = strings.Split(s, ",")
+	}
+	if s := c.Query("success"); s != "" {
+		success, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, errors.New("invalid success")
+		}
+		filter.Success = &success
+	}
+	if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+		return nil, err
+	}
+	if filter.To, err = parseTimeQuery(c, "to"); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// parseTimeQuery accepts RFC 3339 timestamps or plain dates (midnight UTC)
+func parseTimeQuery(c *gin.Context, key string) (*time.Time, error) {
+	s := c.Query(key)
+	if s == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			t = t.UTC()
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid %s: use RFC 3339 or YYYY-MM-DD", key)
+}
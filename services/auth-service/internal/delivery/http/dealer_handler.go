@@ -0,0 +1,177 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
+	"github.com/aselahemantha/exoticsLanka/pkg/rbac"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DealerHandler struct {
+	dealerUseCase domain.DealerUseCase
+}
+
+func NewDealerHandler(dealerUseCase domain.DealerUseCase) *DealerHandler {
+	return &DealerHandler{
+		dealerUseCase: dealerUseCase,
+	}
+}
+
+func (h *DealerHandler) RegisterRoutes(router *gin.Engine, authMiddleware *auth.Middleware, authz *rbac.Authorizer) {
+	applications := router.Group("/api/dealer-applications")
+	applications.Use(authMiddleware.Required())
+	{
+		applications.POST("", h.Apply)
+		applications.GET("/me", h.GetMyApplication)
+	}
+
+	// Admin review queue
+	admin := router.Group("/api/admin/dealer-applications")
+	admin.Use(authMiddleware.Required(), authz.Require(rbac.DealerVerify))
+	{
+		admin.GET("", h.ListApplications)
+		admin.GET("/:id", h.GetApplication)
+		admin.POST("/:id/approve", h.Approve)
+		admin.POST("/:id/reject", h.Reject)
+		admin.POST("/:id/revoke", h.Revoke)
+	}
+}
+
+func (h *DealerHandler) Apply(c *gin.Context) {
+	var req domain.DealerApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	req.UserID = userID
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	app, err := h.dealerUseCase.Apply(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": app})
+}
+
+func (h *DealerHandler) GetMyApplication(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	app, err := h.dealerUseCase.GetMyApplication(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": app})
+}
+
+// GET /api/admin/dealer-applications?status=pending
+func (h *DealerHandler) ListApplications(c *gin.Context) {
+	params := pagination.FromQuery(c, 20)
+
+	apps, meta, err := h.dealerUseCase.ListApplications(c.Request.Context(), c.Query("status"), params)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": apps, "pagination": meta})
+}
+
+func (h *DealerHandler) GetApplication(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid application ID")
+		return
+	}
+
+	app, err := h.dealerUseCase.GetApplication(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": app})
+}
+
+func (h *DealerHandler) Approve(c *gin.Context) {
+	h.review(c, h.dealerUseCase.Approve)
+}
+
+func (h *DealerHandler) Reject(c *gin.Context) {
+	h.review(c, h.dealerUseCase.Reject)
+}
+
+func (h *DealerHandler) Revoke(c *gin.Context) {
+	h.review(c, h.dealerUseCase.Revoke)
+}
+
+type reviewFunc func(ctx context.Context, req *domain.DealerReviewRequest) (*domain.DealerApplication, error)
+
+func (h *DealerHandler) review(c *gin.Context, fn reviewFunc) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid application ID")
+		return
+	}
+
+	var req domain.DealerReviewRequest
+	// The body is optional for approvals
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	reviewerID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	req.ApplicationID = id
+	req.ReviewerID = reviewerID
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	app, err := fn(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": app})
+}
+
+func (h *DealerHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrDealerApplicationNotFound):
+		response.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrDealerApplicationExists), errors.Is(err, domain.ErrDealerApplicationState):
+		response.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, domain.ErrInvalidDealerDocument), errors.Is(err, domain.ErrReviewReasonRequired):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -1,12 +1,14 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
 	"github.com/exoticsLanka/auth-service/internal/domain"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 type AuthHandler struct {
@@ -19,7 +21,10 @@ func NewAuthHandler(authUseCase domain.AuthUseCase) *AuthHandler {
 	}
 }
 
-func (h *AuthHandler) RegisterRoutes(router *gin.Engine, authMiddleware *AuthMiddleware) {
+func (h *AuthHandler) RegisterRoutes(router *gin.Engine, authMiddleware *auth.Middleware) {
+	// Support staff impersonating the user cannot change their credentials
+	blockImpersonation := auth.BlockImpersonation()
+
 	auth := router.Group("/api/auth")
 	{
 		// Public routes
@@ -32,11 +37,11 @@ func (h *AuthHandler) RegisterRoutes(router *gin.Engine, authMiddleware *AuthMid
 
 		// Protected routes
 		protected := auth.Group("/")
-		protected.Use(authMiddleware.Handle())
+		protected.Use(authMiddleware.Required())
 		{
 			protected.POST("/logout", h.Logout)
 			protected.GET("/me", h.Me)
-			protected.POST("/change-password", h.ChangePassword)
+			protected.POST("/change-password", blockImpersonation, h.ChangePassword)
 		}
 	}
 }
@@ -44,7 +49,7 @@ func (h *AuthHandler) RegisterRoutes(router *gin.Engine, authMiddleware *AuthMid
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req domain.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -53,7 +58,11 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 	resp, err := h.authUseCase.Register(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if errors.Is(err, domain.ErrRoleNotAllowed) || errors.Is(err, domain.ErrWeakPassword) {
+			response.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -63,7 +72,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req domain.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -73,10 +82,14 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	resp, err := h.authUseCase.Login(c.Request.Context(), &req)
 	if err != nil {
 		if strings.Contains(err.Error(), "invalid credentials") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+			response.Error(c, http.StatusUnauthorized, "Invalid email or password")
+			return
+		}
+		if errors.Is(err, domain.ErrAccountSuspended) || errors.Is(err, domain.ErrAccountDeleted) {
+			response.Error(c, http.StatusForbidden, err.Error())
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -84,14 +97,20 @@ func (h *AuthHandler) Login(c *gin.Context) {
 }
 
 func (h *AuthHandler) Logout(c *gin.Context) {
-	// We extract the token from the middleware (not directly available, so might need to parse header again or store in context)
-	// Ideally the middleware should set the token in context.
-	// For now, let's grab from header again.
-	authHeader := c.GetHeader("Authorization")
-	token := strings.TrimPrefix(authHeader, "Bearer ")
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
 
-	if err := h.authUseCase.Logout(c.Request.Context(), token); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	err = h.authUseCase.Logout(c.Request.Context(), &domain.LogoutRequest{
+		UserID:    userID,
+		Token:     auth.GetToken(c),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -101,13 +120,16 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	var req domain.RefreshTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	resp, err := h.authUseCase.RefreshToken(c.Request.Context(), req.RefreshToken)
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	resp, err := h.authUseCase.RefreshToken(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
@@ -115,22 +137,15 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 }
 
 func (h *AuthHandler) Me(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
-	}
-
-	// Assuming userID is of type uuid.UUID
-	id, ok := userID.(uuid.UUID)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID type"})
+	id, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	resp, err := h.authUseCase.GetMe(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -140,12 +155,12 @@ func (h *AuthHandler) Me(c *gin.Context) {
 func (h *AuthHandler) VerifyEmail(c *gin.Context) {
 	var req domain.VerifyEmailRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	if err := h.authUseCase.VerifyEmail(c.Request.Context(), req.Token); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -155,12 +170,12 @@ func (h *AuthHandler) VerifyEmail(c *gin.Context) {
 func (h *AuthHandler) ForgotPassword(c *gin.Context) {
 	var req domain.ForgotPasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	if err := h.authUseCase.ForgotPassword(c.Request.Context(), req.Email); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -170,12 +185,12 @@ func (h *AuthHandler) ForgotPassword(c *gin.Context) {
 func (h *AuthHandler) ResetPassword(c *gin.Context) {
 	var req domain.ResetPasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	if err := h.authUseCase.ResetPassword(c.Request.Context(), &req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -185,19 +200,21 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 func (h *AuthHandler) ChangePassword(c *gin.Context) {
 	var req domain.ChangePasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
-	req.UserID = userID.(uuid.UUID)
+	req.UserID = userID
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
 
 	if err := h.authUseCase.ChangePassword(c.Request.Context(), &req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
+	"github.com/exoticsLanka/auth-service/internal/keystore"
+	"github.com/gin-gonic/gin"
+)
+
+// KeysHandler publishes the public keys other services use to verify access tokens.
+type KeysHandler struct {
+	keys *keystore.KeyStore
+}
+
+func NewKeysHandler(keys *keystore.KeyStore) *KeysHandler {
+	return &KeysHandler{keys: keys}
+}
+
+func (h *KeysHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/.well-known/jwks.json", h.JWKS)
+}
+
+func (h *KeysHandler) JWKS(c *gin.Context) {
+	set, err := h.keys.JWKS()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Verifiers cache the set; keep this short so rotations propagate quickly
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, set)
+}
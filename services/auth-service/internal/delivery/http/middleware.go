@@ -1,110 +1,34 @@
 package http
 
 import (
-	"net/http"
-	"strings"
+	"context"
+	"errors"
 
-	"github.com/exoticsLanka/auth-service/internal/config"
+	"github.com/aselahemantha/exoticsLanka/pkg/audit"
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
 	"github.com/exoticsLanka/auth-service/internal/domain"
-	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
-	"github.com/google/uuid"
+	"github.com/exoticsLanka/auth-service/internal/keystore"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-type AuthMiddleware struct {
-	cfg         *config.Config
-	sessionRepo domain.SessionRepository
-}
-
-func NewAuthMiddleware(cfg *config.Config, sessionRepo domain.SessionRepository) *AuthMiddleware {
-	return &AuthMiddleware{
-		cfg:         cfg,
-		sessionRepo: sessionRepo,
-	}
-}
-
-func (m *AuthMiddleware) Handle() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			return
-		}
-
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		if tokenString == authHeader {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
-			return
-		}
-
-		// 1. Verify JWT Signature and Claims
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return []byte(m.cfg.JWTSecret), nil
-		})
-
-		if err != nil || !token.Valid {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			return
-		}
-
-		// 2. Check if session is active in Redis (Session Revocation Check)
-		session, err := m.sessionRepo.GetByToken(c.Request.Context(), tokenString)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Session validation failed"})
-			return
-		}
-		if session == nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session expired or revoked"})
-			return
-		}
-
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
-			return
-		}
-
-		userIDStr, ok := claims["sub"].(string)
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token user id"})
-			return
-		}
-
-		userID, err := uuid.Parse(userIDStr)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token user id format"})
-			return
-		}
-
-		c.Set("userID", userID)
-		c.Set("role", claims["role"])
-
-		c.Next()
-	}
-}
-
-// Optional: Role-based authorization middleware
-func (m *AuthMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		userRole, exists := c.Get("role")
-		if !exists {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-			return
-		}
-
-		roleStr, ok := userRole.(string)
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid role"})
-			return
-		}
-
-		for _, role := range roles {
-			if role == roleStr {
-				c.Next()
-				return
+// NewAuthMiddleware returns the shared JWT middleware verifying tokens against our
+// own signing keys. Unlike other services, auth-service also requires the token's
+// session to still exist in Redis, so logouts and revocations take effect immediately.
+// Like every service it also rejects suspended and deleted accounts and audits
+// impersonated requests to sink.
+func NewAuthMiddleware(keys *keystore.KeyStore, sessionRepo domain.SessionRepository, db *pgxpool.Pool, sink audit.Sink) *auth.Middleware {
+	return auth.NewMiddleware(keys.Keyfunc,
+		auth.WithTokenCheck(func(ctx context.Context, token string, _ *auth.Claims) error {
+			session, err := sessionRepo.GetByToken(ctx, token)
+			if err != nil {
+				return errors.New("Session validation failed")
 			}
-		}
-
-		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
-	}
+			if session == nil {
+				return errors.New("Session expired or revoked")
+			}
+			return nil
+		}),
+		auth.WithTokenCheck(auth.AccountStatusCheck(db)),
+		auth.WithImpersonationAudit(sink, "auth-service"),
+	)
 }
@@ -0,0 +1,192 @@
+package http
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// oauthStateCookie binds an authorization request to the browser that started
+// it, so a callback carrying someone else's state is refused. It is Lax
+// rather than Strict because the provider redirects back cross-site.
+const oauthStateCookie = "oauth_state"
+
+type OAuthHandler struct {
+	oauthUseCase domain.OAuthUseCase
+}
+
+func NewOAuthHandler(oauthUseCase domain.OAuthUseCase) *OAuthHandler {
+	return &OAuthHandler{
+		oauthUseCase: oauthUseCase,
+	}
+}
+
+func (h *OAuthHandler) RegisterRoutes(router *gin.Engine, authMiddleware *auth.Middleware) {
+	oauth := router.Group("/api/auth/oauth")
+	{
+		// Public routes
+		oauth.GET("/providers", h.Providers)
+		oauth.GET("/:provider/authorize", h.Authorize)
+		oauth.GET("/:provider/callback", h.Callback)
+
+		// Protected routes
+		protected := oauth.Group("/")
+		protected.Use(authMiddleware.Required())
+		{
+			protected.GET("/identities", h.ListIdentities)
+			protected.POST("/:provider/link", auth.BlockImpersonation(), h.Link)
+			protected.DELETE("/:provider", h.Unlink)
+		}
+	}
+}
+
+func (h *OAuthHandler) Providers(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"providers": h.oauthUseCase.Providers()})
+}
+
+// GET /api/auth/oauth/:provider/authorize - returns the provider's consent page URL
+func (h *OAuthHandler) Authorize(c *gin.Context) {
+	resp, err := h.oauthUseCase.Authorize(c.Request.Context(), &domain.OAuthAuthorizeRequest{
+		Provider: c.Param("provider"),
+		Mode:     domain.OAuthModeLogin,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	setStateCookie(c, resp.State, int(domain.OAuthStateTTL.Seconds()))
+	c.JSON(http.StatusOK, resp)
+}
+
+// POST /api/auth/oauth/:provider/link - starts the flow to link a provider to the current user
+func (h *OAuthHandler) Link(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	resp, err := h.oauthUseCase.Authorize(c.Request.Context(), &domain.OAuthAuthorizeRequest{
+		Provider: c.Param("provider"),
+		Mode:     domain.OAuthModeLink,
+		UserID:   &userID,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	setStateCookie(c, resp.State, int(domain.OAuthStateTTL.Seconds()))
+	c.JSON(http.StatusOK, resp)
+}
+
+// GET /api/auth/oauth/:provider/callback?code=...&state=...
+func (h *OAuthHandler) Callback(c *gin.Context) {
+	var req domain.OAuthCallbackRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		// Providers report a denied consent as ?error=access_denied
+		if providerErr := c.Query("error"); providerErr != "" {
+			response.Error(c, http.StatusUnauthorized, providerErr)
+			return
+		}
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// The state must come back to the browser that asked for it
+	cookie, err := c.Cookie(oauthStateCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie), []byte(req.State)) != 1 {
+		h.handleError(c, domain.ErrInvalidOAuthState)
+		return
+	}
+	setStateCookie(c, "", -1)
+
+	req.Provider = c.Param("provider")
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	resp, err := h.oauthUseCase.Callback(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	if resp.Mode == domain.OAuthModeLink {
+		c.JSON(http.StatusOK, gin.H{"message": "Provider linked successfully", "identity": resp.Identity})
+		return
+	}
+	c.JSON(http.StatusOK, resp.Login)
+}
+
+func (h *OAuthHandler) ListIdentities(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	identities, err := h.oauthUseCase.ListIdentities(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"identities": identities})
+}
+
+func (h *OAuthHandler) Unlink(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	err = h.oauthUseCase.Unlink(c.Request.Context(), &domain.OAuthUnlinkRequest{
+		UserID:    userID,
+		Provider:  c.Param("provider"),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Provider unlinked successfully"})
+}
+
+// setStateCookie sets the state cookie for maxAge seconds, or clears it if
+// maxAge is negative
+func setStateCookie(c *gin.Context, state string, maxAge int) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    state,
+		Path:     "/api/auth/oauth",
+		MaxAge:   maxAge,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (h *OAuthHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUnknownOAuthProvider), errors.Is(err, domain.ErrIdentityNotLinked):
+		response.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidOAuthState), errors.Is(err, domain.ErrOAuthEmailNotVerified):
+		response.Error(c, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, domain.ErrAccountSuspended), errors.Is(err, domain.ErrAccountDeleted):
+		response.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, domain.ErrIdentityLinkedElsewhere), errors.Is(err, domain.ErrLastLoginMethod),
+		errors.Is(err, domain.ErrOAuthPasswordLoginRequired):
+		response.Error(c, http.StatusConflict, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,268 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type OrganizationHandler struct {
+	orgUseCase domain.OrganizationUseCase
+}
+
+func NewOrganizationHandler(orgUseCase domain.OrganizationUseCase) *OrganizationHandler {
+	return &OrganizationHandler{
+		orgUseCase: orgUseCase,
+	}
+}
+
+func (h *OrganizationHandler) RegisterRoutes(router *gin.Engine, authMiddleware *auth.Middleware) {
+	orgs := router.Group("/api/organizations")
+	orgs.Use(authMiddleware.Required())
+	{
+		orgs.POST("", h.Create)
+		// Support staff impersonating a user can neither grant nor take up team access
+		orgs.POST("/invitations/accept", auth.BlockImpersonation(), h.AcceptInvitation)
+
+		orgs.GET("/me", h.GetMine)
+		orgs.PUT("/me", h.Rename)
+		orgs.POST("/me/invitations", auth.BlockImpersonation(), h.Invite)
+		orgs.GET("/me/invitations", h.ListInvitations)
+		orgs.DELETE("/me/invitations/:id", h.RevokeInvitation)
+		orgs.PUT("/me/members/:userId/role", h.ChangeMemberRole)
+		orgs.DELETE("/me/members/:userId", h.RemoveMember)
+	}
+}
+
+// POST /api/organizations - turns the dealer's account into a team they own
+func (h *OrganizationHandler) Create(c *gin.Context) {
+	var req domain.CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	req.UserID = userID
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	org, err := h.orgUseCase.Create(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": org})
+}
+
+func (h *OrganizationHandler) GetMine(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	org, err := h.orgUseCase.GetMine(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": org})
+}
+
+func (h *OrganizationHandler) Rename(c *gin.Context) {
+	var req domain.RenameOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	req.UserID = userID
+
+	org, err := h.orgUseCase.Rename(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": org})
+}
+
+// POST /api/organizations/me/invitations - the response is the only time the token is shown
+func (h *OrganizationHandler) Invite(c *gin.Context) {
+	var req domain.InviteMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	req.UserID = userID
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	inv, err := h.orgUseCase.Invite(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": inv})
+}
+
+func (h *OrganizationHandler) ListInvitations(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	invitations, err := h.orgUseCase.ListInvitations(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": invitations})
+}
+
+func (h *OrganizationHandler) RevokeInvitation(c *gin.Context) {
+	req, ok := organizationAction(c, "id", "Invalid invitation ID")
+	if !ok {
+		return
+	}
+
+	if err := h.orgUseCase.RevokeInvitation(c.Request.Context(), req); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Invitation revoked"})
+}
+
+// POST /api/organizations/invitations/accept - {"token": "..."}, signed in as the invited email
+func (h *OrganizationHandler) AcceptInvitation(c *gin.Context) {
+	var req domain.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	req.UserID = userID
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	org, err := h.orgUseCase.AcceptInvitation(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": org})
+}
+
+// PUT /api/organizations/me/members/:userId/role - {"role": "manager"}
+func (h *OrganizationHandler) ChangeMemberRole(c *gin.Context) {
+	var req domain.ChangeMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	action, ok := organizationAction(c, "userId", "Invalid user ID")
+	if !ok {
+		return
+	}
+	req.UserID = action.UserID
+	req.MemberID = action.TargetID
+	req.IPAddress = action.IPAddress
+	req.UserAgent = action.UserAgent
+
+	if err := h.orgUseCase.ChangeMemberRole(c.Request.Context(), &req); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Member role updated"})
+}
+
+// DELETE /api/organizations/me/members/:userId - use your own ID to leave the team
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	req, ok := organizationAction(c, "userId", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	if err := h.orgUseCase.RemoveMember(c.Request.Context(), req); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Member removed"})
+}
+
+// organizationAction identifies the signed-in member and the ID in param; it
+// writes the error response and returns false if either is missing.
+func organizationAction(c *gin.Context, param, invalidMsg string) (*domain.OrganizationActionRequest, bool) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return nil, false
+	}
+
+	targetID, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, invalidMsg)
+		return nil, false
+	}
+
+	return &domain.OrganizationActionRequest{
+		UserID:    userID,
+		TargetID:  targetID,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}, true
+}
+
+func (h *OrganizationHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrOrganizationNotFound), errors.Is(err, domain.ErrMemberNotFound),
+		errors.Is(err, domain.ErrInvitationNotFound), errors.Is(err, domain.ErrUserNotFound):
+		response.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidMemberRole), errors.Is(err, domain.ErrInvitationInvalid):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrOrganizationDealerOnly), errors.Is(err, domain.ErrOrganizationForbidden),
+		errors.Is(err, domain.ErrInvitationEmailMismatch):
+		response.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, domain.ErrAlreadyInOrganization), errors.Is(err, domain.ErrInvitationExists),
+		errors.Is(err, domain.ErrOwnerRoleFixed):
+		response.Error(c, http.StatusConflict, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
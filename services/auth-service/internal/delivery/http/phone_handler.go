@@ -0,0 +1,171 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type PhoneHandler struct {
+	phoneUseCase domain.PhoneUseCase
+}
+
+func NewPhoneHandler(phoneUseCase domain.PhoneUseCase) *PhoneHandler {
+	return &PhoneHandler{
+		phoneUseCase: phoneUseCase,
+	}
+}
+
+func (h *PhoneHandler) RegisterRoutes(router *gin.Engine, authMiddleware *auth.Middleware) {
+	phone := router.Group("/api/auth/phone")
+	{
+		// Public routes
+		phone.POST("/register", h.Register)
+		phone.POST("/login", h.RequestLoginCode)
+		phone.POST("/verify", h.VerifyLogin)
+	}
+
+	// Attach a phone number to the signed-in account
+	me := router.Group("/api/auth/me/phone")
+	me.Use(authMiddleware.Required(), auth.BlockImpersonation())
+	{
+		me.POST("", h.StartVerification)
+		me.POST("/verify", h.ConfirmVerification)
+	}
+}
+
+// POST /api/auth/phone/register - creates a pending account and sends a code to the number
+func (h *PhoneHandler) Register(c *gin.Context) {
+	var req domain.PhoneRegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	resp, err := h.phoneUseCase.Register(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// POST /api/auth/phone/login - sends a login code to a verified number
+func (h *PhoneHandler) RequestLoginCode(c *gin.Context) {
+	var req domain.PhoneLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	resp, err := h.phoneUseCase.RequestLoginCode(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// POST /api/auth/phone/verify - exchanges a login code for tokens
+func (h *PhoneHandler) VerifyLogin(c *gin.Context) {
+	var req domain.PhoneVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	resp, err := h.phoneUseCase.VerifyLogin(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// POST /api/auth/me/phone - sends a code to the number being added
+func (h *PhoneHandler) StartVerification(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req domain.PhoneLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	req.UserID = userID
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	resp, err := h.phoneUseCase.StartVerification(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// POST /api/auth/me/phone/verify - confirms the code and marks the number verified
+func (h *PhoneHandler) ConfirmVerification(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req domain.PhoneVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	req.UserID = userID
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	user, err := h.phoneUseCase.ConfirmVerification(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Tokens issued before this point still carry phone_verified=false until refreshed
+	c.JSON(http.StatusOK, gin.H{"message": "Phone number verified", "user": user})
+}
+
+func (h *PhoneHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidPhoneNumber), errors.Is(err, domain.ErrRoleNotAllowed):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrInvalidOTP), errors.Is(err, domain.ErrOTPAttemptsExceeded):
+		response.Error(c, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, domain.ErrPhoneTaken):
+		response.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, domain.ErrAccountSuspended), errors.Is(err, domain.ErrAccountDeleted):
+		response.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, domain.ErrOTPCooldown), errors.Is(err, domain.ErrOTPSendLimit):
+		response.Error(c, http.StatusTooManyRequests, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
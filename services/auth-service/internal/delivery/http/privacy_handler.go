@@ -0,0 +1,178 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PrivacyHandler struct {
+	privacyUseCase domain.PrivacyUseCase
+}
+
+func NewPrivacyHandler(privacyUseCase domain.PrivacyUseCase) *PrivacyHandler {
+	return &PrivacyHandler{
+		privacyUseCase: privacyUseCase,
+	}
+}
+
+func (h *PrivacyHandler) RegisterRoutes(router *gin.Engine, authMiddleware *auth.Middleware) {
+	// Support staff impersonating the user can neither take their data nor close their account
+	me := router.Group("/api/auth/me")
+	me.Use(authMiddleware.Required(), auth.BlockImpersonation())
+	{
+		me.POST("/data-export", h.RequestExport)
+		me.GET("/data-export", h.ListExports)
+		me.GET("/data-export/:id/download", h.Download)
+
+		me.POST("/erasure", h.RequestErasure)
+		me.GET("/erasure", h.GetErasure)
+		me.DELETE("/erasure", h.CancelErasure)
+	}
+}
+
+// POST /api/auth/me/data-export - queues a ZIP of everything stored about the user
+func (h *PrivacyHandler) RequestExport(c *gin.Context) {
+	req, ok := privacyRequest(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.privacyUseCase.RequestExport(c.Request.Context(), req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"success": true, "data": export})
+}
+
+func (h *PrivacyHandler) ListExports(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	exports, err := h.privacyUseCase.ListExports(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": exports})
+}
+
+// GET /api/auth/me/data-export/:id/download - the ZIP, while it has not expired
+func (h *PrivacyHandler) Download(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	exportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid export ID")
+		return
+	}
+
+	export, err := h.privacyUseCase.GetDownload(c.Request.Context(), userID, exportID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.FileAttachment(*export.FilePath, "exoticslanka-data-"+export.CreatedAt.Format("2006-01-02")+".zip")
+}
+
+// POST /api/auth/me/erasure - {"password": "..."}; the account is erased once the grace period ends
+func (h *PrivacyHandler) RequestErasure(c *gin.Context) {
+	req, ok := privacyRequest(c)
+	if !ok {
+		return
+	}
+
+	// The body may be omitted by accounts without a password
+	var body domain.ErasureRequest
+	if err := c.ShouldBindJSON(&body); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	body.PrivacyRequest = *req
+
+	status, err := h.privacyUseCase.RequestErasure(c.Request.Context(), &body)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"success": true, "data": status})
+}
+
+func (h *PrivacyHandler) GetErasure(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	status, err := h.privacyUseCase.GetErasure(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": status})
+}
+
+// DELETE /api/auth/me/erasure - cancels a scheduled erasure during the grace period
+func (h *PrivacyHandler) CancelErasure(c *gin.Context) {
+	req, ok := privacyRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.privacyUseCase.CancelErasure(c.Request.Context(), req); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Account erasure cancelled"})
+}
+
+// privacyRequest identifies the signed-in user; it writes the error response
+// and returns false if there is none.
+func privacyRequest(c *gin.Context) (*domain.PrivacyRequest, bool) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return nil, false
+	}
+
+	return &domain.PrivacyRequest{
+		UserID:    userID,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}, true
+}
+
+func (h *PrivacyHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrDataExportNotFound), errors.Is(err, domain.ErrUserNotFound):
+		response.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrPasswordConfirmation):
+		response.Error(c, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, domain.ErrDataExportInProgress), errors.Is(err, domain.ErrDataExportNotReady),
+		errors.Is(err, domain.ErrErasureAlreadyScheduled), errors.Is(err, domain.ErrErasureNotScheduled):
+		response.Error(c, http.StatusConflict, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,229 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
+	"github.com/aselahemantha/exoticsLanka/pkg/rbac"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type UserAdminHandler struct {
+	userAdminUseCase domain.UserAdminUseCase
+	authz            *rbac.Authorizer
+}
+
+func NewUserAdminHandler(userAdminUseCase domain.UserAdminUseCase, authz *rbac.Authorizer) *UserAdminHandler {
+	return &UserAdminHandler{
+		userAdminUseCase: userAdminUseCase,
+		authz:            authz,
+	}
+}
+
+func (h *UserAdminHandler) RegisterRoutes(router *gin.Engine, authMiddleware *auth.Middleware) {
+	admin := router.Group("/api/admin/users")
+	admin.Use(authMiddleware.Required(), h.authz.Require(rbac.UserManage))
+	{
+		admin.GET("", h.List)
+		admin.GET("/:id", h.Get)
+		admin.PUT("/:id/role", h.ChangeRole)
+		admin.POST("/:id/suspend", h.Suspend)
+		admin.POST("/:id/reactivate", h.Reactivate)
+		admin.POST("/:id/logout", h.ForceLogout)
+		admin.DELETE("/:id", h.Delete)
+		admin.POST("/:id/impersonate", h.authz.Require(rbac.UserImpersonate), h.Impersonate)
+	}
+}
+
+// GET /api/admin/users?q=&role=&status=&page=&limit=
+func (h *UserAdminHandler) List(c *gin.Context) {
+	params := pagination.FromQuery(c, 20)
+	filter := &domain.UserFilter{
+		Query:  c.Query("q"),
+		Role:   c.Query("role"),
+		Status: c.Query("status"),
+	}
+
+	users, meta, err := h.userAdminUseCase.List(c.Request.Context(), filter, params)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": users, "pagination": meta})
+}
+
+// GET /api/admin/users/:id - the account with its linked providers and recent audit events
+func (h *UserAdminHandler) Get(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	details, err := h.userAdminUseCase.Get(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": details})
+}
+
+func (h *UserAdminHandler) ChangeRole(c *gin.Context) {
+	action, ok := h.actionRequest(c)
+	if !ok {
+		return
+	}
+
+	var req domain.ChangeRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.UserAdminActionRequest = *action
+
+	user, err := h.userAdminUseCase.ChangeRole(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": user})
+}
+
+// POST /api/admin/users/:id/suspend - {"reason": "...", "until": "2025-07-01T00:00:00Z"}
+func (h *UserAdminHandler) Suspend(c *gin.Context) {
+	action, ok := h.actionRequest(c)
+	if !ok {
+		return
+	}
+
+	var req domain.SuspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.UserAdminActionRequest = *action
+
+	user, err := h.userAdminUseCase.Suspend(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": user})
+}
+
+func (h *UserAdminHandler) Reactivate(c *gin.Context) {
+	action, ok := h.actionRequest(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userAdminUseCase.Reactivate(c.Request.Context(), action)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": user})
+}
+
+// POST /api/admin/users/:id/logout - ends all of the user's sessions
+func (h *UserAdminHandler) ForceLogout(c *gin.Context) {
+	action, ok := h.actionRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.userAdminUseCase.ForceLogout(c.Request.Context(), action); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "User signed out of all sessions"})
+}
+
+func (h *UserAdminHandler) Delete(c *gin.Context) {
+	action, ok := h.actionRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.userAdminUseCase.Delete(c.Request.Context(), action); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "User deleted"})
+}
+
+// POST /api/admin/users/:id/impersonate - {"reason": "Ticket #1234"}; a 15 minute token acting as the user
+func (h *UserAdminHandler) Impersonate(c *gin.Context) {
+	action, ok := h.actionRequest(c)
+	if !ok {
+		return
+	}
+
+	var req domain.ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.UserAdminActionRequest = *action
+
+	resp, err := h.userAdminUseCase.Impersonate(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": resp})
+}
+
+// actionRequest reads the target user and the acting admin; it writes the
+// error response and returns false if either is missing.
+func (h *UserAdminHandler) actionRequest(c *gin.Context) (*domain.UserAdminActionRequest, bool) {
+	actorID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return nil, false
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID")
+		return nil, false
+	}
+
+	return &domain.UserAdminActionRequest{
+		UserID:          userID,
+		ActorID:         actorID,
+		CanManageAdmins: h.authz.Can(c, rbac.UserManageAdmins),
+		IPAddress:       c.ClientIP(),
+		UserAgent:       c.Request.UserAgent(),
+	}, true
+}
+
+func (h *UserAdminHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		response.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrCannotModifySelf), errors.Is(err, domain.ErrAdminAccountProtected),
+		errors.Is(err, domain.ErrImpersonationNotAllowed):
+		response.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, domain.ErrUserNotSuspended), errors.Is(err, domain.ErrAccountSuspended):
+		response.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, domain.ErrInvalidRole), errors.Is(err, domain.ErrReasonRequired), errors.Is(err, domain.ErrInvalidSuspensionEnd),
+		errors.Is(err, domain.ErrDealerRoleNotAssignable), errors.Is(err, domain.ErrDealerRoleNotRemovable):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrAPIKeysDealerOnly is returned when an account that is not a dealer manages API keys
+	ErrAPIKeysDealerOnly = errors.New("API keys are only available to dealer accounts")
+	// ErrInvalidAPIKeyScope is returned when a key is requested with an unknown scope
+	ErrInvalidAPIKeyScope = errors.New("invalid API key scope")
+	// ErrAPIKeyLimitReached is returned when the dealer already has the maximum number of active keys
+	ErrAPIKeyLimitReached = errors.New("maximum number of active API keys reached; revoke one first")
+	// ErrAPIKeyNotFound is returned when a key does not exist or belongs to someone else
+	ErrAPIKeyNotFound = errors.New("API key not found")
+)
+
+// APIKey is a dealer's credential for calling the API from their own systems.
+// The key itself is only ever returned once, when it is created.
+type APIKey struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	Name       string     `json:"name" db:"name"`
+	Prefix     string     `json:"prefix" db:"prefix"`
+	KeyHash    string     `json:"-" db:"key_hash"`
+	Scopes     []string   `json:"scopes" db:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// APIKeyRepository defines methods for API key persistence
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *APIKey) error
+	GetByID(ctx context.Context, id uuid.UUID) (*APIKey, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]APIKey, error)
+	// CountActive counts keys that are neither revoked nor expired
+	CountActive(ctx context.Context, userID uuid.UUID, now time.Time) (int, error)
+	Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) error
+}
+
+// APIKeyUseCase defines the business logic for dealer API keys
+type APIKeyUseCase interface {
+	Create(ctx context.Context, req *CreateAPIKeyRequest) (*CreatedAPIKey, error)
+	List(ctx context.Context, userID uuid.UUID) ([]APIKey, error)
+	Revoke(ctx context.Context, req *RevokeAPIKeyRequest) error
+}
+
+type CreateAPIKeyRequest struct {
+	UserID        uuid.UUID `json:"-"`
+	Name          string    `json:"name" binding:"required,max=100"`
+	Scopes        []string  `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays *int      `json:"expires_in_days" binding:"omitempty,min=1,max=730"`
+	IPAddress     string    `json:"-"`
+	UserAgent     string    `json:"-"`
+}
+
+type RevokeAPIKeyRequest struct {
+	UserID    uuid.UUID
+	KeyID     uuid.UUID
+	IPAddress string
+	UserAgent string
+}
+
+// CreatedAPIKey carries the plain key, which cannot be retrieved again
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+// Audit event types recorded by auth-service. Categories are the shared
+// audit.Category* constants.
+const (
+	EventAccountCreated       = "account_created"
+	EventLoginSuccess         = "login_success"
+	EventLoginFailed          = "login_failed"
+	EventLogout               = "logout"
+	EventTokenRefreshed       = "token_refreshed"
+	EventRefreshTokenReuse    = "refresh_token_reuse"
+	EventPasswordChanged      = "password_changed"
+	EventPasswordChangeFail   = "password_change_failed"
+	EventRoleChanged          = "role_changed"
+	EventUserSuspended        = "user_suspended"
+	EventUserReactivated      = "user_reactivated"
+	EventSessionsRevoked      = "sessions_revoked"
+	EventAccountDeleted       = "account_deleted"
+	EventImpersonationStarted = "impersonation_started"
+	EventPhoneOTPFailed       = "phone_otp_failed"
+	EventPhoneVerified        = "phone_verified"
+	EventOAuthLoginSuccess    = "oauth_login_success"
+	EventOAuthLoginFailed     = "oauth_login_failed"
+	EventOAuthLinked          = "oauth_identity_linked"
+	EventOAuthUnlinked        = "oauth_identity_unlinked"
+	EventDealerApplied        = "dealer_application_submitted"
+	EventDealerApproved       = "dealer_application_approved"
+	EventDealerRejected       = "dealer_application_rejected"
+	EventDealerRevoked        = "dealer_verification_revoked"
+	EventDataExportRequested  = "data_export_requested"
+	EventErasureScheduled     = "account_erasure_scheduled"
+	EventErasureCancelled     = "account_erasure_cancelled"
+	EventAccountErased        = "account_erased"
+	EventAPIKeyCreated        = "api_key_created"
+	EventAPIKeyRevoked        = "api_key_revoked"
+	EventOrganizationCreated  = "organization_created"
+	EventMemberInvited        = "organization_member_invited"
+	EventMemberJoined         = "organization_member_joined"
+	EventMemberRoleChanged    = "organization_member_role_changed"
+	EventMemberRemoved        = "organization_member_removed"
+)
+
+// AuditFilter narrows an audit log query. Zero values match everything.
+type AuditFilter struct {
+	UserID     *uuid.UUID
+	EventType  string
+	Categories []string
+	Success    *bool
+	IPAddress  string
+	From       *time.Time
+	To         *time.Time
+	Page       pagination.CursorParams
+}
+
+// AuditUseCase defines the business logic for reading the audit log
+type AuditUseCase interface {
+	List(ctx context.Context, filter *AuditFilter) ([]AuditLog, pagination.CursorPage, error)
+	// ExportCSV writes every entry matching filter, newest first, up to a fixed cap.
+	ExportCSV(ctx context.Context, filter *AuditFilter, w io.Writer) error
+	// SecurityActivity returns the user's own authentication, security and account events.
+	SecurityActivity(ctx context.Context, userID uuid.UUID, page pagination.CursorParams) ([]AuditLog, pagination.CursorPage, error)
+}
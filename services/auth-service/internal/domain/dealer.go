@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+// Dealer application statuses
+const (
+	DealerApplicationPending  = "pending"
+	DealerApplicationApproved = "approved"
+	DealerApplicationRejected = "rejected"
+	DealerApplicationRevoked  = "revoked"
+)
+
+var (
+	// ErrRoleNotAllowed is returned when registering with a role that must be granted instead
+	ErrRoleNotAllowed = errors.New("role cannot be chosen at registration; dealers must apply for verification")
+	// ErrDealerApplicationNotFound is returned when an application does not exist
+	ErrDealerApplicationNotFound = errors.New("dealer application not found")
+	// ErrDealerApplicationExists is returned when the user already has a pending or approved application
+	ErrDealerApplicationExists = errors.New("dealer application already pending or approved")
+	// ErrDealerApplicationState is returned when a review action does not apply to the current status
+	ErrDealerApplicationState = errors.New("dealer application is not in a valid state for this action")
+	// ErrInvalidDealerDocument is returned when a document was not uploaded by the applicant through image-service
+	ErrInvalidDealerDocument = errors.New("invalid verification document")
+	// ErrReviewReasonRequired is returned when rejecting or revoking without a reason
+	ErrReviewReasonRequired = errors.New("a reason is required")
+)
+
+// DealerDocument is a business verification document uploaded through image-service
+type DealerDocument struct {
+	Type string `json:"type" binding:"required"` // business_registration, tax_certificate, id_document, ...
+	Key  string `json:"key" binding:"required"`
+	URL  string `json:"url" binding:"required,url"`
+}
+
+// DealerApplication is a user's request to be verified as a dealer
+type DealerApplication struct {
+	ID                 uuid.UUID        `json:"id" db:"id"`
+	UserID             uuid.UUID        `json:"user_id" db:"user_id"`
+	BusinessName       string           `json:"business_name" db:"business_name"`
+	RegistrationNumber string           `json:"registration_number" db:"registration_number"`
+	BusinessAddress    string           `json:"business_address" db:"business_address"`
+	ContactPhone       string           `json:"contact_phone" db:"contact_phone"`
+	Website            *string          `json:"website,omitempty" db:"website"`
+	Documents          []DealerDocument `json:"documents" db:"documents"`
+	Status             string           `json:"status" db:"status"` // pending, approved, rejected, revoked
+	ReviewedBy         *uuid.UUID       `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewReason       *string          `json:"review_reason,omitempty" db:"review_reason"`
+	ReviewedAt         *time.Time       `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	CreatedAt          time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// DealerApplicationRepository defines methods for dealer application persistence
+type DealerApplicationRepository interface {
+	Create(ctx context.Context, app *DealerApplication) error
+	GetByID(ctx context.Context, id uuid.UUID) (*DealerApplication, error)
+	GetLatestByUserID(ctx context.Context, userID uuid.UUID) (*DealerApplication, error)
+	List(ctx context.Context, status string, params pagination.Params) ([]DealerApplication, int64, error)
+	// UpdateReview saves the review outcome and, in the same transaction, sets the
+	// applicant's role and the verified flag on all of their listings. It returns
+	// ErrDealerApplicationState if the application is no longer in status from.
+	UpdateReview(ctx context.Context, app *DealerApplication, from, role string, listingsVerified bool) error
+}
+
+// DealerUseCase defines the business logic for dealer onboarding
+type DealerUseCase interface {
+	Apply(ctx context.Context, req *DealerApplicationRequest) (*DealerApplication, error)
+	GetMyApplication(ctx context.Context, userID uuid.UUID) (*DealerApplication, error)
+	ListApplications(ctx context.Context, status string, params pagination.Params) ([]DealerApplication, pagination.Pagination, error)
+	GetApplication(ctx context.Context, id uuid.UUID) (*DealerApplication, error)
+	Approve(ctx context.Context, req *DealerReviewRequest) (*DealerApplication, error)
+	Reject(ctx context.Context, req *DealerReviewRequest) (*DealerApplication, error)
+	Revoke(ctx context.Context, req *DealerReviewRequest) (*DealerApplication, error)
+}
+
+type DealerApplicationRequest struct {
+	UserID             uuid.UUID        `json:"-"`
+	BusinessName       string           `json:"business_name" binding:"required,max=255"`
+	RegistrationNumber string           `json:"registration_number" binding:"required,max=100"`
+	BusinessAddress    string           `json:"business_address" binding:"required"`
+	ContactPhone       string           `json:"contact_phone" binding:"required,max=20"`
+	Website            *string          `json:"website" binding:"omitempty,url"`
+	Documents          []DealerDocument `json:"documents" binding:"required,min=1,dive"`
+	IPAddress          string           `json:"-"`
+	UserAgent          string           `json:"-"`
+}
+
+type DealerReviewRequest struct {
+	ApplicationID uuid.UUID `json:"-"`
+	ReviewerID    uuid.UUID `json:"-"`
+	Reason        string    `json:"reason"`
+	IPAddress     string    `json:"-"`
+	UserAgent     string    `json:"-"`
+}
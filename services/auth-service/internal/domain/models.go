@@ -2,24 +2,33 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
 	"github.com/google/uuid"
 )
 
 // User represents the user model in the database
 type User struct {
 	ID                  uuid.UUID  `json:"id" db:"id"`
-	Email               string     `json:"email" db:"email"`
+	Email               string     `json:"email" db:"email"` // empty for accounts registered by phone
 	PasswordHash        string     `json:"-" db:"password_hash"`
 	Status              string     `json:"status" db:"status"` // pending, active, suspended, deleted
 	EmailVerified       bool       `json:"email_verified" db:"email_verified"`
 	EmailVerifiedAt     *time.Time `json:"email_verified_at,omitempty" db:"email_verified_at"`
 	Role                string     `json:"role" db:"role"` // buyer, seller, dealer, admin, super_admin
+	PhoneNumber         *string    `json:"phone_number,omitempty" db:"phone_number"`
+	PhoneVerified       bool       `json:"phone_verified" db:"phone_verified"`
+	PhoneVerifiedAt     *time.Time `json:"phone_verified_at,omitempty" db:"phone_verified_at"`
 	TwoFactorEnabled    bool       `json:"two_factor_enabled" db:"two_factor_enabled"`
 	TwoFactorSecret     *string    `json:"-" db:"two_factor_secret"`
 	FailedLoginAttempts int        `json:"-" db:"failed_login_attempts"`
 	LockedUntil         *time.Time `json:"locked_until,omitempty" db:"locked_until"`
+	SuspendedReason     *string    `json:"suspended_reason,omitempty" db:"suspended_reason"`
+	SuspendedUntil      *time.Time `json:"suspended_until,omitempty" db:"suspended_until"` // nil while suspended means indefinitely
+	SuspendedAt         *time.Time `json:"suspended_at,omitempty" db:"suspended_at"`
+	SuspendedBy         *uuid.UUID `json:"suspended_by,omitempty" db:"suspended_by"`
 	OAuthProvider       *string    `json:"oauth_provider,omitempty" db:"oauth_provider"`
 	OAuthID             *string    `json:"oauth_id,omitempty" db:"oauth_id"`
 	CreatedAt           time.Time  `json:"created_at" db:"created_at"`
@@ -74,12 +83,36 @@ type AuditLog struct {
 	CreatedAt     time.Time              `json:"created_at" db:"created_at"`
 }
 
+// RefreshTokenFamily tracks the chain of refresh tokens issued from a single login.
+// Only the most recently issued token (CurrentJTI) may be exchanged; presenting an
+// older one means the token was copied and the whole family is revoked.
+type RefreshTokenFamily struct {
+	ID         string    `json:"id"`
+	UserID     uuid.UUID `json:"user_id"`
+	CurrentJTI string    `json:"current_jti"`
+	Revoked    bool      `json:"revoked"`
+	CreatedAt  time.Time `json:"created_at"`
+	RotatedAt  time.Time `json:"rotated_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+var (
+	// ErrRefreshTokenReused is returned when a refresh token that was already rotated is presented again
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+	// ErrRefreshTokenRevoked is returned when the token family no longer exists or was revoked
+	ErrRefreshTokenRevoked = errors.New("refresh token revoked")
+)
+
 // UserRepository defines methods for user persistence
 type UserRepository interface {
 	Create(ctx context.Context, user *User) error
 	GetByID(ctx context.Context, id uuid.UUID) (*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
+	// GetByPhone returns the user holding phone, preferring the one who verified it
+	GetByPhone(ctx context.Context, phone string) (*User, error)
 	Update(ctx context.Context, user *User) error
+	// List searches accounts for admins, newest first, excluding deleted ones unless filtered by status
+	List(ctx context.Context, filter *UserFilter, params pagination.Params) ([]User, int64, error)
 }
 
 // SessionRepository defines methods for session persistence (Redis/DB)
@@ -90,17 +123,30 @@ type SessionRepository interface {
 	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
 }
 
+// RefreshTokenRepository defines methods for refresh token family persistence (Redis)
+type RefreshTokenRepository interface {
+	CreateFamily(ctx context.Context, family *RefreshTokenFamily) error
+	GetFamily(ctx context.Context, familyID string) (*RefreshTokenFamily, error)
+	// Rotate atomically swaps currentJTI for nextJTI. It returns ErrRefreshTokenReused
+	// if currentJTI is not the latest token of the family.
+	Rotate(ctx context.Context, familyID, currentJTI, nextJTI string) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+}
+
 // AuditRepository defines methods for audit logs
 type AuditRepository interface {
 	Create(ctx context.Context, log *AuditLog) error
+	// List returns up to filter.Page.Limit+1 entries after the cursor, newest first
+	List(ctx context.Context, filter *AuditFilter) ([]AuditLog, error)
 }
 
 // AuthUseCase defines the business logic for authentication
 type AuthUseCase interface {
 	Register(ctx context.Context, req *RegisterRequest) (*RegisterResponse, error)
 	Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error)
-	Logout(ctx context.Context, token string) error
-	RefreshToken(ctx context.Context, refreshToken string) (*LoginResponse, error)
+	Logout(ctx context.Context, req *LogoutRequest) error
+	RefreshToken(ctx context.Context, req *RefreshTokenRequest) (*LoginResponse, error)
 	GetMe(ctx context.Context, userID uuid.UUID) (*UserResponse, error)
 	VerifyEmail(ctx context.Context, token string) error
 	ForgotPassword(ctx context.Context, email string) error
@@ -111,7 +157,7 @@ type AuthUseCase interface {
 // DTOs for UseCases
 type RegisterRequest struct {
 	Email     string `json:"email" binding:"required,email"`
-	Password  string `json:"password" binding:"required,min=8"`
+	Password  string `json:"password" binding:"required"` // checked against the password policy
 	Role      string `json:"role"`
 	IPAddress string `json:"-"`
 	UserAgent string `json:"-"`
@@ -139,13 +185,28 @@ type LoginResponse struct {
 }
 
 type UserResponse struct {
-	ID    uuid.UUID `json:"id"`
-	Email string    `json:"email"`
-	Role  string    `json:"role"`
+	ID            uuid.UUID `json:"id"`
+	Email         string    `json:"email,omitempty"`
+	Role          string    `json:"role"`
+	PhoneNumber   *string   `json:"phone_number,omitempty"`
+	PhoneVerified bool      `json:"phone_verified"`
+}
+
+// NewUserResponse builds the public view of a user
+func NewUserResponse(user *User) *UserResponse {
+	return &UserResponse{
+		ID:            user.ID,
+		Email:         user.Email,
+		Role:          user.Role,
+		PhoneNumber:   user.PhoneNumber,
+		PhoneVerified: user.PhoneVerified,
+	}
 }
 
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
+	IPAddress    string `json:"-"`
+	UserAgent    string `json:"-"`
 }
 
 type VerifyEmailRequest struct {
@@ -158,11 +219,20 @@ type ForgotPasswordRequest struct {
 
 type ResetPasswordRequest struct {
 	Token       string `json:"token" binding:"required"`
-	NewPassword string `json:"new_password" binding:"required,min=8"`
+	NewPassword string `json:"new_password" binding:"required"`
 }
 
 type ChangePasswordRequest struct {
 	UserID          uuid.UUID `json:"-"`
 	CurrentPassword string    `json:"current_password" binding:"required"`
-	NewPassword     string    `json:"new_password" binding:"required,min=8"`
+	NewPassword     string    `json:"new_password" binding:"required"`
+	IPAddress       string    `json:"-"`
+	UserAgent       string    `json:"-"`
+}
+
+type LogoutRequest struct {
+	UserID    uuid.UUID
+	Token     string
+	IPAddress string
+	UserAgent string
 }
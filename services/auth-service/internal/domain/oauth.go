@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OAuth flow modes
+const (
+	OAuthModeLogin = "login"
+	OAuthModeLink  = "link"
+)
+
+// OAuthStateTTL bounds how long a user may spend on the provider's consent page
+const OAuthStateTTL = 10 * time.Minute
+
+var (
+	// ErrUnknownOAuthProvider is returned for a provider that is not configured
+	ErrUnknownOAuthProvider = errors.New("unknown oauth provider")
+	// ErrInvalidOAuthState is returned when the state is missing, expired or was already used
+	ErrInvalidOAuthState = errors.New("invalid or expired oauth state")
+	// ErrOAuthEmailNotVerified is returned when the provider cannot vouch for the user's email
+	ErrOAuthEmailNotVerified = errors.New("the provider did not return a verified email address")
+	// ErrOAuthPasswordLoginRequired is returned when an account with the provider's email exists
+	// but cannot be linked automatically; the owner must sign in and link the provider themselves
+	ErrOAuthPasswordLoginRequired = errors.New("an account with this email already exists; sign in with your password to link this provider")
+	// ErrIdentityLinkedElsewhere is returned when the provider account already belongs to another user
+	ErrIdentityLinkedElsewhere = errors.New("this provider account is linked to another user")
+	// ErrIdentityNotLinked is returned when unlinking a provider the user has not linked
+	ErrIdentityNotLinked = errors.New("provider is not linked to this account")
+	// ErrLastLoginMethod is returned when unlinking would leave the user unable to sign in
+	ErrLastLoginMethod = errors.New("set a password or link another provider before unlinking this one")
+)
+
+// OAuthIdentity is the user profile returned by a provider after a successful exchange
+type OAuthIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// OAuthProvider is an OAuth2/OIDC identity provider using the authorization code flow with PKCE
+type OAuthProvider interface {
+	Name() string
+	// AuthCodeURL returns the provider's consent page URL, bound to state and
+	// to the S256 challenge of codeVerifier.
+	AuthCodeURL(state, codeVerifier string) string
+	// Exchange trades an authorization code for the user's identity.
+	Exchange(ctx context.Context, code, codeVerifier string) (*OAuthIdentity, error)
+}
+
+// UserIdentity links a provider account to a user
+type UserIdentity struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Provider  string    `json:"provider" db:"provider"`
+	Subject   string    `json:"-" db:"subject"`
+	Email     *string   `json:"email,omitempty" db:"email"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// OAuthState is the server-side half of an in-flight authorization request
+type OAuthState struct {
+	Provider     string     `json:"provider"`
+	CodeVerifier string     `json:"code_verifier"`
+	Mode         string     `json:"mode"`              // login, link
+	UserID       *uuid.UUID `json:"user_id,omitempty"` // set when linking
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// UserIdentityRepository defines methods for linked provider accounts
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *UserIdentity) error
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*UserIdentity, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]UserIdentity, error)
+	Delete(ctx context.Context, userID uuid.UUID, provider string) error
+}
+
+// OAuthStateRepository stores authorization request state until the callback (Redis)
+type OAuthStateRepository interface {
+	Save(ctx context.Context, state string, data *OAuthState, ttl time.Duration) error
+	// Consume returns and deletes the state, so each one can only be used once.
+	Consume(ctx context.Context, state string) (*OAuthState, error)
+}
+
+// OAuthUseCase defines the business logic for social login and account linking
+type OAuthUseCase interface {
+	Providers() []string
+	Authorize(ctx context.Context, req *OAuthAuthorizeRequest) (*OAuthAuthorizeResponse, error)
+	Callback(ctx context.Context, req *OAuthCallbackRequest) (*OAuthCallbackResponse, error)
+	ListIdentities(ctx context.Context, userID uuid.UUID) ([]UserIdentity, error)
+	Unlink(ctx context.Context, req *OAuthUnlinkRequest) error
+}
+
+type OAuthAuthorizeRequest struct {
+	Provider string
+	Mode     string
+	UserID   *uuid.UUID
+}
+
+type OAuthAuthorizeResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+}
+
+type OAuthCallbackRequest struct {
+	Provider  string `form:"-"`
+	Code      string `form:"code" binding:"required"`
+	State     string `form:"state" binding:"required"`
+	IPAddress string `form:"-"`
+	UserAgent string `form:"-"`
+}
+
+// OAuthCallbackResponse carries tokens after a login, or the new identity after linking
+type OAuthCallbackResponse struct {
+	Mode     string         `json:"mode"`
+	Login    *LoginResponse `json:"login,omitempty"`
+	Identity *UserIdentity  `json:"identity,omitempty"`
+}
+
+type OAuthUnlinkRequest struct {
+	UserID    uuid.UUID
+	Provider  string
+	IPAddress string
+	UserAgent string
+}
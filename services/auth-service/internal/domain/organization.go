@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrOrganizationNotFound is returned when the user is not in an organisation
+	ErrOrganizationNotFound = errors.New("organization not found")
+	// ErrOrganizationDealerOnly is returned when an account that is not a dealer creates an organisation
+	ErrOrganizationDealerOnly = errors.New("only dealer accounts can create an organization")
+	// ErrAlreadyInOrganization is returned when the user already belongs to an organisation
+	ErrAlreadyInOrganization = errors.New("user already belongs to an organization")
+	// ErrOrganizationForbidden is returned when the member's role does not allow the action
+	ErrOrganizationForbidden = errors.New("your organization role does not allow this action")
+	// ErrMemberNotFound is returned when the user is not a member of the organisation
+	ErrMemberNotFound = errors.New("organization member not found")
+	// ErrOwnerRoleFixed is returned when removing the owner or changing the owner's role
+	ErrOwnerRoleFixed = errors.New("the organization owner cannot be removed or change role")
+	// ErrInvalidMemberRole is returned for a role other than manager or salesperson
+	ErrInvalidMemberRole = errors.New("role must be manager or salesperson")
+	// ErrInvitationNotFound is returned when an invitation does not exist
+	ErrInvitationNotFound = errors.New("invitation not found")
+	// ErrInvitationExists is returned when the email already has an open invitation
+	ErrInvitationExists = errors.New("an invitation is already open for this email")
+	// ErrInvitationInvalid is returned when accepting an expired, revoked or used invitation
+	ErrInvitationInvalid = errors.New("invitation is invalid or has expired")
+	// ErrInvitationEmailMismatch is returned when the invitation was sent to another email
+	ErrInvitationEmailMismatch = errors.New("invitation was sent to a different email address")
+)
+
+// Organization is a dealership with a team of member accounts
+type Organization struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	OwnerID   uuid.UUID `json:"owner_id" db:"owner_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	// Populated for the members' own view
+	Role    string               `json:"role,omitempty" db:"-"`
+	Members []OrganizationMember `json:"members,omitempty" db:"-"`
+}
+
+// OrganizationMember is a user's membership with their role in the team
+type OrganizationMember struct {
+	OrganizationID uuid.UUID  `json:"organization_id" db:"organization_id"`
+	UserID         uuid.UUID  `json:"user_id" db:"user_id"`
+	Email          *string    `json:"email,omitempty" db:"email"`
+	Role           string     `json:"role" db:"role"` // owner, manager, salesperson
+	InvitedBy      *uuid.UUID `json:"invited_by,omitempty" db:"invited_by"`
+	JoinedAt       time.Time  `json:"joined_at" db:"joined_at"`
+}
+
+// OrganizationInvitation asks the holder of an email address to join the team
+type OrganizationInvitation struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	OrganizationID uuid.UUID  `json:"organization_id" db:"organization_id"`
+	Email          string     `json:"email" db:"email"`
+	Role           string     `json:"role" db:"role"`
+	TokenHash      string     `json:"-" db:"token_hash"`
+	InvitedBy      *uuid.UUID `json:"invited_by,omitempty" db:"invited_by"`
+	ExpiresAt      time.Time  `json:"expires_at" db:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty" db:"accepted_at"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+// OrganizationRepository defines methods for organisation persistence
+type OrganizationRepository interface {
+	// Create saves the organisation with its owner as the first member and moves
+	// the owner's existing listings and conversations into it.
+	Create(ctx context.Context, org *Organization) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Organization, error)
+	Update(ctx context.Context, org *Organization) error
+	GetMember(ctx context.Context, userID uuid.UUID) (*OrganizationMember, error)
+	ListMembers(ctx context.Context, orgID uuid.UUID) ([]OrganizationMember, error)
+	// AddMember adds the member and, in the same transaction, marks the invitation accepted
+	AddMember(ctx context.Context, member *OrganizationMember, invitationID uuid.UUID) error
+	UpdateMemberRole(ctx context.Context, orgID, userID uuid.UUID, role string) error
+	// RemoveMember deletes the membership and hands the member's listings and
+	// conversations back to the owner.
+	RemoveMember(ctx context.Context, org *Organization, userID uuid.UUID) error
+
+	CreateInvitation(ctx context.Context, inv *OrganizationInvitation) error
+	GetInvitation(ctx context.Context, id uuid.UUID) (*OrganizationInvitation, error)
+	GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*OrganizationInvitation, error)
+	GetOpenInvitation(ctx context.Context, orgID uuid.UUID, email string) (*OrganizationInvitation, error)
+	ListInvitations(ctx context.Context, orgID uuid.UUID) ([]OrganizationInvitation, error)
+	RevokeInvitation(ctx context.Context, id uuid.UUID, revokedAt time.Time) error
+}
+
+// OrganizationUseCase defines the business logic for dealer teams
+type OrganizationUseCase interface {
+	Create(ctx context.Context, req *CreateOrganizationRequest) (*Organization, error)
+	GetMine(ctx context.Context, userID uuid.UUID) (*Organization, error)
+	Rename(ctx context.Context, req *RenameOrganizationRequest) (*Organization, error)
+	Invite(ctx context.Context, req *InviteMemberRequest) (*CreatedInvitation, error)
+	ListInvitations(ctx context.Context, userID uuid.UUID) ([]OrganizationInvitation, error)
+	RevokeInvitation(ctx context.Context, req *OrganizationActionRequest) error
+	AcceptInvitation(ctx context.Context, req *AcceptInvitationRequest) (*Organization, error)
+	ChangeMemberRole(ctx context.Context, req *ChangeMemberRoleRequest) error
+	// RemoveMember removes a member; members can also remove themselves to leave
+	RemoveMember(ctx context.Context, req *OrganizationActionRequest) error
+}
+
+type CreateOrganizationRequest struct {
+	UserID    uuid.UUID `json:"-"`
+	Name      string    `json:"name" binding:"required,max=255"`
+	IPAddress string    `json:"-"`
+	UserAgent string    `json:"-"`
+}
+
+type RenameOrganizationRequest struct {
+	UserID uuid.UUID `json:"-"`
+	Name   string    `json:"name" binding:"required,max=255"`
+}
+
+type InviteMemberRequest struct {
+	UserID    uuid.UUID `json:"-"`
+	Email     string    `json:"email" binding:"required,email"`
+	Role      string    `json:"role" binding:"required"`
+	IPAddress string    `json:"-"`
+	UserAgent string    `json:"-"`
+}
+
+type AcceptInvitationRequest struct {
+	UserID    uuid.UUID `json:"-"`
+	Token     string    `json:"token" binding:"required"`
+	IPAddress string    `json:"-"`
+	UserAgent string    `json:"-"`
+}
+
+type ChangeMemberRoleRequest struct {
+	UserID    uuid.UUID `json:"-"`
+	MemberID  uuid.UUID `json:"-"`
+	Role      string    `json:"role" binding:"required"`
+	IPAddress string    `json:"-"`
+	UserAgent string    `json:"-"`
+}
+
+// OrganizationActionRequest identifies the acting member and the member or invitation acted on
+type OrganizationActionRequest struct {
+	UserID    uuid.UUID
+	TargetID  uuid.UUID
+	IPAddress string
+	UserAgent string
+}
+
+// CreatedInvitation carries the invitation token, which cannot be retrieved again
+type CreatedInvitation struct {
+	OrganizationInvitation
+	Token string `json:"token"`
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "errors"
+
+// ErrWeakPassword is wrapped by every PasswordPolicyError
+var ErrWeakPassword = errors.New("password does not meet the password policy")
+
+// PasswordPolicyError explains why a new password was rejected
+type PasswordPolicyError struct {
+	Reason string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return e.Reason
+}
+
+func (e *PasswordPolicyError) Unwrap() error {
+	return ErrWeakPassword
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OTP purposes
+const (
+	// OTPPurposeLogin signs in (and on first use, activates) the account holding the phone
+	OTPPurposeLogin = "login"
+	// OTPPurposeVerify attaches the phone to an already signed-in account
+	OTPPurposeVerify = "verify"
+)
+
+var (
+	// ErrInvalidPhoneNumber is returned when a number cannot be normalised to E.164
+	ErrInvalidPhoneNumber = errors.New("invalid phone number")
+	// ErrPhoneTaken is returned when the number is already verified by another account
+	ErrPhoneTaken = errors.New("phone number already registered")
+	// ErrInvalidOTP is returned for a wrong, expired or unknown code
+	ErrInvalidOTP = errors.New("invalid or expired verification code")
+	// ErrOTPAttemptsExceeded is returned once a code has been guessed at too often
+	ErrOTPAttemptsExceeded = errors.New("too many incorrect attempts; request a new code")
+	// ErrOTPCooldown is returned when a new code is requested too soon after the last one
+	ErrOTPCooldown = errors.New("please wait before requesting another code")
+	// ErrOTPSendLimit is returned when too many codes were requested from one IP address
+	ErrOTPSendLimit = errors.New("too many codes requested; try again later")
+)
+
+// OTP is a one-time code sent by SMS. Only its hash is stored.
+type OTP struct {
+	UserID   uuid.UUID
+	CodeHash string
+	Attempts int
+}
+
+// OTPCheck is the outcome of checking a code against the pending one
+type OTPCheck struct {
+	UserID   uuid.UUID
+	Valid    bool
+	Attempts int // Wrong guesses so far, including this one
+}
+
+// OTPRepository stores pending one-time codes (Redis)
+type OTPRepository interface {
+	Save(ctx context.Context, purpose, phone string, otp *OTP, ttl time.Duration) error
+	// Check compares codeHash with the pending code in one atomic step. A match
+	// consumes the code; a wrong guess counts an attempt, and the code is
+	// deleted once maxAttempts is reached. It returns nil if no code is pending.
+	Check(ctx context.Context, purpose, phone, codeHash string, maxAttempts int) (*OTPCheck, error)
+	Delete(ctx context.Context, purpose, phone string) error
+	// AcquireCooldown reports whether a code may be sent to phone now, and if so
+	// blocks further sends for the cooldown period.
+	AcquireCooldown(ctx context.Context, phone string, cooldown time.Duration) (bool, error)
+	// CountSend counts a code sent for ipAddress and returns the number sent
+	// within the window that began with the first of them.
+	CountSend(ctx context.Context, ipAddress string, window time.Duration) (int, error)
+}
+
+// SMSSender delivers one-time codes (via notification-service)
+type SMSSender interface {
+	SendOTP(ctx context.Context, userID uuid.UUID, phone, code string, ttl time.Duration) error
+}
+
+// PhoneUseCase defines the business logic for phone registration, login and verification
+type PhoneUseCase interface {
+	Register(ctx context.Context, req *PhoneRegisterRequest) (*PhoneCodeResponse, error)
+	RequestLoginCode(ctx context.Context, req *PhoneLoginRequest) (*PhoneCodeResponse, error)
+	VerifyLogin(ctx context.Context, req *PhoneVerifyRequest) (*LoginResponse, error)
+	StartVerification(ctx context.Context, req *PhoneLoginRequest) (*PhoneCodeResponse, error)
+	ConfirmVerification(ctx context.Context, req *PhoneVerifyRequest) (*UserResponse, error)
+}
+
+type PhoneRegisterRequest struct {
+	PhoneNumber string `json:"phone_number" binding:"required"`
+	Role        string `json:"role"`
+	IPAddress   string `json:"-"`
+	UserAgent   string `json:"-"`
+}
+
+type PhoneLoginRequest struct {
+	UserID      uuid.UUID `json:"-"` // set when verifying a phone for a signed-in user
+	PhoneNumber string    `json:"phone_number" binding:"required"`
+	IPAddress   string    `json:"-"`
+	UserAgent   string    `json:"-"`
+}
+
+type PhoneVerifyRequest struct {
+	UserID      uuid.UUID `json:"-"` // set when verifying a phone for a signed-in user
+	PhoneNumber string    `json:"phone_number" binding:"required"`
+	Code        string    `json:"code" binding:"required,len=6,numeric"`
+	IPAddress   string    `json:"-"`
+	UserAgent   string    `json:"-"`
+}
+
+type PhoneCodeResponse struct {
+	Message   string `json:"message"`
+	ExpiresIn int    `json:"expires_in"`
+}
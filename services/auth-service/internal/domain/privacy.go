@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Data export statuses
+const (
+	DataExportPending    = "pending"
+	DataExportProcessing = "processing"
+	DataExportReady      = "ready"
+	DataExportFailed     = "failed"
+	DataExportExpired    = "expired"
+)
+
+var (
+	// ErrDataExportNotFound is returned when the export does not exist or belongs to another user
+	ErrDataExportNotFound = errors.New("data export not found")
+	// ErrDataExportInProgress is returned when the user already has an export being prepared
+	ErrDataExportInProgress = errors.New("a data export is already being prepared")
+	// ErrDataExportNotReady is returned when downloading an export that is not ready or has expired
+	ErrDataExportNotReady = errors.New("data export is not available for download")
+	// ErrPasswordConfirmation is returned when the password confirming an erasure is wrong
+	ErrPasswordConfirmation = errors.New("password is incorrect")
+	// ErrErasureAlreadyScheduled is returned when the account is already scheduled for erasure
+	ErrErasureAlreadyScheduled = errors.New("account erasure is already scheduled")
+	// ErrErasureNotScheduled is returned when cancelling an erasure that was never requested
+	ErrErasureNotScheduled = errors.New("account erasure is not scheduled")
+)
+
+// DataExport is a "download my data" job. The ZIP lives on local disk until ExpiresAt.
+type DataExport struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	UserID       uuid.UUID  `json:"user_id" db:"user_id"`
+	Status       string     `json:"status" db:"status"` // pending, processing, ready, failed, expired
+	FilePath     *string    `json:"-" db:"file_path"`
+	FileSize     *int64     `json:"file_size,omitempty" db:"file_size"`
+	ErrorMessage *string    `json:"error_message,omitempty" db:"error_message"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+}
+
+// ErasureStatus describes a pending account erasure. Both fields are nil when none is scheduled.
+type ErasureStatus struct {
+	RequestedAt  *time.Time `json:"requested_at"`
+	ScheduledFor *time.Time `json:"scheduled_for"`
+}
+
+// PrivacyRequest identifies the user acting on their own data
+type PrivacyRequest struct {
+	UserID    uuid.UUID `json:"-"`
+	IPAddress string    `json:"-"`
+	UserAgent string    `json:"-"`
+}
+
+type ErasureRequest struct {
+	PrivacyRequest
+	// Password is required for accounts that have one
+	Password string `json:"password"`
+}
+
+// PrivacyRepository reads and erases a user's records across every service's tables
+// in the shared database. Tables of services that have never been migrated are skipped.
+type PrivacyRepository interface {
+	CreateExport(ctx context.Context, export *DataExport) error
+	UpdateExport(ctx context.Context, export *DataExport) error
+	GetExport(ctx context.Context, id uuid.UUID) (*DataExport, error)
+	// GetActiveExport returns the user's pending or processing export, if any
+	GetActiveExport(ctx context.Context, userID uuid.UUID) (*DataExport, error)
+	ListExports(ctx context.Context, userID uuid.UUID) ([]DataExport, error)
+	// ListExpiredExports returns ready exports whose download window closed before now
+	ListExpiredExports(ctx context.Context, now time.Time) ([]DataExport, error)
+	// FailStaleExports marks exports left pending or processing since before cutoff as failed
+	FailStaleExports(ctx context.Context, cutoff time.Time) (int64, error)
+
+	// CollectUserData returns the user's records as JSON arrays keyed by export file name
+	CollectUserData(ctx context.Context, userID uuid.UUID) (map[string]json.RawMessage, error)
+
+	GetErasure(ctx context.Context, userID uuid.UUID) (*ErasureStatus, error)
+	ScheduleErasure(ctx context.Context, userID uuid.UUID, requestedAt, scheduledFor time.Time) error
+	CancelErasure(ctx context.Context, userID uuid.UUID) error
+	// ListDueErasures returns accounts whose erasure grace period ended before now
+	ListDueErasures(ctx context.Context, now time.Time) ([]uuid.UUID, error)
+	// EraseUser anonymises or deletes the user's records in a single transaction
+	EraseUser(ctx context.Context, userID uuid.UUID) error
+}
+
+// PrivacyUseCase defines the business logic for personal data export and erasure
+type PrivacyUseCase interface {
+	// RequestExport queues a data export; the ZIP is built in the background
+	RequestExport(ctx context.Context, req *PrivacyRequest) (*DataExport, error)
+	ListExports(ctx context.Context, userID uuid.UUID) ([]DataExport, error)
+	// GetDownload returns a ready export owned by userID
+	GetDownload(ctx context.Context, userID, exportID uuid.UUID) (*DataExport, error)
+
+	// RequestErasure schedules the account for erasure after the grace period
+	RequestErasure(ctx context.Context, req *ErasureRequest) (*ErasureStatus, error)
+	GetErasure(ctx context.Context, userID uuid.UUID) (*ErasureStatus, error)
+	CancelErasure(ctx context.Context, req *PrivacyRequest) error
+
+	// ProcessDueErasures erases every account whose grace period has ended
+	ProcessDueErasures(ctx context.Context) (int, error)
+	// PurgeExpiredExports deletes export files past their download window
+	PurgeExpiredExports(ctx context.Context) (int, error)
+}
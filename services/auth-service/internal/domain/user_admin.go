@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrUserNotFound is returned when the target account does not exist or was deleted
+	ErrUserNotFound = errors.New("user not found")
+	// ErrInvalidRole is returned for a role that does not exist
+	ErrInvalidRole = errors.New("invalid role")
+	// ErrDealerRoleNotAssignable is returned when an admin grants the dealer role
+	// directly; it comes with an approved dealer application
+	ErrDealerRoleNotAssignable = errors.New("the dealer role is granted by approving a dealer application")
+	// ErrDealerRoleNotRemovable is returned when an admin changes a dealer's role
+	// directly; revoking the application also unverifies the dealer's listings
+	ErrDealerRoleNotRemovable = errors.New("the dealer role is removed by revoking the dealer application")
+	// ErrCannotModifySelf is returned when an admin targets their own account
+	ErrCannotModifySelf = errors.New("you cannot perform this action on your own account")
+	// ErrAdminAccountProtected is returned when an admin without user:manage-admins
+	// targets an admin account or grants an admin role
+	ErrAdminAccountProtected = errors.New("managing admin accounts requires the user:manage-admins permission")
+	// ErrReasonRequired is returned when a suspension has no reason
+	ErrReasonRequired = errors.New("a reason is required")
+	// ErrInvalidSuspensionEnd is returned when a suspension would end in the past
+	ErrInvalidSuspensionEnd = errors.New("suspension end must be in the future")
+	// ErrUserNotSuspended is returned when reactivating an account that is not suspended
+	ErrUserNotSuspended = errors.New("user is not suspended")
+	// ErrAccountSuspended is returned when a suspended user tries to sign in or refresh
+	ErrAccountSuspended = errors.New("account suspended")
+	// ErrAccountDeleted is returned when a deleted user tries to sign in or refresh
+	ErrAccountDeleted = errors.New("account deleted")
+	// ErrImpersonationNotAllowed is returned when impersonating an admin account
+	ErrImpersonationNotAllowed = errors.New("admin accounts cannot be impersonated")
+)
+
+// UserFilter narrows an admin user search. Zero values match everything.
+type UserFilter struct {
+	Query  string // matches email or phone number, or an exact user ID
+	Role   string
+	Status string
+}
+
+// UserDetails is the admin view of a single account
+type UserDetails struct {
+	User           *User          `json:"user"`
+	Identities     []UserIdentity `json:"identities"`
+	RecentActivity []AuditLog     `json:"recent_activity"`
+}
+
+// UserAdminUseCase defines the business logic for admin user management
+type UserAdminUseCase interface {
+	List(ctx context.Context, filter *UserFilter, params pagination.Params) ([]User, pagination.Pagination, error)
+	Get(ctx context.Context, id uuid.UUID) (*UserDetails, error)
+	ChangeRole(ctx context.Context, req *ChangeRoleRequest) (*User, error)
+	Suspend(ctx context.Context, req *SuspendUserRequest) (*User, error)
+	Reactivate(ctx context.Context, req *UserAdminActionRequest) (*User, error)
+	// ForceLogout ends every session and refresh token of the user
+	ForceLogout(ctx context.Context, req *UserAdminActionRequest) error
+	// Delete soft-deletes the account and signs it out everywhere
+	Delete(ctx context.Context, req *UserAdminActionRequest) error
+	// Impersonate issues a short-lived access token acting as the user, for support
+	Impersonate(ctx context.Context, req *ImpersonateRequest) (*ImpersonationResponse, error)
+}
+
+// UserAdminActionRequest identifies the admin acting on an account
+type UserAdminActionRequest struct {
+	UserID  uuid.UUID `json:"-"`
+	ActorID uuid.UUID `json:"-"`
+	// CanManageAdmins is set when the actor holds user:manage-admins
+	CanManageAdmins bool   `json:"-"`
+	IPAddress       string `json:"-"`
+	UserAgent       string `json:"-"`
+}
+
+type ChangeRoleRequest struct {
+	UserAdminActionRequest
+	Role string `json:"role" binding:"required"`
+}
+
+type SuspendUserRequest struct {
+	UserAdminActionRequest
+	Reason string     `json:"reason" binding:"required"`
+	Until  *time.Time `json:"until"` // omitted for an indefinite suspension
+}
+
+type ImpersonateRequest struct {
+	UserAdminActionRequest
+	// Reason, e.g. a support ticket reference, is recorded and shown to the user
+	Reason string `json:"reason" binding:"required"`
+}
+
+// ImpersonationResponse carries an access token with an "act" claim naming the
+// admin. There is no refresh token; a new one must be requested once it expires.
+type ImpersonationResponse struct {
+	User        *UserResponse `json:"user"`
+	AccessToken string        `json:"access_token"`
+	ExpiresIn   int           `json:"expires_in"`
+	ExpiresAt   time.Time     `json:"expires_at"`
+}
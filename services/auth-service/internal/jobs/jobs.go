@@ -0,0 +1,41 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/exoticsLanka/auth-service/internal/domain"
+)
+
+type JobScheduler struct {
+	privacyUseCase domain.PrivacyUseCase
+}
+
+func NewJobScheduler(privacyUseCase domain.PrivacyUseCase) *JobScheduler {
+	return &JobScheduler{privacyUseCase: privacyUseCase}
+}
+
+func (s *JobScheduler) Start() {
+	go s.runHourlyJobs()
+}
+
+func (s *JobScheduler) runHourlyJobs() {
+	ticker := time.NewTicker(1 * time.Hour)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx := context.Background()
+		log.Println("Running hourly jobs...")
+
+		if n, err := s.privacyUseCase.ProcessDueErasures(ctx); err != nil {
+			log.Printf("Error processing account erasures: %v", err)
+		} else if n > 0 {
+			log.Printf("Erased %d accounts", n)
+		}
+
+		if _, err := s.privacyUseCase.PurgeExpiredExports(ctx); err != nil {
+			log.Printf("Error purging expired data exports: %v", err)
+		}
+	}
+}
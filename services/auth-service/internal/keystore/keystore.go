@@ -0,0 +1,163 @@
+package keystore
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/jwks"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey is a private key used to sign access tokens, identified by its kid.
+type SigningKey struct {
+	KID    string
+	Method jwt.SigningMethod
+	Signer crypto.Signer
+}
+
+// KeyStore holds the access token signing keys. Tokens are always signed with the
+// active key; every loaded key is published in the JWKS so tokens signed with a
+// previous key remain verifiable until they expire.
+type KeyStore struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+// Load reads every *.pem private key in dir, using the file name (without the
+// extension) as the kid. Both RSA (RS256) and Ed25519 (EdDSA) keys are supported.
+// When dir is empty an ephemeral RSA key is generated, which is only suitable for
+// local development because tokens will not survive a restart.
+func Load(dir, activeKID string) (*KeyStore, error) {
+	if dir == "" {
+		log.Println("Warning: JWT_KEYS_DIR not set, generating an ephemeral signing key")
+		return newEphemeral()
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	ks := &KeyStore{keys: make(map[string]*SigningKey)}
+	for _, file := range files {
+		kid := strings.TrimSuffix(filepath.Base(file), ".pem")
+		key, err := loadKey(kid, file)
+		if err != nil {
+			return nil, fmt.Errorf("load signing key %s: %w", file, err)
+		}
+		ks.keys[kid] = key
+	}
+
+	if len(ks.keys) == 0 {
+		return nil, fmt.Errorf("no signing keys found in %s", dir)
+	}
+
+	if activeKID == "" {
+		if len(ks.keys) > 1 {
+			return nil, errors.New("JWT_ACTIVE_KID must be set when more than one signing key is present")
+		}
+		for kid := range ks.keys {
+			activeKID = kid
+		}
+	}
+
+	active, ok := ks.keys[activeKID]
+	if !ok {
+		return nil, fmt.Errorf("active signing key %q not found", activeKID)
+	}
+	ks.active = active
+
+	return ks, nil
+}
+
+func newEphemeral() (*KeyStore, error) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	key := &SigningKey{KID: "ephemeral", Method: jwt.SigningMethodRS256, Signer: priv}
+	return &KeyStore{
+		active: key,
+		keys:   map[string]*SigningKey{key.KID: key},
+	}, nil
+}
+
+func loadKey(kid, file string) (*SigningKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var priv interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		priv, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		priv, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := priv.(type) {
+	case *rsa.PrivateKey:
+		return &SigningKey{KID: kid, Method: jwt.SigningMethodRS256, Signer: k}, nil
+	case ed25519.PrivateKey:
+		return &SigningKey{KID: kid, Method: jwt.SigningMethodEdDSA, Signer: k}, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", priv)
+	}
+}
+
+// Sign signs the claims with the active key and sets the kid header.
+func (ks *KeyStore) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.active.Method, claims)
+	token.Header["kid"] = ks.active.KID
+	return token.SignedString(ks.active.Signer)
+}
+
+// Keyfunc resolves the public key for a token signed by this key store.
+func (ks *KeyStore) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key.Signer.Public(), nil
+}
+
+// JWKS returns the public key set for every loaded key.
+func (ks *KeyStore) JWKS() (jwks.Set, error) {
+	kids := make([]string, 0, len(ks.keys))
+	for kid := range ks.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	set := jwks.Set{Keys: make([]jwks.Key, 0, len(kids))}
+	for _, kid := range kids {
+		key, err := jwks.NewKey(kid, ks.keys[kid].Signer.Public())
+		if err != nil {
+			return jwks.Set{}, err
+		}
+		set.Keys = append(set.Keys, key)
+	}
+	return set, nil
+}
@@ -0,0 +1,85 @@
+// Package notification is auth-service's client for notification-service.
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/exoticsLanka/auth-service/internal/keystore"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+type client struct {
+	baseURL    string
+	keys       *keystore.KeyStore
+	httpClient *http.Client
+}
+
+// NewClient creates a notification-service client. Requests are authenticated
+// with a short-lived service token signed by auth-service's own keys.
+func NewClient(baseURL string, keys *keystore.KeyStore) domain.SMSSender {
+	return &client{
+		baseURL:    baseURL,
+		keys:       keys,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *client) SendOTP(ctx context.Context, userID uuid.UUID, phone, code string, ttl time.Duration) error {
+	return c.sendSMS(ctx, map[string]interface{}{
+		"user_id":      userID.String(),
+		"phone_number": phone,
+		"type":         "phone_otp",
+		"data": map[string]interface{}{
+			"code":               code,
+			"expires_in_minutes": int(ttl.Minutes()),
+		},
+	})
+}
+
+func (c *client) sendSMS(ctx context.Context, payload map[string]interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	token, err := c.serviceToken()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/notifications/sms", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach notification service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notification service returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (c *client) serviceToken() (string, error) {
+	now := time.Now()
+	return c.keys.Sign(jwt.MapClaims{
+		"sub":  uuid.Nil.String(),
+		"role": auth.RoleService,
+		"exp":  now.Add(time.Minute).Unix(),
+		"iat":  now.Unix(),
+	})
+}
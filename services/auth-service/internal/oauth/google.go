@@ -0,0 +1,52 @@
+package oauth
+
+import (
+	"context"
+
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"golang.org/x/oauth2"
+)
+
+const googleUserInfoURL = "https://openidconnect.googleapis.com/v1/userinfo"
+
+type googleProvider struct {
+	codeFlow
+}
+
+// NewGoogleProvider creates the Google OpenID Connect provider
+func NewGoogleProvider(clientID, clientSecret, redirectURL string) domain.OAuthProvider {
+	return &googleProvider{codeFlow{config: &oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       []string{"openid", "email", "profile"},
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  "https://accounts.google.com/o/oauth2/v2/auth",
+			TokenURL: "https://oauth2.googleapis.com/token",
+		},
+	}}}
+}
+
+func (p *googleProvider) Name() string {
+	return "google"
+}
+
+func (p *googleProvider) Exchange(ctx context.Context, code, codeVerifier string) (*domain.OAuthIdentity, error) {
+	var profile struct {
+		Sub           string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	if err := p.exchange(ctx, code, codeVerifier, googleUserInfoURL, &profile); err != nil {
+		return nil, err
+	}
+
+	return &domain.OAuthIdentity{
+		Provider:      p.Name(),
+		Subject:       profile.Sub,
+		Email:         profile.Email,
+		EmailVerified: profile.EmailVerified,
+		Name:          profile.Name,
+	}, nil
+}
@@ -0,0 +1,39 @@
+// Package oauth implements the OAuth2/OIDC identity providers used for social login.
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/oauth2"
+)
+
+// codeFlow is the authorization code + PKCE part shared by every real provider.
+type codeFlow struct {
+	config *oauth2.Config
+}
+
+func (f *codeFlow) AuthCodeURL(state, codeVerifier string) string {
+	return f.config.AuthCodeURL(state, oauth2.S256ChallengeOption(codeVerifier))
+}
+
+// exchange trades the code for a token and decodes the JSON profile served at profileURL.
+func (f *codeFlow) exchange(ctx context.Context, code, codeVerifier, profileURL string, profile interface{}) error {
+	token, err := f.config.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+
+	resp, err := f.config.Client(ctx, token).Get(profileURL)
+	if err != nil {
+		return fmt.Errorf("failed to fetch user profile: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch user profile: status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(profile)
+}
@@ -0,0 +1,93 @@
+package password
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// defaultBreachList is a bundled list of common passwords, as upper case SHA-1
+// hashes one per line.
+//
+//go:embed breached_passwords.txt
+var defaultBreachList []byte
+
+// prefixLength is the hash prefix the list is bucketed by, as in the Have I
+// Been Pwned range API, so the same lookup works against a remote source.
+const prefixLength = 5
+
+// BreachList holds SHA-1 hashes of known breached or common passwords,
+// bucketed by hash prefix. Lines may carry a ":count" suffix, as in the Have I
+// Been Pwned downloads, which is ignored.
+type BreachList struct {
+	buckets map[string][]string
+}
+
+// LoadBreachList loads the bundled list and, if path is set, the hashes in
+// that file as well.
+func LoadBreachList(path string) (*BreachList, error) {
+	list := &BreachList{buckets: make(map[string][]string)}
+	if err := list.read(bytes.NewReader(defaultBreachList)); err != nil {
+		return nil, err
+	}
+
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		if err := list.read(f); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	for _, suffixes := range list.buckets {
+		sort.Strings(suffixes)
+	}
+	return list, nil
+}
+
+func (l *BreachList) read(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		hash, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if hash == "" {
+			continue
+		}
+		if len(hash) != sha1.Size*2 {
+			return fmt.Errorf("line %d: not a SHA-1 hash", line)
+		}
+		hash = strings.ToUpper(hash)
+		prefix := hash[:prefixLength]
+		l.buckets[prefix] = append(l.buckets[prefix], hash[prefixLength:])
+	}
+	return scanner.Err()
+}
+
+// Contains reports whether password is on the list.
+func (l *BreachList) Contains(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	suffixes := l.buckets[hash[:prefixLength]]
+	suffix := hash[prefixLength:]
+	i := sort.SearchStrings(suffixes, suffix)
+	return i < len(suffixes) && suffixes[i] == suffix
+}
+
+// Len returns the number of hashes on the list.
+func (l *BreachList) Len() int {
+	n := 0
+	for _, suffixes := range l.buckets {
+		n += len(suffixes)
+	}
+	return n
+}
@@ -0,0 +1,126 @@
+// Package password enforces the password policy for new passwords and hashes
+// them with bcrypt at the configured cost.
+package password
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MaxLength is the longest password accepted; bcrypt ignores anything past 72 bytes.
+const MaxLength = 72
+
+// Policy validates new passwords.
+type Policy struct {
+	minLength  int
+	minClasses int
+	breached   *BreachList
+}
+
+// NewPolicy creates a policy requiring at least minLength characters drawn from
+// at least minClasses of lower case, upper case, digits and symbols. Passwords
+// found in breached (which may be nil) are rejected.
+func NewPolicy(minLength, minClasses int, breached *BreachList) *Policy {
+	return &Policy{minLength: minLength, minClasses: minClasses, breached: breached}
+}
+
+// Validate returns a *domain.PasswordPolicyError if password may not be used
+// by the account with the given email.
+func (p *Policy) Validate(password, email string) error {
+	if utf8.RuneCountInString(password) < p.minLength {
+		return policyError("password must be at least %d characters", p.minLength)
+	}
+	if len(password) > MaxLength {
+		return policyError("password must be at most %d bytes", MaxLength)
+	}
+	if characterClasses(password) < p.minClasses {
+		return policyError("password must contain at least %d of: lower case letters, upper case letters, digits, symbols", p.minClasses)
+	}
+	if containsEmail(password, email) {
+		return policyError("password must not contain your email address")
+	}
+	if p.breached != nil && (p.breached.Contains(password) || p.breached.Contains(strings.ToLower(password))) {
+		return policyError("this password is too common or has appeared in a data breach; choose a different one")
+	}
+	return nil
+}
+
+func characterClasses(password string) int {
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	classes := 0
+	for _, present := range []bool{lower, upper, digit, symbol} {
+		if present {
+			classes++
+		}
+	}
+	return classes
+}
+
+// containsEmail reports whether password contains the email address or the
+// part before the @, ignoring case. Very short local parts are not checked.
+func containsEmail(password, email string) bool {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return false
+	}
+	password = strings.ToLower(password)
+
+	if strings.Contains(password, email) {
+		return true
+	}
+	local, _, _ := strings.Cut(email, "@")
+	return len(local) >= 3 && strings.Contains(password, local)
+}
+
+func policyError(format string, args ...interface{}) error {
+	return &domain.PasswordPolicyError{Reason: fmt.Sprintf(format, args...)}
+}
+
+// Hasher hashes passwords with bcrypt at a fixed cost.
+type Hasher struct {
+	cost int
+}
+
+// NewHasher creates a hasher; cost is clamped to the range bcrypt supports.
+func NewHasher(cost int) *Hasher {
+	if cost < bcrypt.MinCost {
+		cost = bcrypt.MinCost
+	}
+	if cost > bcrypt.MaxCost {
+		cost = bcrypt.MaxCost
+	}
+	return &Hasher{cost: cost}
+}
+
+func (h *Hasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// NeedsRehash reports whether hash was made with a different cost than the
+// current one, so it should be replaced the next time the password is known.
+func (h *Hasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err == nil && cost != h.cost
+}
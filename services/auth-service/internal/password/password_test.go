@@ -0,0 +1,90 @@
+package password
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/exoticsLanka/auth-service/internal/domain"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	breached := breachList(t, "Summer2024!")
+	policy := NewPolicy(10, 3, breached)
+
+	tests := []struct {
+		name     string
+		password string
+		email    string
+		wantErr  string
+	}{
+		{"valid", "Correct-Horse-9", "kasun@example.com", ""},
+		{"too short", "Ab1!", "kasun@example.com", "at least 10 characters"},
+		{"length counts characters not bytes", "Ünïcödé-1ä", "kasun@example.com", ""},
+		{"too long", "Aa1!" + strings.Repeat("x", MaxLength), "kasun@example.com", "at most 72 bytes"},
+		{"too few classes", "alllowercaseletters", "kasun@example.com", "at least 3 of"},
+		{"two classes", "lowercase12345", "kasun@example.com", "at least 3 of"},
+		{"contains email", "x-Kasun@Example.com-1", "kasun@example.com", "email address"},
+		{"contains local part", "Kasun-Rocks-2024", "kasun@example.com", "email address"},
+		{"short local part is allowed", "Ab-Secure-Pass-1", "ab@example.com", ""},
+		{"no email", "Correct-Horse-9", "", ""},
+		{"breached", "Summer2024!", "kasun@example.com", "data breach"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(tt.password, tt.email)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate(%q) = %v, want nil", tt.password, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate(%q) = %v, want error containing %q", tt.password, err, tt.wantErr)
+			}
+			if !errors.Is(err, domain.ErrWeakPassword) {
+				t.Errorf("Validate(%q) error does not wrap ErrWeakPassword", tt.password)
+			}
+		})
+	}
+}
+
+func TestPolicyValidateLowerCaseBreach(t *testing.T) {
+	// Upper case variants of a breached lower case password are rejected too
+	policy := NewPolicy(8, 1, breachList(t, "letmein123"))
+	if err := policy.Validate("LetMeIn123", ""); err == nil {
+		t.Fatal("Validate accepted a case variant of a breached password")
+	}
+}
+
+func TestPolicyValidateWithoutBreachList(t *testing.T) {
+	policy := NewPolicy(8, 2, nil)
+	if err := policy.Validate("Summer2024!", ""); err != nil {
+		t.Fatalf("Validate = %v, want nil", err)
+	}
+}
+
+// breachList loads the bundled list plus the SHA-1 hashes of passwords
+func breachList(t *testing.T, passwords ...string) *BreachList {
+	t.Helper()
+
+	var lines []string
+	for _, p := range passwords {
+		sum := sha1.Sum([]byte(p))
+		lines = append(lines, strings.ToUpper(hex.EncodeToString(sum[:]))+":42")
+	}
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := LoadBreachList(path)
+	if err != nil {
+		t.Fatalf("LoadBreachList: %v", err)
+	}
+	return list
+}
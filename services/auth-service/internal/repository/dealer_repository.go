@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresDealerApplicationRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresDealerApplicationRepository creates a new dealer application repository
+func NewPostgresDealerApplicationRepository(db *pgxpool.Pool) domain.DealerApplicationRepository {
+	return &postgresDealerApplicationRepository{db: db}
+}
+
+const dealerApplicationColumns = `
+	id, user_id, business_name, registration_number, business_address, contact_phone,
+	website, documents, status, reviewed_by, review_reason, reviewed_at, created_at, updated_at
+`
+
+func scanDealerApplication(row pgx.Row) (*domain.DealerApplication, error) {
+	var app domain.DealerApplication
+	err := row.Scan(
+		&app.ID, &app.UserID, &app.BusinessName, &app.RegistrationNumber, &app.BusinessAddress, &app.ContactPhone,
+		&app.Website, &app.Documents, &app.Status, &app.ReviewedBy, &app.ReviewReason, &app.ReviewedAt,
+		&app.CreatedAt, &app.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &app, nil
+}
+
+func (r *postgresDealerApplicationRepository) Create(ctx context.Context, app *domain.DealerApplication) error {
+	query := `
+		INSERT INTO dealer_applications (
+			id, user_id, business_name, registration_number, business_address, contact_phone,
+			website, documents, status, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6,
+			$7, $8, $9, $10, $11
+		)
+	`
+	_, err := r.db.Exec(ctx, query,
+		app.ID, app.UserID, app.BusinessName, app.RegistrationNumber, app.BusinessAddress, app.ContactPhone,
+		app.Website, app.Documents, app.Status, app.CreatedAt, app.UpdatedAt,
+	)
+	return err
+}
+
+func (r *postgresDealerApplicationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.DealerApplication, error) {
+	query := `SELECT ` + dealerApplicationColumns + ` FROM dealer_applications WHERE id = $1`
+	app, err := scanDealerApplication(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return app, nil
+}
+
+func (r *postgresDealerApplicationRepository) GetLatestByUserID(ctx context.Context, userID uuid.UUID) (*domain.DealerApplication, error) {
+	query := `SELECT ` + dealerApplicationColumns + ` FROM dealer_applications WHERE user_id = $1 ORDER BY created_at DESC LIMIT 1`
+	app, err := scanDealerApplication(r.db.QueryRow(ctx, query, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return app, nil
+}
+
+func (r *postgresDealerApplicationRepository) List(ctx context.Context, status string, params pagination.Params) ([]domain.DealerApplication, int64, error) {
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM dealer_applications WHERE ($1 = '' OR status = $1)`
+	if err := r.db.QueryRow(ctx, countQuery, status).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	// Oldest first, so the review queue is worked in submission order
+	query := `SELECT ` + dealerApplicationColumns + `
+		FROM dealer_applications
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at ASC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.db.Query(ctx, query, status, params.Limit, params.Offset())
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	apps := []domain.DealerApplication{}
+	for rows.Next() {
+		app, err := scanDealerApplication(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		apps = append(apps, *app)
+	}
+	return apps, total, rows.Err()
+}
+
+func (r *postgresDealerApplicationRepository) UpdateReview(ctx context.Context, app *domain.DealerApplication, from, role string, listingsVerified bool) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Another reviewer may have acted on the application since it was loaded
+	tag, err := tx.Exec(ctx, `
+		UPDATE dealer_applications SET
+			status = $1, reviewed_by = $2, review_reason = $3, reviewed_at = $4, updated_at = $5
+		WHERE id = $6 AND status = $7
+	`, app.Status, app.ReviewedBy, app.ReviewReason, app.ReviewedAt, app.UpdatedAt, app.ID, from)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDealerApplicationState
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE users SET role = $1, updated_at = $2 WHERE id = $3`, role, app.UpdatedAt, app.UserID)
+	if err != nil {
+		return err
+	}
+
+	// car_listings belongs to listings-service, which shares this database
+	_, err = tx.Exec(ctx, `UPDATE car_listings SET is_verified = $1, updated_at = $2 WHERE user_id = $3`,
+		listingsVerified, app.UpdatedAt, app.UserID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/org"
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresOrganizationRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresOrganizationRepository creates a new dealer organisation repository
+func NewPostgresOrganizationRepository(db *pgxpool.Pool) domain.OrganizationRepository {
+	return &postgresOrganizationRepository{db: db}
+}
+
+const invitationColumns = `
+	id, organization_id, email, role, token_hash, invited_by, expires_at, accepted_at, revoked_at, created_at
+`
+
+func scanInvitation(row pgx.Row) (*domain.OrganizationInvitation, error) {
+	var inv domain.OrganizationInvitation
+	err := row.Scan(
+		&inv.ID, &inv.OrganizationID, &inv.Email, &inv.Role, &inv.TokenHash, &inv.InvitedBy,
+		&inv.ExpiresAt, &inv.AcceptedAt, &inv.RevokedAt, &inv.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+func (r *postgresOrganizationRepository) Create(ctx context.Context, organization *domain.Organization) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO organizations (id, name, owner_id, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)
+	`, organization.ID, organization.Name, organization.OwnerID, organization.CreatedAt, organization.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO organization_members (organization_id, user_id, role, joined_at) VALUES ($1, $2, 'owner', $3)
+	`, organization.ID, organization.OwnerID, organization.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	// listings-service and messaging-service move the dealer's existing
+	// inventory and leads into the team, still assigned to them
+	err = org.RecordEvent(ctx, tx, org.Event{
+		OrganizationID: organization.ID,
+		Type:           org.EventCreated,
+		UserID:         organization.OwnerID,
+		OwnerID:        organization.OwnerID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *postgresOrganizationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Organization, error) {
+	var org domain.Organization
+	err := r.db.QueryRow(ctx, `
+		SELECT id, name, owner_id, created_at, updated_at FROM organizations WHERE id = $1
+	`, id).Scan(&org.ID, &org.Name, &org.OwnerID, &org.CreatedAt, &org.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (r *postgresOrganizationRepository) Update(ctx context.Context, org *domain.Organization) error {
+	_, err := r.db.Exec(ctx, `UPDATE organizations SET name = $1, updated_at = $2 WHERE id = $3`, org.Name, org.UpdatedAt, org.ID)
+	return err
+}
+
+func (r *postgresOrganizationRepository) GetMember(ctx context.Context, userID uuid.UUID) (*domain.OrganizationMember, error) {
+	var m domain.OrganizationMember
+	err := r.db.QueryRow(ctx, `
+		SELECT m.organization_id, m.user_id, u.email, m.role, m.invited_by, m.joined_at
+		FROM organization_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.user_id = $1
+	`, userID).Scan(&m.OrganizationID, &m.UserID, &m.Email, &m.Role, &m.InvitedBy, &m.JoinedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *postgresOrganizationRepository) ListMembers(ctx context.Context, orgID uuid.UUID) ([]domain.OrganizationMember, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT m.organization_id, m.user_id, u.email, m.role, m.invited_by, m.joined_at
+		FROM organization_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.organization_id = $1
+		ORDER BY CASE m.role WHEN 'owner' THEN 0 WHEN 'manager' THEN 1 ELSE 2 END, m.joined_at
+	`, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []domain.OrganizationMember{}
+	for rows.Next() {
+		var m domain.OrganizationMember
+		if err := rows.Scan(&m.OrganizationID, &m.UserID, &m.Email, &m.Role, &m.InvitedBy, &m.JoinedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+func (r *postgresOrganizationRepository) AddMember(ctx context.Context, member *domain.OrganizationMember, invitationID uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO organization_members (organization_id, user_id, role, invited_by, joined_at) VALUES ($1, $2, $3, $4, $5)
+	`, member.OrganizationID, member.UserID, member.Role, member.InvitedBy, member.JoinedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE organization_invitations SET accepted_at = $1 WHERE id = $2`, member.JoinedAt, invitationID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *postgresOrganizationRepository) UpdateMemberRole(ctx context.Context, orgID, userID uuid.UUID, role string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE organization_members SET role = $1 WHERE organization_id = $2 AND user_id = $3
+	`, role, orgID, userID)
+	return err
+}
+
+func (r *postgresOrganizationRepository) RemoveMember(ctx context.Context, organization *domain.Organization, userID uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `DELETE FROM organization_members WHERE organization_id = $1 AND user_id = $2`, organization.ID, userID)
+	if err != nil {
+		return err
+	}
+
+	// Nothing the team owns is left with someone who can no longer see it:
+	// listings-service and messaging-service hand the member's work to the owner
+	err = org.RecordEvent(ctx, tx, org.Event{
+		OrganizationID: organization.ID,
+		Type:           org.EventMemberRemoved,
+		UserID:         userID,
+		OwnerID:        organization.OwnerID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *postgresOrganizationRepository) CreateInvitation(ctx context.Context, inv *domain.OrganizationInvitation) error {
+	query := `
+		INSERT INTO organization_invitations (id, organization_id, email, role, token_hash, invited_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.db.Exec(ctx, query,
+		inv.ID, inv.OrganizationID, inv.Email, inv.Role, inv.TokenHash, inv.InvitedBy, inv.ExpiresAt, inv.CreatedAt,
+	)
+	return err
+}
+
+func (r *postgresOrganizationRepository) GetInvitation(ctx context.Context, id uuid.UUID) (*domain.OrganizationInvitation, error) {
+	return r.getInvitation(ctx, `SELECT `+invitationColumns+` FROM organization_invitations WHERE id = $1`, id)
+}
+
+func (r *postgresOrganizationRepository) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*domain.OrganizationInvitation, error) {
+	return r.getInvitation(ctx, `SELECT `+invitationColumns+` FROM organization_invitations WHERE token_hash = $1`, tokenHash)
+}
+
+func (r *postgresOrganizationRepository) GetOpenInvitation(ctx context.Context, orgID uuid.UUID, email string) (*domain.OrganizationInvitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM organization_invitations
+		WHERE organization_id = $1 AND LOWER(email) = LOWER($2) AND accepted_at IS NULL AND revoked_at IS NULL`
+	return r.getInvitation(ctx, query, orgID, email)
+}
+
+func (r *postgresOrganizationRepository) getInvitation(ctx context.Context, query string, args ...interface{}) (*domain.OrganizationInvitation, error) {
+	inv, err := scanInvitation(r.db.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return inv, nil
+}
+
+func (r *postgresOrganizationRepository) ListInvitations(ctx context.Context, orgID uuid.UUID) ([]domain.OrganizationInvitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM organization_invitations WHERE organization_id = $1 ORDER BY created_at DESC`
+	rows, err := r.db.Query(ctx, query, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := []domain.OrganizationInvitation{}
+	for rows.Next() {
+		inv, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, *inv)
+	}
+	return invitations, rows.Err()
+}
+
+func (r *postgresOrganizationRepository) RevokeInvitation(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE organization_invitations SET revoked_at = $1 WHERE id = $2 AND accepted_at IS NULL AND revoked_at IS NULL
+	`, revokedAt, id)
+	return err
+}
@@ -3,7 +3,10 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
 	"github.com/exoticsLanka/auth-service/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -19,59 +22,68 @@ func NewPostgresUserRepository(db *pgxpool.Pool) domain.UserRepository {
 	return &postgresUserRepository{db: db}
 }
 
+// userColumns is shared by every user lookup; email is NULL for accounts registered by phone
+const userColumns = `
+	id, COALESCE(email, ''), password_hash, status, role, email_verified,
+	phone_number, phone_verified, phone_verified_at,
+	suspended_reason, suspended_until, suspended_at, suspended_by,
+	created_at, updated_at, last_login_at, deleted_at
+`
+
+func scanUser(row pgx.Row) (*domain.User, error) {
+	var user domain.User
+	err := row.Scan(
+		&user.ID, &user.Email, &user.PasswordHash, &user.Status, &user.Role, &user.EmailVerified,
+		&user.PhoneNumber, &user.PhoneVerified, &user.PhoneVerifiedAt,
+		&user.SuspendedReason, &user.SuspendedUntil, &user.SuspendedAt, &user.SuspendedBy,
+		&user.CreatedAt, &user.UpdatedAt, &user.LastLoginAt, &user.DeletedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *postgresUserRepository) Create(ctx context.Context, user *domain.User) error {
 	query := `
 		INSERT INTO users (
 			id, email, password_hash, status, role, 
-			email_verified, created_at, updated_at
+			email_verified, phone_number, phone_verified, phone_verified_at,
+			created_at, updated_at
 		) VALUES (
-			$1, $2, $3, $4, $5, 
-			$6, $7, $8
+			$1, NULLIF($2, ''), $3, $4, $5, 
+			$6, $7, $8, $9,
+			$10, $11
 		)
 	`
 	_, err := r.db.Exec(ctx, query,
 		user.ID, user.Email, user.PasswordHash, user.Status, user.Role,
-		user.EmailVerified, user.CreatedAt, user.UpdatedAt,
+		user.EmailVerified, user.PhoneNumber, user.PhoneVerified, user.PhoneVerifiedAt,
+		user.CreatedAt, user.UpdatedAt,
 	)
 	return err
 }
 
 func (r *postgresUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
-	query := `
-		SELECT id, email, password_hash, status, role, email_verified, created_at, updated_at
-		FROM users WHERE id = $1
-	`
-	var user domain.User
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&user.ID, &user.Email, &user.PasswordHash, &user.Status, &user.Role,
-		&user.EmailVerified, &user.CreatedAt, &user.UpdatedAt,
-	)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, err
-	}
-	return &user, nil
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+	return scanUser(r.db.QueryRow(ctx, query, id))
 }
 
 func (r *postgresUserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
-	query := `
-		SELECT id, email, password_hash, status, role, email_verified, created_at, updated_at
-		FROM users WHERE email = $1
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1`
+	return scanUser(r.db.QueryRow(ctx, query, email))
+}
+
+func (r *postgresUserRepository) GetByPhone(ctx context.Context, phone string) (*domain.User, error) {
+	query := `SELECT ` + userColumns + `
+		FROM users WHERE phone_number = $1
+		ORDER BY phone_verified DESC, created_at DESC
+		LIMIT 1
 	`
-	var user domain.User
-	err := r.db.QueryRow(ctx, query, email).Scan(
-		&user.ID, &user.Email, &user.PasswordHash, &user.Status, &user.Role,
-		&user.EmailVerified, &user.CreatedAt, &user.UpdatedAt,
-	)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, err
-	}
-	return &user, nil
+	return scanUser(r.db.QueryRow(ctx, query, phone))
 }
 
 func (r *postgresUserRepository) Update(ctx context.Context, user *domain.User) error {
@@ -79,17 +91,78 @@ func (r *postgresUserRepository) Update(ctx context.Context, user *domain.User)
 		UPDATE users SET 
 			status = $1, role = $2, email_verified = $3, 
 			updated_at = $4, last_login_at = $5, failed_login_attempts = $6,
-			locked_until = $7
-		WHERE id = $8
+			locked_until = $7, phone_number = $8, phone_verified = $9,
+			phone_verified_at = $10, suspended_reason = $11, suspended_until = $12,
+			suspended_at = $13, suspended_by = $14, deleted_at = $15,
+			password_hash = $16
+		WHERE id = $17
 	`
 	_, err := r.db.Exec(ctx, query,
 		user.Status, user.Role, user.EmailVerified,
 		user.UpdatedAt, user.LastLoginAt, user.FailedLoginAttempts,
-		user.LockedUntil, user.ID,
+		user.LockedUntil, user.PhoneNumber, user.PhoneVerified,
+		user.PhoneVerifiedAt, user.SuspendedReason, user.SuspendedUntil,
+		user.SuspendedAt, user.SuspendedBy, user.DeletedAt,
+		user.PasswordHash,
+		user.ID,
 	)
 	return err
 }
 
+func (r *postgresUserRepository) List(ctx context.Context, filter *domain.UserFilter, params pagination.Params) ([]domain.User, int64, error) {
+	where := " WHERE 1=1"
+	args := []interface{}{}
+	argIdx := 1
+
+	if filter.Query != "" {
+		if id, err := uuid.Parse(filter.Query); err == nil {
+			where += fmt.Sprintf(" AND id = $%d", argIdx)
+			args = append(args, id)
+		} else {
+			where += fmt.Sprintf(" AND (email ILIKE $%d OR phone_number ILIKE $%d)", argIdx, argIdx)
+			args = append(args, "%"+filter.Query+"%")
+		}
+		argIdx++
+	}
+	if filter.Role != "" {
+		where += fmt.Sprintf(" AND role = $%d", argIdx)
+		args = append(args, filter.Role)
+		argIdx++
+	}
+	if filter.Status != "" {
+		where += fmt.Sprintf(" AND status = $%d", argIdx)
+		args = append(args, filter.Status)
+		argIdx++
+	} else {
+		where += " AND deleted_at IS NULL"
+	}
+
+	var total int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM users`+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users` + where +
+		fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", argIdx, argIdx+1)
+	args = append(args, params.Limit, params.Offset())
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := []domain.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		users = append(users, *user)
+	}
+	return users, total, rows.Err()
+}
+
 type postgresAuditRepository struct {
 	db *pgxpool.Pool
 }
@@ -102,16 +175,95 @@ func NewPostgresAuditRepository(db *pgxpool.Pool) domain.AuditRepository {
 func (r *postgresAuditRepository) Create(ctx context.Context, log *domain.AuditLog) error {
 	query := `
 		INSERT INTO audit_logs (
-			user_id, event_type, event_category, description, 
-			ip_address, user_agent, success, created_at
+			user_id, event_type, event_category, description, metadata,
+			ip_address, user_agent, success, error_message, created_at
 		) VALUES (
-			$1, $2, $3, $4, 
-			$5, $6, $7, $8
+			$1, $2, $3, $4, $5,
+			$6, $7, $8, $9, $10
 		)
 	`
 	_, err := r.db.Exec(ctx, query,
-		log.UserID, log.EventType, log.EventCategory, log.Description,
-		log.IPAddress, log.UserAgent, log.Success, log.CreatedAt,
+		log.UserID, log.EventType, log.EventCategory, log.Description, log.Metadata,
+		log.IPAddress, log.UserAgent, log.Success, log.ErrorMessage, log.CreatedAt,
 	)
 	return err
 }
+
+func (r *postgresAuditRepository) List(ctx context.Context, filter *domain.AuditFilter) ([]domain.AuditLog, error) {
+	query := `
+		SELECT id, user_id, event_type, event_category, description, metadata,
+			ip_address, user_agent, success, error_message, created_at
+		FROM audit_logs
+		WHERE 1=1
+	`
+	args := []interface{}{}
+	argIdx := 1
+
+	if filter.UserID != nil {
+		query += fmt.Sprintf(" AND user_id = $%d", argIdx)
+		args = append(args, *filter.UserID)
+		argIdx++
+	}
+	if filter.EventType != "" {
+		query += fmt.Sprintf(" AND event_type = $%d", argIdx)
+		args = append(args, filter.EventType)
+		argIdx++
+	}
+	if len(filter.Categories) > 0 {
+		query += fmt.Sprintf(" AND event_category = ANY($%d)", argIdx)
+		args = append(args, filter.Categories)
+		argIdx++
+	}
+	if filter.Success != nil {
+		query += fmt.Sprintf(" AND success = $%d", argIdx)
+		args = append(args, *filter.Success)
+		argIdx++
+	}
+	if filter.IPAddress != "" {
+		query += fmt.Sprintf(" AND ip_address = $%d", argIdx)
+		args = append(args, filter.IPAddress)
+		argIdx++
+	}
+	if filter.From != nil {
+		query += fmt.Sprintf(" AND created_at >= $%d", argIdx)
+		args = append(args, *filter.From)
+		argIdx++
+	}
+	if filter.To != nil {
+		query += fmt.Sprintf(" AND created_at < $%d", argIdx)
+		args = append(args, *filter.To)
+		argIdx++
+	}
+	if cursor := filter.Page.Cursor; cursor != nil {
+		id, err := strconv.ParseInt(cursor.ID, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		query += fmt.Sprintf(" AND (created_at, id) < ($%d, $%d)", argIdx, argIdx+1)
+		args = append(args, cursor.CreatedAt, id)
+		argIdx += 2
+	}
+
+	// One extra row tells the caller whether there is another page
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d", argIdx)
+	args = append(args, filter.Page.Limit+1)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logs := []domain.AuditLog{}
+	for rows.Next() {
+		var log domain.AuditLog
+		if err := rows.Scan(
+			&log.ID, &log.UserID, &log.EventType, &log.EventCategory, &log.Description, &log.Metadata,
+			&log.IPAddress, &log.UserAgent, &log.Success, &log.ErrorMessage, &log.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+	return logs, rows.Err()
+}
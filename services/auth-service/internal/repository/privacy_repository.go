@@ -0,0 +1,386 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresPrivacyRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresPrivacyRepository creates a new personal data export and erasure repository
+func NewPostgresPrivacyRepository(db *pgxpool.Pool) domain.PrivacyRepository {
+	return &postgresPrivacyRepository{db: db}
+}
+
+// exportSources lists where a user's records live. Each query takes the user ID
+// as $1; its rows are written to file as a JSON array. Secrets such as password
+// hashes and 2FA seeds are never selected.
+var exportSources = []struct {
+	file  string
+	table string
+	query string
+}{
+	{"profile.json", "users", `
+		SELECT id, email, status, role, email_verified, email_verified_at, phone_number, phone_verified,
+		       phone_verified_at, two_factor_enabled, oauth_provider, created_at, updated_at, last_login_at
+		FROM users WHERE id = $1`},
+	{"linked_accounts.json", "user_identities", `SELECT provider, email, created_at FROM user_identities WHERE user_id = $1`},
+	{"dealer_applications.json", "dealer_applications", `SELECT * FROM dealer_applications WHERE user_id = $1 ORDER BY created_at`},
+	{"organization.json", "organization_members", `
+		SELECT o.id AS organization_id, o.name, m.role, m.joined_at
+		FROM organization_members m JOIN organizations o ON o.id = m.organization_id
+		WHERE m.user_id = $1`},
+	{"security_activity.json", "audit_logs", `
+		SELECT event_type, event_category, description, metadata, ip_address, user_agent, success, created_at
+		FROM audit_logs WHERE user_id = $1 ORDER BY created_at DESC`},
+	{"listings.json", "car_listings", `SELECT * FROM car_listings WHERE user_id = $1 ORDER BY created_at`},
+	{"favorites.json", "favorites", `SELECT * FROM favorites WHERE user_id = $1 ORDER BY created_at`},
+	{"saved_searches.json", "saved_searches", `SELECT * FROM saved_searches WHERE user_id = $1 ORDER BY created_at`},
+	{"comparison_items.json", "comparison_items", `SELECT * FROM comparison_items WHERE user_id = $1`},
+	{"conversations.json", "conversations", `SELECT * FROM conversations WHERE buyer_id = $1 OR seller_id = $1 ORDER BY created_at`},
+	{"messages.json", "messages", `
+		SELECT m.* FROM messages m
+		JOIN conversations c ON c.id = m.conversation_id
+		WHERE c.buyer_id = $1 OR c.seller_id = $1
+		ORDER BY m.created_at`},
+	{"message_attachments.json", "message_attachments", `
+		SELECT id, conversation_id, message_id, kind, content_type, file_name, size_bytes, created_at
+		FROM message_attachments WHERE uploader_id = $1 ORDER BY created_at`},
+	{"offers.json", "offers", `
+		SELECT * FROM offers
+		WHERE buyer_id = $1 OR seller_id = $1 OR offered_by = $1 OR responded_by = $1
+		ORDER BY created_at`},
+	{"appointments.json", "appointments", `
+		SELECT * FROM appointments
+		WHERE buyer_id = $1 OR seller_id = $1 OR proposed_by = $1 OR cancelled_by = $1
+		ORDER BY created_at`},
+	{"availability.json", "availability_windows", `
+		SELECT weekday, start_time, end_time, created_at FROM availability_windows
+		WHERE owner_id = $1 ORDER BY weekday, start_time`},
+	{"blocked_users.json", "user_blocks", `
+		SELECT blocked_id, conversation_id, created_at FROM user_blocks
+		WHERE owner_id = $1 OR blocked_by = $1 ORDER BY created_at`},
+	{"quick_replies.json", "quick_replies", `
+		SELECT title, content, created_at, updated_at FROM quick_replies WHERE user_id = $1 ORDER BY created_at`},
+	{"auto_reply.json", "auto_replies", `
+		SELECT enabled, mode, message, business_hours, updated_at FROM auto_replies WHERE owner_id = $1`},
+	{"reviews_written.json", "reviews", `SELECT * FROM reviews WHERE buyer_id = $1 ORDER BY created_at`},
+	{"reviews_received.json", "reviews", `SELECT * FROM reviews WHERE seller_id = $1 ORDER BY created_at`},
+	{"review_votes.json", "review_helpful_votes", `SELECT * FROM review_helpful_votes WHERE user_id = $1`},
+	{"contact_inquiries.json", "contact_inquiries", `SELECT * FROM contact_inquiries WHERE user_id = $1 ORDER BY created_at`},
+	{"listing_views.json", "listing_views", `SELECT * FROM listing_views WHERE user_id = $1 ORDER BY created_at`},
+	{"listing_reports.json", "listing_reports", `SELECT * FROM listing_reports WHERE reporter_id = $1 ORDER BY created_at`},
+	{"notification_preferences.json", "notification_preferences", `SELECT * FROM notification_preferences WHERE user_id = $1`},
+	{"notification_logs.json", "notification_logs", `SELECT * FROM notification_logs WHERE user_id = $1 ORDER BY created_at`},
+	{"notification_outbox.json", "notification_outbox", `
+		SELECT type, channel, data, source, status, created_at, sent_at
+		FROM notification_outbox WHERE user_id = $1 ORDER BY created_at`},
+}
+
+// erasureSteps run in order inside one transaction. Each takes the user ID as
+// $1. Reviews, conversations, messages, offers and appointments are kept for
+// the other party and still point at the account, whose users row is scrubbed
+// and marked deleted rather than removed, so other services' joins keep
+// working and show a deleted user. Message text is kept too, as the other
+// party's record of the conversation.
+var erasureSteps = []struct {
+	table string
+	query string
+}{
+	{"review_helpful_votes", `DELETE FROM review_helpful_votes WHERE user_id = $1`},
+	// Attachment files are deleted from storage by image-service once queued here
+	{"attachment_deletions", `
+		INSERT INTO attachment_deletions (storage_key)
+		SELECT k.key FROM message_attachments a
+		CROSS JOIN LATERAL (VALUES (a.storage_key), (a.thumbnail_key)) AS k(key)
+		WHERE a.uploader_id = $1 AND k.key IS NOT NULL
+		ON CONFLICT DO NOTHING`},
+	{"message_attachments", `DELETE FROM message_attachments WHERE uploader_id = $1`},
+	{"favorites", `DELETE FROM favorites WHERE user_id = $1`},
+	{"saved_searches", `DELETE FROM saved_searches WHERE user_id = $1`},
+	{"comparison_items", `DELETE FROM comparison_items WHERE user_id = $1`},
+	// A member's assigned work goes back to the owner; an owner's team is dissolved
+	// and its listings and leads stay with whoever created them
+	{"car_listings", `
+		UPDATE car_listings cl SET assigned_to = o.owner_id
+		FROM organizations o
+		WHERE cl.organization_id = o.id AND cl.assigned_to = $1 AND o.owner_id <> $1`},
+	{"conversations", `
+		UPDATE conversations c SET assigned_to = o.owner_id
+		FROM organizations o
+		WHERE c.organization_id = o.id AND c.assigned_to = $1 AND o.owner_id <> $1`},
+	{"car_listings", `
+		UPDATE car_listings SET organization_id = NULL, assigned_to = NULL
+		WHERE organization_id IN (SELECT id FROM organizations WHERE owner_id = $1)`},
+	{"conversations", `
+		UPDATE conversations SET organization_id = NULL, assigned_to = NULL
+		WHERE organization_id IN (SELECT id FROM organizations WHERE owner_id = $1)`},
+	// Blocks made for a team stay with the organisation
+	{"user_blocks", `
+		DELETE FROM user_blocks
+		WHERE owner_id = $1 OR blocked_id = $1 OR owner_id IN (SELECT id FROM organizations WHERE owner_id = $1)`},
+	{"quick_replies", `DELETE FROM quick_replies WHERE user_id = $1`},
+	{"auto_replies", `
+		DELETE FROM auto_replies
+		WHERE owner_id = $1 OR owner_id IN (SELECT id FROM organizations WHERE owner_id = $1)`},
+	{"auto_replies", `UPDATE auto_replies SET updated_by = NULL WHERE updated_by = $1`},
+	{"availability_windows", `
+		DELETE FROM availability_windows
+		WHERE owner_id = $1 OR owner_id IN (SELECT id FROM organizations WHERE owner_id = $1)`},
+	{"organization_events", `DELETE FROM organization_events WHERE user_id = $1`},
+	{"organizations", `DELETE FROM organizations WHERE owner_id = $1`},
+	{"organization_members", `DELETE FROM organization_members WHERE user_id = $1`},
+	{"organization_invitations", `
+		DELETE FROM organization_invitations
+		WHERE LOWER(email) = (SELECT LOWER(email) FROM users WHERE id = $1)`},
+	{"car_listings", `
+		UPDATE car_listings
+		SET status = CASE WHEN status IN ('draft', 'pending', 'active') THEN 'expired' ELSE status END,
+		    is_verified = FALSE, contact_phone = NULL, contact_email = NULL, updated_at = NOW()
+		WHERE user_id = $1 AND organization_id IS NULL`},
+	{"attachment_deletions", `
+		INSERT INTO attachment_deletions (storage_key)
+		SELECT d->>'key' FROM dealer_applications a
+		CROSS JOIN LATERAL jsonb_array_elements(a.documents) AS d
+		WHERE a.user_id = $1 AND d->>'key' IS NOT NULL
+		ON CONFLICT DO NOTHING`},
+	// Applications stay for the review history, without the business details,
+	// and an approved one no longer verifies the account
+	{"dealer_applications", `
+		UPDATE dealer_applications
+		SET business_name = 'Deleted user', registration_number = '', business_address = '', contact_phone = '',
+		    website = NULL, documents = '[]',
+		    status = CASE WHEN status IN ('pending', 'approved') THEN 'revoked' ELSE status END, updated_at = NOW()
+		WHERE user_id = $1`},
+	{"contact_inquiries", `
+		UPDATE contact_inquiries
+		SET user_id = NULL, name = 'Deleted user', email = '', phone = NULL, ip_address = NULL, user_agent = NULL
+		WHERE user_id = $1`},
+	{"listing_views", `UPDATE listing_views SET user_id = NULL, ip_address = NULL, user_agent = NULL WHERE user_id = $1`},
+	{"listing_reports", `UPDATE listing_reports SET reporter_id = NULL WHERE reporter_id = $1`},
+	{"notification_preferences", `DELETE FROM notification_preferences WHERE user_id = $1`},
+	{"notification_logs", `DELETE FROM notification_logs WHERE user_id = $1`},
+	{"notification_outbox", `DELETE FROM notification_outbox WHERE user_id = $1`},
+	{"user_identities", `DELETE FROM user_identities WHERE user_id = $1`},
+	{"sessions", `DELETE FROM sessions WHERE user_id = $1`},
+	{"data_exports", `DELETE FROM data_exports WHERE user_id = $1`},
+	{"api_keys", `DELETE FROM api_keys WHERE user_id = $1`},
+	// Security events are kept, without the network details
+	{"audit_logs", `UPDATE audit_logs SET ip_address = NULL, user_agent = NULL WHERE user_id = $1`},
+	// The row itself stays so references from other tables still resolve to a deleted account
+	{"users", `
+		UPDATE users
+		SET email = NULL, password_hash = '', phone_number = NULL, phone_verified = FALSE, phone_verified_at = NULL,
+		    two_factor_enabled = FALSE, two_factor_secret = NULL, oauth_provider = NULL, oauth_id = NULL,
+		    suspended_reason = NULL, status = 'deleted', deleted_at = COALESCE(deleted_at, NOW()),
+		    erasure_requested_at = NULL, erasure_scheduled_for = NULL, updated_at = NOW()
+		WHERE id = $1`},
+}
+
+const dataExportColumns = `id, user_id, status, file_path, file_size, error_message, created_at, completed_at, expires_at`
+
+func scanDataExport(row pgx.Row) (*domain.DataExport, error) {
+	var export domain.DataExport
+	err := row.Scan(
+		&export.ID, &export.UserID, &export.Status, &export.FilePath, &export.FileSize, &export.ErrorMessage,
+		&export.CreatedAt, &export.CompletedAt, &export.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *postgresPrivacyRepository) CreateExport(ctx context.Context, export *domain.DataExport) error {
+	query := `INSERT INTO data_exports (id, user_id, status, created_at) VALUES ($1, $2, $3, $4)`
+	_, err := r.db.Exec(ctx, query, export.ID, export.UserID, export.Status, export.CreatedAt)
+	return err
+}
+
+func (r *postgresPrivacyRepository) UpdateExport(ctx context.Context, export *domain.DataExport) error {
+	query := `
+		UPDATE data_exports
+		SET status = $2, file_path = $3, file_size = $4, error_message = $5, completed_at = $6, expires_at = $7
+		WHERE id = $1
+	`
+	_, err := r.db.Exec(ctx, query,
+		export.ID, export.Status, export.FilePath, export.FileSize, export.ErrorMessage, export.CompletedAt, export.ExpiresAt,
+	)
+	return err
+}
+
+func (r *postgresPrivacyRepository) GetExport(ctx context.Context, id uuid.UUID) (*domain.DataExport, error) {
+	query := `SELECT ` + dataExportColumns + ` FROM data_exports WHERE id = $1`
+	export, err := scanDataExport(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return export, nil
+}
+
+func (r *postgresPrivacyRepository) GetActiveExport(ctx context.Context, userID uuid.UUID) (*domain.DataExport, error) {
+	query := `SELECT ` + dataExportColumns + `
+		FROM data_exports
+		WHERE user_id = $1 AND status IN ('pending', 'processing')
+		ORDER BY created_at DESC LIMIT 1
+	`
+	export, err := scanDataExport(r.db.QueryRow(ctx, query, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return export, nil
+}
+
+func (r *postgresPrivacyRepository) ListExports(ctx context.Context, userID uuid.UUID) ([]domain.DataExport, error) {
+	query := `SELECT ` + dataExportColumns + ` FROM data_exports WHERE user_id = $1 ORDER BY created_at DESC`
+	return r.listExports(ctx, query, userID)
+}
+
+func (r *postgresPrivacyRepository) ListExpiredExports(ctx context.Context, now time.Time) ([]domain.DataExport, error) {
+	query := `SELECT ` + dataExportColumns + ` FROM data_exports WHERE status = 'ready' AND expires_at < $1`
+	return r.listExports(ctx, query, now)
+}
+
+func (r *postgresPrivacyRepository) listExports(ctx context.Context, query string, args ...interface{}) ([]domain.DataExport, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exports := []domain.DataExport{}
+	for rows.Next() {
+		export, err := scanDataExport(rows)
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, *export)
+	}
+	return exports, rows.Err()
+}
+
+func (r *postgresPrivacyRepository) FailStaleExports(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `
+		UPDATE data_exports
+		SET status = 'failed', error_message = 'export was interrupted', completed_at = NOW()
+		WHERE status IN ('pending', 'processing') AND created_at < $1
+	`
+	tag, err := r.db.Exec(ctx, query, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *postgresPrivacyRepository) CollectUserData(ctx context.Context, userID uuid.UUID) (map[string]json.RawMessage, error) {
+	data := make(map[string]json.RawMessage, len(exportSources))
+	for _, src := range exportSources {
+		exists, err := tableExists(ctx, r.db, src.table)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+
+		var rows []byte
+		query := `SELECT COALESCE(json_agg(t), '[]'::json) FROM (` + src.query + `) t`
+		if err := r.db.QueryRow(ctx, query, userID).Scan(&rows); err != nil {
+			return nil, fmt.Errorf("export %s: %w", src.table, err)
+		}
+		data[src.file] = rows
+	}
+	return data, nil
+}
+
+func (r *postgresPrivacyRepository) GetErasure(ctx context.Context, userID uuid.UUID) (*domain.ErasureStatus, error) {
+	var status domain.ErasureStatus
+	query := `SELECT erasure_requested_at, erasure_scheduled_for FROM users WHERE id = $1`
+	if err := r.db.QueryRow(ctx, query, userID).Scan(&status.RequestedAt, &status.ScheduledFor); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (r *postgresPrivacyRepository) ScheduleErasure(ctx context.Context, userID uuid.UUID, requestedAt, scheduledFor time.Time) error {
+	query := `UPDATE users SET erasure_requested_at = $2, erasure_scheduled_for = $3, updated_at = NOW() WHERE id = $1`
+	_, err := r.db.Exec(ctx, query, userID, requestedAt, scheduledFor)
+	return err
+}
+
+func (r *postgresPrivacyRepository) CancelErasure(ctx context.Context, userID uuid.UUID) error {
+	query := `UPDATE users SET erasure_requested_at = NULL, erasure_scheduled_for = NULL, updated_at = NOW() WHERE id = $1`
+	_, err := r.db.Exec(ctx, query, userID)
+	return err
+}
+
+func (r *postgresPrivacyRepository) ListDueErasures(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	query := `SELECT id FROM users WHERE erasure_scheduled_for <= $1 ORDER BY erasure_scheduled_for`
+	rows, err := r.db.Query(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *postgresPrivacyRepository) EraseUser(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, step := range erasureSteps {
+		exists, err := tableExists(ctx, tx, step.table)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+
+		if _, err := tx.Exec(ctx, step.query, userID); err != nil {
+			return fmt.Errorf("erase %s: %w", step.table, err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// tableExists reports whether another service has created table in the shared database yet
+func tableExists(ctx context.Context, db interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}, table string) (bool, error) {
+	var exists bool
+	err := db.QueryRow(ctx, `SELECT to_regclass($1) IS NOT NULL`, table).Scan(&exists)
+	return exists, err
+}
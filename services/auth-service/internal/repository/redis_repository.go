@@ -104,3 +104,257 @@ func (r *redisSessionRepository) DeleteByUserID(ctx context.Context, userID uuid
 	_, err = pipe.Exec(ctx)
 	return err
 }
+
+type redisRefreshTokenRepository struct {
+	client *redis.Client
+}
+
+// NewRedisRefreshTokenRepository creates a new refresh token family repository
+func NewRedisRefreshTokenRepository(client *redis.Client) domain.RefreshTokenRepository {
+	return &redisRefreshTokenRepository{client: client}
+}
+
+func refreshFamilyKey(familyID string) string {
+	return fmt.Sprintf("refresh_family:%s", familyID)
+}
+
+func userRefreshFamiliesKey(userID uuid.UUID) string {
+	return fmt.Sprintf("user_refresh_families:%s", userID.String())
+}
+
+func (r *redisRefreshTokenRepository) CreateFamily(ctx context.Context, family *domain.RefreshTokenFamily) error {
+	data, err := json.Marshal(family)
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Until(family.ExpiresAt)
+	userKey := userRefreshFamiliesKey(family.UserID)
+
+	pipe := r.client.Pipeline()
+	pipe.Set(ctx, refreshFamilyKey(family.ID), data, ttl)
+	pipe.SAdd(ctx, userKey, family.ID)
+	pipe.Expire(ctx, userKey, ttl)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (r *redisRefreshTokenRepository) GetFamily(ctx context.Context, familyID string) (*domain.RefreshTokenFamily, error) {
+	data, err := r.client.Get(ctx, refreshFamilyKey(familyID)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var family domain.RefreshTokenFamily
+	if err := json.Unmarshal(data, &family); err != nil {
+		return nil, err
+	}
+	return &family, nil
+}
+
+func (r *redisRefreshTokenRepository) Rotate(ctx context.Context, familyID, currentJTI, nextJTI string) error {
+	key := refreshFamilyKey(familyID)
+
+	err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+		data, err := tx.Get(ctx, key).Bytes()
+		if err != nil {
+			if err == redis.Nil {
+				return domain.ErrRefreshTokenRevoked
+			}
+			return err
+		}
+
+		var family domain.RefreshTokenFamily
+		if err := json.Unmarshal(data, &family); err != nil {
+			return err
+		}
+		if family.Revoked {
+			return domain.ErrRefreshTokenRevoked
+		}
+		if family.CurrentJTI != currentJTI {
+			return domain.ErrRefreshTokenReused
+		}
+
+		family.CurrentJTI = nextJTI
+		family.RotatedAt = time.Now()
+		updated, err := json.Marshal(&family)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, updated, redis.KeepTTL)
+			return nil
+		})
+		return err
+	}, key)
+
+	// Another request rotated the same token between our read and write,
+	// which means the token was presented twice.
+	if err == redis.TxFailedErr {
+		return domain.ErrRefreshTokenReused
+	}
+	return err
+}
+
+func (r *redisRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	family, err := r.GetFamily(ctx, familyID)
+	if err != nil {
+		return err
+	}
+	if family == nil || family.Revoked {
+		return nil
+	}
+
+	// Keep the record (with its TTL) so later presentations of any token
+	// from this family are still recognised as revoked.
+	family.Revoked = true
+	data, err := json.Marshal(family)
+	if err != nil {
+		return err
+	}
+	return r.client.Set(ctx, refreshFamilyKey(familyID), data, redis.KeepTTL).Err()
+}
+
+func (r *redisRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	familyIDs, err := r.client.SMembers(ctx, userRefreshFamiliesKey(userID)).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, familyID := range familyIDs {
+		if err := r.RevokeFamily(ctx, familyID); err != nil {
+			return err
+		}
+	}
+
+	return r.client.Del(ctx, userRefreshFamiliesKey(userID)).Err()
+}
+
+type redisOAuthStateRepository struct {
+	client *redis.Client
+}
+
+// NewRedisOAuthStateRepository creates a new OAuth state repository
+func NewRedisOAuthStateRepository(client *redis.Client) domain.OAuthStateRepository {
+	return &redisOAuthStateRepository{client: client}
+}
+
+func (r *redisOAuthStateRepository) Save(ctx context.Context, state string, data *domain.OAuthState, ttl time.Duration) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return r.client.Set(ctx, fmt.Sprintf("oauth_state:%s", state), payload, ttl).Err()
+}
+
+func (r *redisOAuthStateRepository) Consume(ctx context.Context, state string) (*domain.OAuthState, error) {
+	payload, err := r.client.GetDel(ctx, fmt.Sprintf("oauth_state:%s", state)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var data domain.OAuthState
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+type redisOTPRepository struct {
+	client *redis.Client
+}
+
+// NewRedisOTPRepository creates a new one-time code repository
+func NewRedisOTPRepository(client *redis.Client) domain.OTPRepository {
+	return &redisOTPRepository{client: client}
+}
+
+func otpKey(purpose, phone string) string {
+	return fmt.Sprintf("phone_otp:%s:%s", purpose, phone)
+}
+
+func (r *redisOTPRepository) Save(ctx context.Context, purpose, phone string, otp *domain.OTP, ttl time.Duration) error {
+	key := otpKey(purpose, phone)
+	pipe := r.client.TxPipeline()
+	// A new code replaces the previous one and resets its attempt counter
+	pipe.Del(ctx, key)
+	pipe.HSet(ctx, key, "user_id", otp.UserID.String(), "code_hash", otp.CodeHash, "attempts", otp.Attempts)
+	pipe.Expire(ctx, key, ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// checkOTPScript consumes the code on a match and otherwise counts the wrong
+// guess, so concurrent guesses can't use the same attempt or the same code.
+// It returns {} if no code is pending, else {user_id, valid, attempts}.
+var checkOTPScript = redis.NewScript(`
+local otp = redis.call('HMGET', KEYS[1], 'user_id', 'code_hash', 'attempts')
+if not otp[1] then
+	return {}
+end
+local max = tonumber(ARGV[2])
+local attempts = tonumber(otp[3]) or 0
+if attempts >= max then
+	redis.call('DEL', KEYS[1])
+	return {otp[1], 0, attempts}
+end
+if otp[2] == ARGV[1] then
+	redis.call('DEL', KEYS[1])
+	return {otp[1], 1, attempts}
+end
+attempts = redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+if attempts >= max then
+	redis.call('DEL', KEYS[1])
+end
+return {otp[1], 0, attempts}
+`)
+
+func (r *redisOTPRepository) Check(ctx context.Context, purpose, phone, codeHash string, maxAttempts int) (*domain.OTPCheck, error) {
+	result, err := checkOTPScript.Run(ctx, r.client, []string{otpKey(purpose, phone)}, codeHash, maxAttempts).Slice()
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+
+	userID, err := uuid.Parse(fmt.Sprint(result[0]))
+	if err != nil {
+		return nil, err
+	}
+	valid, _ := result[1].(int64)
+	attempts, _ := result[2].(int64)
+
+	return &domain.OTPCheck{
+		UserID:   userID,
+		Valid:    valid == 1,
+		Attempts: int(attempts),
+	}, nil
+}
+
+func (r *redisOTPRepository) Delete(ctx context.Context, purpose, phone string) error {
+	return r.client.Del(ctx, otpKey(purpose, phone)).Err()
+}
+
+func (r *redisOTPRepository) AcquireCooldown(ctx context.Context, phone string, cooldown time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, fmt.Sprintf("phone_otp_cooldown:%s", phone), 1, cooldown).Result()
+}
+
+func (r *redisOTPRepository) CountSend(ctx context.Context, ipAddress string, window time.Duration) (int, error) {
+	key := fmt.Sprintf("phone_otp_sends:%s", ipAddress)
+	pipe := r.client.TxPipeline()
+	count := pipe.Incr(ctx, key)
+	// Only the first send of a window sets its expiry
+	pipe.ExpireNX(ctx, key, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return int(count.Val()), nil
+}
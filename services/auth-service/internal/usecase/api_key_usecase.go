@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/audit"
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxActiveAPIKeys caps how many unrevoked, unexpired keys a dealer can hold
+	maxActiveAPIKeys = 10
+	// defaultAPIKeyTTL applies when a key is created without an expiry
+	defaultAPIKeyTTL = 365 * 24 * time.Hour
+)
+
+type apiKeyUseCase struct {
+	apiKeyRepo domain.APIKeyRepository
+	userRepo   domain.UserRepository
+	auditRepo  domain.AuditRepository
+}
+
+// NewAPIKeyUseCase creates a new dealer API key use case
+func NewAPIKeyUseCase(
+	apiKeyRepo domain.APIKeyRepository,
+	userRepo domain.UserRepository,
+	auditRepo domain.AuditRepository,
+) domain.APIKeyUseCase {
+	return &apiKeyUseCase{
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+		auditRepo:  auditRepo,
+	}
+}
+
+func (u *apiKeyUseCase) Create(ctx context.Context, req *domain.CreateAPIKeyRequest) (*domain.CreatedAPIKey, error) {
+	// 1. Only verified dealers integrate their own systems
+	if err := u.requireDealer(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Enforce the active key limit
+	now := time.Now()
+	active, err := u.apiKeyRepo.CountActive(ctx, req.UserID, now)
+	if err != nil {
+		return nil, err
+	}
+	if active >= maxActiveAPIKeys {
+		return nil, domain.ErrAPIKeyLimitReached
+	}
+
+	// 3. Generate and store the key; only its hash is kept
+	plain, prefix, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := now.Add(defaultAPIKeyTTL)
+	if req.ExpiresInDays != nil {
+		expiresAt = now.AddDate(0, 0, *req.ExpiresInDays)
+	}
+
+	key := &domain.APIKey{
+		ID:        uuid.New(),
+		UserID:    req.UserID,
+		Name:      req.Name,
+		Prefix:    prefix,
+		KeyHash:   auth.HashAPIKey(plain),
+		Scopes:    scopes,
+		ExpiresAt: &expiresAt,
+		CreatedAt: now,
+	}
+	if err := u.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	u.logEvent(ctx, domain.EventAPIKeyCreated, req.UserID, req.IPAddress, req.UserAgent, map[string]interface{}{
+		"api_key_id": key.ID.String(),
+		"prefix":     key.Prefix,
+		"scopes":     key.Scopes,
+	})
+
+	return &domain.CreatedAPIKey{APIKey: *key, Key: plain}, nil
+}
+
+func (u *apiKeyUseCase) List(ctx context.Context, userID uuid.UUID) ([]domain.APIKey, error) {
+	return u.apiKeyRepo.ListByUserID(ctx, userID)
+}
+
+func (u *apiKeyUseCase) Revoke(ctx context.Context, req *domain.RevokeAPIKeyRequest) error {
+	key, err := u.apiKeyRepo.GetByID(ctx, req.KeyID)
+	if err != nil {
+		return err
+	}
+	if key == nil || key.UserID != req.UserID {
+		return domain.ErrAPIKeyNotFound
+	}
+	// Revoking twice is harmless
+	if key.RevokedAt != nil {
+		return nil
+	}
+
+	if err := u.apiKeyRepo.Revoke(ctx, key.ID, time.Now()); err != nil {
+		return err
+	}
+
+	u.logEvent(ctx, domain.EventAPIKeyRevoked, req.UserID, req.IPAddress, req.UserAgent, map[string]interface{}{
+		"api_key_id": key.ID.String(),
+		"prefix":     key.Prefix,
+	})
+	return nil
+}
+
+func (u *apiKeyUseCase) requireDealer(ctx context.Context, userID uuid.UUID) error {
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return domain.ErrUserNotFound
+	}
+	if user.Role != auth.RoleDealer {
+		return domain.ErrAPIKeysDealerOnly
+	}
+	return nil
+}
+
+// normalizeScopes rejects unknown scopes and drops duplicates
+func normalizeScopes(requested []string) ([]string, error) {
+	known := make(map[string]bool, len(auth.Scopes))
+	for _, scope := range auth.Scopes {
+		known[scope] = true
+	}
+
+	seen := make(map[string]bool, len(requested))
+	scopes := make([]string, 0, len(requested))
+	for _, scope := range requested {
+		if !known[scope] {
+			return nil, domain.ErrInvalidAPIKeyScope
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+func (u *apiKeyUseCase) logEvent(ctx context.Context, eventType string, userID uuid.UUID, ip, ua string, metadata map[string]interface{}) {
+	_ = u.auditRepo.Create(ctx, &domain.AuditLog{
+		UserID:        &userID,
+		EventType:     eventType,
+		EventCategory: audit.CategorySecurity,
+		Metadata:      metadata,
+		IPAddress:     &ip,
+		UserAgent:     &ua,
+		Success:       true,
+		CreatedAt:     time.Now(),
+	})
+}
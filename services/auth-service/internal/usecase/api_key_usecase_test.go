@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/exoticsLanka/auth-service/internal/domain"
+)
+
+func TestNormalizeScopes(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested []string
+		want      []string
+		wantErr   error
+	}{
+		{"single", []string{auth.ScopeListingsRead}, []string{auth.ScopeListingsRead}, nil},
+		{"all", auth.Scopes, auth.Scopes, nil},
+		{"duplicates dropped in order", []string{auth.ScopeLeadsRead, auth.ScopeListingsRead, auth.ScopeLeadsRead}, []string{auth.ScopeLeadsRead, auth.ScopeListingsRead}, nil},
+		{"none", nil, []string{}, nil},
+		{"unknown", []string{auth.ScopeListingsRead, "listings:delete"}, nil, domain.ErrInvalidAPIKeyScope},
+		{"case sensitive", []string{"Listings:Read"}, nil, domain.ErrInvalidAPIKeyScope},
+		{"empty scope", []string{""}, nil, domain.ErrInvalidAPIKeyScope},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeScopes(tt.requested)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("normalizeScopes(%v) error = %v, want %v", tt.requested, err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("normalizeScopes(%v) = %v, want %v", tt.requested, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/audit"
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+// auditExportMaxRows caps a CSV export; narrow the time range to export more
+const auditExportMaxRows = 50000
+
+// securityActivityCategories are the events a user is shown about their own account
+var securityActivityCategories = []string{
+	audit.CategoryAuthentication,
+	audit.CategorySecurity,
+	audit.CategoryAccountManagement,
+	audit.CategoryImpersonation,
+}
+
+type auditUseCase struct {
+	auditRepo domain.AuditRepository
+}
+
+// NewAuditUseCase creates a new audit log use case
+func NewAuditUseCase(auditRepo domain.AuditRepository) domain.AuditUseCase {
+	return &auditUseCase{auditRepo: auditRepo}
+}
+
+func (u *auditUseCase) List(ctx context.Context, filter *domain.AuditFilter) ([]domain.AuditLog, pagination.CursorPage, error) {
+	logs, err := u.auditRepo.List(ctx, filter)
+	if err != nil {
+		return nil, pagination.CursorPage{}, err
+	}
+
+	page, n := pagination.NewCursorPage(filter.Page, len(logs), func(i int) (time.Time, string) {
+		return logs[i].CreatedAt, strconv.FormatInt(logs[i].ID, 10)
+	})
+	return logs[:n], page, nil
+}
+
+func (u *auditUseCase) ExportCSV(ctx context.Context, filter *domain.AuditFilter, w io.Writer) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{
+		"id", "created_at", "user_id", "event_type", "event_category", "success",
+		"ip_address", "user_agent", "description", "error_message", "metadata",
+	}); err != nil {
+		return err
+	}
+
+	// Walk the result set a page at a time so large exports are streamed
+	export := *filter
+	export.Page = pagination.CursorParams{Limit: pagination.MaxLimit}
+	for written := 0; written < auditExportMaxRows; {
+		logs, page, err := u.List(ctx, &export)
+		if err != nil {
+			return err
+		}
+
+		for _, log := range logs {
+			if err := out.Write(auditCSVRecord(&log)); err != nil {
+				return err
+			}
+		}
+		written += len(logs)
+		out.Flush()
+		if err := out.Error(); err != nil {
+			return err
+		}
+
+		if !page.HasMore {
+			break
+		}
+		export.Page.Cursor, _ = pagination.DecodeCursor(page.NextCursor)
+	}
+
+	return nil
+}
+
+func (u *auditUseCase) SecurityActivity(ctx context.Context, userID uuid.UUID, page pagination.CursorParams) ([]domain.AuditLog, pagination.CursorPage, error) {
+	return u.List(ctx, &domain.AuditFilter{
+		UserID:     &userID,
+		Categories: securityActivityCategories,
+		Page:       page,
+	})
+}
+
+func auditCSVRecord(log *domain.AuditLog) []string {
+	userID := ""
+	if log.UserID != nil {
+		userID = log.UserID.String()
+	}
+	metadata := ""
+	if len(log.Metadata) > 0 {
+		if b, err := json.Marshal(log.Metadata); err == nil {
+			metadata = string(b)
+		}
+	}
+
+	record := []string{
+		strconv.FormatInt(log.ID, 10),
+		log.CreatedAt.UTC().Format(time.RFC3339),
+		userID,
+		log.EventType,
+		log.EventCategory,
+		strconv.FormatBool(log.Success),
+		stringValue(log.IPAddress),
+		stringValue(log.UserAgent),
+		stringValue(log.Description),
+		stringValue(log.ErrorMessage),
+		metadata,
+	}
+	for i, field := range record {
+		record[i] = csvSafe(field)
+	}
+	return record
+}
+
+// csvSafe stops spreadsheet apps from evaluating client-supplied values such as
+// user agents as formulas.
+func csvSafe(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
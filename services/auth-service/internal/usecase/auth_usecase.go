@@ -5,36 +5,53 @@ import (
 	"errors"
 	"time"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/audit"
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
 	"github.com/exoticsLanka/auth-service/internal/config"
 	"github.com/exoticsLanka/auth-service/internal/domain"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/exoticsLanka/auth-service/internal/keystore"
+	"github.com/exoticsLanka/auth-service/internal/password"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type authUseCase struct {
-	userRepo    domain.UserRepository
-	sessionRepo domain.SessionRepository
-	auditRepo   domain.AuditRepository
-	cfg         *config.Config
+	*tokenIssuer
+	userRepo       domain.UserRepository
+	auditRepo      domain.AuditRepository
+	passwordPolicy *password.Policy
+	passwordHasher *password.Hasher
 }
 
 // NewAuthUseCase creates a new auth use case
 func NewAuthUseCase(
 	userRepo domain.UserRepository,
 	sessionRepo domain.SessionRepository,
+	refreshRepo domain.RefreshTokenRepository,
 	auditRepo domain.AuditRepository,
+	passwordPolicy *password.Policy,
+	keys *keystore.KeyStore,
 	cfg *config.Config,
 ) domain.AuthUseCase {
 	return &authUseCase{
-		userRepo:    userRepo,
-		sessionRepo: sessionRepo,
-		auditRepo:   auditRepo,
-		cfg:         cfg,
+		tokenIssuer:    newTokenIssuer(sessionRepo, refreshRepo, keys, cfg),
+		userRepo:       userRepo,
+		auditRepo:      auditRepo,
+		passwordPolicy: passwordPolicy,
+		passwordHasher: password.NewHasher(cfg.BcryptCost),
 	}
 }
 
 func (u *authUseCase) Register(ctx context.Context, req *domain.RegisterRequest) (*domain.RegisterResponse, error) {
+	// Only buyer and seller accounts are self-service; dealers are verified
+	// through the dealer application flow and admins are appointed
+	if req.Role == "" {
+		req.Role = auth.RoleBuyer
+	}
+	if req.Role != auth.RoleBuyer && req.Role != auth.RoleSeller {
+		return nil, domain.ErrRoleNotAllowed
+	}
+
 	// 1. Check if user exists
 	existingUser, err := u.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
@@ -44,8 +61,11 @@ func (u *authUseCase) Register(ctx context.Context, req *domain.RegisterRequest)
 		return nil, errors.New("email already registered")
 	}
 
-	// 2. Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	// 2. Check and hash password
+	if err := u.passwordPolicy.Validate(req.Password, req.Email); err != nil {
+		return nil, err
+	}
+	hashedPassword, err := u.passwordHasher.Hash(req.Password)
 	if err != nil {
 		return nil, err
 	}
@@ -56,15 +76,12 @@ func (u *authUseCase) Register(ctx context.Context, req *domain.RegisterRequest)
 	user := &domain.User{
 		ID:           userID,
 		Email:        req.Email,
-		PasswordHash: string(hashedPassword),
+		PasswordHash: hashedPassword,
 		Role:         req.Role,
 		Status:       "pending",
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
-	if user.Role == "" {
-		user.Role = "buyer"
-	}
 
 	if err := u.userRepo.Create(ctx, user); err != nil {
 		return nil, err
@@ -73,8 +90,8 @@ func (u *authUseCase) Register(ctx context.Context, req *domain.RegisterRequest)
 	// 4. Log audit
 	_ = u.auditRepo.Create(ctx, &domain.AuditLog{
 		UserID:        &userID,
-		EventType:     "account_created",
-		EventCategory: "account_management",
+		EventType:     domain.EventAccountCreated,
+		EventCategory: audit.CategoryAccountManagement,
 		Description:   nil, // Optional: add description
 		IPAddress:     &req.IPAddress,
 		UserAgent:     &req.UserAgent,
@@ -97,6 +114,20 @@ func (u *authUseCase) Login(ctx context.Context, req *domain.LoginRequest) (*dom
 		return nil, err
 	}
 	if user == nil {
+		// Unknown accounts are recorded too, so credential stuffing shows up per IP
+		now := time.Now()
+		_ = u.auditRepo.Create(ctx, &domain.AuditLog{
+			EventType:     domain.EventLoginFailed,
+			EventCategory: audit.CategoryAuthentication,
+			Metadata: map[string]interface{}{
+				"email": req.Email,
+			},
+			IPAddress:    &req.IPAddress,
+			UserAgent:    &req.UserAgent,
+			Success:      false,
+			ErrorMessage: &errStrInvalidCredentials,
+			CreatedAt:    now,
+		})
 		return nil, errors.New("invalid credentials")
 	}
 
@@ -106,8 +137,8 @@ func (u *authUseCase) Login(ctx context.Context, req *domain.LoginRequest) (*dom
 		now := time.Now()
 		_ = u.auditRepo.Create(ctx, &domain.AuditLog{
 			UserID:        &user.ID,
-			EventType:     "login_failed",
-			EventCategory: "authentication",
+			EventType:     domain.EventLoginFailed,
+			EventCategory: audit.CategoryAuthentication,
 			IPAddress:     &req.IPAddress,
 			UserAgent:     &req.UserAgent,
 			Success:       false,
@@ -117,116 +148,89 @@ func (u *authUseCase) Login(ctx context.Context, req *domain.LoginRequest) (*dom
 		return nil, errors.New("invalid credentials")
 	}
 
-	// 3. Generate Tokens
-	accessToken, err := u.generateToken(user, 15*time.Minute)
-	if err != nil {
-		return nil, err
-	}
-	refreshToken, err := u.generateRefreshToken(user)
+	// 3. Start a new refresh token family, generate tokens and create session
+	resp, err := u.login(ctx, user, &req.IPAddress, &req.UserAgent)
 	if err != nil {
 		return nil, err
 	}
 
-	// 4. Create Session
-	session := &domain.Session{
-		ID:             uuid.New(),
-		UserID:         user.ID,
-		Token:          accessToken,
-		RefreshToken:   &refreshToken,
-		IPAddress:      &req.IPAddress,
-		UserAgent:      &req.UserAgent,
-		IsActive:       true,
-		ExpiresAt:      time.Now().Add(15 * time.Minute),
-		LastActivityAt: time.Now(),
-		CreatedAt:      time.Now(),
-	}
-
-	if err := u.sessionRepo.Create(ctx, session); err != nil {
-		return nil, err
-	}
-
-	// 5. Update last login
+	// 4. Update last login, upgrading the hash while the password is at hand
 	now := time.Now()
 	user.LastLoginAt = &now
+	if u.passwordHasher.NeedsRehash(user.PasswordHash) {
+		if hashedPassword, err := u.passwordHasher.Hash(req.Password); err == nil {
+			user.PasswordHash = hashedPassword
+		}
+	}
 	_ = u.userRepo.Update(ctx, user)
 
-	// 6. Log success
+	// 5. Log success
 	_ = u.auditRepo.Create(ctx, &domain.AuditLog{
 		UserID:        &user.ID,
-		EventType:     "login_success",
-		EventCategory: "authentication",
+		EventType:     domain.EventLoginSuccess,
+		EventCategory: audit.CategoryAuthentication,
 		IPAddress:     &req.IPAddress,
 		UserAgent:     &req.UserAgent,
 		Success:       true,
 		CreatedAt:     now,
 	})
 
-	return &domain.LoginResponse{
-		User: &domain.UserResponse{
-			ID:    user.ID,
-			Email: user.Email,
-			Role:  user.Role,
-		},
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    900,
-	}, nil
+	return resp, nil
 }
 
-func (u *authUseCase) generateToken(user *domain.User, duration time.Duration) (string, error) {
-	claims := jwt.MapClaims{
-		"sub":   user.ID.String(),
-		"email": user.Email,
-		"role":  user.Role,
-		"exp":   time.Now().Add(duration).Unix(),
-		"iat":   time.Now().Unix(),
+var errStrInvalidCredentials = "invalid credentials"
+
+func (u *authUseCase) Logout(ctx context.Context, req *domain.LogoutRequest) error {
+	session, err := u.sessionRepo.GetByToken(ctx, req.Token)
+	if err != nil {
+		return err
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(u.cfg.JWTSecret))
-}
 
-func (u *authUseCase) generateRefreshToken(user *domain.User) (string, error) {
-	claims := jwt.MapClaims{
-		"sub": user.ID.String(),
-		"exp": time.Now().Add(30 * 24 * time.Hour).Unix(),
-		"iat": time.Now().Unix(),
-		"typ": "refresh",
+	// Revoke the refresh token family so the session cannot be resumed
+	if session != nil && session.RefreshToken != nil {
+		if _, familyID, _, err := u.parseRefreshToken(*session.RefreshToken); err == nil {
+			if err := u.refreshRepo.RevokeFamily(ctx, familyID); err != nil {
+				return err
+			}
+		}
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(u.cfg.JWTRefreshSecret))
-}
 
-var errStrInvalidCredentials = "invalid credentials"
+	if err := u.sessionRepo.Delete(ctx, req.Token); err != nil {
+		return err
+	}
 
-func (u *authUseCase) Logout(ctx context.Context, token string) error {
-	return u.sessionRepo.Delete(ctx, token)
+	_ = u.auditRepo.Create(ctx, &domain.AuditLog{
+		UserID:        &req.UserID,
+		EventType:     domain.EventLogout,
+		EventCategory: audit.CategoryAuthentication,
+		IPAddress:     &req.IPAddress,
+		UserAgent:     &req.UserAgent,
+		Success:       true,
+		CreatedAt:     time.Now(),
+	})
+	return nil
 }
 
-func (u *authUseCase) RefreshToken(ctx context.Context, refreshToken string) (*domain.LoginResponse, error) {
+func (u *authUseCase) RefreshToken(ctx context.Context, req *domain.RefreshTokenRequest) (*domain.LoginResponse, error) {
 	// 1. Validate Refresh Token
-	token, err := jwt.Parse(refreshToken, func(token *jwt.Token) (interface{}, error) {
-		return []byte(u.cfg.JWTRefreshSecret), nil
-	})
-	if err != nil || !token.Valid {
-		return nil, errors.New("invalid refresh token")
-	}
-
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return nil, errors.New("invalid token claims")
-	}
-
-	userIDStr, ok := claims["sub"].(string)
-	if !ok {
-		return nil, errors.New("invalid token user id")
+	userID, familyID, jti, err := u.parseRefreshToken(req.RefreshToken)
+	if err != nil {
+		return nil, err
 	}
 
-	userID, err := uuid.Parse(userIDStr)
+	// 2. Check the token family
+	family, err := u.refreshRepo.GetFamily(ctx, familyID)
 	if err != nil {
 		return nil, err
 	}
+	if family == nil || family.UserID != userID {
+		return nil, errors.New("invalid refresh token")
+	}
+	if family.Revoked {
+		return nil, domain.ErrRefreshTokenRevoked
+	}
 
-	// 2. Get User
+	// 3. Get User
 	user, err := u.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, err
@@ -235,29 +239,61 @@ func (u *authUseCase) RefreshToken(ctx context.Context, refreshToken string) (*d
 		return nil, errors.New("user not found")
 	}
 
-	// 3. Generate New Access Token
-	accessToken, err := u.generateToken(user, 15*time.Minute)
+	// 4. Rotate: the presented token must be the latest one issued for the family
+	nextJTI := uuid.New().String()
+	if err := u.refreshRepo.Rotate(ctx, familyID, jti, nextJTI); err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenReused) {
+			u.handleRefreshTokenReuse(ctx, user.ID, familyID, req)
+		}
+		return nil, err
+	}
+
+	// 5. Issue new tokens and create Session
+	accessToken, refreshToken, err := u.issueTokens(ctx, user, familyID, nextJTI, &req.IPAddress, &req.UserAgent)
 	if err != nil {
 		return nil, err
 	}
 
-	// 4. Update Session (Optional: Rotate Refresh Token)
-	// For simplicity, we keep the same refresh token until it expires
-	// But we need to ensure the user has an active session
-	// Depending on implementation, we might want to check the session repository here.
+	now := time.Now()
+	_ = u.auditRepo.Create(ctx, &domain.AuditLog{
+		UserID:        &user.ID,
+		EventType:     domain.EventTokenRefreshed,
+		EventCategory: audit.CategoryAuthentication,
+		IPAddress:     &req.IPAddress,
+		UserAgent:     &req.UserAgent,
+		Success:       true,
+		CreatedAt:     now,
+	})
 
 	return &domain.LoginResponse{
-		User: &domain.UserResponse{
-			ID:    user.ID,
-			Email: user.Email,
-			Role:  user.Role,
-		},
+		User:         domain.NewUserResponse(user),
 		AccessToken:  accessToken,
-		RefreshToken: refreshToken, // Return same refresh token
+		RefreshToken: refreshToken,
 		ExpiresIn:    900,
 	}, nil
 }
 
+// handleRefreshTokenReuse revokes a refresh token family whose rotated token was
+// presented again and records the incident as a security event.
+func (u *authUseCase) handleRefreshTokenReuse(ctx context.Context, userID uuid.UUID, familyID string, req *domain.RefreshTokenRequest) {
+	_ = u.refreshRepo.RevokeFamily(ctx, familyID)
+
+	errMsg := domain.ErrRefreshTokenReused.Error()
+	_ = u.auditRepo.Create(ctx, &domain.AuditLog{
+		UserID:        &userID,
+		EventType:     domain.EventRefreshTokenReuse,
+		EventCategory: audit.CategorySecurity,
+		Metadata: map[string]interface{}{
+			"family_id": familyID,
+		},
+		IPAddress:    &req.IPAddress,
+		UserAgent:    &req.UserAgent,
+		Success:      false,
+		ErrorMessage: &errMsg,
+		CreatedAt:    time.Now(),
+	})
+}
+
 func (u *authUseCase) GetMe(ctx context.Context, userID uuid.UUID) (*domain.UserResponse, error) {
 	user, err := u.userRepo.GetByID(ctx, userID)
 	if err != nil {
@@ -267,11 +303,7 @@ func (u *authUseCase) GetMe(ctx context.Context, userID uuid.UUID) (*domain.User
 		return nil, errors.New("user not found")
 	}
 
-	return &domain.UserResponse{
-		ID:    user.ID,
-		Email: user.Email,
-		Role:  user.Role,
-	}, nil
+	return domain.NewUserResponse(user), nil
 }
 
 func (u *authUseCase) VerifyEmail(ctx context.Context, token string) error {
@@ -308,15 +340,29 @@ func (u *authUseCase) ChangePassword(ctx context.Context, req *domain.ChangePass
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.CurrentPassword)); err != nil {
-		return errors.New("invalid current password")
+		errMsg := "invalid current password"
+		_ = u.auditRepo.Create(ctx, &domain.AuditLog{
+			UserID:        &user.ID,
+			EventType:     domain.EventPasswordChangeFail,
+			EventCategory: audit.CategorySecurity,
+			IPAddress:     &req.IPAddress,
+			UserAgent:     &req.UserAgent,
+			Success:       false,
+			ErrorMessage:  &errMsg,
+			CreatedAt:     time.Now(),
+		})
+		return errors.New(errMsg)
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err := u.passwordPolicy.Validate(req.NewPassword, user.Email); err != nil {
+		return err
+	}
+	hashedPassword, err := u.passwordHasher.Hash(req.NewPassword)
 	if err != nil {
 		return err
 	}
 
-	user.PasswordHash = string(hashedPassword)
+	user.PasswordHash = hashedPassword
 	user.UpdatedAt = time.Now()
 	// user.FailedLoginAttempts = 0 // Reset on password change?
 
@@ -324,6 +370,22 @@ func (u *authUseCase) ChangePassword(ctx context.Context, req *domain.ChangePass
 		return err
 	}
 
-	// Revoke all existing sessions
-	return u.sessionRepo.DeleteByUserID(ctx, user.ID)
+	// Revoke all existing sessions and refresh tokens
+	if err := u.refreshRepo.RevokeAllForUser(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := u.sessionRepo.DeleteByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	_ = u.auditRepo.Create(ctx, &domain.AuditLog{
+		UserID:        &user.ID,
+		EventType:     domain.EventPasswordChanged,
+		EventCategory: audit.CategorySecurity,
+		IPAddress:     &req.IPAddress,
+		UserAgent:     &req.UserAgent,
+		Success:       true,
+		CreatedAt:     user.UpdatedAt,
+	})
+	return nil
 }
@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/audit"
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+type dealerUseCase struct {
+	dealerRepo  domain.DealerApplicationRepository
+	userRepo    domain.UserRepository
+	sessionRepo domain.SessionRepository
+	refreshRepo domain.RefreshTokenRepository
+	auditRepo   domain.AuditRepository
+}
+
+// NewDealerUseCase creates a new dealer onboarding use case
+func NewDealerUseCase(
+	dealerRepo domain.DealerApplicationRepository,
+	userRepo domain.UserRepository,
+	sessionRepo domain.SessionRepository,
+	refreshRepo domain.RefreshTokenRepository,
+	auditRepo domain.AuditRepository,
+) domain.DealerUseCase {
+	return &dealerUseCase{
+		dealerRepo:  dealerRepo,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		refreshRepo: refreshRepo,
+		auditRepo:   auditRepo,
+	}
+}
+
+func (u *dealerUseCase) Apply(ctx context.Context, req *domain.DealerApplicationRequest) (*domain.DealerApplication, error) {
+	// 1. Only one open application per user
+	latest, err := u.dealerRepo.GetLatestByUserID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && (latest.Status == domain.DealerApplicationPending || latest.Status == domain.DealerApplicationApproved) {
+		return nil, domain.ErrDealerApplicationExists
+	}
+
+	// 2. Documents must have been uploaded by the applicant through image-service
+	prefix := "kyc/" + req.UserID.String() + "/"
+	for _, doc := range req.Documents {
+		if !strings.HasPrefix(doc.Key, prefix) || !strings.HasSuffix(doc.URL, doc.Key) {
+			return nil, domain.ErrInvalidDealerDocument
+		}
+	}
+
+	// 3. Create application
+	now := time.Now()
+	app := &domain.DealerApplication{
+		ID:                 uuid.New(),
+		UserID:             req.UserID,
+		BusinessName:       req.BusinessName,
+		RegistrationNumber: req.RegistrationNumber,
+		BusinessAddress:    req.BusinessAddress,
+		ContactPhone:       req.ContactPhone,
+		Website:            req.Website,
+		Documents:          req.Documents,
+		Status:             domain.DealerApplicationPending,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if err := u.dealerRepo.Create(ctx, app); err != nil {
+		return nil, err
+	}
+
+	// 4. Log audit
+	_ = u.auditRepo.Create(ctx, &domain.AuditLog{
+		UserID:        &req.UserID,
+		EventType:     domain.EventDealerApplied,
+		EventCategory: audit.CategoryAccountManagement,
+		Metadata: map[string]interface{}{
+			"application_id":      app.ID.String(),
+			"registration_number": app.RegistrationNumber,
+		},
+		IPAddress: &req.IPAddress,
+		UserAgent: &req.UserAgent,
+		Success:   true,
+		CreatedAt: now,
+	})
+
+	return app, nil
+}
+
+func (u *dealerUseCase) GetMyApplication(ctx context.Context, userID uuid.UUID) (*domain.DealerApplication, error) {
+	app, err := u.dealerRepo.GetLatestByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, domain.ErrDealerApplicationNotFound
+	}
+	return app, nil
+}
+
+func (u *dealerUseCase) ListApplications(ctx context.Context, status string, params pagination.Params) ([]domain.DealerApplication, pagination.Pagination, error) {
+	apps, total, err := u.dealerRepo.List(ctx, status, params)
+	if err != nil {
+		return nil, pagination.Pagination{}, err
+	}
+	return apps, pagination.New(params, total), nil
+}
+
+func (u *dealerUseCase) GetApplication(ctx context.Context, id uuid.UUID) (*domain.DealerApplication, error) {
+	app, err := u.dealerRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, domain.ErrDealerApplicationNotFound
+	}
+	return app, nil
+}
+
+func (u *dealerUseCase) Approve(ctx context.Context, req *domain.DealerReviewRequest) (*domain.DealerApplication, error) {
+	app, err := u.review(ctx, req, domain.DealerApplicationPending, domain.DealerApplicationApproved, auth.RoleDealer, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// The new role is picked up on the dealer's next token refresh
+	u.logReview(ctx, domain.EventDealerApproved, app, req)
+	return app, nil
+}
+
+func (u *dealerUseCase) Reject(ctx context.Context, req *domain.DealerReviewRequest) (*domain.DealerApplication, error) {
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, domain.ErrReviewReasonRequired
+	}
+
+	// Rejection leaves the applicant's role untouched
+	app, err := u.review(ctx, req, domain.DealerApplicationPending, domain.DealerApplicationRejected, "", false)
+	if err != nil {
+		return nil, err
+	}
+
+	u.logReview(ctx, domain.EventDealerRejected, app, req)
+	return app, nil
+}
+
+func (u *dealerUseCase) Revoke(ctx context.Context, req *domain.DealerReviewRequest) (*domain.DealerApplication, error) {
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, domain.ErrReviewReasonRequired
+	}
+
+	app, err := u.review(ctx, req, domain.DealerApplicationApproved, domain.DealerApplicationRevoked, auth.RoleSeller, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sign the user out everywhere so tokens carrying the dealer role stop working now
+	_ = u.refreshRepo.RevokeAllForUser(ctx, app.UserID)
+	_ = u.sessionRepo.DeleteByUserID(ctx, app.UserID)
+
+	u.logReview(ctx, domain.EventDealerRevoked, app, req)
+	return app, nil
+}
+
+// review moves an application from one status to another, recording the
+// reviewer and applying the resulting role (empty keeps the current one) and
+// listing verification.
+func (u *dealerUseCase) review(ctx context.Context, req *domain.DealerReviewRequest, from, to, role string, verified bool) (*domain.DealerApplication, error) {
+	app, err := u.GetApplication(ctx, req.ApplicationID)
+	if err != nil {
+		return nil, err
+	}
+	if app.Status != from {
+		return nil, domain.ErrDealerApplicationState
+	}
+	user, err := u.userRepo.GetByID(ctx, app.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrDealerApplicationNotFound
+	}
+	if role == "" {
+		role = user.Role
+	}
+
+	now := time.Now()
+	app.Status = to
+	app.ReviewedBy = &req.ReviewerID
+	app.ReviewedAt = &now
+	app.UpdatedAt = now
+	app.ReviewReason = nil
+	if reason := strings.TrimSpace(req.Reason); reason != "" {
+		app.ReviewReason = &reason
+	}
+
+	if err := u.dealerRepo.UpdateReview(ctx, app, from, role, verified); err != nil {
+		return nil, err
+	}
+
+	if user.Role != role {
+		_ = u.auditRepo.Create(ctx, &domain.AuditLog{
+			UserID:        &app.UserID,
+			EventType:     domain.EventRoleChanged,
+			EventCategory: audit.CategoryAccountManagement,
+			Metadata: map[string]interface{}{
+				"from":           user.Role,
+				"to":             role,
+				"changed_by":     req.ReviewerID.String(),
+				"application_id": app.ID.String(),
+			},
+			IPAddress: &req.IPAddress,
+			UserAgent: &req.UserAgent,
+			Success:   true,
+			CreatedAt: now,
+		})
+	}
+	return app, nil
+}
+
+// logReview records a review decision against the applicant, with the reviewing admin in the metadata.
+func (u *dealerUseCase) logReview(ctx context.Context, eventType string, app *domain.DealerApplication, req *domain.DealerReviewRequest) {
+	metadata := map[string]interface{}{
+		"application_id": app.ID.String(),
+		"reviewer_id":    req.ReviewerID.String(),
+	}
+	if app.ReviewReason != nil {
+		metadata["reason"] = *app.ReviewReason
+	}
+
+	_ = u.auditRepo.Create(ctx, &domain.AuditLog{
+		UserID:        &app.UserID,
+		EventType:     eventType,
+		EventCategory: audit.CategoryAccountManagement,
+		Metadata:      metadata,
+		IPAddress:     &req.IPAddress,
+		UserAgent:     &req.UserAgent,
+		Success:       true,
+		CreatedAt:     time.Now(),
+	})
+}
@@ -0,0 +1,329 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"sort"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/audit"
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/exoticsLanka/auth-service/internal/config"
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/exoticsLanka/auth-service/internal/keystore"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+)
+
+type oauthUseCase struct {
+	*tokenIssuer
+	userRepo     domain.UserRepository
+	identityRepo domain.UserIdentityRepository
+	stateRepo    domain.OAuthStateRepository
+	auditRepo    domain.AuditRepository
+	providers    map[string]domain.OAuthProvider
+}
+
+// NewOAuthUseCase creates a new social login use case for the given providers
+func NewOAuthUseCase(
+	userRepo domain.UserRepository,
+	identityRepo domain.UserIdentityRepository,
+	stateRepo domain.OAuthStateRepository,
+	sessionRepo domain.SessionRepository,
+	refreshRepo domain.RefreshTokenRepository,
+	auditRepo domain.AuditRepository,
+	keys *keystore.KeyStore,
+	cfg *config.Config,
+	providers ...domain.OAuthProvider,
+) domain.OAuthUseCase {
+	byName := make(map[string]domain.OAuthProvider, len(providers))
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+
+	return &oauthUseCase{
+		tokenIssuer:  newTokenIssuer(sessionRepo, refreshRepo, keys, cfg),
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		stateRepo:    stateRepo,
+		auditRepo:    auditRepo,
+		providers:    byName,
+	}
+}
+
+func (u *oauthUseCase) Providers() []string {
+	names := make([]string, 0, len(u.providers))
+	for name := range u.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (u *oauthUseCase) Authorize(ctx context.Context, req *domain.OAuthAuthorizeRequest) (*domain.OAuthAuthorizeResponse, error) {
+	provider, ok := u.providers[req.Provider]
+	if !ok {
+		return nil, domain.ErrUnknownOAuthProvider
+	}
+
+	state, err := randomState()
+	if err != nil {
+		return nil, err
+	}
+
+	// The verifier never leaves the server; the provider only sees its S256 challenge
+	verifier := oauth2.GenerateVerifier()
+	if err := u.stateRepo.Save(ctx, state, &domain.OAuthState{
+		Provider:     req.Provider,
+		CodeVerifier: verifier,
+		Mode:         req.Mode,
+		UserID:       req.UserID,
+		CreatedAt:    time.Now(),
+	}, domain.OAuthStateTTL); err != nil {
+		return nil, err
+	}
+
+	return &domain.OAuthAuthorizeResponse{
+		AuthorizationURL: provider.AuthCodeURL(state, verifier),
+		State:            state,
+	}, nil
+}
+
+func (u *oauthUseCase) Callback(ctx context.Context, req *domain.OAuthCallbackRequest) (*domain.OAuthCallbackResponse, error) {
+	provider, ok := u.providers[req.Provider]
+	if !ok {
+		return nil, domain.ErrUnknownOAuthProvider
+	}
+
+	// 1. The state must be one we issued, for this provider, and not yet used
+	state, err := u.stateRepo.Consume(ctx, req.State)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil || state.Provider != req.Provider {
+		return nil, domain.ErrInvalidOAuthState
+	}
+
+	// 2. Exchange the code, proving possession of the PKCE verifier
+	identity, err := provider.Exchange(ctx, req.Code, state.CodeVerifier)
+	if err != nil {
+		u.logEvent(ctx, nil, domain.EventOAuthLoginFailed, req.Provider, false, err.Error(), req.IPAddress, req.UserAgent)
+		return nil, err
+	}
+
+	if state.Mode == domain.OAuthModeLink && state.UserID != nil {
+		linked, err := u.link(ctx, *state.UserID, identity, req)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.OAuthCallbackResponse{Mode: domain.OAuthModeLink, Identity: linked}, nil
+	}
+
+	login, err := u.signIn(ctx, identity, req)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.OAuthCallbackResponse{Mode: domain.OAuthModeLogin, Login: login}, nil
+}
+
+// signIn signs in the user owning identity. Unknown identities are linked to the
+// account with the same verified email, or get a new account.
+func (u *oauthUseCase) signIn(ctx context.Context, identity *domain.OAuthIdentity, req *domain.OAuthCallbackRequest) (*domain.LoginResponse, error) {
+	user, err := u.findOrCreateUser(ctx, identity, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := u.login(ctx, user, &req.IPAddress, &req.UserAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user.LastLoginAt = &now
+	_ = u.userRepo.Update(ctx, user)
+
+	u.logEvent(ctx, &user.ID, domain.EventOAuthLoginSuccess, identity.Provider, true, "", req.IPAddress, req.UserAgent)
+	return resp, nil
+}
+
+func (u *oauthUseCase) findOrCreateUser(ctx context.Context, identity *domain.OAuthIdentity, req *domain.OAuthCallbackRequest) (*domain.User, error) {
+	// 1. Known identity
+	existing, err := u.identityRepo.GetByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		user, err := u.userRepo.GetByID(ctx, existing.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, domain.ErrInvalidOAuthState
+		}
+		return user, nil
+	}
+
+	if identity.Email == "" {
+		u.logEvent(ctx, nil, domain.EventOAuthLoginFailed, identity.Provider, false, domain.ErrOAuthEmailNotVerified.Error(), req.IPAddress, req.UserAgent)
+		return nil, domain.ErrOAuthEmailNotVerified
+	}
+
+	// 2. Existing account with the same email. It is only linked automatically when
+	// both sides have verified the address; otherwise whoever registered it first
+	// could be handed the account, so the owner must sign in and link it instead.
+	user, err := u.userRepo.GetByEmail(ctx, identity.Email)
+	if err != nil {
+		return nil, err
+	}
+	if user != nil && (!identity.EmailVerified || !user.EmailVerified) {
+		u.logEvent(ctx, &user.ID, domain.EventOAuthLoginFailed, identity.Provider, false, domain.ErrOAuthPasswordLoginRequired.Error(), req.IPAddress, req.UserAgent)
+		return nil, domain.ErrOAuthPasswordLoginRequired
+	}
+
+	now := time.Now()
+	if user == nil {
+		// 3. New account; it has no password until the user sets one
+		user = &domain.User{
+			ID:            uuid.New(),
+			Email:         identity.Email,
+			Role:          auth.RoleBuyer,
+			Status:        "active",
+			EmailVerified: identity.EmailVerified,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		if err := u.userRepo.Create(ctx, user); err != nil {
+			return nil, err
+		}
+		_ = u.auditRepo.Create(ctx, &domain.AuditLog{
+			UserID:        &user.ID,
+			EventType:     domain.EventAccountCreated,
+			EventCategory: audit.CategoryAccountManagement,
+			Metadata: map[string]interface{}{
+				"provider": identity.Provider,
+			},
+			IPAddress: &req.IPAddress,
+			UserAgent: &req.UserAgent,
+			Success:   true,
+			CreatedAt: now,
+		})
+	}
+
+	if _, err := u.createIdentity(ctx, user.ID, identity, req); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// link attaches identity to the signed-in user who started the flow.
+func (u *oauthUseCase) link(ctx context.Context, userID uuid.UUID, identity *domain.OAuthIdentity, req *domain.OAuthCallbackRequest) (*domain.UserIdentity, error) {
+	existing, err := u.identityRepo.GetByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if existing.UserID != userID {
+			return nil, domain.ErrIdentityLinkedElsewhere
+		}
+		return existing, nil
+	}
+
+	return u.createIdentity(ctx, userID, identity, req)
+}
+
+func (u *oauthUseCase) createIdentity(ctx context.Context, userID uuid.UUID, identity *domain.OAuthIdentity, req *domain.OAuthCallbackRequest) (*domain.UserIdentity, error) {
+	linked := &domain.UserIdentity{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		CreatedAt: time.Now(),
+	}
+	if identity.Email != "" {
+		linked.Email = &identity.Email
+	}
+	if err := u.identityRepo.Create(ctx, linked); err != nil {
+		return nil, err
+	}
+
+	u.logEvent(ctx, &userID, domain.EventOAuthLinked, identity.Provider, true, "", req.IPAddress, req.UserAgent)
+	return linked, nil
+}
+
+func (u *oauthUseCase) ListIdentities(ctx context.Context, userID uuid.UUID) ([]domain.UserIdentity, error) {
+	return u.identityRepo.ListByUserID(ctx, userID)
+}
+
+func (u *oauthUseCase) Unlink(ctx context.Context, req *domain.OAuthUnlinkRequest) error {
+	user, err := u.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return domain.ErrIdentityNotLinked
+	}
+
+	identities, err := u.identityRepo.ListByUserID(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	linked := false
+	for _, identity := range identities {
+		if identity.Provider == req.Provider {
+			linked = true
+			break
+		}
+	}
+	if !linked {
+		return domain.ErrIdentityNotLinked
+	}
+
+	// Accounts created through a provider have no password; keep at least one way in
+	methods := len(identities) - 1
+	if user.PasswordHash != "" {
+		methods++
+	}
+	if user.PhoneVerified {
+		methods++
+	}
+	if methods == 0 {
+		return domain.ErrLastLoginMethod
+	}
+
+	if err := u.identityRepo.Delete(ctx, req.UserID, req.Provider); err != nil {
+		return err
+	}
+
+	u.logEvent(ctx, &req.UserID, domain.EventOAuthUnlinked, req.Provider, true, "", req.IPAddress, req.UserAgent)
+	return nil
+}
+
+func (u *oauthUseCase) logEvent(ctx context.Context, userID *uuid.UUID, eventType, provider string, success bool, errMsg, ipAddress, userAgent string) {
+	log := &domain.AuditLog{
+		UserID:        userID,
+		EventType:     eventType,
+		EventCategory: audit.CategoryAuthentication,
+		Metadata: map[string]interface{}{
+			"provider": provider,
+		},
+		IPAddress: &ipAddress,
+		UserAgent: &userAgent,
+		Success:   success,
+		CreatedAt: time.Now(),
+	}
+	if errMsg != "" {
+		log.ErrorMessage = &errMsg
+	}
+	_ = u.auditRepo.Create(ctx, log)
+}
+
+func randomState() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
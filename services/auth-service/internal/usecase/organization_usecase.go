@@ -0,0 +1,403 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/audit"
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/org"
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+// invitationTTL is how long an invitation can be accepted
+const invitationTTL = 7 * 24 * time.Hour
+
+type organizationUseCase struct {
+	orgRepo   domain.OrganizationRepository
+	userRepo  domain.UserRepository
+	auditRepo domain.AuditRepository
+}
+
+// NewOrganizationUseCase creates a new dealer organisation use case
+func NewOrganizationUseCase(
+	orgRepo domain.OrganizationRepository,
+	userRepo domain.UserRepository,
+	auditRepo domain.AuditRepository,
+) domain.OrganizationUseCase {
+	return &organizationUseCase{
+		orgRepo:   orgRepo,
+		userRepo:  userRepo,
+		auditRepo: auditRepo,
+	}
+}
+
+func (u *organizationUseCase) Create(ctx context.Context, req *domain.CreateOrganizationRequest) (*domain.Organization, error) {
+	// 1. Only verified dealers can build a team, and only one
+	user, err := u.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if user.Role != auth.RoleDealer {
+		return nil, domain.ErrOrganizationDealerOnly
+	}
+	member, err := u.orgRepo.GetMember(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if member != nil {
+		return nil, domain.ErrAlreadyInOrganization
+	}
+
+	// 2. Create it with the dealer as owner; their listings and leads move in
+	now := time.Now()
+	organization := &domain.Organization{
+		ID:        uuid.New(),
+		Name:      strings.TrimSpace(req.Name),
+		OwnerID:   req.UserID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := u.orgRepo.Create(ctx, organization); err != nil {
+		return nil, err
+	}
+
+	u.logEvent(ctx, domain.EventOrganizationCreated, req.UserID, req.IPAddress, req.UserAgent, map[string]interface{}{
+		"organization_id": organization.ID.String(),
+	})
+
+	return u.GetMine(ctx, req.UserID)
+}
+
+func (u *organizationUseCase) GetMine(ctx context.Context, userID uuid.UUID) (*domain.Organization, error) {
+	member, organization, err := u.membership(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := u.orgRepo.ListMembers(ctx, organization.ID)
+	if err != nil {
+		return nil, err
+	}
+	organization.Role = member.Role
+	organization.Members = members
+	return organization, nil
+}
+
+func (u *organizationUseCase) Rename(ctx context.Context, req *domain.RenameOrganizationRequest) (*domain.Organization, error) {
+	member, organization, err := u.membership(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Role != org.RoleOwner {
+		return nil, domain.ErrOrganizationForbidden
+	}
+
+	organization.Name = strings.TrimSpace(req.Name)
+	organization.UpdatedAt = time.Now()
+	if err := u.orgRepo.Update(ctx, organization); err != nil {
+		return nil, err
+	}
+	return u.GetMine(ctx, req.UserID)
+}
+
+func (u *organizationUseCase) Invite(ctx context.Context, req *domain.InviteMemberRequest) (*domain.CreatedInvitation, error) {
+	// 1. Owners invite managers and salespeople; managers invite salespeople
+	member, organization, err := u.membership(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAssignableRole(member, req.Role); err != nil {
+		return nil, err
+	}
+
+	// 2. One open invitation per email; an expired one is replaced
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	open, err := u.orgRepo.GetOpenInvitation(ctx, organization.ID, email)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if open != nil {
+		if open.ExpiresAt.After(now) {
+			return nil, domain.ErrInvitationExists
+		}
+		if err := u.orgRepo.RevokeInvitation(ctx, open.ID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. Only a hash of the token is kept; the inviter passes the token on
+	token, err := randomState()
+	if err != nil {
+		return nil, err
+	}
+	inv := &domain.OrganizationInvitation{
+		ID:             uuid.New(),
+		OrganizationID: organization.ID,
+		Email:          email,
+		Role:           req.Role,
+		TokenHash:      hashInvitationToken(token),
+		InvitedBy:      &req.UserID,
+		ExpiresAt:      now.Add(invitationTTL),
+		CreatedAt:      now,
+	}
+	if err := u.orgRepo.CreateInvitation(ctx, inv); err != nil {
+		return nil, err
+	}
+
+	u.logEvent(ctx, domain.EventMemberInvited, req.UserID, req.IPAddress, req.UserAgent, map[string]interface{}{
+		"organization_id": organization.ID.String(),
+		"invitation_id":   inv.ID.String(),
+		"email":           inv.Email,
+		"role":            inv.Role,
+	})
+
+	return &domain.CreatedInvitation{OrganizationInvitation: *inv, Token: token}, nil
+}
+
+func (u *organizationUseCase) ListInvitations(ctx context.Context, userID uuid.UUID) ([]domain.OrganizationInvitation, error) {
+	member, organization, err := u.membership(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isManager(member) {
+		return nil, domain.ErrOrganizationForbidden
+	}
+	return u.orgRepo.ListInvitations(ctx, organization.ID)
+}
+
+func (u *organizationUseCase) RevokeInvitation(ctx context.Context, req *domain.OrganizationActionRequest) error {
+	member, organization, err := u.membership(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	if !isManager(member) {
+		return domain.ErrOrganizationForbidden
+	}
+
+	inv, err := u.orgRepo.GetInvitation(ctx, req.TargetID)
+	if err != nil {
+		return err
+	}
+	if inv == nil || inv.OrganizationID != organization.ID {
+		return domain.ErrInvitationNotFound
+	}
+	return u.orgRepo.RevokeInvitation(ctx, inv.ID, time.Now())
+}
+
+func (u *organizationUseCase) AcceptInvitation(ctx context.Context, req *domain.AcceptInvitationRequest) (*domain.Organization, error) {
+	// 1. The invitation must be open and addressed to this account
+	inv, err := u.orgRepo.GetInvitationByTokenHash(ctx, hashInvitationToken(req.Token))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if inv == nil || inv.AcceptedAt != nil || inv.RevokedAt != nil || !inv.ExpiresAt.After(now) {
+		return nil, domain.ErrInvitationInvalid
+	}
+
+	user, err := u.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if !strings.EqualFold(user.Email, inv.Email) {
+		return nil, domain.ErrInvitationEmailMismatch
+	}
+
+	existing, err := u.orgRepo.GetMember(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, domain.ErrAlreadyInOrganization
+	}
+
+	// 2. Join
+	member := &domain.OrganizationMember{
+		OrganizationID: inv.OrganizationID,
+		UserID:         req.UserID,
+		Role:           inv.Role,
+		InvitedBy:      inv.InvitedBy,
+		JoinedAt:       now,
+	}
+	if err := u.orgRepo.AddMember(ctx, member, inv.ID); err != nil {
+		return nil, err
+	}
+
+	// 3. Team members post listings, so buyers become sellers; the new role is
+	// picked up on the next token refresh
+	if user.Role == auth.RoleBuyer {
+		user.Role = auth.RoleSeller
+		user.UpdatedAt = now
+		if err := u.userRepo.Update(ctx, user); err != nil {
+			return nil, err
+		}
+		u.logEvent(ctx, domain.EventRoleChanged, req.UserID, req.IPAddress, req.UserAgent, map[string]interface{}{
+			"from":            auth.RoleBuyer,
+			"to":              auth.RoleSeller,
+			"organization_id": inv.OrganizationID.String(),
+		})
+	}
+
+	u.logEvent(ctx, domain.EventMemberJoined, req.UserID, req.IPAddress, req.UserAgent, map[string]interface{}{
+		"organization_id": inv.OrganizationID.String(),
+		"invitation_id":   inv.ID.String(),
+		"role":            inv.Role,
+	})
+
+	return u.GetMine(ctx, req.UserID)
+}
+
+func (u *organizationUseCase) ChangeMemberRole(ctx context.Context, req *domain.ChangeMemberRoleRequest) error {
+	member, organization, err := u.membership(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	if member.Role != org.RoleOwner {
+		return domain.ErrOrganizationForbidden
+	}
+	if err := checkAssignableRole(member, req.Role); err != nil {
+		return err
+	}
+
+	target, err := u.teammate(ctx, organization, req.MemberID)
+	if err != nil {
+		return err
+	}
+	if target.Role == org.RoleOwner {
+		return domain.ErrOwnerRoleFixed
+	}
+	if target.Role == req.Role {
+		return nil
+	}
+
+	if err := u.orgRepo.UpdateMemberRole(ctx, organization.ID, target.UserID, req.Role); err != nil {
+		return err
+	}
+
+	u.logEvent(ctx, domain.EventMemberRoleChanged, target.UserID, req.IPAddress, req.UserAgent, map[string]interface{}{
+		"organization_id": organization.ID.String(),
+		"from":            target.Role,
+		"to":              req.Role,
+		"changed_by":      req.UserID.String(),
+	})
+	return nil
+}
+
+func (u *organizationUseCase) RemoveMember(ctx context.Context, req *domain.OrganizationActionRequest) error {
+	member, organization, err := u.membership(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	target, err := u.teammate(ctx, organization, req.TargetID)
+	if err != nil {
+		return err
+	}
+	if target.Role == org.RoleOwner {
+		return domain.ErrOwnerRoleFixed
+	}
+
+	// Anyone can leave; owners remove anyone else, managers remove salespeople
+	leaving := target.UserID == req.UserID
+	if !leaving {
+		switch {
+		case member.Role == org.RoleOwner:
+		case member.Role == org.RoleManager && target.Role == org.RoleSalesperson:
+		default:
+			return domain.ErrOrganizationForbidden
+		}
+	}
+
+	if err := u.orgRepo.RemoveMember(ctx, organization, target.UserID); err != nil {
+		return err
+	}
+
+	u.logEvent(ctx, domain.EventMemberRemoved, target.UserID, req.IPAddress, req.UserAgent, map[string]interface{}{
+		"organization_id": organization.ID.String(),
+		"role":            target.Role,
+		"removed_by":      req.UserID.String(),
+	})
+	return nil
+}
+
+// membership returns the user's membership and organisation, or ErrOrganizationNotFound.
+func (u *organizationUseCase) membership(ctx context.Context, userID uuid.UUID) (*domain.OrganizationMember, *domain.Organization, error) {
+	member, err := u.orgRepo.GetMember(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if member == nil {
+		return nil, nil, domain.ErrOrganizationNotFound
+	}
+
+	organization, err := u.orgRepo.GetByID(ctx, member.OrganizationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if organization == nil {
+		return nil, nil, domain.ErrOrganizationNotFound
+	}
+	return member, organization, nil
+}
+
+// teammate returns userID's membership if they are in organization.
+func (u *organizationUseCase) teammate(ctx context.Context, organization *domain.Organization, userID uuid.UUID) (*domain.OrganizationMember, error) {
+	member, err := u.orgRepo.GetMember(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil || member.OrganizationID != organization.ID {
+		return nil, domain.ErrMemberNotFound
+	}
+	return member, nil
+}
+
+// checkAssignableRole validates a role the member is giving someone else.
+func checkAssignableRole(member *domain.OrganizationMember, role string) error {
+	if role != org.RoleManager && role != org.RoleSalesperson {
+		return domain.ErrInvalidMemberRole
+	}
+	switch member.Role {
+	case org.RoleOwner:
+		return nil
+	case org.RoleManager:
+		if role == org.RoleSalesperson {
+			return nil
+		}
+	}
+	return domain.ErrOrganizationForbidden
+}
+
+func isManager(member *domain.OrganizationMember) bool {
+	return member.Role == org.RoleOwner || member.Role == org.RoleManager
+}
+
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (u *organizationUseCase) logEvent(ctx context.Context, eventType string, userID uuid.UUID, ip, ua string, metadata map[string]interface{}) {
+	_ = u.auditRepo.Create(ctx, &domain.AuditLog{
+		UserID:        &userID,
+		EventType:     eventType,
+		EventCategory: audit.CategoryAccountManagement,
+		Metadata:      metadata,
+		IPAddress:     &ip,
+		UserAgent:     &ua,
+		Success:       true,
+		CreatedAt:     time.Now(),
+	})
+}
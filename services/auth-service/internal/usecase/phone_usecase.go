@@ -0,0 +1,397 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/audit"
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/exoticsLanka/auth-service/internal/config"
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/exoticsLanka/auth-service/internal/keystore"
+	"github.com/google/uuid"
+)
+
+const (
+	otpTTL            = 5 * time.Minute
+	otpMaxAttempts    = 5
+	otpResendCooldown = 60 * time.Second
+	// Codes one IP address may request per window, whatever the numbers,
+	// so the per-number cooldown can't be sidestepped by cycling numbers
+	otpSendsPerIP  = 10
+	otpSendsWindow = time.Hour
+)
+
+var e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+type phoneUseCase struct {
+	*tokenIssuer
+	userRepo  domain.UserRepository
+	otpRepo   domain.OTPRepository
+	auditRepo domain.AuditRepository
+	sms       domain.SMSSender
+	otpSecret []byte
+}
+
+// NewPhoneUseCase creates a new phone registration, login and verification use case
+func NewPhoneUseCase(
+	userRepo domain.UserRepository,
+	otpRepo domain.OTPRepository,
+	sessionRepo domain.SessionRepository,
+	refreshRepo domain.RefreshTokenRepository,
+	auditRepo domain.AuditRepository,
+	sms domain.SMSSender,
+	keys *keystore.KeyStore,
+	cfg *config.Config,
+) domain.PhoneUseCase {
+	return &phoneUseCase{
+		tokenIssuer: newTokenIssuer(sessionRepo, refreshRepo, keys, cfg),
+		userRepo:    userRepo,
+		otpRepo:     otpRepo,
+		auditRepo:   auditRepo,
+		sms:         sms,
+		otpSecret:   []byte(cfg.OTPSecret),
+	}
+}
+
+func (u *phoneUseCase) Register(ctx context.Context, req *domain.PhoneRegisterRequest) (*domain.PhoneCodeResponse, error) {
+	phone, err := normalizePhone(req.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Role == "" {
+		req.Role = auth.RoleBuyer
+	}
+	if req.Role != auth.RoleBuyer && req.Role != auth.RoleSeller {
+		return nil, domain.ErrRoleNotAllowed
+	}
+
+	// 1. The number must not belong to a verified account
+	user, err := u.userRepo.GetByPhone(ctx, phone)
+	if err != nil {
+		return nil, err
+	}
+	if user != nil && user.PhoneVerified {
+		return nil, domain.ErrPhoneTaken
+	}
+
+	// 2. Create the account, or reuse one whose registration was never completed
+	if user == nil || user.Email != "" {
+		now := time.Now()
+		user = &domain.User{
+			ID:          uuid.New(),
+			Role:        req.Role,
+			Status:      "pending",
+			PhoneNumber: &phone,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if err := u.userRepo.Create(ctx, user); err != nil {
+			return nil, err
+		}
+
+		_ = u.auditRepo.Create(ctx, &domain.AuditLog{
+			UserID:        &user.ID,
+			EventType:     domain.EventAccountCreated,
+			EventCategory: audit.CategoryAccountManagement,
+			Metadata: map[string]interface{}{
+				"method": "phone",
+			},
+			IPAddress: &req.IPAddress,
+			UserAgent: &req.UserAgent,
+			Success:   true,
+			CreatedAt: now,
+		})
+	}
+
+	// 3. Send the code; verifying it activates the account and signs in
+	return u.sendCode(ctx, domain.OTPPurposeLogin, user.ID, phone, req.IPAddress)
+}
+
+func (u *phoneUseCase) RequestLoginCode(ctx context.Context, req *domain.PhoneLoginRequest) (*domain.PhoneCodeResponse, error) {
+	phone, err := normalizePhone(req.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := u.userRepo.GetByPhone(ctx, phone)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.PhoneVerified {
+		// Don't reveal whether the number is registered
+		return &domain.PhoneCodeResponse{
+			Message:   "If the number is registered, a code has been sent",
+			ExpiresIn: int(otpTTL.Seconds()),
+		}, nil
+	}
+
+	resp, err := u.sendCode(ctx, domain.OTPPurposeLogin, user.ID, phone, req.IPAddress)
+	if err != nil {
+		return nil, err
+	}
+	resp.Message = "If the number is registered, a code has been sent"
+	return resp, nil
+}
+
+func (u *phoneUseCase) VerifyLogin(ctx context.Context, req *domain.PhoneVerifyRequest) (*domain.LoginResponse, error) {
+	phone, err := normalizePhone(req.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := u.checkCode(ctx, domain.OTPPurposeLogin, phone, req.Code, req.IPAddress, req.UserAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrInvalidOTP
+	}
+
+	// Suspended and deleted accounts are turned away before anything is changed
+	now := time.Now()
+	if err := checkAccountStatus(user, now); err != nil {
+		return nil, err
+	}
+
+	// First successful code completes a phone registration
+	if !user.PhoneVerified {
+		u.markVerified(ctx, user, phone, req.IPAddress, req.UserAgent)
+	}
+	if user.Status == "pending" {
+		user.Status = "active"
+	}
+	user.LastLoginAt = &now
+	user.UpdatedAt = now
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	resp, err := u.login(ctx, user, &req.IPAddress, &req.UserAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = u.auditRepo.Create(ctx, &domain.AuditLog{
+		UserID:        &user.ID,
+		EventType:     domain.EventLoginSuccess,
+		EventCategory: audit.CategoryAuthentication,
+		Metadata: map[string]interface{}{
+			"method": "phone",
+		},
+		IPAddress: &req.IPAddress,
+		UserAgent: &req.UserAgent,
+		Success:   true,
+		CreatedAt: now,
+	})
+
+	return resp, nil
+}
+
+func (u *phoneUseCase) StartVerification(ctx context.Context, req *domain.PhoneLoginRequest) (*domain.PhoneCodeResponse, error) {
+	phone, err := normalizePhone(req.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	holder, err := u.userRepo.GetByPhone(ctx, phone)
+	if err != nil {
+		return nil, err
+	}
+	if holder != nil && holder.PhoneVerified {
+		if holder.ID == req.UserID {
+			return nil, fmt.Errorf("%w: already verified on your account", domain.ErrPhoneTaken)
+		}
+		return nil, domain.ErrPhoneTaken
+	}
+
+	return u.sendCode(ctx, domain.OTPPurposeVerify, req.UserID, phone, req.IPAddress)
+}
+
+func (u *phoneUseCase) ConfirmVerification(ctx context.Context, req *domain.PhoneVerifyRequest) (*domain.UserResponse, error) {
+	phone, err := normalizePhone(req.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := u.checkCode(ctx, domain.OTPPurposeVerify, phone, req.Code, req.IPAddress, req.UserAgent)
+	if err != nil {
+		return nil, err
+	}
+	// The code was sent for another account's session
+	if userID != req.UserID {
+		return nil, domain.ErrInvalidOTP
+	}
+
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrInvalidOTP
+	}
+
+	u.markVerified(ctx, user, phone, req.IPAddress, req.UserAgent)
+	user.UpdatedAt = time.Now()
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return domain.NewUserResponse(user), nil
+}
+
+// sendCode generates a code for phone, stores its hash and sends it by SMS.
+func (u *phoneUseCase) sendCode(ctx context.Context, purpose string, userID uuid.UUID, phone, ipAddress string) (*domain.PhoneCodeResponse, error) {
+	sends, err := u.otpRepo.CountSend(ctx, ipAddress, otpSendsWindow)
+	if err != nil {
+		return nil, err
+	}
+	if sends > otpSendsPerIP {
+		return nil, domain.ErrOTPSendLimit
+	}
+
+	ok, err := u.otpRepo.AcquireCooldown(ctx, phone, otpResendCooldown)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, domain.ErrOTPCooldown
+	}
+
+	code, err := generateOTP()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.otpRepo.Save(ctx, purpose, phone, &domain.OTP{
+		UserID:   userID,
+		CodeHash: u.hashCode(phone, code),
+	}, otpTTL); err != nil {
+		return nil, err
+	}
+
+	if err := u.sms.SendOTP(ctx, userID, phone, code, otpTTL); err != nil {
+		_ = u.otpRepo.Delete(ctx, purpose, phone)
+		return nil, err
+	}
+
+	return &domain.PhoneCodeResponse{
+		Message:   "Verification code sent",
+		ExpiresIn: int(otpTTL.Seconds()),
+	}, nil
+}
+
+// checkCode validates code against the pending code for phone and returns the
+// user it was issued to. Codes are single use and die after otpMaxAttempts
+// wrong guesses; the repository checks and counts in one atomic step.
+func (u *phoneUseCase) checkCode(ctx context.Context, purpose, phone, code, ipAddress, userAgent string) (uuid.UUID, error) {
+	check, err := u.otpRepo.Check(ctx, purpose, phone, u.hashCode(phone, code), otpMaxAttempts)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if check == nil {
+		return uuid.Nil, domain.ErrInvalidOTP
+	}
+	if check.Valid {
+		return check.UserID, nil
+	}
+
+	errMsg := domain.ErrInvalidOTP.Error()
+	_ = u.auditRepo.Create(ctx, &domain.AuditLog{
+		UserID:        &check.UserID,
+		EventType:     domain.EventPhoneOTPFailed,
+		EventCategory: audit.CategoryAuthentication,
+		Metadata: map[string]interface{}{
+			"purpose":  purpose,
+			"attempts": check.Attempts,
+		},
+		IPAddress:    &ipAddress,
+		UserAgent:    &userAgent,
+		Success:      false,
+		ErrorMessage: &errMsg,
+		CreatedAt:    time.Now(),
+	})
+
+	if check.Attempts >= otpMaxAttempts {
+		return uuid.Nil, domain.ErrOTPAttemptsExceeded
+	}
+	return uuid.Nil, domain.ErrInvalidOTP
+}
+
+func (u *phoneUseCase) markVerified(ctx context.Context, user *domain.User, phone, ipAddress, userAgent string) {
+	now := time.Now()
+	user.PhoneNumber = &phone
+	user.PhoneVerified = true
+	user.PhoneVerifiedAt = &now
+
+	_ = u.auditRepo.Create(ctx, &domain.AuditLog{
+		UserID:        &user.ID,
+		EventType:     domain.EventPhoneVerified,
+		EventCategory: audit.CategoryAccountManagement,
+		IPAddress:     &ipAddress,
+		UserAgent:     &userAgent,
+		Success:       true,
+		CreatedAt:     now,
+	})
+}
+
+// hashCode keys the code hash with a server secret and the phone number, so a
+// leaked hash cannot be brute-forced offline or replayed for another number.
+func (u *phoneUseCase) hashCode(phone, code string) string {
+	mac := hmac.New(sha256.New, u.otpSecret)
+	mac.Write([]byte(phone + ":" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func generateOTP() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// normalizePhone converts a number to E.164. Local Sri Lankan numbers
+// (07X XXX XXXX) and numbers without the leading + are accepted.
+func normalizePhone(phone string) (string, error) {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(phone) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '(' || r == ')':
+		default:
+			return "", domain.ErrInvalidPhoneNumber
+		}
+	}
+
+	normalized := b.String()
+	switch {
+	case strings.HasPrefix(normalized, "+"):
+	case strings.HasPrefix(normalized, "0") && len(normalized) == 10:
+		normalized = "+94" + normalized[1:]
+	default:
+		normalized = "+" + normalized
+	}
+
+	if !e164Pattern.MatchString(normalized) {
+		return "", domain.ErrInvalidPhoneNumber
+	}
+	return normalized, nil
+}
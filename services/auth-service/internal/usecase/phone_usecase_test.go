@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+// memoryOTPRepository keeps codes in memory, following the contract of the
+// Redis repository's Check script
+type memoryOTPRepository struct {
+	codes map[string]*domain.OTP
+}
+
+func newMemoryOTPRepository() *memoryOTPRepository {
+	return &memoryOTPRepository{codes: make(map[string]*domain.OTP)}
+}
+
+func (r *memoryOTPRepository) Save(ctx context.Context, purpose, phone string, otp *domain.OTP, ttl time.Duration) error {
+	saved := *otp
+	r.codes[purpose+":"+phone] = &saved
+	return nil
+}
+
+func (r *memoryOTPRepository) Check(ctx context.Context, purpose, phone, codeHash string, maxAttempts int) (*domain.OTPCheck, error) {
+	key := purpose + ":" + phone
+	otp, ok := r.codes[key]
+	if !ok {
+		return nil, nil
+	}
+	if otp.Attempts >= maxAttempts {
+		delete(r.codes, key)
+		return &domain.OTPCheck{UserID: otp.UserID, Attempts: otp.Attempts}, nil
+	}
+	if otp.CodeHash == codeHash {
+		delete(r.codes, key)
+		return &domain.OTPCheck{UserID: otp.UserID, Valid: true, Attempts: otp.Attempts}, nil
+	}
+	otp.Attempts++
+	if otp.Attempts >= maxAttempts {
+		delete(r.codes, key)
+	}
+	return &domain.OTPCheck{UserID: otp.UserID, Attempts: otp.Attempts}, nil
+}
+
+func (r *memoryOTPRepository) Delete(ctx context.Context, purpose, phone string) error {
+	delete(r.codes, purpose+":"+phone)
+	return nil
+}
+
+func (r *memoryOTPRepository) AcquireCooldown(ctx context.Context, phone string, cooldown time.Duration) (bool, error) {
+	return true, nil
+}
+
+func (r *memoryOTPRepository) CountSend(ctx context.Context, ipAddress string, window time.Duration) (int, error) {
+	return 1, nil
+}
+
+type memoryAuditRepository struct {
+	logs []domain.AuditLog
+}
+
+func (r *memoryAuditRepository) Create(ctx context.Context, log *domain.AuditLog) error {
+	r.logs = append(r.logs, *log)
+	return nil
+}
+
+func (r *memoryAuditRepository) List(ctx context.Context, filter *domain.AuditFilter) ([]domain.AuditLog, error) {
+	return r.logs, nil
+}
+
+func TestCheckCode(t *testing.T) {
+	const (
+		phone = "+94771234567"
+		code  = "123456"
+		wrong = "000000"
+	)
+
+	tests := []struct {
+		name         string
+		noCode       bool
+		guesses      []string
+		wantErrs     []error
+		wantFailures int
+	}{
+		{
+			name:     "correct code",
+			guesses:  []string{code},
+			wantErrs: []error{nil},
+		},
+		{
+			name:     "single use",
+			guesses:  []string{code, code},
+			wantErrs: []error{nil, domain.ErrInvalidOTP},
+		},
+		{
+			name:         "wrong then correct",
+			guesses:      []string{wrong, wrong, code},
+			wantErrs:     []error{domain.ErrInvalidOTP, domain.ErrInvalidOTP, nil},
+			wantFailures: 2,
+		},
+		{
+			name:         "correct on the last attempt",
+			guesses:      []string{wrong, wrong, wrong, wrong, code},
+			wantErrs:     []error{domain.ErrInvalidOTP, domain.ErrInvalidOTP, domain.ErrInvalidOTP, domain.ErrInvalidOTP, nil},
+			wantFailures: 4,
+		},
+		{
+			name:    "attempts exhausted",
+			guesses: []string{wrong, wrong, wrong, wrong, wrong},
+			wantErrs: []error{
+				domain.ErrInvalidOTP, domain.ErrInvalidOTP, domain.ErrInvalidOTP, domain.ErrInvalidOTP,
+				domain.ErrOTPAttemptsExceeded,
+			},
+			wantFailures: otpMaxAttempts,
+		},
+		{
+			name:    "code dies once attempts are exhausted",
+			guesses: []string{wrong, wrong, wrong, wrong, wrong, code},
+			wantErrs: []error{
+				domain.ErrInvalidOTP, domain.ErrInvalidOTP, domain.ErrInvalidOTP, domain.ErrInvalidOTP,
+				domain.ErrOTPAttemptsExceeded, domain.ErrInvalidOTP,
+			},
+			wantFailures: otpMaxAttempts,
+		},
+		{
+			name:     "no pending code",
+			noCode:   true,
+			guesses:  []string{code},
+			wantErrs: []error{domain.ErrInvalidOTP},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			otpRepo := newMemoryOTPRepository()
+			auditRepo := &memoryAuditRepository{}
+			u := &phoneUseCase{otpRepo: otpRepo, auditRepo: auditRepo, otpSecret: []byte("test-secret")}
+
+			userID := uuid.New()
+			if !tt.noCode {
+				otp := &domain.OTP{UserID: userID, CodeHash: u.hashCode(phone, code)}
+				if err := otpRepo.Save(ctx, domain.OTPPurposeLogin, phone, otp, otpTTL); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for i, guess := range tt.guesses {
+				got, err := u.checkCode(ctx, domain.OTPPurposeLogin, phone, guess, "127.0.0.1", "test")
+				if !errors.Is(err, tt.wantErrs[i]) {
+					t.Fatalf("guess %d: checkCode error = %v, want %v", i+1, err, tt.wantErrs[i])
+				}
+				if err == nil && got != userID {
+					t.Fatalf("guess %d: checkCode = %s, want %s", i+1, got, userID)
+				}
+			}
+
+			if len(auditRepo.logs) != tt.wantFailures {
+				t.Errorf("logged %d failed attempts, want %d", len(auditRepo.logs), tt.wantFailures)
+			}
+			for _, log := range auditRepo.logs {
+				if log.EventType != domain.EventPhoneOTPFailed {
+					t.Errorf("logged %s, want %s", log.EventType, domain.EventPhoneOTPFailed)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckCodePurposeAndPhone(t *testing.T) {
+	ctx := context.Background()
+	otpRepo := newMemoryOTPRepository()
+	u := &phoneUseCase{otpRepo: otpRepo, auditRepo: &memoryAuditRepository{}, otpSecret: []byte("test-secret")}
+
+	const phone, code = "+94771234567", "123456"
+	otp := &domain.OTP{UserID: uuid.New(), CodeHash: u.hashCode(phone, code)}
+	if err := otpRepo.Save(ctx, domain.OTPPurposeVerify, phone, otp, otpTTL); err != nil {
+		t.Fatal(err)
+	}
+
+	// A verification code can't be used to sign in, or for another number
+	if _, err := u.checkCode(ctx, domain.OTPPurposeLogin, phone, code, "", ""); !errors.Is(err, domain.ErrInvalidOTP) {
+		t.Errorf("login with a verification code: error = %v, want %v", err, domain.ErrInvalidOTP)
+	}
+	if _, err := u.checkCode(ctx, domain.OTPPurposeVerify, "+94777654321", code, "", ""); !errors.Is(err, domain.ErrInvalidOTP) {
+		t.Errorf("code for another number: error = %v, want %v", err, domain.ErrInvalidOTP)
+	}
+	if _, err := u.checkCode(ctx, domain.OTPPurposeVerify, phone, code, "", ""); err != nil {
+		t.Errorf("code for its own number and purpose: error = %v", err)
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"0771234567", "+94771234567", false},
+		{"077 123 4567", "+94771234567", false},
+		{"(077) 123-4567", "+94771234567", false},
+		{"+94 77 123 4567", "+94771234567", false},
+		{"94771234567", "+94771234567", false},
+		{"+447911123456", "+447911123456", false},
+		{"077123456", "", true},
+		{"+0771234567", "", true},
+		{"077-123-456x", "", true},
+		{"77+1234567", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := normalizePhone(tt.in)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("normalizePhone(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,370 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/audit"
+	"github.com/exoticsLanka/auth-service/internal/config"
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// dataExportTTL is how long a finished export can be downloaded
+	dataExportTTL = 7 * 24 * time.Hour
+	// dataExportTimeout bounds building a single export
+	dataExportTimeout = 10 * time.Minute
+	// staleExportAge is when an unfinished export is assumed lost to a restart
+	staleExportAge = time.Hour
+)
+
+type privacyUseCase struct {
+	privacyRepo domain.PrivacyRepository
+	userRepo    domain.UserRepository
+	sessionRepo domain.SessionRepository
+	refreshRepo domain.RefreshTokenRepository
+	auditRepo   domain.AuditRepository
+	exportDir   string
+	gracePeriod time.Duration
+}
+
+// NewPrivacyUseCase creates a new personal data export and erasure use case
+func NewPrivacyUseCase(
+	privacyRepo domain.PrivacyRepository,
+	userRepo domain.UserRepository,
+	sessionRepo domain.SessionRepository,
+	refreshRepo domain.RefreshTokenRepository,
+	auditRepo domain.AuditRepository,
+	cfg *config.Config,
+) domain.PrivacyUseCase {
+	return &privacyUseCase{
+		privacyRepo: privacyRepo,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		refreshRepo: refreshRepo,
+		auditRepo:   auditRepo,
+		exportDir:   cfg.DataExportDir,
+		gracePeriod: time.Duration(cfg.ErasureGraceDays) * 24 * time.Hour,
+	}
+}
+
+func (u *privacyUseCase) RequestExport(ctx context.Context, req *domain.PrivacyRequest) (*domain.DataExport, error) {
+	active, err := u.privacyRepo.GetActiveExport(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		return nil, domain.ErrDataExportInProgress
+	}
+
+	export := &domain.DataExport{
+		ID:        uuid.New(),
+		UserID:    req.UserID,
+		Status:    domain.DataExportPending,
+		CreatedAt: time.Now(),
+	}
+	if err := u.privacyRepo.CreateExport(ctx, export); err != nil {
+		return nil, err
+	}
+
+	u.logEvent(ctx, domain.EventDataExportRequested, req, map[string]interface{}{"export_id": export.ID.String()})
+
+	// Collecting every service's tables can take a while, so don't hold the request open
+	go u.buildExport(*export)
+
+	return export, nil
+}
+
+func (u *privacyUseCase) ListExports(ctx context.Context, userID uuid.UUID) ([]domain.DataExport, error) {
+	return u.privacyRepo.ListExports(ctx, userID)
+}
+
+func (u *privacyUseCase) GetDownload(ctx context.Context, userID, exportID uuid.UUID) (*domain.DataExport, error) {
+	export, err := u.privacyRepo.GetExport(ctx, exportID)
+	if err != nil {
+		return nil, err
+	}
+	if export == nil || export.UserID != userID {
+		return nil, domain.ErrDataExportNotFound
+	}
+	if export.Status != domain.DataExportReady || export.FilePath == nil ||
+		(export.ExpiresAt != nil && !export.ExpiresAt.After(time.Now())) {
+		return nil, domain.ErrDataExportNotReady
+	}
+	return export, nil
+}
+
+// buildExport writes the ZIP for export and records the outcome. It runs
+// detached from the request that queued it.
+func (u *privacyUseCase) buildExport(export domain.DataExport) {
+	ctx, cancel := context.WithTimeout(context.Background(), dataExportTimeout)
+	defer cancel()
+
+	export.Status = domain.DataExportProcessing
+	if err := u.privacyRepo.UpdateExport(ctx, &export); err != nil {
+		log.Printf("Error starting data export %s: %v", export.ID, err)
+		return
+	}
+
+	path, size, err := u.writeExport(ctx, &export)
+	now := time.Now()
+	export.CompletedAt = &now
+	if err != nil {
+		log.Printf("Error building data export %s: %v", export.ID, err)
+		msg := "export could not be generated; please try again"
+		export.Status = domain.DataExportFailed
+		export.ErrorMessage = &msg
+	} else {
+		expiresAt := now.Add(dataExportTTL)
+		export.Status = domain.DataExportReady
+		export.FilePath = &path
+		export.FileSize = &size
+		export.ExpiresAt = &expiresAt
+	}
+
+	if err := u.privacyRepo.UpdateExport(ctx, &export); err != nil {
+		log.Printf("Error saving data export %s: %v", export.ID, err)
+	}
+}
+
+// writeExport collects the user's records and writes them to a ZIP with one JSON file per source.
+func (u *privacyUseCase) writeExport(ctx context.Context, export *domain.DataExport) (string, int64, error) {
+	data, err := u.privacyRepo.CollectUserData(ctx, export.UserID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	info, err := json.Marshal(map[string]interface{}{
+		"user_id":      export.UserID,
+		"export_id":    export.ID,
+		"generated_at": time.Now().UTC(),
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	data["export_info.json"] = info
+
+	if err := os.MkdirAll(u.exportDir, 0o700); err != nil {
+		return "", 0, err
+	}
+	path := filepath.Join(u.exportDir, export.ID.String()+".zip")
+
+	if err := writeZip(path, data); err != nil {
+		_ = os.Remove(path)
+		return "", 0, err
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return "", 0, err
+	}
+	return path, stat.Size(), nil
+}
+
+func writeZip(path string, files map[string]json.RawMessage) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	zw := zip.NewWriter(f)
+	for _, name := range names {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, files[name], "", "  "); err != nil {
+			return err
+		}
+		w, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := buf.WriteTo(w); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func (u *privacyUseCase) RequestErasure(ctx context.Context, req *domain.ErasureRequest) (*domain.ErasureStatus, error) {
+	user, err := u.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.DeletedAt != nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	// Accounts created through social or phone login have no password to confirm
+	if user.PasswordHash != "" {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+			return nil, domain.ErrPasswordConfirmation
+		}
+	}
+
+	status, err := u.privacyRepo.GetErasure(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if status != nil && status.ScheduledFor != nil {
+		return nil, domain.ErrErasureAlreadyScheduled
+	}
+
+	now := time.Now()
+	scheduledFor := now.Add(u.gracePeriod)
+	if err := u.privacyRepo.ScheduleErasure(ctx, req.UserID, now, scheduledFor); err != nil {
+		return nil, err
+	}
+
+	u.logEvent(ctx, domain.EventErasureScheduled, &req.PrivacyRequest, map[string]interface{}{
+		"scheduled_for": scheduledFor.UTC().Format(time.RFC3339),
+	})
+	return &domain.ErasureStatus{RequestedAt: &now, ScheduledFor: &scheduledFor}, nil
+}
+
+func (u *privacyUseCase) GetErasure(ctx context.Context, userID uuid.UUID) (*domain.ErasureStatus, error) {
+	status, err := u.privacyRepo.GetErasure(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if status == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	return status, nil
+}
+
+func (u *privacyUseCase) CancelErasure(ctx context.Context, req *domain.PrivacyRequest) error {
+	status, err := u.privacyRepo.GetErasure(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	if status == nil || status.ScheduledFor == nil {
+		return domain.ErrErasureNotScheduled
+	}
+
+	if err := u.privacyRepo.CancelErasure(ctx, req.UserID); err != nil {
+		return err
+	}
+
+	u.logEvent(ctx, domain.EventErasureCancelled, req, nil)
+	return nil
+}
+
+func (u *privacyUseCase) ProcessDueErasures(ctx context.Context) (int, error) {
+	userIDs, err := u.privacyRepo.ListDueErasures(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	erased := 0
+	for _, userID := range userIDs {
+		// One failure shouldn't hold up the rest; it is retried on the next run
+		if err := u.erase(ctx, userID); err != nil {
+			log.Printf("Error erasing account %s: %v", userID, err)
+			continue
+		}
+		erased++
+	}
+	return erased, nil
+}
+
+func (u *privacyUseCase) erase(ctx context.Context, userID uuid.UUID) error {
+	exports, err := u.privacyRepo.ListExports(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, export := range exports {
+		if err := removeExportFile(&export); err != nil {
+			return err
+		}
+	}
+
+	if err := u.privacyRepo.EraseUser(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := u.refreshRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := u.sessionRepo.DeleteByUserID(ctx, userID); err != nil {
+		return err
+	}
+
+	_ = u.auditRepo.Create(ctx, &domain.AuditLog{
+		UserID:        &userID,
+		EventType:     domain.EventAccountErased,
+		EventCategory: audit.CategoryAccountManagement,
+		Success:       true,
+		CreatedAt:     time.Now(),
+	})
+	return nil
+}
+
+func (u *privacyUseCase) PurgeExpiredExports(ctx context.Context) (int, error) {
+	// Exports are built in-process, so any still unfinished after an hour were cut off by a restart
+	if _, err := u.privacyRepo.FailStaleExports(ctx, time.Now().Add(-staleExportAge)); err != nil {
+		return 0, err
+	}
+
+	exports, err := u.privacyRepo.ListExpiredExports(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for i := range exports {
+		export := &exports[i]
+		if err := removeExportFile(export); err != nil {
+			log.Printf("Error removing data export %s: %v", export.ID, err)
+			continue
+		}
+		export.Status = domain.DataExportExpired
+		export.FilePath = nil
+		if err := u.privacyRepo.UpdateExport(ctx, export); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+func removeExportFile(export *domain.DataExport) error {
+	if export.FilePath == nil {
+		return nil
+	}
+	if err := os.Remove(*export.FilePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (u *privacyUseCase) logEvent(ctx context.Context, eventType string, req *domain.PrivacyRequest, metadata map[string]interface{}) {
+	_ = u.auditRepo.Create(ctx, &domain.AuditLog{
+		UserID:        &req.UserID,
+		EventType:     eventType,
+		EventCategory: audit.CategoryAccountManagement,
+		Metadata:      metadata,
+		IPAddress:     &req.IPAddress,
+		UserAgent:     &req.UserAgent,
+		Success:       true,
+		CreatedAt:     time.Now(),
+	})
+}
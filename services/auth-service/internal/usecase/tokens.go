@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/exoticsLanka/auth-service/internal/config"
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/exoticsLanka/auth-service/internal/keystore"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	refreshTokenTTL = 30 * 24 * time.Hour
+	// impersonationTTL is the lifetime of a support impersonation token; it cannot be refreshed
+	impersonationTTL = 15 * time.Minute
+)
+
+// tokenIssuer creates and validates the access/refresh token pairs and the
+// sessions backing them. It is shared by every way of signing in.
+type tokenIssuer struct {
+	sessionRepo domain.SessionRepository
+	refreshRepo domain.RefreshTokenRepository
+	keys        *keystore.KeyStore
+	cfg         *config.Config
+}
+
+func newTokenIssuer(
+	sessionRepo domain.SessionRepository,
+	refreshRepo domain.RefreshTokenRepository,
+	keys *keystore.KeyStore,
+	cfg *config.Config,
+) *tokenIssuer {
+	return &tokenIssuer{
+		sessionRepo: sessionRepo,
+		refreshRepo: refreshRepo,
+		keys:        keys,
+		cfg:         cfg,
+	}
+}
+
+// login starts a new refresh token family for user and issues its first token pair.
+func (t *tokenIssuer) login(ctx context.Context, user *domain.User, ipAddress, userAgent *string) (*domain.LoginResponse, error) {
+	if err := checkAccountStatus(user, time.Now()); err != nil {
+		return nil, err
+	}
+
+	familyID, jti, err := t.startRefreshFamily(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, refreshToken, err := t.issueTokens(ctx, user, familyID, jti, ipAddress, userAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.LoginResponse{
+		User:         domain.NewUserResponse(user),
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    900,
+	}, nil
+}
+
+func (t *tokenIssuer) generateToken(user *domain.User, duration time.Duration) (string, error) {
+	return t.keys.Sign(accessClaims(user, duration))
+}
+
+func accessClaims(user *domain.User, duration time.Duration) jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":            user.ID.String(),
+		"email":          user.Email,
+		"role":           user.Role,
+		"phone_verified": user.PhoneVerified,
+		"exp":            time.Now().Add(duration).Unix(),
+		"iat":            time.Now().Unix(),
+	}
+}
+
+// impersonate issues an access token for user carrying actor in its "act"
+// claim, backed by a session so it can be revoked like any other. No refresh
+// token is issued, so it lapses after impersonationTTL.
+func (t *tokenIssuer) impersonate(ctx context.Context, user, actor *domain.User, ipAddress, userAgent *string) (string, time.Time, error) {
+	now := time.Now()
+	if err := checkAccountStatus(user, now); err != nil {
+		return "", time.Time{}, err
+	}
+
+	claims := accessClaims(user, impersonationTTL)
+	claims["act"] = map[string]interface{}{
+		"sub":   actor.ID.String(),
+		"email": actor.Email,
+	}
+	accessToken, err := t.keys.Sign(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	deviceName := "impersonation"
+	expiresAt := now.Add(impersonationTTL)
+	session := &domain.Session{
+		ID:             uuid.New(),
+		UserID:         user.ID,
+		Token:          accessToken,
+		DeviceName:     &deviceName,
+		IPAddress:      ipAddress,
+		UserAgent:      userAgent,
+		IsActive:       true,
+		ExpiresAt:      expiresAt,
+		LastActivityAt: now,
+		CreatedAt:      now,
+	}
+	if err := t.sessionRepo.Create(ctx, session); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return accessToken, expiresAt, nil
+}
+
+func (t *tokenIssuer) generateRefreshToken(user *domain.User, familyID, jti string) (string, error) {
+	claims := jwt.MapClaims{
+		"sub": user.ID.String(),
+		"fid": familyID,
+		"jti": jti,
+		"exp": time.Now().Add(refreshTokenTTL).Unix(),
+		"iat": time.Now().Unix(),
+		"typ": "refresh",
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(t.cfg.JWTRefreshSecret))
+}
+
+// startRefreshFamily begins a new refresh token family for a fresh login and
+// returns the family ID together with the ID of its first token.
+func (t *tokenIssuer) startRefreshFamily(ctx context.Context, userID uuid.UUID) (string, string, error) {
+	now := time.Now()
+	family := &domain.RefreshTokenFamily{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		CurrentJTI: uuid.New().String(),
+		CreatedAt:  now,
+		RotatedAt:  now,
+		ExpiresAt:  now.Add(refreshTokenTTL),
+	}
+	if err := t.refreshRepo.CreateFamily(ctx, family); err != nil {
+		return "", "", err
+	}
+	return family.ID, family.CurrentJTI, nil
+}
+
+// issueTokens creates an access token, a refresh token for the given family and
+// the session backing them. Suspended and deleted accounts get no tokens.
+func (t *tokenIssuer) issueTokens(ctx context.Context, user *domain.User, familyID, jti string, ipAddress, userAgent *string) (string, string, error) {
+	now := time.Now()
+	if err := checkAccountStatus(user, now); err != nil {
+		return "", "", err
+	}
+
+	accessToken, err := t.generateToken(user, 15*time.Minute)
+	if err != nil {
+		return "", "", err
+	}
+	refreshToken, err := t.generateRefreshToken(user, familyID, jti)
+	if err != nil {
+		return "", "", err
+	}
+
+	session := &domain.Session{
+		ID:             uuid.New(),
+		UserID:         user.ID,
+		Token:          accessToken,
+		RefreshToken:   &refreshToken,
+		IPAddress:      ipAddress,
+		UserAgent:      userAgent,
+		IsActive:       true,
+		ExpiresAt:      now.Add(15 * time.Minute),
+		LastActivityAt: now,
+		CreatedAt:      now,
+	}
+	if err := t.sessionRepo.Create(ctx, session); err != nil {
+		return "", "", err
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// parseRefreshToken validates a refresh token and returns its user, family and token IDs.
+func (t *tokenIssuer) parseRefreshToken(refreshToken string) (uuid.UUID, string, string, error) {
+	token, err := jwt.Parse(refreshToken, func(token *jwt.Token) (interface{}, error) {
+		return []byte(t.cfg.JWTRefreshSecret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid {
+		return uuid.Nil, "", "", errors.New("invalid refresh token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return uuid.Nil, "", "", errors.New("invalid token claims")
+	}
+
+	if typ, _ := claims["typ"].(string); typ != "refresh" {
+		return uuid.Nil, "", "", errors.New("invalid refresh token")
+	}
+
+	userIDStr, ok := claims["sub"].(string)
+	if !ok {
+		return uuid.Nil, "", "", errors.New("invalid token user id")
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return uuid.Nil, "", "", err
+	}
+
+	familyID, _ := claims["fid"].(string)
+	jti, _ := claims["jti"].(string)
+	if familyID == "" || jti == "" {
+		// Tokens issued before rotation was introduced are not bound to a family
+		return uuid.Nil, "", "", errors.New("invalid refresh token")
+	}
+
+	return userID, familyID, jti, nil
+}
+
+func checkAccountStatus(user *domain.User, now time.Time) error {
+	switch {
+	case user.Status == auth.StatusDeleted || user.DeletedAt != nil:
+		return domain.ErrAccountDeleted
+	case auth.IsSuspended(user.Status, user.SuspendedUntil, now):
+		return domain.ErrAccountSuspended
+	}
+	return nil
+}
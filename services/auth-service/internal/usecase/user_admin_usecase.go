@@ -0,0 +1,327 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/audit"
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
+	"github.com/exoticsLanka/auth-service/internal/config"
+	"github.com/exoticsLanka/auth-service/internal/domain"
+	"github.com/exoticsLanka/auth-service/internal/keystore"
+	"github.com/google/uuid"
+)
+
+// userDetailsActivityLimit is how many recent audit events the admin user view shows
+const userDetailsActivityLimit = 10
+
+// assignableRoles are the roles an admin can set directly. Dealers are only
+// made by approving a dealer application, which also verifies their listings.
+var assignableRoles = map[string]bool{
+	auth.RoleBuyer:      true,
+	auth.RoleSeller:     true,
+	auth.RoleAdmin:      true,
+	auth.RoleSuperAdmin: true,
+}
+
+type userAdminUseCase struct {
+	*tokenIssuer
+	userRepo     domain.UserRepository
+	identityRepo domain.UserIdentityRepository
+	sessionRepo  domain.SessionRepository
+	refreshRepo  domain.RefreshTokenRepository
+	auditRepo    domain.AuditRepository
+}
+
+// NewUserAdminUseCase creates a new admin user management use case
+func NewUserAdminUseCase(
+	userRepo domain.UserRepository,
+	identityRepo domain.UserIdentityRepository,
+	sessionRepo domain.SessionRepository,
+	refreshRepo domain.RefreshTokenRepository,
+	auditRepo domain.AuditRepository,
+	keys *keystore.KeyStore,
+	cfg *config.Config,
+) domain.UserAdminUseCase {
+	return &userAdminUseCase{
+		tokenIssuer:  newTokenIssuer(sessionRepo, refreshRepo, keys, cfg),
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		sessionRepo:  sessionRepo,
+		refreshRepo:  refreshRepo,
+		auditRepo:    auditRepo,
+	}
+}
+
+func (u *userAdminUseCase) List(ctx context.Context, filter *domain.UserFilter, params pagination.Params) ([]domain.User, pagination.Pagination, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	users, total, err := u.userRepo.List(ctx, filter, params)
+	if err != nil {
+		return nil, pagination.Pagination{}, err
+	}
+	return users, pagination.New(params, total), nil
+}
+
+func (u *userAdminUseCase) Get(ctx context.Context, id uuid.UUID) (*domain.UserDetails, error) {
+	user, err := u.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	identities, err := u.identityRepo.ListByUserID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	activity, err := u.auditRepo.List(ctx, &domain.AuditFilter{
+		UserID: &id,
+		Page:   pagination.CursorParams{Limit: userDetailsActivityLimit},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(activity) > userDetailsActivityLimit {
+		activity = activity[:userDetailsActivityLimit]
+	}
+
+	return &domain.UserDetails{
+		User:           user,
+		Identities:     identities,
+		RecentActivity: activity,
+	}, nil
+}
+
+func (u *userAdminUseCase) ChangeRole(ctx context.Context, req *domain.ChangeRoleRequest) (*domain.User, error) {
+	if req.Role == auth.RoleDealer {
+		return nil, domain.ErrDealerRoleNotAssignable
+	}
+	if !assignableRoles[req.Role] {
+		return nil, domain.ErrInvalidRole
+	}
+	if isAdminRole(req.Role) && !req.CanManageAdmins {
+		return nil, domain.ErrAdminAccountProtected
+	}
+
+	user, err := u.target(ctx, &req.UserAdminActionRequest)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == req.Role {
+		return user, nil
+	}
+	if user.Role == auth.RoleDealer {
+		return nil, domain.ErrDealerRoleNotRemovable
+	}
+
+	from := user.Role
+	user.Role = req.Role
+	user.UpdatedAt = time.Now()
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	// Access tokens carry the role, so sign the user out for it to apply now
+	if err := u.revokeSessions(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	u.logEvent(ctx, domain.EventRoleChanged, audit.CategoryAccountManagement, &req.UserAdminActionRequest, map[string]interface{}{
+		"from": from,
+		"to":   req.Role,
+	})
+	return user, nil
+}
+
+func (u *userAdminUseCase) Suspend(ctx context.Context, req *domain.SuspendUserRequest) (*domain.User, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, domain.ErrReasonRequired
+	}
+	now := time.Now()
+	if req.Until != nil && !req.Until.After(now) {
+		return nil, domain.ErrInvalidSuspensionEnd
+	}
+
+	user, err := u.target(ctx, &req.UserAdminActionRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Status = auth.StatusSuspended
+	user.SuspendedReason = &reason
+	user.SuspendedUntil = req.Until
+	user.SuspendedAt = &now
+	user.SuspendedBy = &req.ActorID
+	user.UpdatedAt = now
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	// Other services already reject the user's tokens; this also stops refreshes
+	if err := u.revokeSessions(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]interface{}{"reason": reason}
+	if req.Until != nil {
+		metadata["until"] = req.Until.UTC().Format(time.RFC3339)
+	}
+	u.logEvent(ctx, domain.EventUserSuspended, audit.CategoryAccountManagement, &req.UserAdminActionRequest, metadata)
+	return user, nil
+}
+
+func (u *userAdminUseCase) Reactivate(ctx context.Context, req *domain.UserAdminActionRequest) (*domain.User, error) {
+	user, err := u.target(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status != auth.StatusSuspended {
+		return nil, domain.ErrUserNotSuspended
+	}
+
+	user.Status = auth.StatusActive
+	user.SuspendedReason = nil
+	user.SuspendedUntil = nil
+	user.SuspendedAt = nil
+	user.SuspendedBy = nil
+	user.UpdatedAt = time.Now()
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	u.logEvent(ctx, domain.EventUserReactivated, audit.CategoryAccountManagement, req, nil)
+	return user, nil
+}
+
+func (u *userAdminUseCase) ForceLogout(ctx context.Context, req *domain.UserAdminActionRequest) error {
+	user, err := u.target(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if err := u.revokeSessions(ctx, user.ID); err != nil {
+		return err
+	}
+
+	u.logEvent(ctx, domain.EventSessionsRevoked, audit.CategorySecurity, req, nil)
+	return nil
+}
+
+func (u *userAdminUseCase) Delete(ctx context.Context, req *domain.UserAdminActionRequest) error {
+	user, err := u.target(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	user.Status = auth.StatusDeleted
+	user.DeletedAt = &now
+	user.UpdatedAt = now
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	if err := u.revokeSessions(ctx, user.ID); err != nil {
+		return err
+	}
+
+	u.logEvent(ctx, domain.EventAccountDeleted, audit.CategoryAccountManagement, req, nil)
+	return nil
+}
+
+func (u *userAdminUseCase) Impersonate(ctx context.Context, req *domain.ImpersonateRequest) (*domain.ImpersonationResponse, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, domain.ErrReasonRequired
+	}
+
+	user, err := u.target(ctx, &req.UserAdminActionRequest)
+	if err != nil {
+		return nil, err
+	}
+	// Acting as an admin would hand out their permissions, whoever is asking
+	if isAdminRole(user.Role) {
+		return nil, domain.ErrImpersonationNotAllowed
+	}
+
+	actor, err := u.userRepo.GetByID(ctx, req.ActorID)
+	if err != nil {
+		return nil, err
+	}
+	if actor == nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	accessToken, expiresAt, err := u.impersonate(ctx, user, actor, &req.IPAddress, &req.UserAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	u.logEvent(ctx, domain.EventImpersonationStarted, audit.CategoryImpersonation, &req.UserAdminActionRequest, map[string]interface{}{
+		"reason":      reason,
+		"actor_email": actor.Email,
+		"expires_at":  expiresAt.UTC().Format(time.RFC3339),
+	})
+
+	return &domain.ImpersonationResponse{
+		User:        domain.NewUserResponse(user),
+		AccessToken: accessToken,
+		ExpiresIn:   int(impersonationTTL.Seconds()),
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+// target loads the account an admin action applies to, refusing the actor's
+// own account and, unless the actor may manage admins, admin accounts.
+func (u *userAdminUseCase) target(ctx context.Context, req *domain.UserAdminActionRequest) (*domain.User, error) {
+	if req.UserID == req.ActorID {
+		return nil, domain.ErrCannotModifySelf
+	}
+
+	user, err := u.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.DeletedAt != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if isAdminRole(user.Role) && !req.CanManageAdmins {
+		return nil, domain.ErrAdminAccountProtected
+	}
+	return user, nil
+}
+
+func (u *userAdminUseCase) revokeSessions(ctx context.Context, userID uuid.UUID) error {
+	if err := u.refreshRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+	return u.sessionRepo.DeleteByUserID(ctx, userID)
+}
+
+// logEvent records an admin action against the target user, with the acting admin in the metadata.
+func (u *userAdminUseCase) logEvent(ctx context.Context, eventType, category string, req *domain.UserAdminActionRequest, metadata map[string]interface{}) {
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadata["changed_by"] = req.ActorID.String()
+
+	_ = u.auditRepo.Create(ctx, &domain.AuditLog{
+		UserID:        &req.UserID,
+		EventType:     eventType,
+		EventCategory: category,
+		Metadata:      metadata,
+		IPAddress:     &req.IPAddress,
+		UserAgent:     &req.UserAgent,
+		Success:       true,
+		CreatedAt:     time.Now(),
+	})
+}
+
+func isAdminRole(role string) bool {
+	return role == auth.RoleAdmin || role == auth.RoleSuperAdmin
+}
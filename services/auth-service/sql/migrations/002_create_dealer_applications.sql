@@ -0,0 +1,27 @@
+-- 002_create_dealer_applications.sql
+
+-- Dealer onboarding (KYC) applications, reviewed by admins
+CREATE TABLE IF NOT EXISTS dealer_applications (
+  id                    UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id               UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  business_name         VARCHAR(255) NOT NULL,
+  registration_number   VARCHAR(100) NOT NULL,
+  business_address      TEXT NOT NULL,
+  contact_phone         VARCHAR(20) NOT NULL,
+  website               VARCHAR(255),
+  documents             JSONB NOT NULL DEFAULT '[]',
+  status                VARCHAR(20) NOT NULL DEFAULT 'pending'
+                        CHECK (status IN ('pending', 'approved', 'rejected', 'revoked')),
+  reviewed_by           UUID REFERENCES users(id) ON DELETE SET NULL,
+  review_reason         TEXT,
+  reviewed_at           TIMESTAMP,
+  created_at            TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at            TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_dealer_applications_user_id ON dealer_applications(user_id);
+CREATE INDEX IF NOT EXISTS idx_dealer_applications_status ON dealer_applications(status, created_at);
+
+-- A user can only have one application under review or approved at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dealer_applications_active
+  ON dealer_applications(user_id) WHERE status IN ('pending', 'approved');
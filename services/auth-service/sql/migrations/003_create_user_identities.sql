@@ -0,0 +1,21 @@
+-- 003_create_user_identities.sql
+
+-- Social login accounts (Google, Facebook, ...) linked to a user
+CREATE TABLE IF NOT EXISTS user_identities (
+  id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id     UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  provider    VARCHAR(50) NOT NULL,
+  subject     VARCHAR(255) NOT NULL,
+  email       VARCHAR(255),
+  created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+  UNIQUE (provider, subject),
+  UNIQUE (user_id, provider)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id);
+
+-- Carry over accounts created with the legacy single-provider columns
+INSERT INTO user_identities (user_id, provider, subject, email)
+SELECT id, oauth_provider, oauth_id, email FROM users
+WHERE oauth_provider IS NOT NULL AND oauth_id IS NOT NULL
+ON CONFLICT DO NOTHING;
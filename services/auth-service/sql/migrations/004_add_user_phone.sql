@@ -0,0 +1,13 @@
+-- 004_add_user_phone.sql
+
+-- Phone numbers (E.164), verified by SMS one-time code
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_number VARCHAR(20);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_verified BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_verified_at TIMESTAMP;
+
+-- Accounts registered by phone have no email
+ALTER TABLE users ALTER COLUMN email DROP NOT NULL;
+
+-- A number can be verified by one account only
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_phone_verified ON users(phone_number) WHERE phone_verified;
+CREATE INDEX IF NOT EXISTS idx_users_phone_number ON users(phone_number);
@@ -0,0 +1,24 @@
+-- 007_create_privacy_tables.sql
+
+-- "Download my data" jobs; the ZIP is kept on disk until expires_at
+CREATE TABLE IF NOT EXISTS data_exports (
+  id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id        UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  status         VARCHAR(20) NOT NULL DEFAULT 'pending'
+                 CHECK (status IN ('pending', 'processing', 'ready', 'failed', 'expired')),
+  file_path      TEXT,
+  file_size      BIGINT,
+  error_message  TEXT,
+  created_at     TIMESTAMP NOT NULL DEFAULT NOW(),
+  completed_at   TIMESTAMP,
+  expires_at     TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_exports_user_id ON data_exports(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_data_exports_expires_at ON data_exports(expires_at) WHERE status = 'ready';
+
+-- Account erasure requested by the user; carried out once the grace period ends
+ALTER TABLE users ADD COLUMN IF NOT EXISTS erasure_requested_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS erasure_scheduled_for TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_users_erasure_scheduled_for ON users(erasure_scheduled_for) WHERE erasure_scheduled_for IS NOT NULL;
@@ -0,0 +1,18 @@
+-- 008_create_api_keys.sql
+
+-- Dealer API keys for server-to-server integrations. Only a SHA-256 hash of the
+-- key is stored; prefix is the clear part of the key used to look it up.
+CREATE TABLE IF NOT EXISTS api_keys (
+  id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id       UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name          VARCHAR(100) NOT NULL,
+  prefix        VARCHAR(16) NOT NULL UNIQUE,
+  key_hash      VARCHAR(64) NOT NULL,
+  scopes        TEXT[] NOT NULL DEFAULT '{}',
+  expires_at    TIMESTAMP,
+  last_used_at  TIMESTAMP,
+  revoked_at    TIMESTAMP,
+  created_at    TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys(user_id, created_at DESC);
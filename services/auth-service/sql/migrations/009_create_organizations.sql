@@ -0,0 +1,43 @@
+-- 009_create_organizations.sql
+
+-- Dealer organisations: a dealership's team of accounts. Listings and
+-- conversations carry organization_id and assigned_to (see listings-service and
+-- messaging-service migrations).
+CREATE TABLE IF NOT EXISTS organizations (
+  id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  name        VARCHAR(255) NOT NULL,
+  owner_id    UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- A user belongs to at most one organisation
+CREATE TABLE IF NOT EXISTS organization_members (
+  organization_id  UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+  user_id          UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+  role             VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'manager', 'salesperson')),
+  invited_by       UUID REFERENCES users(id) ON DELETE SET NULL,
+  joined_at        TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (organization_id, user_id)
+);
+
+-- Invitations are accepted by the account with the invited email; only a
+-- SHA-256 hash of the token is stored
+CREATE TABLE IF NOT EXISTS organization_invitations (
+  id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  organization_id  UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+  email            VARCHAR(255) NOT NULL,
+  role             VARCHAR(20) NOT NULL CHECK (role IN ('manager', 'salesperson')),
+  token_hash       VARCHAR(64) NOT NULL UNIQUE,
+  invited_by       UUID REFERENCES users(id) ON DELETE SET NULL,
+  expires_at       TIMESTAMP NOT NULL,
+  accepted_at      TIMESTAMP,
+  revoked_at       TIMESTAMP,
+  created_at       TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_invitations_org ON organization_invitations(organization_id, created_at DESC);
+
+-- One open invitation per email per organisation
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organization_invitations_open
+  ON organization_invitations(organization_id, LOWER(email)) WHERE accepted_at IS NULL AND revoked_at IS NULL;
@@ -0,0 +1,18 @@
+-- 010_create_organization_events.sql
+
+-- Team changes for the services that own a dealer's listings and leads:
+-- listings-service applies them to car_listings and messaging-service to
+-- conversations, each marking the event applied on its side (see pkg/org)
+CREATE TABLE IF NOT EXISTS organization_events (
+  id                        UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  organization_id           UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+  type                      VARCHAR(30) NOT NULL CHECK (type IN ('organization_created', 'member_removed')),
+  user_id                   UUID NOT NULL, -- The owner who created the team, or the member removed
+  owner_id                  UUID NOT NULL,
+  listings_applied_at       TIMESTAMP,
+  conversations_applied_at  TIMESTAMP,
+  created_at                TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_events_listings ON organization_events(created_at) WHERE listings_applied_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_organization_events_conversations ON organization_events(created_at) WHERE conversations_applied_at IS NULL;
@@ -10,6 +10,12 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/audit"
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/cors"
+	"github.com/aselahemantha/exoticsLanka/pkg/jwks"
+	"github.com/aselahemantha/exoticsLanka/pkg/migrate"
+	"github.com/aselahemantha/exoticsLanka/pkg/rbac"
 	"github.com/aselahemantha/exoticsLanka/services/comparison-service/internal/config"
 	"github.com/aselahemantha/exoticsLanka/services/comparison-service/internal/handler"
 	"github.com/aselahemantha/exoticsLanka/services/comparison-service/internal/repository"
@@ -38,7 +44,7 @@ func main() {
 	fmt.Println("Connected to PostgreSQL")
 
 	// 3. Run Migrations
-	if err := repository.RunMigrations(dbPool, "migrations"); err != nil {
+	if err := migrate.Run(context.Background(), dbPool, "migrations"); err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
@@ -47,27 +53,27 @@ func main() {
 	svc := service.NewService(repo)
 	h := handler.NewHandler(svc)
 
+	// Token verification keys, fetched from auth-service; suspended and deleted
+	// accounts are rejected straight away and impersonated requests are audited
+	auditSink := audit.NewPostgresSink(dbPool)
+	authMW := auth.NewMiddleware(
+		jwks.NewClient(cfg.JWKSURL, cfg.JWTPublicKeyFile).Keyfunc,
+		auth.WithTokenCheck(auth.AccountStatusCheck(dbPool)),
+		auth.WithImpersonationAudit(auditSink, "comparison-service"),
+	)
+
+	// Role to permission mapping shared by all services
+	authz := rbac.MustLoadAuthorizer(cfg.RBACPolicyFile, auditSink, "comparison-service")
+
 	// 5. Setup Router
 	router := gin.Default()
 
-	// CORS
-	router.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-		c.Next()
-	})
+	router.Use(cors.New())
 
 	api := router.Group("/api/comparison")
 
 	// Protected Routes (User-Specific)
-	api.Use(handler.AuthMiddleware(cfg))
+	api.Use(authMW.Required(), authz.Require(rbac.ComparisonManage))
 	{
 		api.GET("", h.GetList)
 		api.POST("/:listingId", h.Add)
@@ -3,6 +3,8 @@ package handler
 import (
 	"net/http"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
 	"github.com/aselahemantha/exoticsLanka/services/comparison-service/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -18,15 +20,15 @@ func NewHandler(service service.Service) *Handler {
 
 // GET /api/comparison
 func (h *Handler) GetList(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	res, err := h.service.GetComparisonList(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -35,29 +37,29 @@ func (h *Handler) GetList(c *gin.Context) {
 
 // POST /api/comparison/:listingId
 func (h *Handler) Add(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	listingID, err := uuid.Parse(c.Param("listingId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid listing ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid listing ID")
 		return
 	}
 
 	count, err := h.service.AddToComparison(c.Request.Context(), userID, listingID)
 	if err != nil {
 		if err.Error() == "limit exceeded: you can only compare up to 4 vehicles" {
-			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "LIMIT_EXCEEDED", "message": err.Error()}})
+			response.ErrorWithCode(c, http.StatusBadRequest, "LIMIT_EXCEEDED", err.Error())
 			return
 		}
 		if err.Error() == "listing not found or inactive" {
-			c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": err.Error()}})
+			response.ErrorWithCode(c, http.StatusNotFound, "NOT_FOUND", err.Error())
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -70,20 +72,20 @@ func (h *Handler) Add(c *gin.Context) {
 
 // DELETE /api/comparison/:listingId
 func (h *Handler) Remove(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	listingID, err := uuid.Parse(c.Param("listingId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid listing ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid listing ID")
 		return
 	}
 
 	if err := h.service.RemoveFromComparison(c.Request.Context(), userID, listingID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -92,14 +94,14 @@ func (h *Handler) Remove(c *gin.Context) {
 
 // DELETE /api/comparison
 func (h *Handler) Clear(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	if err := h.service.ClearComparison(c.Request.Context(), userID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -108,15 +110,15 @@ func (h *Handler) Clear(c *gin.Context) {
 
 // GET /api/comparison/compare
 func (h *Handler) Compare(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	data, err := h.service.GetComparison(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -125,21 +127,21 @@ func (h *Handler) Compare(c *gin.Context) {
 
 // GET /api/comparison/check/:listingId
 func (h *Handler) Check(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	listingID, err := uuid.Parse(c.Param("listingId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid listing ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid listing ID")
 		return
 	}
 
 	inComparison, err := h.service.CheckStatus(c.Request.Context(), userID, listingID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -1,81 +0,0 @@
-package handler
-
-import (
-	"fmt"
-	"net/http"
-	"strings"
-
-	"github.com/aselahemantha/exoticsLanka/services/comparison-service/internal/config"
-	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
-	"github.com/google/uuid"
-)
-
-func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			c.Abort()
-			return
-		}
-
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
-			c.Abort()
-			return
-		}
-
-		tokenString := parts[1]
-
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(cfg.JWTSecret), nil
-		})
-
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			c.Abort()
-			return
-		}
-
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
-			c.Abort()
-			return
-		}
-
-		sub, ok := claims["sub"].(string)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in token"})
-			c.Abort()
-			return
-		}
-
-		userID, err := uuid.Parse(sub)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid User ID format"})
-			c.Abort()
-			return
-		}
-
-		c.Set("userID", userID)
-		c.Next()
-	}
-}
-
-func GetUserID(c *gin.Context) (uuid.UUID, error) {
-	val, exists := c.Get("userID")
-	if !exists {
-		return uuid.Nil, fmt.Errorf("user ID not found in context")
-	}
-	id, ok := val.(uuid.UUID)
-	if !ok {
-		return uuid.Nil, fmt.Errorf("invalid user ID type in context")
-	}
-	return id, nil
-}
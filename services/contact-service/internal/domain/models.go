@@ -57,10 +57,3 @@ type RespondInquiryRequest struct {
 	AdminResponse string `json:"adminResponse"`
 	Priority      string `json:"priority"`
 }
-
-type Pagination struct {
-	Page       int   `json:"page"`
-	Limit      int   `json:"limit"`
-	Total      int64 `json:"total"`
-	TotalPages int   `json:"totalPages"`
-}
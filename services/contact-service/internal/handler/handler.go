@@ -2,8 +2,10 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
 	"github.com/aselahemantha/exoticsLanka/services/contact-service/internal/domain"
 	"github.com/aselahemantha/exoticsLanka/services/contact-service/internal/service"
 	"github.com/gin-gonic/gin"
@@ -22,7 +24,7 @@ func NewHandler(service service.Service) *Handler {
 func (h *Handler) SubmitInquiry(c *gin.Context) {
 	var req domain.CreateInquiryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -30,17 +32,17 @@ func (h *Handler) SubmitInquiry(c *gin.Context) {
 	meta := make(map[string]interface{})
 	meta["ipAddress"] = c.ClientIP()
 	meta["userAgent"] = c.Request.UserAgent()
-	if userID, err := GetUserID(c); err == nil {
+	if userID, err := auth.GetUserID(c); err == nil {
 		meta["userId"] = userID
 	}
 
 	inq, err := h.service.SubmitInquiry(c.Request.Context(), req, meta)
 	if err != nil {
 		if err.Error() == "too many inquiries. please try again later" {
-			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			response.Error(c, http.StatusTooManyRequests, err.Error())
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -53,16 +55,15 @@ func (h *Handler) SubmitInquiry(c *gin.Context) {
 
 // GET /api/contact (Admin)
 func (h *Handler) GetInquiries(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	params := pagination.FromQuery(c, 20)
 	status := c.Query("status")
 	subject := c.Query("subject")
 	priority := c.Query("priority")
 	search := c.Query("search")
 
-	inquiries, pagination, err := h.service.GetInquiries(c.Request.Context(), status, subject, priority, search, page, limit)
+	inquiries, meta, err := h.service.GetInquiries(c.Request.Context(), status, subject, priority, search, params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -70,7 +71,7 @@ func (h *Handler) GetInquiries(c *gin.Context) {
 		"success": true,
 		"data": gin.H{
 			"inquiries":  inquiries,
-			"pagination": pagination,
+			"pagination": meta,
 		},
 	})
 }
@@ -80,17 +81,17 @@ func (h *Handler) GetInquiry(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid inquiry ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid inquiry ID")
 		return
 	}
 
 	inq, err := h.service.GetInquiry(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 	if inq == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Inquiry not found"})
+		response.Error(c, http.StatusNotFound, "Inquiry not found")
 		return
 	}
 
@@ -99,28 +100,28 @@ func (h *Handler) GetInquiry(c *gin.Context) {
 
 // PUT /api/contact/:id (Admin)
 func (h *Handler) RespondInquiry(c *gin.Context) {
-	adminID, err := GetUserID(c)
+	adminID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid inquiry ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid inquiry ID")
 		return
 	}
 
 	var req domain.RespondInquiryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	inq, err := h.service.RespondInquiry(c.Request.Context(), id, adminID, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -135,7 +136,7 @@ func (h *Handler) RespondInquiry(c *gin.Context) {
 func (h *Handler) GetStats(c *gin.Context) {
 	stats, err := h.service.GetStats(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -1,112 +0,0 @@
-package handler
-
-import (
-	"fmt"
-	"net/http"
-	"strings"
-
-	"github.com/aselahemantha/exoticsLanka/services/contact-service/internal/config"
-	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
-	"github.com/google/uuid"
-)
-
-// AuthMiddleware - standard protected routes
-func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		claims, err := extractClaims(c, cfg)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-			c.Abort()
-			return
-		}
-
-		setContext(c, claims)
-		c.Next()
-	}
-}
-
-// OptionalAuthMiddleware - for public endpoints that can optionally link to a user
-func OptionalAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.Next()
-			return
-		}
-
-		claims, err := extractClaims(c, cfg)
-		if err == nil {
-			setContext(c, claims)
-		}
-		// If error, we just ignore it and treat as anonymous for optional auth
-		c.Next()
-	}
-}
-
-func extractClaims(c *gin.Context, cfg *config.Config) (jwt.MapClaims, error) {
-	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" {
-		return nil, fmt.Errorf("authorization header required")
-	}
-
-	parts := strings.Split(authHeader, " ")
-	if len(parts) != 2 || parts[0] != "Bearer" {
-		return nil, fmt.Errorf("invalid authorization header format")
-	}
-
-	tokenString := parts[1]
-
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(cfg.JWTSecret), nil
-	})
-
-	if err != nil || !token.Valid {
-		return nil, fmt.Errorf("invalid or expired token")
-	}
-
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return nil, fmt.Errorf("invalid token claims")
-	}
-
-	return claims, nil
-}
-
-func setContext(c *gin.Context, claims jwt.MapClaims) {
-	sub, ok := claims["sub"].(string)
-	if ok {
-		if userID, err := uuid.Parse(sub); err == nil {
-			c.Set("userID", userID)
-		}
-	}
-	role, _ := claims["role"].(string)
-	c.Set("userRole", role)
-}
-
-func RequireAdmin() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		role, exists := c.Get("userRole")
-		if !exists || role != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
-			c.Abort()
-			return
-		}
-		c.Next()
-	}
-}
-
-func GetUserID(c *gin.Context) (uuid.UUID, error) {
-	val, exists := c.Get("userID")
-	if !exists {
-		return uuid.Nil, fmt.Errorf("user ID not found in context")
-	}
-	id, ok := val.(uuid.UUID)
-	if !ok {
-		return uuid.Nil, fmt.Errorf("invalid user ID type in context")
-	}
-	return id, nil
-}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
 	"github.com/aselahemantha/exoticsLanka/services/contact-service/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -12,7 +13,7 @@ import (
 
 type Repository interface {
 	CreateInquiry(ctx context.Context, inq *domain.Inquiry) (*domain.Inquiry, error)
-	GetInquiries(ctx context.Context, status, subject, priority, search string, page, limit int) ([]domain.Inquiry, int64, error)
+	GetInquiries(ctx context.Context, status, subject, priority, search string, params pagination.Params) ([]domain.Inquiry, int64, error)
 	GetInquiryByID(ctx context.Context, id uuid.UUID) (*domain.Inquiry, error)
 	UpdateInquiry(ctx context.Context, inq *domain.Inquiry) error
 	GetInquiryStats(ctx context.Context) (*domain.InquiryStats, error)
@@ -42,7 +43,7 @@ func (r *postgresRepository) CreateInquiry(ctx context.Context, inq *domain.Inqu
 	return inq, err
 }
 
-func (r *postgresRepository) GetInquiries(ctx context.Context, status, subject, priority, search string, page, limit int) ([]domain.Inquiry, int64, error) {
+func (r *postgresRepository) GetInquiries(ctx context.Context, status, subject, priority, search string, params pagination.Params) ([]domain.Inquiry, int64, error) {
 	// Base Query
 	query := `
 		SELECT ci.id, ci.name, ci.email, ci.phone, ci.subject, ci.message, ci.status, ci.priority,
@@ -110,7 +111,7 @@ func (r *postgresRepository) GetInquiries(ctx context.Context, status, subject,
 
 	// Pagination
 	query += fmt.Sprintf(" ORDER BY ci.created_at DESC LIMIT $%d OFFSET $%d", argIdx, argIdx+1)
-	args = append(args, limit, (page-1)*limit)
+	args = append(args, params.Limit, params.Offset())
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
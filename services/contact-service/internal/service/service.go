@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
 	"github.com/aselahemantha/exoticsLanka/services/contact-service/internal/domain"
 	"github.com/aselahemantha/exoticsLanka/services/contact-service/internal/repository"
 	"github.com/google/uuid"
@@ -13,7 +14,7 @@ import (
 
 type Service interface {
 	SubmitInquiry(ctx context.Context, req domain.CreateInquiryRequest, metadata map[string]interface{}) (*domain.Inquiry, error)
-	GetInquiries(ctx context.Context, status, subject, priority, search string, page, limit int) ([]domain.Inquiry, domain.Pagination, error)
+	GetInquiries(ctx context.Context, status, subject, priority, search string, params pagination.Params) ([]domain.Inquiry, pagination.Pagination, error)
 	GetInquiry(ctx context.Context, id uuid.UUID) (*domain.Inquiry, error)
 	RespondInquiry(ctx context.Context, id uuid.UUID, adminID uuid.UUID, req domain.RespondInquiryRequest) (*domain.Inquiry, error)
 	GetStats(ctx context.Context) (*domain.InquiryStats, error)
@@ -97,26 +98,17 @@ func (s *service) SubmitInquiry(ctx context.Context, req domain.CreateInquiryReq
 	return createdInq, nil
 }
 
-func (s *service) GetInquiries(ctx context.Context, status, subject, priority, search string, page, limit int) ([]domain.Inquiry, domain.Pagination, error) {
-	inquiries, total, err := s.repo.GetInquiries(ctx, status, subject, priority, search, page, limit)
+func (s *service) GetInquiries(ctx context.Context, status, subject, priority, search string, params pagination.Params) ([]domain.Inquiry, pagination.Pagination, error) {
+	inquiries, total, err := s.repo.GetInquiries(ctx, status, subject, priority, search, params)
 	if err != nil {
-		return nil, domain.Pagination{}, err
-	}
-
-	pagination := domain.Pagination{
-		Page:  page,
-		Limit: limit,
-		Total: total,
-	}
-	if limit > 0 {
-		pagination.TotalPages = int((total + int64(limit) - 1) / int64(limit))
+		return nil, pagination.Pagination{}, err
 	}
 
 	// Decorate Reference Number if needed (mocked for list view as we don't store it)
 	// Ideally we store it. Since we didn't add column, we can't show consistent refs in list.
 	// We will skip ref number in list for now or generate a hash-based one from ID.
 
-	return inquiries, pagination, nil
+	return inquiries, pagination.New(params, total), nil
 }
 
 func (s *service) GetInquiry(ctx context.Context, id uuid.UUID) (*domain.Inquiry, error) {
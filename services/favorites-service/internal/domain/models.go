@@ -6,14 +6,6 @@ import (
 	"github.com/google/uuid"
 )
 
-// Pagination meta data
-type Pagination struct {
-	Page       int   `json:"page"`
-	Limit      int   `json:"limit"`
-	Total      int64 `json:"total"`
-	TotalPages int   `json:"totalPages"`
-}
-
 // Favorite represents a user's favorite listing
 type Favorite struct {
 	ID        uuid.UUID `json:"id"`
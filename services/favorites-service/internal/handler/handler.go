@@ -2,8 +2,10 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
 	"github.com/aselahemantha/exoticsLanka/services/favorites-service/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -18,16 +20,16 @@ func NewHandler(service service.Service) *Handler {
 }
 
 func (h *Handler) AddFavorite(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	listingIDStr := c.Param("listingId")
 	listingID, err := uuid.Parse(listingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid listing ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid listing ID")
 		return
 	}
 
@@ -35,10 +37,10 @@ func (h *Handler) AddFavorite(c *gin.Context) {
 	if err != nil {
 		// Could differentiate between "already exists" and other errors
 		if err.Error() == "listing is already in favorites" { // Hypothetical check if repo/service handled it specific
-			c.JSON(http.StatusConflict, gin.H{"success": false, "error": "Listing is already in favorites"})
+			response.Error(c, http.StatusConflict, "Listing is already in favorites")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -50,26 +52,26 @@ func (h *Handler) AddFavorite(c *gin.Context) {
 }
 
 func (h *Handler) RemoveFavorite(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	listingIDStr := c.Param("listingId")
 	listingID, err := uuid.Parse(listingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid listing ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid listing ID")
 		return
 	}
 
 	err = h.service.RemoveFavorite(c.Request.Context(), userID, listingID)
 	if err != nil {
 		if err.Error() == "favorite not found" {
-			c.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Favorite not found"})
+			response.Error(c, http.StatusNotFound, "Favorite not found")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -80,18 +82,17 @@ func (h *Handler) RemoveFavorite(c *gin.Context) {
 }
 
 func (h *Handler) GetFavorites(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	params := pagination.FromQuery(c, 20)
 
-	favorites, pagination, err := h.service.GetFavorites(c.Request.Context(), userID, page, limit)
+	favorites, meta, err := h.service.GetFavorites(c.Request.Context(), userID, params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -99,28 +100,28 @@ func (h *Handler) GetFavorites(c *gin.Context) {
 		"success": true,
 		"data": gin.H{
 			"favorites":  favorites,
-			"pagination": pagination,
+			"pagination": meta,
 		},
 	})
 }
 
 func (h *Handler) CheckFavorite(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	listingIDStr := c.Param("listingId")
 	listingID, err := uuid.Parse(listingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid listing ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid listing ID")
 		return
 	}
 
 	isFavorited, favoritedAt, err := h.service.CheckFavorite(c.Request.Context(), userID, listingID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -136,15 +137,15 @@ func (h *Handler) CheckFavorite(c *gin.Context) {
 }
 
 func (h *Handler) GetFavoritesCount(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	count, err := h.service.GetFavoritesCount(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -157,15 +158,15 @@ func (h *Handler) GetFavoritesCount(c *gin.Context) {
 }
 
 func (h *Handler) ClearAllFavorites(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	count, err := h.service.ClearAllFavorites(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
 	"github.com/aselahemantha/exoticsLanka/services/favorites-service/internal/domain"
 	"github.com/aselahemantha/exoticsLanka/services/favorites-service/internal/repository"
 	"github.com/google/uuid"
@@ -12,7 +13,7 @@ import (
 type Service interface {
 	AddFavorite(ctx context.Context, userID, listingID uuid.UUID) (*domain.Favorite, error)
 	RemoveFavorite(ctx context.Context, userID, listingID uuid.UUID) error
-	GetFavorites(ctx context.Context, userID uuid.UUID, page, limit int) ([]domain.Favorite, *domain.Pagination, error)
+	GetFavorites(ctx context.Context, userID uuid.UUID, params pagination.Params) ([]domain.Favorite, *pagination.Pagination, error)
 	CheckFavorite(ctx context.Context, userID, listingID uuid.UUID) (bool, *time.Time, error)
 	GetFavoritesCount(ctx context.Context, userID uuid.UUID) (int64, error)
 	ClearAllFavorites(ctx context.Context, userID uuid.UUID) (int64, error)
@@ -40,33 +41,14 @@ func (s *service) RemoveFavorite(ctx context.Context, userID, listingID uuid.UUI
 	return s.repo.RemoveFavorite(ctx, userID, listingID)
 }
 
-func (s *service) GetFavorites(ctx context.Context, userID uuid.UUID, page, limit int) ([]domain.Favorite, *domain.Pagination, error) {
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 {
-		limit = 20
-	}
-	offset := (page - 1) * limit
-
-	favorites, total, err := s.repo.GetFavorites(ctx, userID, limit, offset)
+func (s *service) GetFavorites(ctx context.Context, userID uuid.UUID, params pagination.Params) ([]domain.Favorite, *pagination.Pagination, error) {
+	favorites, total, err := s.repo.GetFavorites(ctx, userID, params.Limit, params.Offset())
 	if err != nil {
 		return nil, nil, err
 	}
 
-	totalPages := 0
-	if limit > 0 {
-		totalPages = int((total + int64(limit) - 1) / int64(limit))
-	}
-
-	pagination := &domain.Pagination{
-		Page:       page,
-		Limit:      limit,
-		Total:      total,
-		TotalPages: totalPages,
-	}
-
-	return favorites, pagination, nil
+	meta := pagination.New(params, total)
+	return favorites, &meta, nil
 }
 
 func (s *service) CheckFavorite(ctx context.Context, userID, listingID uuid.UUID) (bool, *time.Time, error) {
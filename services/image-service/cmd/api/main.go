@@ -6,11 +6,19 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/audit"
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/cors"
+	"github.com/aselahemantha/exoticsLanka/pkg/jwks"
+	"github.com/aselahemantha/exoticsLanka/pkg/rbac"
 	"github.com/aselahemantha/exoticsLanka/services/image-service/internal/config"
 	"github.com/aselahemantha/exoticsLanka/services/image-service/internal/handler"
+	"github.com/aselahemantha/exoticsLanka/services/image-service/internal/jobs"
 	"github.com/aselahemantha/exoticsLanka/services/image-service/internal/repository"
 	"github.com/aselahemantha/exoticsLanka/services/image-service/internal/service"
 	"github.com/aselahemantha/exoticsLanka/services/image-service/internal/storage"
@@ -28,49 +36,91 @@ func main() {
 	}
 	defer dbPool.Close()
 
-	// S3 Client
-	s3Client, err := storage.NewS3Client(cfg.AWSRegion, cfg.S3Bucket, cfg.S3Endpoint)
-	if err != nil {
-		log.Fatalf("Unable to initialize S3 client: %v\n", err)
+	// Storage: S3, or a local directory for development
+	var store storage.Storage
+	var localStore *storage.LocalStorage
+	switch cfg.StorageBackend {
+	case "local":
+		localStore, err = storage.NewLocalStorage(cfg.LocalStorageDir, strings.TrimSuffix(cfg.PublicURL, "/")+"/files")
+		if err != nil {
+			log.Fatalf("Unable to initialize local storage: %v\n", err)
+		}
+		store = localStore
+	case "s3":
+		s3Client, err := storage.NewS3Client(cfg.AWSRegion, cfg.S3Bucket, cfg.S3Endpoint)
+		if err != nil {
+			log.Fatalf("Unable to initialize S3 client: %v\n", err)
+		}
+		store = s3Client
+	default:
+		log.Fatalf("Unknown STORAGE_BACKEND %q: use s3 or local", cfg.StorageBackend)
+	}
+
+	// Chat attachment links are signed by messaging-service with the same secret
+	if cfg.AttachmentURLSecret == "" {
+		log.Fatal("ATTACHMENT_URL_SECRET must be set")
 	}
 
 	// Dependencies
 	repo := repository.NewRepository(dbPool)
-	svc := service.NewService(repo, s3Client)
-	h := handler.NewHandler(svc, cfg.JWTSecret)
-	auth := handler.NewAuthMiddleware(cfg.JWTSecret)
+	svc := service.NewService(repo, store)
+	h := handler.NewHandler(svc, []byte(cfg.AttachmentURLSecret))
+
+	// Deletes the files of attachments removed from the database, e.g. by account erasure
+	jobs.NewJobScheduler(svc).Start()
+
+	// Dealer API keys are accepted on the routes mapped to a scope below
+	auditSink := audit.NewPostgresSink(dbPool)
+	authMW := auth.NewMiddleware(
+		jwks.NewClient(cfg.JWKSURL, cfg.JWTPublicKeyFile).Keyfunc,
+		auth.WithTokenCheck(auth.AccountStatusCheck(dbPool)),
+		auth.WithImpersonationAudit(auditSink, "image-service"),
+		auth.WithAPIKeys(dbPool, auth.APIKeyRoutes{
+			"POST /api/listings/:id/images":        auth.ScopeListingsWrite,
+			"PUT /api/listings/:id/images/reorder": auth.ScopeListingsWrite,
+		}),
+	)
+
+	// Role to permission mapping shared by all services
+	authz := rbac.MustLoadAuthorizer(cfg.RBACPolicyFile, auditSink, "image-service")
 
 	// Router
 	r := gin.Default()
 
-	// CORS (Basic)
-	r.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-		c.Next()
-	})
+	r.Use(cors.New())
+
+	// Only public files are served from local storage; attachments and KYC
+	// documents stay behind their own endpoints
+	if localStore != nil {
+		r.Static("/files/listings", filepath.Join(localStore.Dir(), "listings"))
+		r.Static("/files/avatars", filepath.Join(localStore.Dir(), "avatars"))
+	}
 
 	api := r.Group("/api")
 	{
 		// Listings images
 		listings := api.Group("/listings/:id/images")
-		listings.Use(auth.RequireAuth())
+		listings.Use(authMW.Required())
 		{
-			listings.POST("", h.UploadListingImage)
+			listings.POST("", authz.Require(rbac.ImageUpload), h.UploadListingImage)
 			listings.PUT("/reorder", h.ReorderListingImages)
 			listings.DELETE("/:imageId", h.DeleteListingImage)
 		}
 
 		// User avatar
 		users := api.Group("/users")
-		users.Use(auth.RequireAuth())
+		users.Use(authMW.Required())
+		{
+			users.PUT("/me/avatar", authz.Require(rbac.ImageUpload), h.UploadUserAvatar)
+			users.POST("/me/documents", authz.Require(rbac.ImageUpload), h.UploadVerificationDocument)
+		}
+
+		// Chat attachments: uploads need a session, reads need a signed link
+		attachments := api.Group("/attachments")
 		{
-			users.PUT("/me/avatar", h.UploadUserAvatar)
+			attachments.POST("", authMW.Required(), authz.Require(rbac.MessageSend), h.UploadAttachment)
+			attachments.GET("/:id/content", h.GetAttachment)
+			attachments.GET("/:id/thumbnail", h.GetAttachmentThumbnail)
 		}
 	}
 
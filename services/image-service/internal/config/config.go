@@ -10,12 +10,23 @@ import (
 type Config struct {
 	Port               string
 	DatabaseURL        string
-	JWTSecret          string
+	JWKSURL            string
+	JWTPublicKeyFile   string
+	RBACPolicyFile     string
 	AWSRegion          string
 	AWSAccessKeyID     string
 	AWSSecretAccessKey string
 	S3Bucket           string
 	S3Endpoint         string
+
+	// StorageBackend is "s3" or "local"; local keeps files in LocalStorageDir
+	// and serves public ones under PublicURL/files
+	StorageBackend  string
+	LocalStorageDir string
+	PublicURL       string
+
+	// AttachmentURLSecret verifies the signed chat attachment links issued by messaging-service
+	AttachmentURLSecret string
 }
 
 func LoadConfig() *Config {
@@ -27,12 +38,20 @@ func LoadConfig() *Config {
 	return &Config{
 		Port:               getEnv("PORT", "8091"),
 		DatabaseURL:        getEnv("DATABASE_URL", ""),
-		JWTSecret:          getEnv("JWT_SECRET", ""),
+		JWKSURL:            getEnv("JWKS_URL", "http://localhost:8081/.well-known/jwks.json"),
+		JWTPublicKeyFile:   getEnv("JWT_PUBLIC_KEY_FILE", ""),
+		RBACPolicyFile:     getEnv("RBAC_POLICY_FILE", ""),
 		AWSRegion:          getEnv("AWS_REGION", "us-east-1"),
 		AWSAccessKeyID:     getEnv("AWS_ACCESS_KEY_ID", ""),
 		AWSSecretAccessKey: getEnv("AWS_SECRET_ACCESS_KEY", ""),
 		S3Bucket:           getEnv("S3_BUCKET", "exotics-lanka-images"),
 		S3Endpoint:         getEnv("S3_ENDPOINT", ""),
+
+		StorageBackend:  getEnv("STORAGE_BACKEND", "s3"),
+		LocalStorageDir: getEnv("LOCAL_STORAGE_DIR", "./uploads"),
+		PublicURL:       getEnv("PUBLIC_URL", "http://localhost:8091"),
+
+		AttachmentURLSecret: getEnv("ATTACHMENT_URL_SECRET", ""),
 	}
 }
 
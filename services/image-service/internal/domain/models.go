@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type ImageUploadResponse struct {
 	ID        string `json:"id"`
 	URL       string `json:"url"`
@@ -9,3 +11,30 @@ type ImageUploadResponse struct {
 type ReorderRequest struct {
 	ImageIDs []string `json:"image_ids"`
 }
+
+type DocumentUploadResponse struct {
+	Key         string `json:"key"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+}
+
+// Attachment kinds
+const (
+	AttachmentImage    = "image"
+	AttachmentDocument = "document"
+)
+
+// Attachment is a file uploaded to a conversation. Files are private: they are
+// read through links that messaging-service signs for the participants.
+type Attachment struct {
+	ID             string    `json:"id"`
+	ConversationID string    `json:"conversation_id"`
+	UploaderID     string    `json:"uploader_id"`
+	Kind           string    `json:"kind"`
+	ContentType    string    `json:"content_type"`
+	FileName       string    `json:"file_name"`
+	SizeBytes      int64     `json:"size_bytes"`
+	StorageKey     string    `json:"-"`
+	ThumbnailKey   *string   `json:"-"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
+	"github.com/aselahemantha/exoticsLanka/pkg/signedurl"
+	"github.com/gin-gonic/gin"
+)
+
+// POST /api/attachments - a photo or PDF to send in a conversation
+func (h *Handler) UploadAttachment(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	conversationID := c.PostForm("conversationId")
+	if conversationID == "" {
+		response.Error(c, http.StatusBadRequest, "conversationId is required")
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Attachment file required")
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.service.UploadAttachment(c.Request.Context(), conversationID, userID.String(), file, header)
+	if err != nil {
+		switch {
+		case err.Error() == "invalid conversation ID":
+			response.Error(c, http.StatusBadRequest, err.Error())
+		case strings.HasPrefix(err.Error(), "unauthorized"):
+			response.Error(c, http.StatusForbidden, err.Error())
+		case strings.HasPrefix(err.Error(), "attachment exceeds"):
+			response.Error(c, http.StatusRequestEntityTooLarge, err.Error())
+		case strings.HasPrefix(err.Error(), "unsupported attachment type"), strings.HasPrefix(err.Error(), "failed to decode image"):
+			response.Error(c, http.StatusUnsupportedMediaType, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": attachment})
+}
+
+// GET /api/attachments/:id/content - through a link signed by messaging-service
+func (h *Handler) GetAttachment(c *gin.Context) {
+	h.serveAttachment(c, false)
+}
+
+// GET /api/attachments/:id/thumbnail - through a link signed by messaging-service
+func (h *Handler) GetAttachmentThumbnail(c *gin.Context) {
+	h.serveAttachment(c, true)
+}
+
+func (h *Handler) serveAttachment(c *gin.Context, thumbnail bool) {
+	if err := signedurl.Verify(h.attachmentSecret, c.Request.URL.Path, c.Request.URL.Query(), time.Now()); err != nil {
+		response.Error(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	attachment, file, err := h.service.OpenAttachment(c.Request.Context(), c.Param("id"), thumbnail)
+	if err != nil {
+		if err.Error() == "attachment not found" {
+			response.Error(c, http.StatusNotFound, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer file.Close()
+
+	disposition := "inline"
+	if attachment.FileName != "" {
+		disposition = mime.FormatMediaType("inline", map[string]string{"filename": attachment.FileName})
+	}
+	c.Header("Content-Type", attachment.ContentType)
+	c.Header("Content-Disposition", disposition)
+	c.Header("Cache-Control", "private, max-age=600")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Status(http.StatusOK)
+	_, _ = io.Copy(c.Writer, file)
+}
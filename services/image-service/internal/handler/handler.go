@@ -3,37 +3,43 @@ package handler
 import (
 	"net/http"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
 	"github.com/aselahemantha/exoticsLanka/services/image-service/internal/domain"
 	"github.com/aselahemantha/exoticsLanka/services/image-service/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
 type Handler struct {
-	service    *service.Service
-	authSecret string
+	service          *service.Service
+	attachmentSecret []byte
 }
 
-func NewHandler(service *service.Service, authSecret string) *Handler {
+func NewHandler(service *service.Service, attachmentSecret []byte) *Handler {
 	return &Handler{
-		service:    service,
-		authSecret: authSecret,
+		service:          service,
+		attachmentSecret: attachmentSecret,
 	}
 }
 
 func (h *Handler) UploadListingImage(c *gin.Context) {
 	listingID := c.Param("id")
-	userID := c.GetString("userID")
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
 
 	file, header, err := c.Request.FormFile("image")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Image file required"})
+		response.Error(c, http.StatusBadRequest, "Image file required")
 		return
 	}
 	defer file.Close()
 
-	resp, err := h.service.UploadListingImage(c.Request.Context(), listingID, userID, file, header)
+	resp, err := h.service.UploadListingImage(c.Request.Context(), listingID, userID.String(), file, header)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -42,17 +48,21 @@ func (h *Handler) UploadListingImage(c *gin.Context) {
 
 func (h *Handler) ReorderListingImages(c *gin.Context) {
 	listingID := c.Param("id")
-	userID := c.GetString("userID")
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
 
 	var req domain.ReorderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	err := h.service.ReorderImages(c.Request.Context(), listingID, userID, req.ImageIDs)
+	err = h.service.ReorderImages(c.Request.Context(), listingID, userID.String(), req.ImageIDs)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -62,11 +72,15 @@ func (h *Handler) ReorderListingImages(c *gin.Context) {
 func (h *Handler) DeleteListingImage(c *gin.Context) {
 	_ = c.Param("id") // listingID - not strictly needed if we just delete by imageID, but good for URL structure
 	imageID := c.Param("imageId")
-	userID := c.GetString("userID")
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
 
-	err := h.service.DeleteListingImage(c.Request.Context(), imageID, userID)
+	err = h.service.DeleteListingImage(c.Request.Context(), imageID, userID.String())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -74,20 +88,48 @@ func (h *Handler) DeleteListingImage(c *gin.Context) {
 }
 
 func (h *Handler) UploadUserAvatar(c *gin.Context) {
-	userID := c.GetString("userID")
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
 
 	file, header, err := c.Request.FormFile("avatar")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Avatar file required"})
+		response.Error(c, http.StatusBadRequest, "Avatar file required")
 		return
 	}
 	defer file.Close()
 
-	url, err := h.service.UploadUserAvatar(c.Request.Context(), userID, file, header)
+	url, err := h.service.UploadUserAvatar(c.Request.Context(), userID.String(), file, header)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"url": url}})
 }
+
+// POST /api/users/me/documents - business verification documents for dealer applications
+func (h *Handler) UploadVerificationDocument(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	file, header, err := c.Request.FormFile("document")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Document file required")
+		return
+	}
+	defer file.Close()
+
+	resp, err := h.service.UploadVerificationDocument(c.Request.Context(), userID.String(), file, header)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": resp})
+}
@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/services/image-service/internal/service"
+)
+
+// jobInterval bounds how long the files of a removed attachment stay in storage
+const jobInterval = 10 * time.Minute
+
+type JobScheduler struct {
+	svc *service.Service
+}
+
+func NewJobScheduler(svc *service.Service) *JobScheduler {
+	return &JobScheduler{svc: svc}
+}
+
+func (s *JobScheduler) Start() {
+	go s.runPurgeJobs()
+}
+
+func (s *JobScheduler) runPurgeJobs() {
+	ticker := time.NewTicker(jobInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if n, err := s.svc.PurgeAttachmentFiles(context.Background()); err != nil {
+			log.Printf("Error purging attachment files: %v", err)
+		} else if n > 0 {
+			log.Printf("Deleted %d attachment files", n)
+		}
+	}
+}
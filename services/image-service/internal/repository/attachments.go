@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aselahemantha/exoticsLanka/services/image-service/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// IsConversationParticipant applies messaging-service's rules in the shared
+// database: the buyer, the seller, or for a dealer organisation's conversation
+// its owners and managers and the member it is assigned to.
+func (r *Repository) IsConversationParticipant(ctx context.Context, conversationID, userID string) (bool, error) {
+	var ok bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM conversations c
+			LEFT JOIN organization_members om ON om.organization_id = c.organization_id AND om.user_id = $2
+			WHERE c.id = $1 AND (
+				c.buyer_id = $2
+				OR (c.organization_id IS NULL AND c.seller_id = $2)
+				OR (om.user_id IS NOT NULL AND (om.role IN ('owner', 'manager') OR c.assigned_to = $2))
+			)
+		)
+	`
+	err := r.db.QueryRow(ctx, query, conversationID, userID).Scan(&ok)
+	if err != nil {
+		return false, fmt.Errorf("failed to check conversation participant: %w", err)
+	}
+	return ok, nil
+}
+
+// CreateAttachment records an uploaded chat attachment. messaging-service links
+// it to a message when it is sent.
+func (r *Repository) CreateAttachment(ctx context.Context, a *domain.Attachment) error {
+	query := `
+		INSERT INTO message_attachments (
+			id, conversation_id, uploader_id, kind, content_type, file_name, size_bytes, storage_key, thumbnail_key
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at
+	`
+	err := r.db.QueryRow(ctx, query,
+		a.ID, a.ConversationID, a.UploaderID, a.Kind, a.ContentType, a.FileName, a.SizeBytes, a.StorageKey, a.ThumbnailKey,
+	).Scan(&a.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert attachment: %w", err)
+	}
+	return nil
+}
+
+// GetAttachment returns nil if the attachment does not exist
+func (r *Repository) GetAttachment(ctx context.Context, id string) (*domain.Attachment, error) {
+	var a domain.Attachment
+	query := `
+		SELECT id, conversation_id, uploader_id, kind, content_type, file_name, size_bytes, storage_key, thumbnail_key, created_at
+		FROM message_attachments
+		WHERE id = $1
+	`
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&a.ID, &a.ConversationID, &a.UploaderID, &a.Kind, &a.ContentType, &a.FileName, &a.SizeBytes, &a.StorageKey, &a.ThumbnailKey, &a.CreatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	return &a, nil
+}
+
+// GetAttachmentDeletions returns up to limit storage keys of removed
+// attachments whose files are still to be deleted
+func (r *Repository) GetAttachmentDeletions(ctx context.Context, limit int) ([]string, error) {
+	rows, err := r.db.Query(ctx, `SELECT storage_key FROM attachment_deletions ORDER BY created_at LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment deletions: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// CompleteAttachmentDeletion removes a key once its file has been deleted
+func (r *Repository) CompleteAttachmentDeletion(ctx context.Context, key string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM attachment_deletions WHERE storage_key = $1`, key)
+	if err != nil {
+		return fmt.Errorf("failed to complete attachment deletion: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/aselahemantha/exoticsLanka/services/image-service/internal/domain"
+	"github.com/disintegration/imaging"
+	"github.com/google/uuid"
+)
+
+// maxAttachmentSize caps chat attachments at 10MB
+const maxAttachmentSize = 10 << 20
+
+// attachmentTypes are the content types accepted in chat, with their kind
+var attachmentTypes = map[string]string{
+	"image/jpeg":      domain.AttachmentImage,
+	"image/png":       domain.AttachmentImage,
+	"application/pdf": domain.AttachmentDocument,
+}
+
+// Chat photos are stored up to attachmentMaxSide pixels, with a thumbnail for the conversation view
+const (
+	attachmentMaxSide = 2560
+	thumbnailMaxSide  = 320
+)
+
+// UploadAttachment stores a photo or PDF sent in a conversation the user takes
+// part in. Photos are re-encoded, which also strips metadata such as their GPS
+// position, and get a thumbnail; PDFs are stored as-is.
+func (s *Service) UploadAttachment(ctx context.Context, conversationID, userID string, file multipart.File, header *multipart.FileHeader) (*domain.Attachment, error) {
+	if _, err := uuid.Parse(conversationID); err != nil {
+		return nil, fmt.Errorf("invalid conversation ID")
+	}
+	ok, err := s.repo.IsConversationParticipant(ctx, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("unauthorized: not a participant in this conversation")
+	}
+
+	if header.Size > maxAttachmentSize {
+		return nil, fmt.Errorf("attachment exceeds the %dMB limit", maxAttachmentSize>>20)
+	}
+
+	// Detect the type from the content rather than trusting the client
+	sniff := make([]byte, 512)
+	n, err := io.ReadFull(file, sniff)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	contentType := http.DetectContentType(sniff[:n])
+	kind, ok := attachmentTypes[contentType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported attachment type %s: use JPEG, PNG or PDF", contentType)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	id := uuid.New().String()
+	prefix := fmt.Sprintf("attachments/%s/%s", conversationID, id)
+	attachment := &domain.Attachment{
+		ID:             id,
+		ConversationID: conversationID,
+		UploaderID:     userID,
+		Kind:           kind,
+		ContentType:    contentType,
+		FileName:       attachmentName(header.Filename),
+		SizeBytes:      header.Size,
+	}
+
+	var keys []string
+	if kind == domain.AttachmentImage {
+		img, err := imaging.Decode(file, imaging.AutoOrientation(true))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode image: %w", err)
+		}
+
+		full, err := encodeJPEG(imaging.Fit(img, attachmentMaxSide, attachmentMaxSide, imaging.Lanczos), 85)
+		if err != nil {
+			return nil, err
+		}
+		thumb, err := encodeJPEG(imaging.Fit(img, thumbnailMaxSide, thumbnailMaxSide, imaging.Lanczos), 75)
+		if err != nil {
+			return nil, err
+		}
+
+		attachment.ContentType = "image/jpeg"
+		attachment.SizeBytes = int64(full.Len())
+		attachment.FileName = strings.TrimSuffix(attachment.FileName, filepath.Ext(attachment.FileName)) + ".jpg"
+		attachment.StorageKey = prefix + ".jpg"
+		thumbKey := prefix + "_thumb.jpg"
+		attachment.ThumbnailKey = &thumbKey
+
+		if _, err := s.storage.UploadFile(attachment.StorageKey, full, "image/jpeg"); err != nil {
+			return nil, err
+		}
+		keys = append(keys, attachment.StorageKey)
+		if _, err := s.storage.UploadFile(thumbKey, thumb, "image/jpeg"); err != nil {
+			s.deleteFiles(keys)
+			return nil, err
+		}
+		keys = append(keys, thumbKey)
+	} else {
+		attachment.StorageKey = prefix + ".pdf"
+		if _, err := s.storage.UploadFile(attachment.StorageKey, file, contentType); err != nil {
+			return nil, err
+		}
+		keys = append(keys, attachment.StorageKey)
+	}
+
+	if err := s.repo.CreateAttachment(ctx, attachment); err != nil {
+		s.deleteFiles(keys)
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// OpenAttachment reads an attachment, or its thumbnail. Callers check the
+// signed link first: the link is what grants access.
+func (s *Service) OpenAttachment(ctx context.Context, id string, thumbnail bool) (*domain.Attachment, io.ReadCloser, error) {
+	attachment, err := s.repo.GetAttachment(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attachment == nil {
+		return nil, nil, fmt.Errorf("attachment not found")
+	}
+
+	key := attachment.StorageKey
+	if thumbnail {
+		if attachment.ThumbnailKey == nil {
+			return nil, nil, fmt.Errorf("attachment not found")
+		}
+		key = *attachment.ThumbnailKey
+		attachment.ContentType = "image/jpeg"
+	}
+
+	file, err := s.storage.Open(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, file, nil
+}
+
+// attachmentDeletionBatch caps how many files one purge deletes
+const attachmentDeletionBatch = 500
+
+// PurgeAttachmentFiles deletes the files of attachments removed from the
+// database, such as those of an erased account. Files that fail to delete
+// are retried on the next run.
+func (s *Service) PurgeAttachmentFiles(ctx context.Context) (int, error) {
+	keys, err := s.repo.GetAttachmentDeletions(ctx, attachmentDeletionBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, key := range keys {
+		if err := s.storage.DeleteFile(key); err != nil {
+			log.Printf("Error deleting attachment file %s: %v", key, err)
+			continue
+		}
+		if err := s.repo.CompleteAttachmentDeletion(ctx, key); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+func (s *Service) deleteFiles(keys []string) {
+	for _, key := range keys {
+		_ = s.storage.DeleteFile(key)
+	}
+}
+
+func encodeJPEG(img image.Image, quality int) (*bytes.Buffer, error) {
+	buf := new(bytes.Buffer)
+	if err := jpeg.Encode(buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf, nil
+}
+
+// attachmentName keeps the uploaded file's base name for display and downloads
+func attachmentName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" || name == "" {
+		name = "attachment"
+	}
+	if len(name) > 200 {
+		name = name[len(name)-200:]
+	}
+	return name
+}
@@ -5,7 +5,9 @@ import (
 	"context"
 	"fmt"
 	"image/jpeg"
+	"io"
 	"mime/multipart"
+	"net/http"
 	"path/filepath"
 	"strings"
 
@@ -18,10 +20,10 @@ import (
 
 type Service struct {
 	repo    *repository.Repository
-	storage *storage.S3Client
+	storage storage.Storage
 }
 
-func NewService(repo *repository.Repository, storage *storage.S3Client) *Service {
+func NewService(repo *repository.Repository, storage storage.Storage) *Service {
 	return &Service{
 		repo:    repo,
 		storage: storage,
@@ -173,3 +175,48 @@ func (s *Service) UploadUserAvatar(ctx context.Context, userID string, file mult
 
 	return url, nil
 }
+
+// maxDocumentSize caps business verification documents at 10MB
+const maxDocumentSize = 10 << 20
+
+// documentTypes are the content types accepted for verification documents, with their file extension
+var documentTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+// UploadVerificationDocument stores a dealer KYC document as-is under the user's
+// kyc/ prefix. Unlike photos it is not re-encoded, so scans stay legible.
+func (s *Service) UploadVerificationDocument(ctx context.Context, userID string, file multipart.File, header *multipart.FileHeader) (*domain.DocumentUploadResponse, error) {
+	if header.Size > maxDocumentSize {
+		return nil, fmt.Errorf("document exceeds the %dMB limit", maxDocumentSize>>20)
+	}
+
+	// Detect the type from the content rather than trusting the client
+	sniff := make([]byte, 512)
+	n, err := io.ReadFull(file, sniff)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("failed to read document: %w", err)
+	}
+	contentType := http.DetectContentType(sniff[:n])
+	ext, ok := documentTypes[contentType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported document type %s: use PDF, JPEG or PNG", contentType)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("kyc/%s/%s%s", userID, uuid.New().String(), ext)
+	url, err := s.storage.UploadFile(key, file, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.DocumentUploadResponse{
+		Key:         key,
+		URL:         url,
+		ContentType: contentType,
+	}, nil
+}
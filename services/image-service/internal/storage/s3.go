@@ -64,6 +64,17 @@ func (s *S3Client) UploadFile(key string, file io.Reader, contentType string) (s
 	return url, nil
 }
 
+func (s *S3Client) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file from S3: %w", err)
+	}
+	return out.Body, nil
+}
+
 func (s *S3Client) DeleteFile(key string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -0,0 +1,17 @@
+// Package storage keeps uploaded files in S3 or, for local development and
+// single-host deployments, on the local filesystem.
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+// Storage stores files by key, e.g. "listings/<listing id>/<uuid>.jpg".
+// UploadFile returns the file's public URL; files under private prefixes such
+// as attachments/ are instead read back with Open and served by image-service.
+type Storage interface {
+	UploadFile(key string, file io.Reader, contentType string) (string, error)
+	DeleteFile(key string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+}
@@ -9,6 +9,12 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/audit"
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/cors"
+	"github.com/aselahemantha/exoticsLanka/pkg/jwks"
+	"github.com/aselahemantha/exoticsLanka/pkg/migrate"
+	"github.com/aselahemantha/exoticsLanka/pkg/rbac"
 	"github.com/aselahemantha/exoticsLanka/services/listings-service/internal/config"
 	"github.com/aselahemantha/exoticsLanka/services/listings-service/internal/handler"
 	"github.com/aselahemantha/exoticsLanka/services/listings-service/internal/jobs"
@@ -38,7 +44,7 @@ func main() {
 	log.Println("Connected to PostgreSQL")
 
 	// 3. Run Migrations
-	if err := repository.RunMigrations(context.Background(), dbPool, "migrations"); err != nil {
+	if err := migrate.Run(context.Background(), dbPool, "migrations"); err != nil {
 		log.Printf("Warning: Failed to run migrations: %v", err)
 	}
 
@@ -51,8 +57,27 @@ func main() {
 	jobScheduler := jobs.NewJobScheduler(dbPool)
 	jobScheduler.Start()
 
+	// Token verification keys, fetched from auth-service; suspended and deleted
+	// accounts are rejected straight away and impersonated requests are audited.
+	// Dealer API keys are accepted on the routes mapped to a scope below.
+	auditSink := audit.NewPostgresSink(dbPool)
+	authMW := auth.NewMiddleware(
+		jwks.NewClient(cfg.JWKSURL, cfg.JWTPublicKeyFile).Keyfunc,
+		auth.WithTokenCheck(auth.AccountStatusCheck(dbPool)),
+		auth.WithImpersonationAudit(auditSink, "listings-service"),
+		auth.WithAPIKeys(dbPool, auth.APIKeyRoutes{
+			"POST /api/listings":    auth.ScopeListingsWrite,
+			"GET /api/listings":     auth.ScopeListingsRead,
+			"GET /api/listings/:id": auth.ScopeListingsRead,
+		}),
+	)
+
+	// Role to permission mapping shared by all services
+	authz := rbac.MustLoadAuthorizer(cfg.RBACPolicyFile, auditSink, "listings-service")
+
 	// 6. Setup Router
 	router := gin.Default()
+	router.Use(cors.New())
 
 	// Health check
 	router.GET("/health", func(c *gin.Context) {
@@ -60,15 +85,19 @@ func main() {
 	})
 
 	api := router.Group("/api")
-	api.Use(handler.AuthMiddleware(cfg))
+	api.Use(authMW.Required())
 	{
 		// Listings
-		api.POST("/listings", h.CreateListing)
+		api.POST("/listings", authz.Require(rbac.ListingCreate), h.CreateListing)
 		api.GET("/listings", h.GetListings)
 		api.GET("/listings/:id", h.GetListing)
 		api.GET("/listings/featured", h.GetFeatured)
 		api.GET("/listings/trending", h.GetTrending)
 
+		// Dealer organisation inventory
+		api.GET("/listings/organization", h.GetOrganizationListings)
+		api.PUT("/listings/:id/assign", h.AssignListing)
+
 		// Brands
 		api.GET("/brands", h.GetBrands)
 	}
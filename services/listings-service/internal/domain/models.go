@@ -23,12 +23,18 @@ const (
 	StatusSold     = "sold"
 	StatusExpired  = "expired"
 	StatusRejected = "rejected"
+
+	// Set by the seller when they accept an offer in messaging-service
+	StatusReserved    = "reserved"
+	StatusPendingSale = "pending_sale"
 )
 
 // CarListing represents a car listing in the system
 type CarListing struct {
 	ID             uuid.UUID  `json:"id" db:"id"`
 	UserID         uuid.UUID  `json:"userId" db:"user_id"`
+	OrganizationID *uuid.UUID `json:"organizationId,omitempty" db:"organization_id"` // Dealer organisation that owns the listing
+	AssignedTo     *uuid.UUID `json:"assignedTo,omitempty" db:"assigned_to"`         // Salesperson responsible for it
 	Title          string     `json:"title" db:"title"`
 	Make           string     `json:"make" db:"make"`
 	Model          string     `json:"model" db:"model"`
@@ -64,6 +70,12 @@ type CarListing struct {
 	PublishedAt    *time.Time `json:"publishedAt,omitempty" db:"published_at"`
 	ExpiresAt      *time.Time `json:"expiresAt,omitempty" db:"expires_at"`
 
+	// The offer accepted in messaging-service, if any
+	AcceptedOfferID       *uuid.UUID `json:"acceptedOfferId,omitempty" db:"accepted_offer_id"`
+	AcceptedOfferAmount   *float64   `json:"acceptedOfferAmount,omitempty" db:"accepted_offer_amount"`
+	AcceptedOfferCurrency *string    `json:"acceptedOfferCurrency,omitempty" db:"accepted_offer_currency"`
+	OfferAcceptedAt       *time.Time `json:"offerAcceptedAt,omitempty" db:"offer_accepted_at"`
+
 	// Associations - populated separately
 	Images   []ListingImage   `json:"images,omitempty" db:"-"`
 	Features []ListingFeature `json:"features,omitempty" db:"-"`
@@ -137,6 +149,11 @@ type UpdateListingRequest struct {
 	Features     []string `json:"features"` // Replaces all features if provided
 }
 
+// AssignListingRequest hands an organisation's listing to a team member
+type AssignListingRequest struct {
+	AssignedTo uuid.UUID `json:"assignedTo" binding:"required"`
+}
+
 // User struct for embedding user info in response
 type User struct {
 	ID        uuid.UUID `json:"id"`
@@ -5,6 +5,9 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
 	"github.com/aselahemantha/exoticsLanka/services/listings-service/internal/domain"
 	"github.com/aselahemantha/exoticsLanka/services/listings-service/internal/repository"
 	"github.com/aselahemantha/exoticsLanka/services/listings-service/internal/service"
@@ -25,26 +28,26 @@ func NewHandler(svc service.Service) *Handler {
 func (h *Handler) CreateListing(c *gin.Context) {
 	var req domain.CreateListingRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// Get user ID from legacy header or fall back to context auth
 	// Ideally we switch entirely to GetUserID
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
 		// Fallback for transition or dev (if desired), otherwise:
-		// c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		// response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		// return
 
 		// For now, let's strictly require auth via middleware
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	listing, err := h.svc.CreateListing(c.Request.Context(), &req, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -64,13 +67,13 @@ func (h *Handler) GetListing(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid listing ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid listing ID")
 		return
 	}
 
 	listing, err := h.svc.GetListing(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusNotFound, err.Error())
 		return
 	}
 
@@ -84,8 +87,7 @@ func (h *Handler) GetListing(c *gin.Context) {
 // GET /api/listings
 func (h *Handler) GetListings(c *gin.Context) {
 	// Parse query params
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	params := pagination.FromQuery(c, 20)
 	minPrice, _ := strconv.ParseFloat(c.Query("minPrice"), 64)
 	maxPrice, _ := strconv.ParseFloat(c.Query("maxPrice"), 64)
 
@@ -94,8 +96,7 @@ func (h *Handler) GetListings(c *gin.Context) {
 		MinPrice: minPrice,
 		MaxPrice: maxPrice,
 		SortBy:   c.Query("sortBy"),
-		Page:     page,
-		Limit:    limit,
+		Page:     params,
 		// Add other filters parsing...
 	}
 
@@ -105,32 +106,105 @@ func (h *Handler) GetListings(c *gin.Context) {
 
 	listings, total, err := h.svc.GetListings(c.Request.Context(), filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	totalPages := (total + limit - 1) / limit
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"listings":   listings,
+			"pagination": pagination.New(params, int64(total)),
+		},
+	})
+}
+
+// GetOrganizationListings handles the dealer team's inventory
+// GET /api/listings/organization?assignedTo=
+func (h *Handler) GetOrganizationListings(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var assignedTo *uuid.UUID
+	if v := c.Query("assignedTo"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid assignedTo user ID")
+			return
+		}
+		assignedTo = &id
+	}
+
+	params := pagination.FromQuery(c, 20)
+	listings, total, err := h.svc.GetOrganizationListings(c.Request.Context(), userID, assignedTo, params)
+	if err != nil {
+		if err.Error() == "you are not a member of a dealer organisation" {
+			response.Error(c, http.StatusForbidden, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
-			"listings": listings,
-			"pagination": gin.H{
-				"page":       page,
-				"limit":      limit,
-				"total":      total,
-				"totalPages": totalPages,
-			},
+			"listings":   listings,
+			"pagination": pagination.New(params, int64(total)),
 		},
 	})
 }
 
+// AssignListing hands a team listing to another member
+// PUT /api/listings/:id/assign
+func (h *Handler) AssignListing(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid listing ID")
+		return
+	}
+
+	var req domain.AssignListingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.svc.AssignListing(c.Request.Context(), userID, id, &req); err != nil {
+		switch err.Error() {
+		case "only organisation owners and managers can assign listings":
+			response.Error(c, http.StatusForbidden, err.Error())
+		case "listing not found":
+			response.Error(c, http.StatusNotFound, err.Error())
+		case "assignee is not a member of your organisation":
+			response.Error(c, http.StatusBadRequest, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Listing assigned",
+	})
+}
+
 // GetFeatured handles getting featured listings
 // GET /api/listings/featured
 func (h *Handler) GetFeatured(c *gin.Context) {
 	listings, err := h.svc.GetFeaturedListings(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -145,7 +219,7 @@ func (h *Handler) GetFeatured(c *gin.Context) {
 func (h *Handler) GetTrending(c *gin.Context) {
 	listings, err := h.svc.GetTrendingListings(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -160,7 +234,7 @@ func (h *Handler) GetTrending(c *gin.Context) {
 func (h *Handler) GetBrands(c *gin.Context) {
 	brands, err := h.svc.GetBrands(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
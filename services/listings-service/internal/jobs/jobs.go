@@ -5,9 +5,14 @@ import (
 	"log"
 	"time"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/org"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// organizationEventBatch is how many dealer team changes are applied per minute
+const organizationEventBatch = 100
+
 type JobScheduler struct {
 	db *pgxpool.Pool
 }
@@ -19,6 +24,7 @@ func NewJobScheduler(db *pgxpool.Pool) *JobScheduler {
 func (s *JobScheduler) Start() {
 	go s.runDailyJobs()
 	go s.runHourlyJobs()
+	go s.runMinuteJobs()
 }
 
 func (s *JobScheduler) runDailyJobs() {
@@ -60,6 +66,41 @@ func (s *JobScheduler) runHourlyJobs() {
 	}
 }
 
+func (s *JobScheduler) runMinuteJobs() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx := context.Background()
+		if n, err := s.applyOrganizationEvents(ctx); err != nil {
+			log.Printf("Error applying organisation events: %v", err)
+		} else if n > 0 {
+			log.Printf("Applied %d organisation events to listings", n)
+		}
+	}
+}
+
+// applyOrganizationEvents brings listings in line with dealer team changes
+// made in auth-service
+func (s *JobScheduler) applyOrganizationEvents(ctx context.Context) (int, error) {
+	return org.ApplyEvents(ctx, s.db, org.ConsumerListings, organizationEventBatch, func(ctx context.Context, tx pgx.Tx, event org.Event) error {
+		var err error
+		switch event.Type {
+		case org.EventCreated:
+			// The owner's inventory joins the team, still assigned to them
+			_, err = tx.Exec(ctx, `
+				UPDATE car_listings SET organization_id = $1, assigned_to = user_id
+				WHERE user_id = $2 AND organization_id IS NULL
+			`, event.OrganizationID, event.UserID)
+		case org.EventMemberRemoved:
+			_, err = tx.Exec(ctx, `
+				UPDATE car_listings SET assigned_to = $3 WHERE organization_id = $1 AND assigned_to = $2
+			`, event.OrganizationID, event.UserID, event.OwnerID)
+		}
+		return err
+	})
+}
+
 func (s *JobScheduler) updateDaysListed(ctx context.Context) error {
 	_, err := s.db.Exec(ctx, `
 		UPDATE car_listings 
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/org"
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
 	"github.com/aselahemantha/exoticsLanka/services/listings-service/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -27,6 +29,15 @@ type Repository interface {
 	GetTrendingListings(ctx context.Context, limit int) ([]*domain.CarListing, error)
 	GetListingsByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.CarListing, error)
 
+	// Dealer organisations
+	GetOrganizationListings(ctx context.Context, orgID uuid.UUID, assignedTo *uuid.UUID, params pagination.Params) ([]*domain.CarListing, int, error)
+	AssignListing(ctx context.Context, id, assignedTo uuid.UUID) error
+	GetMembership(ctx context.Context, userID uuid.UUID) (*org.Membership, error)
+	IsOrganizationMember(ctx context.Context, orgID, userID uuid.UUID) (bool, error)
+
+	// Sellers
+	IsVerifiedDealer(ctx context.Context, userID uuid.UUID) (bool, error)
+
 	// Brands
 	GetBrands(ctx context.Context) ([]*domain.CarBrand, error)
 	CreateBrand(ctx context.Context, brand *domain.CarBrand) error
@@ -58,8 +69,7 @@ type ListingFilter struct {
 	Conditions    []string
 	Status        string
 	SortBy        string
-	Page          int
-	Limit         int
+	Page          pagination.Params
 }
 
 // CreateListing inserts a new listing into the database
@@ -77,13 +87,13 @@ func (r *postgresRepository) CreateListing(ctx context.Context, listing *domain.
 			transmission, fuel_type, body_type, color, doors, seats, engine_size, drivetrain,
 			description, location, contact_phone, contact_email, status,
 			health_score, is_new, is_featured, is_verified, trending,
-			created_at, updated_at
+			created_at, updated_at, organization_id, assigned_to
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8, $9,
 			$10, $11, $12, $13, $14, $15, $16, $17,
 			$18, $19, $20, $21, $22,
 			$23, $24, $25, $26, $27,
-			$28, $29
+			$28, $29, $30, $31
 		)
 	`
 	_, err = tx.Exec(ctx, query,
@@ -91,7 +101,7 @@ func (r *postgresRepository) CreateListing(ctx context.Context, listing *domain.
 		listing.Transmission, listing.FuelType, listing.BodyType, listing.Color, listing.Doors, listing.Seats, listing.EngineSize, listing.Drivetrain,
 		listing.Description, listing.Location, listing.ContactPhone, listing.ContactEmail, listing.Status,
 		listing.HealthScore, listing.IsNew, listing.IsFeatured, listing.IsVerified, listing.Trending,
-		listing.CreatedAt, listing.UpdatedAt,
+		listing.CreatedAt, listing.UpdatedAt, listing.OrganizationID, listing.AssignedTo,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert listing: %w", err)
@@ -119,7 +129,9 @@ func (r *postgresRepository) GetListingByID(ctx context.Context, id uuid.UUID) (
 			   health_score, views, favorites_count, days_listed,
 			   is_new, is_featured, is_verified, trending,
 			   market_avg_price, price_alert,
-			   created_at, updated_at, published_at, expires_at
+			   created_at, updated_at, published_at, expires_at,
+			   organization_id, assigned_to,
+			   accepted_offer_id, accepted_offer_amount, accepted_offer_currency, offer_accepted_at
 		FROM car_listings
 		WHERE id = $1
 	`
@@ -133,6 +145,8 @@ func (r *postgresRepository) GetListingByID(ctx context.Context, id uuid.UUID) (
 		&l.IsNew, &l.IsFeatured, &l.IsVerified, &l.Trending,
 		&l.MarketAvgPrice, &l.PriceAlert,
 		&l.CreatedAt, &l.UpdatedAt, &l.PublishedAt, &l.ExpiresAt,
+		&l.OrganizationID, &l.AssignedTo,
+		&l.AcceptedOfferID, &l.AcceptedOfferAmount, &l.AcceptedOfferCurrency, &l.OfferAcceptedAt,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -223,6 +237,69 @@ func (r *postgresRepository) GetListingsByUserID(ctx context.Context, userID uui
 	return r.getSimpleListings(ctx, "SELECT id, title, make, model, year, price, mileage, condition, location, status, health_score, created_at, is_new, is_featured, is_verified, trending FROM car_listings WHERE user_id = $1 ORDER BY created_at DESC", userID)
 }
 
+// GetOrganizationListings returns a dealer organisation's inventory in every
+// status, newest first, optionally only the listings assigned to one member.
+func (r *postgresRepository) GetOrganizationListings(ctx context.Context, orgID uuid.UUID, assignedTo *uuid.UUID, params pagination.Params) ([]*domain.CarListing, int, error) {
+	var total int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM car_listings
+		WHERE organization_id = $1 AND ($2::uuid IS NULL OR assigned_to = $2)
+	`, orgID, assignedTo).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, user_id, title, make, model, year, price, mileage, condition,
+		       location, status, health_score, views, created_at, is_verified, organization_id, assigned_to
+		FROM car_listings
+		WHERE organization_id = $1 AND ($2::uuid IS NULL OR assigned_to = $2)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`, orgID, assignedTo, params.Limit, params.Offset())
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	listings := []*domain.CarListing{}
+	for rows.Next() {
+		var l domain.CarListing
+		if err := rows.Scan(
+			&l.ID, &l.UserID, &l.Title, &l.Make, &l.Model, &l.Year, &l.Price, &l.Mileage, &l.Condition,
+			&l.Location, &l.Status, &l.HealthScore, &l.Views, &l.CreatedAt, &l.IsVerified, &l.OrganizationID, &l.AssignedTo,
+		); err != nil {
+			return nil, 0, err
+		}
+		listings = append(listings, &l)
+	}
+	return listings, total, rows.Err()
+}
+
+func (r *postgresRepository) AssignListing(ctx context.Context, id, assignedTo uuid.UUID) error {
+	_, err := r.db.Exec(ctx, "UPDATE car_listings SET assigned_to = $1, updated_at = NOW() WHERE id = $2", assignedTo, id)
+	return err
+}
+
+// GetMembership returns the user's dealer organisation membership, if any
+// (organizations is owned by auth-service, in the shared database).
+func (r *postgresRepository) GetMembership(ctx context.Context, userID uuid.UUID) (*org.Membership, error) {
+	return org.Lookup(ctx, r.db, userID)
+}
+
+func (r *postgresRepository) IsOrganizationMember(ctx context.Context, orgID, userID uuid.UUID) (bool, error) {
+	return org.IsMember(ctx, r.db, orgID, userID)
+}
+
+// IsVerifiedDealer reports whether the user has an approved dealer application
+// (dealer_applications is owned by auth-service, in the shared database).
+func (r *postgresRepository) IsVerifiedDealer(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var verified bool
+	query := `SELECT EXISTS (SELECT 1 FROM dealer_applications WHERE user_id = $1 AND status = 'approved')`
+	err := r.db.QueryRow(ctx, query, userID).Scan(&verified)
+	return verified, err
+}
+
 // Helper for simple listing queries (without deep associated data like features/images unless needed)
 // For home screens etc we usually just need the cover image
 func (r *postgresRepository) getSimpleListings(ctx context.Context, query string, args ...interface{}) ([]*domain.CarListing, error) {
@@ -318,17 +395,8 @@ func (r *postgresRepository) GetListings(ctx context.Context, filter ListingFilt
 	}
 
 	// Pagination
-	limit := filter.Limit
-	if limit <= 0 {
-		limit = 20
-	}
-	offset := (filter.Page - 1) * limit
-	if offset < 0 {
-		offset = 0
-	}
-
 	baseQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argCounter, argCounter+1)
-	args = append(args, limit, offset)
+	args = append(args, filter.Page.Limit, filter.Page.Offset())
 
 	listings, err := r.getSimpleListings(ctx, baseQuery, args...)
 	if err != nil {
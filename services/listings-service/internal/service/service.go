@@ -5,6 +5,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
 	"github.com/aselahemantha/exoticsLanka/services/listings-service/internal/domain"
 	"github.com/aselahemantha/exoticsLanka/services/listings-service/internal/repository"
 	"github.com/google/uuid"
@@ -20,6 +21,10 @@ type Service interface {
 	GetFeaturedListings(ctx context.Context) ([]*domain.CarListing, error)
 	GetTrendingListings(ctx context.Context) ([]*domain.CarListing, error)
 	GetBrands(ctx context.Context) ([]*domain.CarBrand, error)
+
+	// Dealer organisations
+	GetOrganizationListings(ctx context.Context, userID uuid.UUID, assignedTo *uuid.UUID, params pagination.Params) ([]*domain.CarListing, int, error)
+	AssignListing(ctx context.Context, userID, listingID uuid.UUID, req *domain.AssignListingRequest) error
 }
 
 type service struct {
@@ -59,6 +64,26 @@ func (s *service) CreateListing(ctx context.Context, req *domain.CreateListingRe
 		UpdatedAt:    time.Now(),
 	}
 
+	// Listings created by a dealer's team belong to the organisation and are
+	// assigned to the member who created them
+	membership, err := s.repo.GetMembership(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	verifiedUserID := userID
+	if membership != nil {
+		listing.OrganizationID = &membership.OrganizationID
+		listing.AssignedTo = &userID
+		verifiedUserID = membership.OwnerID
+	}
+
+	// Listings from verified dealers carry the verified badge
+	verified, err := s.repo.IsVerifiedDealer(ctx, verifiedUserID)
+	if err != nil {
+		return nil, err
+	}
+	listing.IsVerified = verified
+
 	// Calculate initial health score
 	listing.HealthScore = calculateHealthScore(listing, len(req.Features), 0) // 0 images initially
 
@@ -111,6 +136,50 @@ func (s *service) GetBrands(ctx context.Context) ([]*domain.CarBrand, error) {
 	return s.repo.GetBrands(ctx)
 }
 
+// GetOrganizationListings returns the team inventory. Owners and managers see
+// every listing (optionally one member's); salespeople only their own.
+func (s *service) GetOrganizationListings(ctx context.Context, userID uuid.UUID, assignedTo *uuid.UUID, params pagination.Params) ([]*domain.CarListing, int, error) {
+	membership, err := s.repo.GetMembership(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if membership == nil {
+		return nil, 0, errors.New("you are not a member of a dealer organisation")
+	}
+	if !membership.CanManage() {
+		assignedTo = &userID
+	}
+	return s.repo.GetOrganizationListings(ctx, membership.OrganizationID, assignedTo, params)
+}
+
+func (s *service) AssignListing(ctx context.Context, userID, listingID uuid.UUID, req *domain.AssignListingRequest) error {
+	membership, err := s.repo.GetMembership(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !membership.CanManage() {
+		return errors.New("only organisation owners and managers can assign listings")
+	}
+
+	listing, err := s.repo.GetListingByID(ctx, listingID)
+	if err != nil {
+		return err
+	}
+	if listing == nil || listing.OrganizationID == nil || *listing.OrganizationID != membership.OrganizationID {
+		return errors.New("listing not found")
+	}
+
+	member, err := s.repo.IsOrganizationMember(ctx, membership.OrganizationID, req.AssignedTo)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return errors.New("assignee is not a member of your organisation")
+	}
+
+	return s.repo.AssignListing(ctx, listingID, req.AssignedTo)
+}
+
 // Logic helpers
 
 func calculateHealthScore(l *domain.CarListing, featureCount, imageCount int) int {
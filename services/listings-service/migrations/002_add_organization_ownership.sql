@@ -0,0 +1,8 @@
+-- Listings owned by a dealer organisation (organizations is owned by
+-- auth-service). user_id stays the member who created the listing;
+-- assigned_to is the salesperson responsible for it.
+ALTER TABLE car_listings ADD COLUMN IF NOT EXISTS organization_id UUID;
+ALTER TABLE car_listings ADD COLUMN IF NOT EXISTS assigned_to UUID;
+
+CREATE INDEX IF NOT EXISTS idx_listings_organization_id ON car_listings(organization_id, status) WHERE organization_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_listings_assigned_to ON car_listings(assigned_to) WHERE assigned_to IS NOT NULL;
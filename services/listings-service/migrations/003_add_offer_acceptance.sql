@@ -0,0 +1,10 @@
+-- Offers are negotiated in messaging-service. The accepted offer is recorded
+-- on its listing, which the seller can reserve or mark as pending sale.
+ALTER TABLE car_listings DROP CONSTRAINT IF EXISTS car_listings_status_check;
+ALTER TABLE car_listings ADD CONSTRAINT car_listings_status_check
+    CHECK (status IN ('draft', 'pending', 'active', 'reserved', 'pending_sale', 'sold', 'expired', 'rejected'));
+
+ALTER TABLE car_listings ADD COLUMN IF NOT EXISTS accepted_offer_id UUID;
+ALTER TABLE car_listings ADD COLUMN IF NOT EXISTS accepted_offer_amount DECIMAL(15, 2);
+ALTER TABLE car_listings ADD COLUMN IF NOT EXISTS accepted_offer_currency CHAR(3);
+ALTER TABLE car_listings ADD COLUMN IF NOT EXISTS offer_accepted_at TIMESTAMP;
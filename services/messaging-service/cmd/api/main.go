@@ -10,12 +10,23 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/audit"
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/cors"
+	"github.com/aselahemantha/exoticsLanka/pkg/jwks"
+	"github.com/aselahemantha/exoticsLanka/pkg/migrate"
+	"github.com/aselahemantha/exoticsLanka/pkg/rbac"
 	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/config"
 	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/handler"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/jobs"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/listings"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/notifications"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/realtime"
 	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/repository"
 	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 )
 
 func main() {
@@ -38,47 +49,94 @@ func main() {
 	fmt.Println("Connected to PostgreSQL")
 
 	// 3. Run Migrations
-	if err := repository.RunMigrations(dbPool, "migrations"); err != nil {
+	if err := migrate.Run(context.Background(), dbPool, "migrations"); err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
-	// 4. Initialize Dependency Injection
-	repo := repository.NewPostgresRepository(dbPool)
-	svc := service.NewService(repo)
-	h := handler.NewHandler(svc)
+	// 4. Redis carries real-time events between replicas
+	redisOpts, err := redis.ParseURL(cfg.RedisURL)
+	if err != nil {
+		log.Fatalf("Invalid Redis URL: %v", err)
+	}
+	rdb := redis.NewClient(redisOpts)
+	if err := rdb.Ping(context.Background()).Err(); err != nil {
+		log.Fatalf("Unable to connect to Redis: %v", err)
+	}
+	fmt.Println("Connected to Redis")
+
+	hub := realtime.NewHub(rdb)
+	go hub.Run()
 
-	// 5. Setup Router
+	// 5. Initialize Dependency Injection
+	repo := repository.NewPostgresRepository(dbPool)
+	listingLookup := listings.NewPostgresLookup(dbPool)
+	if cfg.AttachmentURLSecret == "" {
+		log.Fatal("ATTACHMENT_URL_SECRET must be set")
+	}
+	links := service.AttachmentLinks{BaseURL: cfg.ImageServiceURL, Secret: []byte(cfg.AttachmentURLSecret)}
+	svc := service.NewService(repo, listingLookup, hub, links, notifications.NewPostgresQueue(dbPool))
+
+	// Keeps the listing details cached on conversations current, expires offers,
+	// sends appointment reminders, delivers messages released from review and
+	// applies dealer team changes to conversations
+	jobs.NewJobScheduler(svc).Start()
+
+	// Token verification keys, fetched from auth-service; suspended and deleted
+	// accounts are rejected straight away and impersonated requests are audited.
+	// Dealer API keys are accepted on the routes mapped to a scope below.
+	auditSink := audit.NewPostgresSink(dbPool)
+	authMW := auth.NewMiddleware(
+		jwks.NewClient(cfg.JWKSURL, cfg.JWTPublicKeyFile).Keyfunc,
+		auth.WithTokenCheck(auth.AccountStatusCheck(dbPool)),
+		auth.WithImpersonationAudit(auditSink, "messaging-service"),
+		auth.WithAPIKeys(dbPool, auth.APIKeyRoutes{
+			"GET /api/conversations":         auth.ScopeLeadsRead,
+			"GET /api/conversations/:id":     auth.ScopeLeadsRead,
+			"GET /api/messages/unread-count": auth.ScopeLeadsRead,
+		}),
+	)
+
+	h := handler.NewHandler(svc, hub, authMW.Recheck)
+
+	// Role to permission mapping shared by all services
+	authz := rbac.MustLoadAuthorizer(cfg.RBACPolicyFile, auditSink, "messaging-service")
+
+	// 6. Setup Router
 	router := gin.Default()
 
-	// CORS
-	router.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+	router.Use(cors.New())
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-		c.Next()
-	})
+	// Real-time events. Browsers can't set headers on WebSocket and EventSource
+	// connections, so these also accept ?access_token=
+	router.GET("/api/messages/ws", auth.TokenFromQuery("access_token"), authMW.Required(), h.WebSocket)
+	router.GET("/api/messages/events", auth.TokenFromQuery("access_token"), authMW.Required(), h.Events)
 
 	api := router.Group("/api")
 
 	// Protected Endpoints (All messaging seems protected in spec)
-	api.Use(handler.AuthMiddleware(cfg))
+	api.Use(authMW.Required())
 	{
 		// Conversations
 		api.GET("/conversations", h.GetUserConversations)
-		api.POST("/conversations", h.CreateConversation)
+		api.POST("/conversations", authz.Require(rbac.MessageSend), h.CreateConversation)
 		api.GET("/conversations/:id", h.GetConversationByID)
 		api.PUT("/conversations/:id/read", h.MarkRead)
-		// Delete/Archive not strictly in initial MVP plan but in spec "DELETE /conversations/:id"
-		// h.ArchiveConversation placeholder if needed
+		api.PUT("/conversations/:id/assign", h.AssignConversation)
+		api.PUT("/conversations/:id/archive", h.ArchiveConversation)
+		api.PUT("/conversations/:id/unarchive", h.UnarchiveConversation)
+		api.PUT("/conversations/:id/mute", h.MuteConversation)
+		api.PUT("/conversations/:id/unmute", h.UnmuteConversation)
+		api.DELETE("/conversations/:id", h.DeleteConversation)
+		api.PUT("/conversations/:id/block", h.BlockConversation)
+		api.PUT("/conversations/:id/unblock", h.UnblockConversation)
+		api.POST("/conversations/:id/report", authz.Require(rbac.ReportCreate), h.ReportConversation)
+
+		// Blocks
+		api.GET("/blocks", h.GetBlockedUsers)
+		api.DELETE("/blocks/:id", h.UnblockUser)
 
 		// Messages
-		api.POST("/conversations/:id/messages", h.SendMessage)
+		api.POST("/conversations/:id/messages", authz.Require(rbac.MessageSend), h.SendMessage)
 		// GET messages is implicit in GetConversationByID or separate?
 		// Spec says `GET /api/conversations/:id/messages`
 		// My handler merged them into GetConversationByID response but let's expose specific one too if needed?
@@ -86,11 +144,45 @@ func main() {
 		// But spec lists `GET /api/conversations/:id/messages`.
 		// I will just rely on the main detail endpoint for now as it's more efficient for UI.
 
+		// Offers
+		api.POST("/conversations/:id/offers", authz.Require(rbac.MessageSend), h.MakeOffer)
+		api.GET("/conversations/:id/offers", h.GetOffers)
+		api.POST("/offers/:id/counter", authz.Require(rbac.MessageSend), h.CounterOffer)
+		api.PUT("/offers/:id/accept", h.AcceptOffer)
+		api.PUT("/offers/:id/reject", h.RejectOffer)
+
+		// Appointments
+		api.POST("/conversations/:id/appointments", authz.Require(rbac.MessageSend), h.RequestAppointment)
+		api.GET("/conversations/:id/appointments", h.GetAppointments)
+		api.GET("/conversations/:id/availability", h.GetConversationAvailability)
+		api.GET("/appointments", h.GetUserAppointments)
+		api.PUT("/appointments/:id/reschedule", authz.Require(rbac.MessageSend), h.RescheduleAppointment)
+		api.PUT("/appointments/:id/confirm", h.ConfirmAppointment)
+		api.PUT("/appointments/:id/cancel", h.CancelAppointment)
+		api.PUT("/appointments/:id/complete", h.CompleteAppointment)
+		api.GET("/availability", h.GetAvailability)
+		api.PUT("/availability", h.SetAvailability)
+
+		// Quick replies and auto-reply
+		api.GET("/quick-replies", h.GetQuickReplies)
+		api.POST("/quick-replies", h.CreateQuickReply)
+		api.PUT("/quick-replies/:id", h.UpdateQuickReply)
+		api.DELETE("/quick-replies/:id", h.DeleteQuickReply)
+		api.GET("/conversations/:id/quick-replies", h.GetConversationQuickReplies)
+		api.GET("/auto-reply", h.GetAutoReply)
+		api.PUT("/auto-reply", h.SetAutoReply)
+
 		// Utility
 		api.GET("/messages/unread-count", h.GetUnreadCount)
+		api.GET("/messages/search", h.SearchMessages)
+
+		// Screening (Admin)
+		api.GET("/admin/scam-phrases", authz.Require(rbac.MessageModerate), h.GetScamPhrases)
+		api.POST("/admin/scam-phrases", authz.Require(rbac.MessageModerate), h.AddScamPhrase)
+		api.DELETE("/admin/scam-phrases/:id", authz.Require(rbac.MessageModerate), h.DeleteScamPhrase)
 	}
 
-	// 6. Start Server
+	// 7. Start Server
 	srv := &http.Server{
 		Addr:    ":" + cfg.Port,
 		Handler: router,
@@ -103,12 +195,17 @@ func main() {
 		}
 	}()
 
-	// 7. Graceful Shutdown
+	// 8. Graceful Shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("Shutting down server...")
 
+	// Streaming connections are hijacked or long-lived, so Shutdown won't wait for them
+	if err := hub.Close(); err != nil {
+		log.Printf("Error closing real-time hub: %v", err)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	if err := srv.Shutdown(ctx); err != nil {
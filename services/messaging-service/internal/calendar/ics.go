@@ -0,0 +1,92 @@
+// Package calendar builds iCalendar (RFC 5545) files for appointments.
+package calendar
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the MIME type of the files Event builds
+const ContentType = "text/calendar; charset=utf-8; method=PUBLISH"
+
+// Event is a single calendar event. Calendars match updates to the event by
+// UID and apply them in Sequence order; a cancelled event is removed.
+type Event struct {
+	UID         string
+	Sequence    int
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Cancelled   bool
+}
+
+// ICS encodes the event as a calendar file stamped at now
+func (e Event) ICS(now time.Time) []byte {
+	status := "CONFIRMED"
+	if e.Cancelled {
+		status = "CANCELLED"
+	}
+
+	var b bytes.Buffer
+	line := func(name, value string) {
+		writeFolded(&b, name+":"+value)
+	}
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//Exotics Lanka//Appointments//EN")
+	line("METHOD", "PUBLISH")
+	line("BEGIN", "VEVENT")
+	line("UID", e.UID)
+	line("SEQUENCE", fmt.Sprint(e.Sequence))
+	line("DTSTAMP", formatTime(now))
+	line("DTSTART", formatTime(e.Start))
+	line("DTEND", formatTime(e.End))
+	line("SUMMARY", escape(e.Summary))
+	if e.Description != "" {
+		line("DESCRIPTION", escape(e.Description))
+	}
+	if e.Location != "" {
+		line("LOCATION", escape(e.Location))
+	}
+	if e.URL != "" {
+		line("URL", e.URL)
+	}
+	line("STATUS", status)
+	line("END", "VEVENT")
+	line("END", "VCALENDAR")
+	return b.Bytes()
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\r", `\n`, "\n", `\n`)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// writeFolded writes a content line, folded at 75 octets without splitting
+// UTF-8 characters, and terminated by CRLF
+func writeFolded(b *bytes.Buffer, s string) {
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		limit = 74 // Continuation lines start with a space
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+}
@@ -13,6 +13,11 @@ type Conversation struct {
 	BuyerID   uuid.UUID  `json:"buyerId,omitempty"`  // Internal use mainly
 	SellerID  uuid.UUID  `json:"sellerId,omitempty"` // Internal use mainly
 
+	// Set when the listing belongs to a dealer organisation; AssignedTo is the
+	// team member handling the seller side
+	OrganizationID *uuid.UUID `json:"organizationId,omitempty"`
+	AssignedTo     *uuid.UUID `json:"assignedTo,omitempty"`
+
 	// Cached Listing Info
 	ListingTitle string  `json:"listingTitle,omitempty"` // Mapped to nested structure in response usually
 	ListingImage *string `json:"listingImage,omitempty"`
@@ -22,7 +27,19 @@ type Conversation struct {
 	LastMessageAt      time.Time `json:"lastMessageAt"`
 	LastMessagePreview string    `json:"lastMessage"`
 
+	// Each side's inbox state, as stored; the viewer's side is mapped to
+	// IsArchived and IsMuted
+	ArchivedByBuyer   bool       `json:"-"`
+	ArchivedBySeller  bool       `json:"-"`
+	MutedByBuyer      bool       `json:"-"`
+	MutedBySeller     bool       `json:"-"`
+	DeletedByBuyerAt  *time.Time `json:"-"`
+	DeletedBySellerAt *time.Time `json:"-"`
+
 	// Calculated/Contextual fields
+	IsArchived  bool            `json:"isArchived"`
+	IsMuted     bool            `json:"isMuted"`
+	IsBlocked   bool            `json:"isBlocked"` // Either side blocked the other; set on the detail view
 	UnreadCount int             `json:"unreadCount"`
 	Participant *UserSummary    `json:"participant,omitempty"`
 	Listing     *ListingSummary `json:"listing,omitempty"`
@@ -30,18 +47,329 @@ type Conversation struct {
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+// MutedBy reports whether the buyer's or the seller's side muted the conversation.
+func (c *Conversation) MutedBy(asBuyer bool) bool {
+	if asBuyer {
+		return c.MutedByBuyer
+	}
+	return c.MutedBySeller
+}
+
+// DeletedBy returns when the buyer's or the seller's side deleted the
+// conversation, or nil if they haven't.
+func (c *Conversation) DeletedBy(asBuyer bool) *time.Time {
+	if asBuyer {
+		return c.DeletedByBuyerAt
+	}
+	return c.DeletedBySellerAt
+}
+
+// HiddenFrom reports whether the side deleted the conversation and nothing has
+// been said since.
+func (c *Conversation) HiddenFrom(asBuyer bool) bool {
+	deletedAt := c.DeletedBy(asBuyer)
+	return deletedAt != nil && !c.LastMessageAt.After(*deletedAt)
+}
+
+// Message types
+const (
+	MessageTypeText        = "text"
+	MessageTypeOffer       = "offer"
+	MessageTypeAttachment  = "attachment"
+	MessageTypeAppointment = "appointment"
+
+	// MessageTypeAutoReply messages are sent by the seller side's auto-reply
+	MessageTypeAutoReply = "auto_reply"
+)
+
 type Message struct {
 	ID             uuid.UUID  `json:"id"`
 	ConversationID uuid.UUID  `json:"conversationId"`
 	SenderID       uuid.UUID  `json:"senderId"`
+	Type           string     `json:"type"`
 	Content        string     `json:"content"`
 	IsRead         bool       `json:"isRead"`
 	ReadAt         *time.Time `json:"readAt,omitempty"`
 	CreatedAt      time.Time  `json:"createdAt"`
 
+	OfferID *uuid.UUID `json:"-"`
+	Offer   *Offer     `json:"offer,omitempty"` // Hydrated for offer messages
+
+	AppointmentID *uuid.UUID   `json:"-"`
+	Appointment   *Appointment `json:"appointment,omitempty"` // Hydrated for appointment messages
+
+	AttachmentIDs []uuid.UUID  `json:"-"`                     // Uploaded attachments to link when the message is saved
+	Attachments   []Attachment `json:"attachments,omitempty"` // Hydrated
+
+	// Screening. Held messages are only shown to their sender until reviewed;
+	// Masked messages had contact details hidden.
+	ModerationStatus string `json:"moderationStatus"`
+	ModerationReason string `json:"-"`
+	Masked           bool   `json:"masked,omitempty"`
+
 	SenderName string `json:"senderName,omitempty"` // Hydrated
 }
 
+// Message moderation statuses
+const (
+	ModerationVisible = "visible"
+	// ModerationHeld messages wait for an admin to resolve their report in reports-service
+	ModerationHeld = "held"
+	// ModerationReleased messages were approved and are waiting to be delivered
+	ModerationReleased = "released"
+	ModerationRemoved  = "removed"
+)
+
+// SenderActivity is what send limits and masking are decided on. Restricted
+// senders have accounts less than RestrictedAccountAge old, or haven't
+// verified an email address or phone number.
+type SenderActivity struct {
+	Restricted bool
+	LastMinute int // Messages sent in the last minute
+	LastHour   int
+}
+
+// RestrictedAccountAge is how long new accounts send under tighter screening
+const RestrictedAccountAge = 7 * 24 * time.Hour
+
+// BlockedUser is a user or dealer organisation the viewer's side blocked
+type BlockedUser struct {
+	ID             uuid.UUID  `json:"id"`
+	Name           string     `json:"name"`
+	BlockedBy      uuid.UUID  `json:"blockedBy"`
+	ConversationID *uuid.UUID `json:"conversationId,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+// ConversationReport is filed with reports-service against the other side of
+// a conversation, with the latest messages as evidence
+type ConversationReport struct {
+	ID             uuid.UUID     `json:"id"`
+	ConversationID uuid.UUID     `json:"conversationId"`
+	ReporterID     uuid.UUID     `json:"reporterId"`
+	ReportedUserID uuid.UUID     `json:"reportedUserId"`
+	Reason         string        `json:"reason"`
+	Details        string        `json:"details"`
+	Excerpt        []ExcerptLine `json:"excerpt"`
+	Status         string        `json:"status"`
+	CreatedAt      time.Time     `json:"createdAt"`
+}
+
+// ExcerptLine is a message copied into a report
+type ExcerptLine struct {
+	MessageID  uuid.UUID `json:"messageId"`
+	SenderID   uuid.UUID `json:"senderId"`
+	SenderName string    `json:"senderName"`
+	Content    string    `json:"content"`
+	SentAt     time.Time `json:"sentAt"`
+}
+
+// ReportExcerptLength is how many of the latest messages a conversation report keeps
+const ReportExcerptLength = 20
+
+// ScamPhrase is a phrase that holds a message for review
+type ScamPhrase struct {
+	ID        uuid.UUID  `json:"id"`
+	Phrase    string     `json:"phrase"`
+	CreatedBy *uuid.UUID `json:"createdBy,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// Attachment kinds
+const (
+	AttachmentImage    = "image"
+	AttachmentDocument = "document"
+)
+
+// Attachment is a photo or PDF sent with a message. URL and ThumbnailURL are
+// signed links to image-service that stop working at ExpiresAt.
+type Attachment struct {
+	ID           uuid.UUID `json:"id"`
+	MessageID    uuid.UUID `json:"-"`
+	Kind         string    `json:"kind"`
+	ContentType  string    `json:"contentType"`
+	FileName     string    `json:"fileName"`
+	SizeBytes    int64     `json:"sizeBytes"`
+	HasThumbnail bool      `json:"-"`
+
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnailUrl,omitempty"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+// Offer statuses
+const (
+	OfferPending   = "pending"
+	OfferAccepted  = "accepted"
+	OfferRejected  = "rejected"
+	OfferCountered = "countered"
+	OfferExpired   = "expired"
+)
+
+// DefaultOfferCurrency is used when an offer doesn't name one; listing prices are in LKR
+const DefaultOfferCurrency = "LKR"
+
+// Offer is a price offer made in a conversation. The other side can accept,
+// reject or counter it while it is pending and hasn't expired.
+type Offer struct {
+	ID             uuid.UUID  `json:"id"`
+	ConversationID uuid.UUID  `json:"conversationId"`
+	ListingID      *uuid.UUID `json:"listingId,omitempty"`
+	BuyerID        uuid.UUID  `json:"-"`
+	SellerID       uuid.UUID  `json:"-"`
+	OrganizationID *uuid.UUID `json:"-"`
+	OfferedBy      uuid.UUID  `json:"offeredBy"`
+	FromBuyer      bool       `json:"fromBuyer"`
+	Amount         float64    `json:"amount"`
+	Currency       string     `json:"currency"`
+	ExpiresAt      time.Time  `json:"expiresAt"`
+	CounterTo      *uuid.UUID `json:"counterTo,omitempty"`
+	Status         string     `json:"status"`
+	RespondedBy    *uuid.UUID `json:"respondedBy,omitempty"`
+	RespondedAt    *time.Time `json:"respondedAt,omitempty"`
+	ListingStatus  *string    `json:"listingStatus,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+// Appointment kinds
+const (
+	AppointmentTestDrive = "test_drive"
+	AppointmentViewing   = "viewing"
+)
+
+// Appointment statuses
+const (
+	AppointmentRequested = "requested"
+	AppointmentConfirmed = "confirmed"
+	AppointmentCancelled = "cancelled"
+	AppointmentCompleted = "completed"
+	AppointmentNoShow    = "no_show"
+)
+
+// SriLankaTime is the time zone appointments and availability are shown and
+// checked in. Sri Lanka doesn't observe daylight saving.
+var SriLankaTime = time.FixedZone("Asia/Colombo", 5*60*60+30*60)
+
+// Appointment slot limits
+const (
+	MaxAppointmentSlots    = 5
+	MaxAppointmentLength   = 4 * time.Hour
+	MaxAppointmentLeadTime = 90 * 24 * time.Hour
+)
+
+// AppointmentReminderLead is how long before a confirmed appointment both sides are reminded
+const AppointmentReminderLead = 24 * time.Hour
+
+// TimeSlot is a proposed or confirmed appointment time
+type TimeSlot struct {
+	Start time.Time `json:"start" binding:"required"`
+	End   time.Time `json:"end" binding:"required"`
+}
+
+// Appointment is a test drive or viewing arranged in a conversation. One side
+// proposes slots and the other confirms one; either side can propose new
+// slots, which needs confirming again.
+type Appointment struct {
+	ID             uuid.UUID  `json:"id"`
+	ConversationID uuid.UUID  `json:"conversationId"`
+	ListingID      *uuid.UUID `json:"listingId,omitempty"`
+	BuyerID        uuid.UUID  `json:"-"`
+	SellerID       uuid.UUID  `json:"-"`
+	OrganizationID *uuid.UUID `json:"-"`
+	Kind           string     `json:"kind"`
+	Status         string     `json:"status"`
+	ProposedSlots  []TimeSlot `json:"proposedSlots"`
+	ProposedBy     uuid.UUID  `json:"proposedBy"`
+	FromBuyer      bool       `json:"fromBuyer"` // Slots were proposed by the buyer side
+	StartsAt       *time.Time `json:"startsAt,omitempty"`
+	EndsAt         *time.Time `json:"endsAt,omitempty"`
+	InvitedStart   *time.Time `json:"-"` // The slot the last calendar invite was for
+	InvitedEnd     *time.Time `json:"-"`
+	Location       string     `json:"location,omitempty"`
+	Notes          string     `json:"notes,omitempty"`
+	CancelledBy    *uuid.UUID `json:"cancelledBy,omitempty"`
+	CancelReason   string     `json:"cancelReason,omitempty"`
+	Sequence       int        `json:"-"` // Revision, for calendar updates
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+// IsOpen reports whether the appointment is still to take place
+func (a *Appointment) IsOpen() bool {
+	return a.Status == AppointmentRequested || a.Status == AppointmentConfirmed
+}
+
+// AvailabilityWindow is a weekly period, in Sri Lanka time, when a seller or
+// dealer organisation takes appointments. Weekday 0 is Sunday.
+type AvailabilityWindow struct {
+	Weekday int    `json:"weekday" binding:"min=0,max=6"`
+	Start   string `json:"start" binding:"required"` // HH:MM
+	End     string `json:"end" binding:"required"`
+}
+
+// Contains reports whether the slot falls within the window
+func (w AvailabilityWindow) Contains(slot TimeSlot) bool {
+	start, end := slot.Start.In(SriLankaTime), slot.End.In(SriLankaTime)
+	if int(start.Weekday()) != w.Weekday || start.YearDay() != end.YearDay() {
+		return false
+	}
+	return w.Start <= start.Format("15:04") && end.Format("15:04") <= w.End
+}
+
+// ContainsTime reports whether the time falls within the window
+func (w AvailabilityWindow) ContainsTime(t time.Time) bool {
+	t = t.In(SriLankaTime)
+	clock := t.Format("15:04")
+	return int(t.Weekday()) == w.Weekday && w.Start <= clock && clock < w.End
+}
+
+// QuickReply is a reply template saved by a user. Placeholders such as
+// {listing_title} are filled in from the conversation it is used in.
+type QuickReply struct {
+	ID        uuid.UUID `json:"id"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// MaxQuickReplies is how many quick replies a user can save
+const MaxQuickReplies = 50
+
+// AutoReply answers the first message of a new conversation on behalf of the
+// seller side: a seller, or a dealer organisation. Business hours use the same
+// weekly windows as availability.
+type AutoReply struct {
+	Enabled       bool                 `json:"enabled"`
+	Mode          string               `json:"mode"`
+	Message       string               `json:"message"` // May use quick reply placeholders
+	BusinessHours []AvailabilityWindow `json:"businessHours"`
+	UpdatedBy     *uuid.UUID           `json:"updatedBy,omitempty"`
+	UpdatedAt     *time.Time           `json:"updatedAt,omitempty"`
+}
+
+// Auto-reply modes
+const (
+	AutoReplyAlways       = "always"
+	AutoReplyOutsideHours = "outside_hours"
+)
+
+// Due reports whether the auto-reply answers a conversation started at t
+func (a *AutoReply) Due(t time.Time) bool {
+	if !a.Enabled || a.Message == "" {
+		return false
+	}
+	if a.Mode == AutoReplyAlways {
+		return true
+	}
+	for _, w := range a.BusinessHours {
+		if w.ContainsTime(t) {
+			return false
+		}
+	}
+	return true
+}
+
 type UserSummary struct {
 	ID     uuid.UUID `json:"id"`
 	Name   string    `json:"name"`
@@ -57,6 +385,79 @@ type ListingSummary struct {
 	Status string    `json:"status,omitempty"` // Fetched from Real listing table if possible
 }
 
+// SearchHit is a message matching a search, with the conversation it is in.
+// Snippet and the conversation's ListingTitle mark matched words with
+// <mark></mark>; everything else in them is HTML-escaped.
+type SearchHit struct {
+	MessageID    uuid.UUID          `json:"messageId"`
+	SenderID     uuid.UUID          `json:"senderId"`
+	SenderName   string             `json:"senderName"`
+	Snippet      string             `json:"snippet"`
+	CreatedAt    time.Time          `json:"createdAt"`
+	Conversation SearchConversation `json:"conversation"`
+}
+
+// SearchConversation is the context shown with a search hit
+type SearchConversation struct {
+	ID           uuid.UUID   `json:"id"`
+	ListingID    *uuid.UUID  `json:"listingId,omitempty"`
+	ListingTitle string      `json:"listingTitle"`
+	ListingImage *string     `json:"listingImage,omitempty"`
+	Participant  UserSummary `json:"participant"` // The other side
+}
+
+// Search query length limits
+const (
+	MinSearchQueryLength = 2
+	MaxSearchQueryLength = 200
+)
+
+// ListingStatusActive is the only listing status that accepts new conversations
+const ListingStatusActive = "active"
+
+// ListingSnapshot is the listing a conversation is about, as resolved when the
+// conversation starts. Title, image and price are cached on the conversation.
+type ListingSnapshot struct {
+	ID             uuid.UUID
+	SellerID       uuid.UUID
+	OrganizationID *uuid.UUID
+	AssignedTo     *uuid.UUID
+	Title          string
+	Image          *string
+	Price          float64
+	Status         string
+}
+
+// Viewer is the user acting on conversations. Members of a dealer organisation
+// act on the seller side of the team's conversations: owners and managers on
+// all of them, salespeople on those assigned to them.
+type Viewer struct {
+	UserID         uuid.UUID
+	OrganizationID *uuid.UUID
+	CanManage      bool
+}
+
+// IsBuyer reports whether the viewer started the conversation.
+func (v Viewer) IsBuyer(c *Conversation) bool {
+	return c.BuyerID == v.UserID
+}
+
+// IsSeller reports whether the viewer handles the seller side of the conversation.
+func (v Viewer) IsSeller(c *Conversation) bool {
+	if c.OrganizationID == nil {
+		return c.SellerID == v.UserID
+	}
+	if v.OrganizationID == nil || *v.OrganizationID != *c.OrganizationID {
+		return false
+	}
+	return v.CanManage || (c.AssignedTo != nil && *c.AssignedTo == v.UserID)
+}
+
+// IsParticipant reports whether the viewer may read and reply to the conversation.
+func (v Viewer) IsParticipant(c *Conversation) bool {
+	return v.IsBuyer(c) || v.IsSeller(c)
+}
+
 // Request Models
 type CreateConversationRequest struct {
 	ListingID      uuid.UUID `json:"listingId" binding:"required"`
@@ -64,10 +465,96 @@ type CreateConversationRequest struct {
 	InitialMessage string    `json:"initialMessage" binding:"required"`
 }
 
+type AssignConversationRequest struct {
+	AssignedTo uuid.UUID `json:"assignedTo" binding:"required"`
+}
+
+// SendMessageRequest sends text, attachments uploaded to image-service, or both
 type SendMessageRequest struct {
-	Content string `json:"content" binding:"required"`
+	Content       string      `json:"content"`
+	AttachmentIDs []uuid.UUID `json:"attachmentIds" binding:"max=10"`
+}
+
+// ReportConversationRequest reports the other side of a conversation, and can
+// block them at the same time
+type ReportConversationRequest struct {
+	Reason  string `json:"reason" binding:"required,oneof=spam scam harassment inappropriate other"`
+	Details string `json:"details" binding:"max=2000"`
+	Block   bool   `json:"block"`
+}
+
+// CreateScamPhraseRequest adds a phrase to the screening list
+type CreateScamPhraseRequest struct {
+	Phrase string `json:"phrase" binding:"required,max=200"`
+}
+
+// MakeOfferRequest makes an offer, or counters one. ExpiresAt defaults to
+// DefaultOfferTTL from now; Message is shown with the offer.
+type MakeOfferRequest struct {
+	Amount    float64    `json:"amount" binding:"required,gt=0"`
+	Currency  string     `json:"currency" binding:"omitempty,len=3,uppercase"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+	Message   string     `json:"message" binding:"max=500"`
+}
+
+// AcceptOfferRequest can also reserve the listing or mark it as pending sale.
+// Only the seller side can change the listing's status.
+type AcceptOfferRequest struct {
+	ListingStatus string `json:"listingStatus" binding:"omitempty,oneof=reserved pending_sale"`
+}
+
+// RequestAppointmentRequest proposes a test drive or viewing
+type RequestAppointmentRequest struct {
+	Kind     string     `json:"kind" binding:"required,oneof=test_drive viewing"`
+	Slots    []TimeSlot `json:"slots" binding:"required,min=1,max=5,dive"`
+	Location string     `json:"location" binding:"max=255"`
+	Notes    string     `json:"notes" binding:"max=1000"`
+}
+
+// RescheduleAppointmentRequest proposes new slots in place of the current ones
+type RescheduleAppointmentRequest struct {
+	Slots   []TimeSlot `json:"slots" binding:"required,min=1,max=5,dive"`
+	Message string     `json:"message" binding:"max=500"`
 }
 
+// ConfirmAppointmentRequest picks one of the proposed slots by its start time.
+// Location, if set, replaces the proposed meeting place.
+type ConfirmAppointmentRequest struct {
+	Start    time.Time `json:"start" binding:"required"`
+	Location string    `json:"location" binding:"max=255"`
+}
+
+type CancelAppointmentRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// CompleteAppointmentRequest records how a confirmed appointment went
+type CompleteAppointmentRequest struct {
+	NoShow bool `json:"noShow"`
+}
+
+type SetAvailabilityRequest struct {
+	Windows []AvailabilityWindow `json:"windows" binding:"max=28,dive"`
+}
+
+type SaveQuickReplyRequest struct {
+	Title   string `json:"title" binding:"required,max=100"`
+	Content string `json:"content" binding:"required,max=2000"`
+}
+
+type SetAutoReplyRequest struct {
+	Enabled       bool                 `json:"enabled"`
+	Mode          string               `json:"mode" binding:"required,oneof=always outside_hours"`
+	Message       string               `json:"message" binding:"max=2000"`
+	BusinessHours []AvailabilityWindow `json:"businessHours" binding:"max=28,dive"`
+}
+
+// Offer expiry limits
+const (
+	DefaultOfferTTL = 48 * time.Hour
+	MaxOfferTTL     = 14 * 24 * time.Hour
+)
+
 // Response Models
 type ConversationResponse struct {
 	ID    uuid.UUID `json:"id"`
@@ -84,9 +571,9 @@ type ConversationUnread struct {
 	Unread         int       `json:"unread"`
 }
 
-type Pagination struct {
-	Page       int   `json:"page"`
-	Limit      int   `json:"limit"`
-	Total      int64 `json:"total"`
-	TotalPages int   `json:"totalPages"`
+// Realtime event payloads
+type ConversationReadEvent struct {
+	ConversationID uuid.UUID `json:"conversationId"`
+	ReadBy         uuid.UUID `json:"readBy"`
+	ReadAt         time.Time `json:"readAt"`
 }
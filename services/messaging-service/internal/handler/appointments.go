@@ -0,0 +1,268 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// POST /api/conversations/:id/appointments
+func (h *Handler) RequestAppointment(c *gin.Context) {
+	userID, id, ok := userAndID(c, "Invalid conversation ID")
+	if !ok {
+		return
+	}
+
+	var req domain.RequestAppointmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	msg, err := h.service.RequestAppointment(c.Request.Context(), id, userID, req)
+	if err != nil {
+		appointmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    msg,
+	})
+}
+
+// GET /api/conversations/:id/appointments
+func (h *Handler) GetAppointments(c *gin.Context) {
+	userID, id, ok := userAndID(c, "Invalid conversation ID")
+	if !ok {
+		return
+	}
+
+	appointments, err := h.service.GetAppointments(c.Request.Context(), id, userID)
+	if err != nil {
+		appointmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    appointments,
+	})
+}
+
+// GET /api/conversations/:id/availability - the seller side's weekly availability
+func (h *Handler) GetConversationAvailability(c *gin.Context) {
+	userID, id, ok := userAndID(c, "Invalid conversation ID")
+	if !ok {
+		return
+	}
+
+	windows, err := h.service.GetConversationAvailability(c.Request.Context(), id, userID)
+	if err != nil {
+		appointmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    windows,
+	})
+}
+
+// GET /api/appointments?status=confirmed
+func (h *Handler) GetUserAppointments(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	appointments, err := h.service.GetUserAppointments(c.Request.Context(), userID, c.Query("status"))
+	if err != nil {
+		appointmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    appointments,
+	})
+}
+
+// PUT /api/appointments/:id/reschedule
+func (h *Handler) RescheduleAppointment(c *gin.Context) {
+	userID, id, ok := userAndID(c, "Invalid appointment ID")
+	if !ok {
+		return
+	}
+
+	var req domain.RescheduleAppointmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	msg, err := h.service.RescheduleAppointment(c.Request.Context(), id, userID, req)
+	if err != nil {
+		appointmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    msg,
+	})
+}
+
+// PUT /api/appointments/:id/confirm
+func (h *Handler) ConfirmAppointment(c *gin.Context) {
+	userID, id, ok := userAndID(c, "Invalid appointment ID")
+	if !ok {
+		return
+	}
+
+	var req domain.ConfirmAppointmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	msg, err := h.service.ConfirmAppointment(c.Request.Context(), id, userID, req)
+	if err != nil {
+		appointmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Appointment confirmed",
+		"data":    msg,
+	})
+}
+
+// PUT /api/appointments/:id/cancel - optionally {"reason": "..."}
+func (h *Handler) CancelAppointment(c *gin.Context) {
+	userID, id, ok := userAndID(c, "Invalid appointment ID")
+	if !ok {
+		return
+	}
+
+	// The body is optional
+	var req domain.CancelAppointmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	msg, err := h.service.CancelAppointment(c.Request.Context(), id, userID, req)
+	if err != nil {
+		appointmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Appointment cancelled",
+		"data":    msg,
+	})
+}
+
+// PUT /api/appointments/:id/complete - {"noShow": true} if the buyer didn't come
+func (h *Handler) CompleteAppointment(c *gin.Context) {
+	userID, id, ok := userAndID(c, "Invalid appointment ID")
+	if !ok {
+		return
+	}
+
+	// The body is optional
+	var req domain.CompleteAppointmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	appointment, err := h.service.CompleteAppointment(c.Request.Context(), id, userID, req)
+	if err != nil {
+		appointmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    appointment,
+	})
+}
+
+// GET /api/availability - the weekly availability the user manages
+func (h *Handler) GetAvailability(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	windows, err := h.service.GetAvailability(c.Request.Context(), userID)
+	if err != nil {
+		appointmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    windows,
+	})
+}
+
+// PUT /api/availability
+func (h *Handler) SetAvailability(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req domain.SetAvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	windows, err := h.service.SetAvailability(c.Request.Context(), userID, req)
+	if err != nil {
+		appointmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    windows,
+	})
+}
+
+func appointmentError(c *gin.Context, err error) {
+	msg := err.Error()
+	switch {
+	case msg == "conversation not found", msg == "appointment not found":
+		response.Error(c, http.StatusNotFound, msg)
+	case msg == "not a participant", msg == "only the buyer can request an appointment",
+		msg == "you cannot confirm your own proposal", msg == "only the seller can complete an appointment",
+		msg == "only organisation owners and managers can change availability", msg == "you cannot message this user":
+		response.Error(c, http.StatusForbidden, msg)
+	case msg == "an appointment is already open", msg == "appointment is no longer open",
+		msg == "appointment is already confirmed", msg == "appointment is not confirmed",
+		strings.HasPrefix(msg, "appointment was changed"):
+		response.Error(c, http.StatusConflict, msg)
+	case strings.HasPrefix(msg, "slot"), msg == "start time is not one of the proposed slots",
+		msg == "that slot has already passed", msg == "appointment hasn't started yet",
+		strings.HasPrefix(msg, "availability"):
+		response.Error(c, http.StatusBadRequest, msg)
+	default:
+		response.Error(c, http.StatusInternalServerError, msg)
+	}
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PUT /api/conversations/:id/block
+func (h *Handler) BlockConversation(c *gin.Context) {
+	h.updateInbox(c, "User blocked", func(ctx context.Context, id, userID uuid.UUID) error {
+		return h.service.BlockConversation(ctx, id, userID, true)
+	})
+}
+
+// PUT /api/conversations/:id/unblock
+func (h *Handler) UnblockConversation(c *gin.Context) {
+	h.updateInbox(c, "User unblocked", func(ctx context.Context, id, userID uuid.UUID) error {
+		return h.service.BlockConversation(ctx, id, userID, false)
+	})
+}
+
+// POST /api/conversations/:id/report
+func (h *Handler) ReportConversation(c *gin.Context) {
+	userID, id, ok := userAndID(c, "Invalid conversation ID")
+	if !ok {
+		return
+	}
+
+	var req domain.ReportConversationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.service.ReportConversation(c.Request.Context(), id, userID, req)
+	if err != nil {
+		switch err.Error() {
+		case "conversation not found":
+			response.Error(c, http.StatusNotFound, err.Error())
+		case "not a participant":
+			response.Error(c, http.StatusForbidden, err.Error())
+		case "you have already reported this conversation recently":
+			response.Error(c, http.StatusConflict, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Report submitted successfully",
+		"data":    report,
+	})
+}
+
+// GET /api/blocks
+func (h *Handler) GetBlockedUsers(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	blocked, err := h.service.GetBlockedUsers(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    blocked,
+	})
+}
+
+// DELETE /api/blocks/:id - the blocked user or organisation
+func (h *Handler) UnblockUser(c *gin.Context) {
+	userID, id, ok := userAndID(c, "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	if err := h.service.UnblockUser(c.Request.Context(), userID, id); err != nil {
+		if err.Error() == "user is not blocked" {
+			response.Error(c, http.StatusNotFound, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "User unblocked",
+	})
+}
@@ -1,10 +1,14 @@
 package handler
 
 import (
+	"context"
 	"net/http"
-	"strconv"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
 	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/domain"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/realtime"
 	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -12,27 +16,28 @@ import (
 
 type Handler struct {
 	service service.Service
+	hub     *realtime.Hub
+	recheck func(*gin.Context) error // Revalidates the account behind a real-time connection
 }
 
-func NewHandler(service service.Service) *Handler {
-	return &Handler{service: service}
+func NewHandler(service service.Service, hub *realtime.Hub, recheck func(*gin.Context) error) *Handler {
+	return &Handler{service: service, hub: hub, recheck: recheck}
 }
 
 // GET /api/conversations
 func (h *Handler) GetUserConversations(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	params := pagination.FromQuery(c, 20)
 	archived := c.Query("archived") == "true"
 
-	conversations, pagination, err := h.service.GetUserConversations(c.Request.Context(), userID, page, limit, archived)
+	conversations, meta, err := h.service.GetUserConversations(c.Request.Context(), userID, params, archived)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -47,28 +52,37 @@ func (h *Handler) GetUserConversations(c *gin.Context) {
 		"success": true,
 		"data": gin.H{
 			"conversations": conversations,
-			"pagination":    pagination,
+			"pagination":    meta,
 		},
 	})
 }
 
 // POST /api/conversations
 func (h *Handler) CreateConversation(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	var req domain.CreateConversationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	resp, err := h.service.CreateConversation(c.Request.Context(), req, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		switch err.Error() {
+		case "listing not found":
+			response.Error(c, http.StatusNotFound, err.Error())
+		case "listing is not active", "seller does not own this listing", "cannot start conversation with yourself":
+			response.Error(c, http.StatusBadRequest, err.Error())
+		case "you cannot message this user":
+			response.Error(c, http.StatusForbidden, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
 
@@ -86,22 +100,22 @@ func (h *Handler) CreateConversation(c *gin.Context) {
 
 // GET /api/conversations/:id
 func (h *Handler) GetConversationByID(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid conversation ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid conversation ID")
 		return
 	}
 
 	conv, messages, err := h.service.GetConversationByID(c.Request.Context(), id, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -123,6 +137,8 @@ func (h *Handler) GetConversationByID(c *gin.Context) {
 				"price": conv.ListingPrice,
 			},
 			"participant":   conv.Participant,
+			"isArchived":    conv.IsArchived,
+			"isMuted":       conv.IsMuted,
 			"messages":      messages,
 			"createdAt":     conv.CreatedAt,
 			"lastMessageAt": conv.LastMessageAt,
@@ -132,28 +148,37 @@ func (h *Handler) GetConversationByID(c *gin.Context) {
 
 // POST /api/conversations/:id/messages
 func (h *Handler) SendMessage(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid conversation ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid conversation ID")
 		return
 	}
 
 	var req domain.SendMessageRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	msg, err := h.service.SendMessage(c.Request.Context(), id, userID, req.Content)
+	msg, err := h.service.SendMessage(c.Request.Context(), id, userID, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		switch err.Error() {
+		case "message content or an attachment is required", "attachment not found":
+			response.Error(c, http.StatusBadRequest, err.Error())
+		case "too many messages, try again later":
+			response.Error(c, http.StatusTooManyRequests, err.Error())
+		case "you cannot message this user":
+			response.Error(c, http.StatusForbidden, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
 
@@ -165,22 +190,22 @@ func (h *Handler) SendMessage(c *gin.Context) {
 
 // PUT /api/conversations/:id/read
 func (h *Handler) MarkRead(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid conversation ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid conversation ID")
 		return
 	}
 
 	err = h.service.MarkConversationRead(c.Request.Context(), id, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -192,15 +217,15 @@ func (h *Handler) MarkRead(c *gin.Context) {
 
 // GET /api/messages/unread-count
 func (h *Handler) GetUnreadCount(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	stats, err := h.service.GetUnreadCount(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -209,3 +234,108 @@ func (h *Handler) GetUnreadCount(c *gin.Context) {
 		"data":    stats,
 	})
 }
+
+// PUT /api/conversations/:id/assign
+func (h *Handler) AssignConversation(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid conversation ID")
+		return
+	}
+
+	var req domain.AssignConversationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.service.AssignConversation(c.Request.Context(), id, userID, req); err != nil {
+		switch err.Error() {
+		case "conversation not found":
+			response.Error(c, http.StatusNotFound, err.Error())
+		case "not allowed to assign this conversation":
+			response.Error(c, http.StatusForbidden, err.Error())
+		case "assignee is not a member of the organisation":
+			response.Error(c, http.StatusBadRequest, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Conversation assigned",
+	})
+}
+
+// PUT /api/conversations/:id/archive
+func (h *Handler) ArchiveConversation(c *gin.Context) {
+	h.updateInbox(c, "Conversation archived", func(ctx context.Context, id, userID uuid.UUID) error {
+		return h.service.ArchiveConversation(ctx, id, userID, true)
+	})
+}
+
+// PUT /api/conversations/:id/unarchive
+func (h *Handler) UnarchiveConversation(c *gin.Context) {
+	h.updateInbox(c, "Conversation unarchived", func(ctx context.Context, id, userID uuid.UUID) error {
+		return h.service.ArchiveConversation(ctx, id, userID, false)
+	})
+}
+
+// PUT /api/conversations/:id/mute
+func (h *Handler) MuteConversation(c *gin.Context) {
+	h.updateInbox(c, "Conversation muted", func(ctx context.Context, id, userID uuid.UUID) error {
+		return h.service.MuteConversation(ctx, id, userID, true)
+	})
+}
+
+// PUT /api/conversations/:id/unmute
+func (h *Handler) UnmuteConversation(c *gin.Context) {
+	h.updateInbox(c, "Conversation unmuted", func(ctx context.Context, id, userID uuid.UUID) error {
+		return h.service.MuteConversation(ctx, id, userID, false)
+	})
+}
+
+// DELETE /api/conversations/:id - removes the conversation from the caller's side only
+func (h *Handler) DeleteConversation(c *gin.Context) {
+	h.updateInbox(c, "Conversation deleted", h.service.DeleteConversation)
+}
+
+// updateInbox runs a change to the caller's view of a conversation
+func (h *Handler) updateInbox(c *gin.Context, message string, update func(ctx context.Context, id, userID uuid.UUID) error) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid conversation ID")
+		return
+	}
+
+	if err := update(c.Request.Context(), id, userID); err != nil {
+		switch err.Error() {
+		case "conversation not found", "user is not blocked":
+			response.Error(c, http.StatusNotFound, err.Error())
+		case "not a participant":
+			response.Error(c, http.StatusForbidden, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": message,
+	})
+}
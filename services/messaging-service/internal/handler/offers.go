@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// POST /api/conversations/:id/offers
+func (h *Handler) MakeOffer(c *gin.Context) {
+	userID, id, ok := userAndID(c, "Invalid conversation ID")
+	if !ok {
+		return
+	}
+
+	var req domain.MakeOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	msg, err := h.service.MakeOffer(c.Request.Context(), id, userID, req)
+	if err != nil {
+		offerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    msg,
+	})
+}
+
+// GET /api/conversations/:id/offers
+func (h *Handler) GetOffers(c *gin.Context) {
+	userID, id, ok := userAndID(c, "Invalid conversation ID")
+	if !ok {
+		return
+	}
+
+	offers, err := h.service.GetOffers(c.Request.Context(), id, userID)
+	if err != nil {
+		offerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    offers,
+	})
+}
+
+// POST /api/offers/:id/counter
+func (h *Handler) CounterOffer(c *gin.Context) {
+	userID, id, ok := userAndID(c, "Invalid offer ID")
+	if !ok {
+		return
+	}
+
+	var req domain.MakeOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	msg, err := h.service.CounterOffer(c.Request.Context(), id, userID, req)
+	if err != nil {
+		offerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    msg,
+	})
+}
+
+// PUT /api/offers/:id/accept - optionally {"listingStatus": "reserved" | "pending_sale"}
+func (h *Handler) AcceptOffer(c *gin.Context) {
+	userID, id, ok := userAndID(c, "Invalid offer ID")
+	if !ok {
+		return
+	}
+
+	// The body is optional
+	var req domain.AcceptOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	offer, err := h.service.AcceptOffer(c.Request.Context(), id, userID, req)
+	if err != nil {
+		offerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Offer accepted",
+		"data":    offer,
+	})
+}
+
+// PUT /api/offers/:id/reject
+func (h *Handler) RejectOffer(c *gin.Context) {
+	userID, id, ok := userAndID(c, "Invalid offer ID")
+	if !ok {
+		return
+	}
+
+	offer, err := h.service.RejectOffer(c.Request.Context(), id, userID)
+	if err != nil {
+		offerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Offer rejected",
+		"data":    offer,
+	})
+}
+
+// userAndID reads the signed-in user and the :id parameter; it writes the
+// error response and returns false if either is missing or invalid.
+func userAndID(c *gin.Context, invalidID string) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, invalidID)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, id, true
+}
+
+func offerError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "conversation not found", "offer not found":
+		response.Error(c, http.StatusNotFound, err.Error())
+	case "not a participant", "you cannot respond to your own offer", "only the seller can change the listing status",
+		"you cannot message this user":
+		response.Error(c, http.StatusForbidden, err.Error())
+	case "an offer is already pending", "offer is no longer pending", "offer has expired":
+		response.Error(c, http.StatusConflict, err.Error())
+	case "offer expiry must be in the future", "offer expiry cannot be more than 14 days away":
+		response.Error(c, http.StatusBadRequest, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
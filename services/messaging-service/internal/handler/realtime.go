@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/realtime"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// heartbeatInterval keeps idle connections open through proxies
+	heartbeatInterval = 30 * time.Second
+	writeTimeout      = 10 * time.Second
+	// recheckInterval is how soon a connection ends after its account is
+	// suspended or deleted
+	recheckInterval = time.Minute
+)
+
+// GET /api/messages/ws - WebSocket stream of the user's conversation events
+func (h *Handler) WebSocket(c *gin.Context) {
+	client, ok := h.connect(c)
+	if !ok {
+		return
+	}
+	defer h.hub.Unregister(client)
+
+	server := websocket.Server{Handler: func(ws *websocket.Conn) {
+		ctx, cancel := context.WithCancel(c.Request.Context())
+		defer cancel()
+
+		// Clients don't send anything we act on; reading detects the close
+		go func() {
+			defer cancel()
+			var discard string
+			for websocket.Message.Receive(ws, &discard) == nil {
+			}
+		}()
+
+		send := func(payload []byte) error {
+			_ = ws.SetWriteDeadline(time.Now().Add(writeTimeout))
+			return websocket.Message.Send(ws, string(payload))
+		}
+		h.stream(ctx, c, client, func(msg realtime.Message) error {
+			return send(msg.Payload)
+		}, func() error {
+			return send([]byte(`{"type":"ping"}`))
+		})
+	}}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// GET /api/messages/events - Server-Sent Events fallback for clients without WebSockets
+func (h *Handler) Events(c *gin.Context) {
+	client, ok := h.connect(c)
+	if !ok {
+		return
+	}
+	defer h.hub.Unregister(client)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Stop nginx buffering the stream
+	c.Status(http.StatusOK)
+
+	// Payloads are single-line JSON, so each event is one data line
+	write := func(format string, args ...interface{}) error {
+		if _, err := fmt.Fprintf(c.Writer, format, args...); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	}
+	h.stream(c.Request.Context(), c, client, func(msg realtime.Message) error {
+		return write("event: %s\ndata: %s\n\n", msg.Type, msg.Payload)
+	}, func() error {
+		return write(": ping\n\n")
+	})
+}
+
+// connect registers the signed-in user's device with the hub
+func (h *Handler) connect(c *gin.Context) (*realtime.Client, bool) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return nil, false
+	}
+
+	client, err := h.hub.Register(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, http.StatusServiceUnavailable, "Real-time updates are unavailable")
+		return nil, false
+	}
+	return client, true
+}
+
+// stream sends the current unread counts, then the user's events as they
+// arrive, until the connection closes, the hub drops the client, the access
+// token expires or the account is no longer allowed in. Clients reconnect with
+// a fresh token and refetch what they show.
+func (h *Handler) stream(ctx context.Context, c *gin.Context, client *realtime.Client, send func(realtime.Message) error, ping func() error) {
+	expired := make(<-chan time.Time)
+	if claims := auth.GetClaims(c); claims != nil && claims.ExpiresAt != nil {
+		timer := time.NewTimer(time.Until(claims.ExpiresAt.Time))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	if msg, err := h.unreadMessage(ctx, client.UserID); err == nil {
+		if send(msg) != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	recheck := time.NewTicker(recheckInterval)
+	defer recheck.Stop()
+
+	for {
+		select {
+		case msg, ok := <-client.Messages():
+			if !ok || send(msg) != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if ping() != nil {
+				return
+			}
+		case <-recheck.C:
+			if h.recheck(c) != nil {
+				return
+			}
+		case <-expired:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (h *Handler) unreadMessage(ctx context.Context, userID uuid.UUID) (realtime.Message, error) {
+	counts, err := h.service.GetUnreadCount(ctx, userID)
+	if err != nil {
+		return realtime.Message{}, err
+	}
+	payload, err := json.Marshal(realtime.Event{Type: realtime.EventUnreadUpdated, Data: counts})
+	if err != nil {
+		return realtime.Message{}, err
+	}
+	return realtime.Message{Type: realtime.EventUnreadUpdated, Payload: payload}, nil
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// GET /api/quick-replies
+func (h *Handler) GetQuickReplies(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	saved, err := h.service.GetQuickReplies(c.Request.Context(), userID)
+	if err != nil {
+		replyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    saved,
+	})
+}
+
+// POST /api/quick-replies
+func (h *Handler) CreateQuickReply(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req domain.SaveQuickReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reply, err := h.service.CreateQuickReply(c.Request.Context(), userID, req)
+	if err != nil {
+		replyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    reply,
+	})
+}
+
+// PUT /api/quick-replies/:id
+func (h *Handler) UpdateQuickReply(c *gin.Context) {
+	userID, id, ok := userAndID(c, "Invalid quick reply ID")
+	if !ok {
+		return
+	}
+
+	var req domain.SaveQuickReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reply, err := h.service.UpdateQuickReply(c.Request.Context(), id, userID, req)
+	if err != nil {
+		replyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    reply,
+	})
+}
+
+// DELETE /api/quick-replies/:id
+func (h *Handler) DeleteQuickReply(c *gin.Context) {
+	userID, id, ok := userAndID(c, "Invalid quick reply ID")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteQuickReply(c.Request.Context(), id, userID); err != nil {
+		replyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Quick reply deleted",
+	})
+}
+
+// GET /api/conversations/:id/quick-replies - quick replies filled in for the conversation
+func (h *Handler) GetConversationQuickReplies(c *gin.Context) {
+	userID, id, ok := userAndID(c, "Invalid conversation ID")
+	if !ok {
+		return
+	}
+
+	filled, err := h.service.GetConversationQuickReplies(c.Request.Context(), id, userID)
+	if err != nil {
+		replyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    filled,
+	})
+}
+
+// GET /api/auto-reply - the auto-reply of the seller side the user is on
+func (h *Handler) GetAutoReply(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	reply, err := h.service.GetAutoReply(c.Request.Context(), userID)
+	if err != nil {
+		replyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    reply,
+	})
+}
+
+// PUT /api/auto-reply
+func (h *Handler) SetAutoReply(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req domain.SetAutoReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reply, err := h.service.SetAutoReply(c.Request.Context(), userID, req)
+	if err != nil {
+		replyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    reply,
+	})
+}
+
+func replyError(c *gin.Context, err error) {
+	msg := err.Error()
+	switch {
+	case msg == "quick reply not found", msg == "conversation not found":
+		response.Error(c, http.StatusNotFound, msg)
+	case msg == "not a participant", msg == "only organisation owners and managers can change the auto-reply":
+		response.Error(c, http.StatusForbidden, msg)
+	case strings.HasPrefix(msg, "unknown placeholder"), strings.HasPrefix(msg, "you can save up to"),
+		strings.HasPrefix(msg, "business hours"), strings.HasSuffix(msg, "required"):
+		response.Error(c, http.StatusBadRequest, msg)
+	default:
+		response.Error(c, http.StatusInternalServerError, msg)
+	}
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GET /api/admin/scam-phrases
+func (h *Handler) GetScamPhrases(c *gin.Context) {
+	phrases, err := h.service.GetScamPhrases(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    phrases,
+	})
+}
+
+// POST /api/admin/scam-phrases
+func (h *Handler) AddScamPhrase(c *gin.Context) {
+	adminID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req domain.CreateScamPhraseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	phrase, err := h.service.AddScamPhrase(c.Request.Context(), adminID, req)
+	if err != nil {
+		switch err.Error() {
+		case "phrase is required":
+			response.Error(c, http.StatusBadRequest, err.Error())
+		case "scam phrase already exists":
+			response.Error(c, http.StatusConflict, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    phrase,
+	})
+}
+
+// DELETE /api/admin/scam-phrases/:id
+func (h *Handler) DeleteScamPhrase(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid scam phrase ID")
+		return
+	}
+
+	if err := h.service.DeleteScamPhrase(c.Request.Context(), id); err != nil {
+		if err.Error() == "scam phrase not found" {
+			response.Error(c, http.StatusNotFound, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Scam phrase deleted",
+	})
+}
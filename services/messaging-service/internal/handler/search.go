@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// GET /api/messages/search?q=
+func (h *Handler) SearchMessages(c *gin.Context) {
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	params, err := pagination.CursorFromQuery(c, 20)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	hits, meta, err := h.service.SearchMessages(c.Request.Context(), userID, c.Query("q"), params)
+	if err != nil {
+		switch {
+		case errors.Is(err, pagination.ErrInvalidCursor), strings.HasPrefix(err.Error(), "search query"):
+			response.Error(c, http.StatusBadRequest, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"results":    hits,
+			"pagination": meta,
+		},
+	})
+}
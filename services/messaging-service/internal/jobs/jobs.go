@@ -0,0 +1,65 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/service"
+)
+
+// jobInterval bounds how long a conversation can show a stale listing price,
+// an offer can stay pending past its expiry, an appointment reminder is late,
+// a message released from review waits to be delivered, or a dealer team
+// change waits to reach the team's leads
+const jobInterval = time.Minute
+
+type JobScheduler struct {
+	svc service.Service
+}
+
+func NewJobScheduler(svc service.Service) *JobScheduler {
+	return &JobScheduler{svc: svc}
+}
+
+func (s *JobScheduler) Start() {
+	go s.runMinuteJobs()
+}
+
+func (s *JobScheduler) runMinuteJobs() {
+	ticker := time.NewTicker(jobInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx := context.Background()
+		if n, err := s.svc.RefreshListingSnapshots(ctx); err != nil {
+			log.Printf("Error refreshing conversation listing snapshots: %v", err)
+		} else if n > 0 {
+			log.Printf("Refreshed the listing snapshot of %d conversations", n)
+		}
+
+		if n, err := s.svc.ApplyOrganizationEvents(ctx); err != nil {
+			log.Printf("Error applying organisation events: %v", err)
+		} else if n > 0 {
+			log.Printf("Applied %d organisation events to conversations", n)
+		}
+
+		if n, err := s.svc.ExpireOffers(ctx); err != nil {
+			log.Printf("Error expiring offers: %v", err)
+		} else if n > 0 {
+			log.Printf("Expired %d offers", n)
+		}
+
+		if n, err := s.svc.SendAppointmentReminders(ctx); err != nil {
+			log.Printf("Error sending appointment reminders: %v", err)
+		} else if n > 0 {
+			log.Printf("Sent reminders for %d appointments", n)
+		}
+
+		if n, err := s.svc.DeliverReleasedMessages(ctx); err != nil {
+			log.Printf("Error delivering released messages: %v", err)
+		} else if n > 0 {
+			log.Printf("Delivered %d messages released from review", n)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Package listings resolves the listing a conversation is about and records
+// the offers accepted on it. Listings are owned by listings-service; the
+// Postgres lookup uses the shared database and can be swapped for a client of
+// listings-service's API.
+package listings
+
+import (
+	"context"
+
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Lookup interface {
+	// GetListing returns the current state of a listing, or nil if it does not exist.
+	GetListing(ctx context.Context, id uuid.UUID) (*domain.ListingSnapshot, error)
+	// RecordAcceptedOffer stores the accepted offer on its listing and, if the
+	// offer names one, moves an active listing to its new status.
+	RecordAcceptedOffer(ctx context.Context, offer *domain.Offer) error
+}
+
+type postgresLookup struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresLookup(db *pgxpool.Pool) Lookup {
+	return &postgresLookup{db: db}
+}
+
+func (l *postgresLookup) GetListing(ctx context.Context, id uuid.UUID) (*domain.ListingSnapshot, error) {
+	var s domain.ListingSnapshot
+	// The cover image, or the first image if none is marked as the cover
+	err := l.db.QueryRow(ctx, `
+		SELECT cl.id, cl.user_id, cl.organization_id, cl.assigned_to, cl.title, cl.price, cl.status,
+			(SELECT li.image_url FROM listing_images li WHERE li.listing_id = cl.id
+			 ORDER BY li.is_cover DESC, li.sort_order ASC LIMIT 1)
+		FROM car_listings cl
+		WHERE cl.id = $1
+	`, id).Scan(&s.ID, &s.SellerID, &s.OrganizationID, &s.AssignedTo, &s.Title, &s.Price, &s.Status, &s.Image)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (l *postgresLookup) RecordAcceptedOffer(ctx context.Context, offer *domain.Offer) error {
+	_, err := l.db.Exec(ctx, `
+		UPDATE car_listings
+		SET accepted_offer_id = $2,
+			accepted_offer_amount = $3,
+			accepted_offer_currency = $4,
+			offer_accepted_at = $5,
+			status = CASE WHEN $6::text IS NOT NULL AND status = 'active' THEN $6 ELSE status END,
+			updated_at = NOW()
+		WHERE id = $1
+	`, offer.ListingID, offer.ID, offer.Amount, offer.Currency, offer.RespondedAt, offer.ListingStatus)
+	return err
+}
@@ -0,0 +1,55 @@
+// Package notifications queues emails and texts for notification-service,
+// which delivers them from its outbox table in the shared database.
+package notifications
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Attachment is a file attached to an email, such as a calendar invite
+type Attachment struct {
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Content     []byte `json:"content"`
+}
+
+// Notification is rendered by notification-service from the template named
+// by Type, over the channels the user has enabled
+type Notification struct {
+	UserID      uuid.UUID
+	Type        string
+	Data        map[string]interface{}
+	Attachments []Attachment
+}
+
+type Queue interface {
+	Enqueue(ctx context.Context, notifications ...Notification) error
+}
+
+type postgresQueue struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresQueue(db *pgxpool.Pool) Queue {
+	return &postgresQueue{db: db}
+}
+
+func (q *postgresQueue) Enqueue(ctx context.Context, notifications ...Notification) error {
+	for _, n := range notifications {
+		attachments := n.Attachments
+		if attachments == nil {
+			attachments = []Attachment{}
+		}
+		_, err := q.db.Exec(ctx, `
+			INSERT INTO notification_outbox (user_id, type, data, attachments, source)
+			VALUES ($1, $2, $3, $4, 'messaging-service')
+		`, n.UserID, n.Type, n.Data, attachments)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
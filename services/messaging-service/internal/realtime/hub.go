@@ -0,0 +1,192 @@
+// Package realtime pushes conversation events to users' connected devices.
+// Events are published to a Redis channel per user, so a device connected to
+// any messaging-service replica receives them.
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Event types pushed to clients
+const (
+	EventMessageCreated     = "message.created"
+	EventConversationRead   = "conversation.read"
+	EventUnreadUpdated      = "unread.updated"
+	EventOfferUpdated       = "offer.updated"
+	EventAppointmentUpdated = "appointment.updated"
+)
+
+const (
+	channelPrefix = "messaging:user:"
+	// clientBuffer is how many events a slow device may fall behind before it is disconnected
+	clientBuffer = 64
+)
+
+// Event is the JSON sent to clients: {"type": "...", "data": {...}}. Silent
+// events update the client's views without an alert, e.g. for a muted conversation.
+type Event struct {
+	Type   string      `json:"type"`
+	Data   interface{} `json:"data"`
+	Silent bool        `json:"silent,omitempty"`
+}
+
+// Message is an encoded Event delivered to a connected device.
+type Message struct {
+	Type    string
+	Payload []byte
+}
+
+// Client is one connected device of a user.
+type Client struct {
+	UserID   uuid.UUID
+	messages chan Message
+	closed   bool
+}
+
+// Messages delivers the user's events. It is closed when the hub drops the
+// client, because it fell behind or the hub is shutting down.
+func (c *Client) Messages() <-chan Message {
+	return c.messages
+}
+
+// Hub tracks the devices connected to this replica and relays the events
+// published for their users.
+type Hub struct {
+	rdb    *redis.Client
+	pubsub *redis.PubSub
+
+	mu      sync.Mutex
+	clients map[uuid.UUID]map[*Client]struct{}
+}
+
+func NewHub(rdb *redis.Client) *Hub {
+	return &Hub{
+		rdb:     rdb,
+		pubsub:  rdb.Subscribe(context.Background()),
+		clients: make(map[uuid.UUID]map[*Client]struct{}),
+	}
+}
+
+// Run relays events from Redis to local clients until the hub is closed.
+func (h *Hub) Run() {
+	for msg := range h.pubsub.Channel() {
+		userID, err := uuid.Parse(strings.TrimPrefix(msg.Channel, channelPrefix))
+		if err != nil {
+			continue
+		}
+
+		var event struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+			log.Printf("realtime: dropping malformed event on %s: %v", msg.Channel, err)
+			continue
+		}
+
+		h.deliver(userID, Message{Type: event.Type, Payload: []byte(msg.Payload)})
+	}
+}
+
+// Publish sends event to every connected device of each user, on any replica.
+func (h *Hub) Publish(ctx context.Context, userIDs []uuid.UUID, event Event) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	pipe := h.rdb.Pipeline()
+	seen := make(map[uuid.UUID]bool, len(userIDs))
+	for _, id := range userIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		pipe.Publish(ctx, channelPrefix+id.String(), payload)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// Register connects a device for userID, subscribing this replica to the
+// user's channel if it is their first device here.
+func (h *Hub) Register(ctx context.Context, userID uuid.UUID) (*Client, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	devices, ok := h.clients[userID]
+	if !ok {
+		if err := h.pubsub.Subscribe(ctx, channelPrefix+userID.String()); err != nil {
+			return nil, err
+		}
+		devices = make(map[*Client]struct{})
+		h.clients[userID] = devices
+	}
+
+	client := &Client{UserID: userID, messages: make(chan Message, clientBuffer)}
+	devices[client] = struct{}{}
+	return client, nil
+}
+
+// Unregister disconnects a device, unsubscribing from the user's channel
+// once they have no devices left on this replica.
+func (h *Hub) Unregister(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(client)
+}
+
+// Close disconnects every device and stops Run.
+func (h *Hub) Close() error {
+	h.mu.Lock()
+	for _, devices := range h.clients {
+		for client := range devices {
+			h.remove(client)
+		}
+	}
+	h.mu.Unlock()
+	return h.pubsub.Close()
+}
+
+func (h *Hub) deliver(userID uuid.UUID, msg Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for client := range h.clients[userID] {
+		select {
+		case client.messages <- msg:
+		default:
+			// The device stopped reading; it will resync when it reconnects
+			log.Printf("realtime: disconnecting slow client of user %s", userID)
+			h.remove(client)
+		}
+	}
+}
+
+// remove must be called with h.mu held
+func (h *Hub) remove(client *Client) {
+	if client.closed {
+		return
+	}
+	client.closed = true
+	close(client.messages)
+
+	devices := h.clients[client.UserID]
+	delete(devices, client)
+	if len(devices) == 0 {
+		delete(h.clients, client.UserID)
+		if err := h.pubsub.Unsubscribe(context.Background(), channelPrefix+client.UserID.String()); err != nil {
+			log.Printf("realtime: failed to unsubscribe from user %s: %v", client.UserID, err)
+		}
+	}
+}
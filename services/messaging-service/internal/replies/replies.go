@@ -0,0 +1,70 @@
+// Package replies fills in the placeholders of quick reply templates and
+// auto-replies from the conversation they are sent in.
+package replies
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Placeholders templates can use
+const (
+	ListingTitle = "{listing_title}"
+	Price        = "{price}"
+	BuyerName    = "{buyer_name}"
+)
+
+var placeholderPattern = regexp.MustCompile(`\{[A-Za-z_]+\}`)
+
+// Values are what placeholders are filled in with: the conversation's listing
+// snapshot and the buyer
+type Values struct {
+	ListingTitle string
+	Price        float64
+	Currency     string
+	BuyerName    string
+}
+
+// Unknown returns the placeholders in the template that can't be filled in
+func Unknown(template string) []string {
+	var unknown []string
+	for _, p := range placeholderPattern.FindAllString(template, -1) {
+		switch p {
+		case ListingTitle, Price, BuyerName:
+		default:
+			unknown = append(unknown, p)
+		}
+	}
+	return unknown
+}
+
+// Render fills in the template's placeholders
+func Render(template string, v Values) string {
+	price := "price on request"
+	if v.Price > 0 {
+		price = FormatPrice(v.Currency, v.Price)
+	}
+	buyer := "there"
+	if fields := strings.Fields(v.BuyerName); len(fields) > 0 {
+		buyer = fields[0]
+	}
+	return strings.NewReplacer(
+		ListingTitle, v.ListingTitle,
+		Price, price,
+		BuyerName, buyer,
+	).Replace(template)
+}
+
+// FormatPrice writes a price with thousands separators, as in "LKR 12,500,000"
+func FormatPrice(currency string, amount float64) string {
+	digits := strconv.FormatFloat(amount, 'f', 0, 64)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return currency + " " + b.String()
+}
@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const appointmentColumns = `id, conversation_id, listing_id, buyer_id, seller_id, organization_id,
+	kind, status, proposed_slots, proposed_by, from_buyer, starts_at, ends_at, invited_starts_at, invited_ends_at,
+	location, notes, cancelled_by, cancel_reason, sequence, created_at, updated_at`
+
+func scanAppointment(row pgx.Row) (*domain.Appointment, error) {
+	var a domain.Appointment
+	err := row.Scan(
+		&a.ID, &a.ConversationID, &a.ListingID, &a.BuyerID, &a.SellerID, &a.OrganizationID,
+		&a.Kind, &a.Status, &a.ProposedSlots, &a.ProposedBy, &a.FromBuyer, &a.StartsAt, &a.EndsAt, &a.InvitedStart, &a.InvitedEnd,
+		&a.Location, &a.Notes,
+		&a.CancelledBy, &a.CancelReason, &a.Sequence, &a.CreatedAt, &a.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func scanAppointments(rows pgx.Rows) ([]domain.Appointment, error) {
+	defer rows.Close()
+
+	appointments := []domain.Appointment{}
+	for rows.Next() {
+		a, err := scanAppointment(rows)
+		if err != nil {
+			return nil, err
+		}
+		appointments = append(appointments, *a)
+	}
+	return appointments, rows.Err()
+}
+
+// CreateAppointment saves an appointment request and the message that carries
+// it. Only one appointment per conversation can be open.
+func (r *postgresRepository) CreateAppointment(ctx context.Context, a *domain.Appointment, msg *domain.Message) (*domain.Message, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialises appointments requested in the same conversation
+	if _, err := tx.Exec(ctx, "SELECT id FROM conversations WHERE id = $1 FOR UPDATE", a.ConversationID); err != nil {
+		return nil, err
+	}
+	var open bool
+	err = tx.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM appointments WHERE conversation_id = $1 AND status IN ('requested', 'confirmed'))",
+		a.ConversationID).Scan(&open)
+	if err != nil {
+		return nil, err
+	}
+	if open {
+		return nil, fmt.Errorf("an appointment is already open")
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO appointments (
+			conversation_id, listing_id, buyer_id, seller_id, organization_id,
+			kind, proposed_slots, proposed_by, from_buyer, location, notes
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, status, sequence, created_at, updated_at
+	`, a.ConversationID, a.ListingID, a.BuyerID, a.SellerID, a.OrganizationID,
+		a.Kind, a.ProposedSlots, a.ProposedBy, a.FromBuyer, a.Location, a.Notes,
+	).Scan(&a.ID, &a.Status, &a.Sequence, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertAppointmentMessage(ctx, tx, a, msg); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// UpdateAppointment saves a change to an appointment, with the message that
+// records it if msg isn't nil. It returns nil if the appointment changed
+// since it was loaded.
+func (r *postgresRepository) UpdateAppointment(ctx context.Context, a *domain.Appointment, msg *domain.Message) (*domain.Message, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// The loaded revision must still be current; a new proposal also needs a new reminder
+	err = tx.QueryRow(ctx, `
+		UPDATE appointments
+		SET status = $3, proposed_slots = $4, proposed_by = $5, from_buyer = $6,
+			starts_at = $7, ends_at = $8, invited_starts_at = $12, invited_ends_at = $13,
+			location = $9, cancelled_by = $10, cancel_reason = $11,
+			reminder_sent_at = CASE WHEN starts_at IS DISTINCT FROM $7 THEN NULL ELSE reminder_sent_at END,
+			sequence = sequence + 1, updated_at = NOW()
+		WHERE id = $1 AND sequence = $2
+		RETURNING sequence, updated_at
+	`, a.ID, a.Sequence, a.Status, a.ProposedSlots, a.ProposedBy, a.FromBuyer,
+		a.StartsAt, a.EndsAt, a.Location, a.CancelledBy, a.CancelReason, a.InvitedStart, a.InvitedEnd,
+	).Scan(&a.Sequence, &a.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("appointment was changed by someone else, reload it and try again")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if msg != nil {
+		if err := insertAppointmentMessage(ctx, tx, a, msg); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func insertAppointmentMessage(ctx context.Context, tx pgx.Tx, a *domain.Appointment, msg *domain.Message) error {
+	msg.AppointmentID = &a.ID
+	err := tx.QueryRow(ctx, `
+		INSERT INTO messages (conversation_id, sender_id, message_type, appointment_id, content, is_read, created_at)
+		VALUES ($1, $2, $3, $4, $5, FALSE, NOW())
+		RETURNING id, moderation_status, created_at
+	`, msg.ConversationID, msg.SenderID, msg.Type, msg.AppointmentID, msg.Content).Scan(&msg.ID, &msg.ModerationStatus, &msg.CreatedAt)
+	if err != nil {
+		return err
+	}
+	msg.Appointment = a
+	return nil
+}
+
+func (r *postgresRepository) GetAppointment(ctx context.Context, id uuid.UUID) (*domain.Appointment, error) {
+	a, err := scanAppointment(r.db.QueryRow(ctx, "SELECT "+appointmentColumns+" FROM appointments WHERE id = $1", id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return a, err
+}
+
+// GetConversationAppointments returns the conversation's appointments, oldest first
+func (r *postgresRepository) GetConversationAppointments(ctx context.Context, conversationID uuid.UUID) ([]domain.Appointment, error) {
+	rows, err := r.db.Query(ctx,
+		"SELECT "+appointmentColumns+" FROM appointments WHERE conversation_id = $1 ORDER BY created_at", conversationID)
+	if err != nil {
+		return nil, err
+	}
+	return scanAppointments(rows)
+}
+
+// GetUserAppointments returns the appointments on either side of the viewer's
+// conversations, soonest first, optionally only those with the given status
+func (r *postgresRepository) GetUserAppointments(ctx context.Context, viewer domain.Viewer, status string) ([]domain.Appointment, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+prefixed("a", appointmentColumns)+`
+		FROM appointments a
+		JOIN conversations c ON c.id = a.conversation_id
+		WHERE (c.buyer_id = $1 OR `+sellerSide+`)
+		AND ($4 = '' OR a.status = $4)
+		ORDER BY COALESCE(a.starts_at, (a.proposed_slots->0->>'start')::timestamptz), a.created_at
+	`, viewer.UserID, viewer.OrganizationID, viewer.CanManage, status)
+	if err != nil {
+		return nil, err
+	}
+	return scanAppointments(rows)
+}
+
+// ClaimAppointmentReminders marks the confirmed appointments starting within
+// the reminder lead as reminded and returns them, so each is reminded once
+// across replicas
+func (r *postgresRepository) ClaimAppointmentReminders(ctx context.Context) ([]domain.Appointment, error) {
+	rows, err := r.db.Query(ctx, `
+		UPDATE appointments SET reminder_sent_at = NOW()
+		WHERE status = 'confirmed' AND reminder_sent_at IS NULL
+		AND starts_at > NOW() AND starts_at <= NOW() + $1 * INTERVAL '1 second'
+		RETURNING `+appointmentColumns, domain.AppointmentReminderLead.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	return scanAppointments(rows)
+}
+
+// GetAvailability returns the owner's weekly availability, in weekday order
+func (r *postgresRepository) GetAvailability(ctx context.Context, ownerID uuid.UUID) ([]domain.AvailabilityWindow, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT weekday, to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI')
+		FROM availability_windows
+		WHERE owner_id = $1
+		ORDER BY weekday, start_time
+	`, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	windows := []domain.AvailabilityWindow{}
+	for rows.Next() {
+		var w domain.AvailabilityWindow
+		if err := rows.Scan(&w.Weekday, &w.Start, &w.End); err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
+	}
+	return windows, rows.Err()
+}
+
+// SetAvailability replaces the owner's weekly availability
+func (r *postgresRepository) SetAvailability(ctx context.Context, ownerID uuid.UUID, windows []domain.AvailabilityWindow) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "DELETE FROM availability_windows WHERE owner_id = $1", ownerID); err != nil {
+		return err
+	}
+	for _, w := range windows {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO availability_windows (owner_id, weekday, start_time, end_time)
+			VALUES ($1, $2, $3::time, $4::time)
+		`, ownerID, w.Weekday, w.Start, w.End)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// prefixed qualifies each column in a column list with the table alias
+func prefixed(alias, columns string) string {
+	parts := strings.Split(columns, ",")
+	for i, p := range parts {
+		parts[i] = alias + "." + strings.TrimSpace(p)
+	}
+	return strings.Join(parts, ", ")
+}
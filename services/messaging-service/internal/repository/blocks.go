@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Blocks. Owners and blocked parties are users, or dealer organisations.
+
+func (r *postgresRepository) BlockUser(ctx context.Context, ownerID, blockedID, blockedBy uuid.UUID, conversationID *uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO user_blocks (owner_id, blocked_id, blocked_by, conversation_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (owner_id, blocked_id) DO NOTHING
+	`, ownerID, blockedID, blockedBy, conversationID)
+	return err
+}
+
+func (r *postgresRepository) UnblockUser(ctx context.Context, ownerIDs []uuid.UUID, blockedID uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, "DELETE FROM user_blocks WHERE owner_id = ANY($1) AND blocked_id = $2", ownerIDs, blockedID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("user is not blocked")
+	}
+	return nil
+}
+
+func (r *postgresRepository) GetBlockedUsers(ctx context.Context, ownerIDs []uuid.UUID) ([]domain.BlockedUser, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT b.blocked_id, COALESCE(u.name, o.name, ''), b.blocked_by, b.conversation_id, b.created_at
+		FROM user_blocks b
+		LEFT JOIN users u ON u.id = b.blocked_id
+		LEFT JOIN organizations o ON o.id = b.blocked_id
+		WHERE b.owner_id = ANY($1)
+		ORDER BY b.created_at DESC
+	`, ownerIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocked := []domain.BlockedUser{}
+	for rows.Next() {
+		var b domain.BlockedUser
+		if err := rows.Scan(&b.ID, &b.Name, &b.BlockedBy, &b.ConversationID, &b.CreatedAt); err != nil {
+			return nil, err
+		}
+		blocked = append(blocked, b)
+	}
+	return blocked, rows.Err()
+}
+
+// IsBlocked reports whether the buyer and the seller side have blocked each
+// other, in either direction
+func (r *postgresRepository) IsBlocked(ctx context.Context, buyerID, sellerOwnerID uuid.UUID) (bool, error) {
+	var blocked bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM user_blocks
+			WHERE (owner_id = $1 AND blocked_id = $2) OR (owner_id = $2 AND blocked_id = $1)
+		)
+	`, buyerID, sellerOwnerID).Scan(&blocked)
+	return blocked, err
+}
+
+// Conversation reports are stored with reports-service's listing reports, in the shared database
+
+func (r *postgresRepository) HasRecentConversationReport(ctx context.Context, reporterID, conversationID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM listing_reports
+			WHERE target_type = 'conversation' AND target_id = $1 AND reporter_id = $2
+			AND created_at > NOW() - INTERVAL '24 hours'
+		)
+	`, conversationID, reporterID).Scan(&exists)
+	return exists, err
+}
+
+func (r *postgresRepository) CreateConversationReport(ctx context.Context, report *domain.ConversationReport) error {
+	excerpt, err := json.Marshal(report.Excerpt)
+	if err != nil {
+		return err
+	}
+	return r.db.QueryRow(ctx, `
+		INSERT INTO listing_reports (target_type, target_id, reporter_id, reported_user_id, reason, details, excerpt)
+		VALUES ('conversation', $1, $2, $3, $4, $5, $6)
+		RETURNING id, status, created_at
+	`, report.ConversationID, report.ReporterID, report.ReportedUserID, report.Reason, report.Details, excerpt).Scan(
+		&report.ID, &report.Status, &report.CreatedAt,
+	)
+}
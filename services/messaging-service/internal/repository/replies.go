@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Quick replies
+
+func (r *postgresRepository) GetQuickReplies(ctx context.Context, userID uuid.UUID) ([]domain.QuickReply, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, title, content, created_at, updated_at
+		FROM quick_replies
+		WHERE user_id = $1
+		ORDER BY LOWER(title), created_at
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	replies := []domain.QuickReply{}
+	for rows.Next() {
+		var q domain.QuickReply
+		if err := rows.Scan(&q.ID, &q.Title, &q.Content, &q.CreatedAt, &q.UpdatedAt); err != nil {
+			return nil, err
+		}
+		replies = append(replies, q)
+	}
+	return replies, rows.Err()
+}
+
+// CreateQuickReply saves a quick reply, unless the user already has
+// domain.MaxQuickReplies
+func (r *postgresRepository) CreateQuickReply(ctx context.Context, userID uuid.UUID, reply *domain.QuickReply) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO quick_replies (user_id, title, content)
+		SELECT $1, $2, $3
+		WHERE (SELECT COUNT(*) FROM quick_replies WHERE user_id = $1) < $4
+		RETURNING id, created_at, updated_at
+	`, userID, reply.Title, reply.Content, domain.MaxQuickReplies).Scan(&reply.ID, &reply.CreatedAt, &reply.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return fmt.Errorf("you can save up to %d quick replies", domain.MaxQuickReplies)
+	}
+	return err
+}
+
+func (r *postgresRepository) UpdateQuickReply(ctx context.Context, userID uuid.UUID, reply *domain.QuickReply) error {
+	err := r.db.QueryRow(ctx, `
+		UPDATE quick_replies SET title = $3, content = $4, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+		RETURNING created_at, updated_at
+	`, reply.ID, userID, reply.Title, reply.Content).Scan(&reply.CreatedAt, &reply.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return fmt.Errorf("quick reply not found")
+	}
+	return err
+}
+
+func (r *postgresRepository) DeleteQuickReply(ctx context.Context, userID, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, "DELETE FROM quick_replies WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("quick reply not found")
+	}
+	return nil
+}
+
+// Auto-replies. Owners are sellers, or dealer organisations.
+
+// GetAutoReply returns the owner's auto-reply, or nil if they haven't set one
+func (r *postgresRepository) GetAutoReply(ctx context.Context, ownerID uuid.UUID) (*domain.AutoReply, error) {
+	var a domain.AutoReply
+	var hours []byte
+	err := r.db.QueryRow(ctx, `
+		SELECT enabled, mode, message, business_hours, updated_by, updated_at
+		FROM auto_replies WHERE owner_id = $1
+	`, ownerID).Scan(&a.Enabled, &a.Mode, &a.Message, &hours, &a.UpdatedBy, &a.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(hours, &a.BusinessHours); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *postgresRepository) SetAutoReply(ctx context.Context, ownerID uuid.UUID, reply *domain.AutoReply) error {
+	hours, err := json.Marshal(reply.BusinessHours)
+	if err != nil {
+		return err
+	}
+	return r.db.QueryRow(ctx, `
+		INSERT INTO auto_replies (owner_id, enabled, mode, message, business_hours, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (owner_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled, mode = EXCLUDED.mode, message = EXCLUDED.message,
+			business_hours = EXCLUDED.business_hours, updated_by = EXCLUDED.updated_by, updated_at = NOW()
+		RETURNING updated_at
+	`, ownerID, reply.Enabled, reply.Mode, reply.Message, hours, reply.UpdatedBy).Scan(&reply.UpdatedAt)
+}
+
+// GetUserName returns the user's display name, from the users table owned by auth-service
+func (r *postgresRepository) GetUserName(ctx context.Context, userID uuid.UUID) (string, error) {
+	var name string
+	err := r.db.QueryRow(ctx, "SELECT COALESCE(name, '') FROM users WHERE id = $1", userID).Scan(&name)
+	if err == pgx.ErrNoRows {
+		return "", nil
+	}
+	return name, err
+}
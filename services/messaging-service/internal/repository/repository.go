@@ -2,7 +2,11 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/org"
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
 	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -14,14 +18,68 @@ type Repository interface {
 	CreateConversation(ctx context.Context, conv *domain.Conversation) (uuid.UUID, error) // Returns ID
 	GetConversationByID(ctx context.Context, id uuid.UUID) (*domain.Conversation, error)
 	GetConversationByParticipants(ctx context.Context, listingID, buyerID, sellerID uuid.UUID) (*domain.Conversation, error)
-	GetUserConversations(ctx context.Context, userID uuid.UUID, params domain.Pagination, archived bool) ([]domain.Conversation, int64, error)
-	UpdateConversationLastMessage(ctx context.Context, id uuid.UUID, message string, senderID uuid.UUID) error
-	MarkConversationRead(ctx context.Context, id, userID uuid.UUID) error
+	GetUserConversations(ctx context.Context, viewer domain.Viewer, params pagination.Params, archived bool) ([]domain.Conversation, int64, error)
+	UpdateConversationLastMessage(ctx context.Context, id uuid.UUID, message string, fromBuyer bool) error
+	MarkConversationRead(ctx context.Context, id uuid.UUID, asBuyer bool) error
+	SetConversationArchived(ctx context.Context, id uuid.UUID, asBuyer, archived bool) error
+	SetConversationMuted(ctx context.Context, id uuid.UUID, asBuyer, muted bool) error
+	DeleteConversation(ctx context.Context, id uuid.UUID, asBuyer bool) error
+	RefreshListingSnapshots(ctx context.Context) (int64, error)
+	ApplyOrganizationEvents(ctx context.Context, limit int) (int, error)
+	AssignConversation(ctx context.Context, id, assignedTo uuid.UUID) error
 
 	// Message
 	CreateMessage(ctx context.Context, msg *domain.Message) (*domain.Message, error)
-	GetMessagesByConversation(ctx context.Context, conversationID uuid.UUID, params domain.Pagination) ([]domain.Message, int64, error)
-	GetTotalUnreadCount(ctx context.Context, userID uuid.UUID) (int, []domain.ConversationUnread, error)
+	GetMessagesByConversation(ctx context.Context, conversationID, viewerID uuid.UUID, since *time.Time, params pagination.Params) ([]domain.Message, int64, error)
+	GetTotalUnreadCount(ctx context.Context, viewer domain.Viewer) (int, []domain.ConversationUnread, error)
+	GetMessageAttachments(ctx context.Context, messageIDs []uuid.UUID) ([]domain.Attachment, error)
+	SearchMessages(ctx context.Context, viewer domain.Viewer, query string, page pagination.CursorParams) ([]domain.SearchHit, error)
+
+	// Screening
+	GetSenderActivity(ctx context.Context, userID uuid.UUID) (*domain.SenderActivity, error)
+	GetScamPhrases(ctx context.Context) ([]domain.ScamPhrase, error)
+	CreateScamPhrase(ctx context.Context, phrase string, createdBy uuid.UUID) (*domain.ScamPhrase, error)
+	DeleteScamPhrase(ctx context.Context, id uuid.UUID) error
+	ClaimReleasedMessages(ctx context.Context) ([]domain.Message, error)
+
+	// Blocks and conversation reports
+	BlockUser(ctx context.Context, ownerID, blockedID, blockedBy uuid.UUID, conversationID *uuid.UUID) error
+	UnblockUser(ctx context.Context, ownerIDs []uuid.UUID, blockedID uuid.UUID) error
+	GetBlockedUsers(ctx context.Context, ownerIDs []uuid.UUID) ([]domain.BlockedUser, error)
+	IsBlocked(ctx context.Context, buyerID, sellerOwnerID uuid.UUID) (bool, error)
+	HasRecentConversationReport(ctx context.Context, reporterID, conversationID uuid.UUID) (bool, error)
+	CreateConversationReport(ctx context.Context, report *domain.ConversationReport) error
+
+	// Quick replies and auto-replies
+	GetQuickReplies(ctx context.Context, userID uuid.UUID) ([]domain.QuickReply, error)
+	CreateQuickReply(ctx context.Context, userID uuid.UUID, reply *domain.QuickReply) error
+	UpdateQuickReply(ctx context.Context, userID uuid.UUID, reply *domain.QuickReply) error
+	DeleteQuickReply(ctx context.Context, userID, id uuid.UUID) error
+	GetAutoReply(ctx context.Context, ownerID uuid.UUID) (*domain.AutoReply, error)
+	SetAutoReply(ctx context.Context, ownerID uuid.UUID, reply *domain.AutoReply) error
+	GetUserName(ctx context.Context, userID uuid.UUID) (string, error)
+
+	// Offers
+	CreateOffer(ctx context.Context, offer *domain.Offer, msg *domain.Message) (*domain.Message, error)
+	GetOffer(ctx context.Context, id uuid.UUID) (*domain.Offer, error)
+	GetConversationOffers(ctx context.Context, conversationID uuid.UUID) ([]domain.Offer, error)
+	RespondToOffer(ctx context.Context, id, responderID uuid.UUID, status string, listingStatus *string) (*domain.Offer, error)
+	ExpireOffers(ctx context.Context) (int64, error)
+
+	// Appointments
+	CreateAppointment(ctx context.Context, a *domain.Appointment, msg *domain.Message) (*domain.Message, error)
+	UpdateAppointment(ctx context.Context, a *domain.Appointment, msg *domain.Message) (*domain.Message, error)
+	GetAppointment(ctx context.Context, id uuid.UUID) (*domain.Appointment, error)
+	GetConversationAppointments(ctx context.Context, conversationID uuid.UUID) ([]domain.Appointment, error)
+	GetUserAppointments(ctx context.Context, viewer domain.Viewer, status string) ([]domain.Appointment, error)
+	ClaimAppointmentReminders(ctx context.Context) ([]domain.Appointment, error)
+	GetAvailability(ctx context.Context, ownerID uuid.UUID) ([]domain.AvailabilityWindow, error)
+	SetAvailability(ctx context.Context, ownerID uuid.UUID, windows []domain.AvailabilityWindow) error
+
+	// Dealer organisations (organizations are owned by auth-service, in the shared database)
+	GetMembership(ctx context.Context, userID uuid.UUID) (*org.Membership, error)
+	IsOrganizationMember(ctx context.Context, orgID, userID uuid.UUID) (bool, error)
+	GetOrganizationManagers(ctx context.Context, orgID uuid.UUID) ([]uuid.UUID, error)
 }
 
 type postgresRepository struct {
@@ -38,33 +96,32 @@ func (r *postgresRepository) CreateConversation(ctx context.Context, conv *domai
 	var id uuid.UUID
 	err := r.db.QueryRow(ctx, `
 		INSERT INTO conversations (
-			listing_id, buyer_id, seller_id, 
+			listing_id, buyer_id, seller_id, organization_id, assigned_to,
 			listing_title, listing_image, listing_price,
-			last_message_preview, last_message_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+			last_message_preview
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id
-	`, conv.ListingID, conv.BuyerID, conv.SellerID, conv.ListingTitle, conv.ListingImage, conv.ListingPrice, conv.LastMessagePreview).Scan(&id)
+	`, conv.ListingID, conv.BuyerID, conv.SellerID, conv.OrganizationID, conv.AssignedTo,
+		conv.ListingTitle, conv.ListingImage, conv.ListingPrice, conv.LastMessagePreview).Scan(&id)
 	return id, err
 }
 
 func (r *postgresRepository) GetConversationByID(ctx context.Context, id uuid.UUID) (*domain.Conversation, error) {
+	// Unread counts depend on who is asking, so the service maps them; this is the raw row
 	var c domain.Conversation
-	var listingID *uuid.UUID
 	err := r.db.QueryRow(ctx, `
-		SELECT id, listing_id, buyer_id, seller_id, listing_title, listing_image, listing_price, 
-		last_message_at, last_message_preview, buyer_unread_count, seller_unread_count, created_at
+		SELECT id, listing_id, buyer_id, seller_id, organization_id, assigned_to,
+		listing_title, listing_image, listing_price, COALESCE(last_message_at, created_at), last_message_preview, created_at,
+		COALESCE(is_archived_by_buyer, FALSE), COALESCE(is_archived_by_seller, FALSE),
+		COALESCE(is_muted_by_buyer, FALSE), COALESCE(is_muted_by_seller, FALSE),
+		deleted_by_buyer_at, deleted_by_seller_at
 		FROM conversations WHERE id = $1
 	`, id).Scan(
-		&c.ID, &listingID, &c.BuyerID, &c.SellerID, &c.ListingTitle, &c.ListingImage, &c.ListingPrice,
-		&c.LastMessageAt, &c.LastMessagePreview, &c.UnreadCount, &c.SellerID, &c.CreatedAt, // Note: Mapping unread/seller count incorrectly here in scan line, need fix below
+		&c.ID, &c.ListingID, &c.BuyerID, &c.SellerID, &c.OrganizationID, &c.AssignedTo,
+		&c.ListingTitle, &c.ListingImage, &c.ListingPrice, &c.LastMessageAt, &c.LastMessagePreview, &c.CreatedAt,
+		&c.ArchivedByBuyer, &c.ArchivedBySeller, &c.MutedByBuyer, &c.MutedBySeller,
+		&c.DeletedByBuyerAt, &c.DeletedBySellerAt,
 	)
-
-	// FIX: Direct scan of unread counts needs conditional mapping based on who is asking.
-	// The repo returns raw data, Service maps context.
-	// But struct has single 'UnreadCount' field. Let's create a raw struct or scan into temp vars.
-	// Sticking to domain model directly is tricky if it needs context.
-	// Let's reload to scan into specific vars.
-
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil // Not found
@@ -72,45 +129,6 @@ func (r *postgresRepository) GetConversationByID(ctx context.Context, id uuid.UU
 		return nil, err
 	}
 
-	// Re-do correctly
-	var buyerUnread, sellerUnread int
-	err = r.db.QueryRow(ctx, `
-		SELECT id, listing_id, buyer_id, seller_id, listing_title, listing_image, listing_price, 
-		last_message_at, last_message_preview, buyer_unread_count, seller_unread_count, created_at
-		FROM conversations WHERE id = $1
-	`, id).Scan(
-		&c.ID, &listingID, &c.BuyerID, &c.SellerID, &c.ListingTitle, &c.ListingImage, &c.ListingPrice,
-		&c.LastMessageAt, &c.LastMessagePreview, &buyerUnread, &sellerUnread, &c.CreatedAt,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	c.ListingID = listingID
-	// Store raw counts in mapped struct?
-	// We can't know "UnreadCount" (user specific) without userID.
-	// We will fill 'UnreadCount' in Service layer or just store both in a different internal struct.
-	// For simplicity, let's misuse the struct or adding temp fields?
-	// No, let's just use UnreadCount for Buyer temporarily data passing or better yet, methods should handle it.
-	// Actually, `GetConversationByID` usually takes a UserID to format it, but here it's generic.
-	// Let's assign 0 and let context filler handle it, or pass UserID to this method.
-	// We'll update interface to be generic and let service map it.
-	// For generic internal usage, we return basic data.
-
-	// IMPORTANT: For proper logic, we might need an internal struct or use domain struct fields cleverly.
-	// Let's assume the caller uses `c.BuyerID` and `c.SellerID` to pick the right count from DB re-query if needed? N/A.
-	// We should probably include BuyerUnreadCount and SellerUnreadCount in domain model if we want to be pure.
-	// BUT, complying with `domain.Conversation` which has single `UnreadCount`.
-	// We will repurpose `UnreadCount` to 0 here. Service must select.
-	// Wait, we can't easily select if we don't return both.
-	// Hack: We'll put BuyerUnread in `UnreadCount` and SellerUnread in... ?
-	// Let's modify the query in Service instead?
-	// Or, update `domain.Conversation` to have `BuyerUnreadCount` and `SellerUnreadCount`.
-	// Given I can't easily change domain file now without another tool call (expensive), I will assume Service handles user context mapping
-	// and Repo returns raw row data if I add fields, OR I just pass UserID to `GetConversationByID` which is cleaner.
-
-	// DECISION: I will change `GetConversationByID` and `GetUserConversations` to take UserID, merging business logic slightly for efficiency.
-
 	return &c, nil
 }
 
@@ -121,7 +139,7 @@ func (r *postgresRepository) GetConversationByIDWithUser(ctx context.Context, id
 
 	err := r.db.QueryRow(ctx, `
 		SELECT id, listing_id, buyer_id, seller_id, listing_title, listing_image, listing_price, 
-		last_message_at, last_message_preview, buyer_unread_count, seller_unread_count, created_at
+		COALESCE(last_message_at, created_at), last_message_preview, buyer_unread_count, seller_unread_count, created_at
 		FROM conversations WHERE id = $1
 	`, id).Scan(
 		&c.ID, &c.ListingID, &c.BuyerID, &c.SellerID, &c.ListingTitle, &c.ListingImage, &c.ListingPrice,
@@ -178,40 +196,56 @@ func (r *postgresRepository) GetConversationByParticipants(ctx context.Context,
 	return &domain.Conversation{ID: id}, nil
 }
 
-func (r *postgresRepository) GetUserConversations(ctx context.Context, userID uuid.UUID, params domain.Pagination, archived bool) ([]domain.Conversation, int64, error) {
+// sellerSide matches conversations where the viewer ($1, with organisation $2
+// and manage flag $3) handles the seller side: their own listings' leads, or a
+// dealer organisation's leads for owners and managers and the assigned member.
+const sellerSide = `(CASE
+		WHEN c.organization_id IS NULL THEN c.seller_id = $1
+		ELSE c.organization_id = $2::uuid AND ($3::boolean OR c.assigned_to = $1)
+	END)`
+
+// visibleToViewer hides conversations the viewer's side deleted, until a new
+// message arrives. One without a delivered message yet is only the buyer's.
+const visibleToViewer = `COALESCE(CASE WHEN c.buyer_id = $1 THEN c.deleted_by_buyer_at ELSE c.deleted_by_seller_at END, '-infinity') <
+	COALESCE(c.last_message_at, CASE WHEN c.buyer_id = $1 THEN c.created_at END)`
+
+func (r *postgresRepository) GetUserConversations(ctx context.Context, viewer domain.Viewer, params pagination.Params, archived bool) ([]domain.Conversation, int64, error) {
 	// Count
 	var total int64
 	err := r.db.QueryRow(ctx, `
-		SELECT COUNT(*) FROM conversations 
-		WHERE (buyer_id = $1 OR seller_id = $1)
+		SELECT COUNT(*) FROM conversations c
+		WHERE (c.buyer_id = $1 OR `+sellerSide+`)
 		AND CASE 
-			WHEN buyer_id = $1 THEN is_archived_by_buyer
-			ELSE is_archived_by_seller
-		END = $2
-	`, userID, archived).Scan(&total)
+			WHEN c.buyer_id = $1 THEN c.is_archived_by_buyer
+			ELSE c.is_archived_by_seller
+		END = $4
+		AND `+visibleToViewer+`
+	`, viewer.UserID, viewer.OrganizationID, viewer.CanManage, archived).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	offset := (params.Page - 1) * params.Limit
+	offset := params.Offset()
 
 	query := `
 		SELECT 
-			c.id, c.listing_id, c.buyer_id, c.seller_id, c.listing_title, c.listing_image, c.listing_price,
-			c.last_message_at, c.last_message_preview, c.created_at,
+			c.id, c.listing_id, c.buyer_id, c.seller_id, c.organization_id, c.assigned_to, c.listing_title, c.listing_image, c.listing_price,
+			COALESCE(c.last_message_at, c.created_at), c.last_message_preview, c.created_at,
 			CASE WHEN c.buyer_id = $1 THEN c.buyer_unread_count ELSE c.seller_unread_count END as unread,
+			COALESCE(CASE WHEN c.buyer_id = $1 THEN c.is_muted_by_buyer ELSE c.is_muted_by_seller END, FALSE) as muted,
 			u.id as part_id, u.name as part_name, u.avatar_url as part_avatar
 		FROM conversations c
-		JOIN users u ON u.id = CASE WHEN c.buyer_id = $1 THEN c.seller_id ELSE c.buyer_id END
-		WHERE (c.buyer_id = $1 OR c.seller_id = $1)
+		JOIN users u ON u.id = CASE WHEN c.buyer_id = $1 THEN COALESCE(c.assigned_to, c.seller_id) ELSE c.buyer_id END
+		WHERE (c.buyer_id = $1 OR ` + sellerSide + `)
 		AND CASE 
 			WHEN c.buyer_id = $1 THEN c.is_archived_by_buyer
 			ELSE c.is_archived_by_seller
-		END = $2
-		ORDER BY c.last_message_at DESC
-		LIMIT $3 OFFSET $4
+		END = $4
+		AND ` + visibleToViewer + `
+		ORDER BY COALESCE(c.last_message_at, c.created_at) DESC
+		LIMIT $5 OFFSET $6
 	`
-	rows, err := r.db.Query(ctx, query, userID, archived, params.Limit, offset)
+	rows, err := r.db.Query(ctx, query, viewer.UserID, viewer.OrganizationID, viewer.CanManage, archived, params.Limit, offset)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -225,13 +259,14 @@ func (r *postgresRepository) GetUserConversations(ctx context.Context, userID uu
 		var partAvatar *string
 
 		err := rows.Scan(
-			&c.ID, &c.ListingID, &c.BuyerID, &c.SellerID, &c.ListingTitle, &c.ListingImage, &c.ListingPrice,
-			&c.LastMessageAt, &c.LastMessagePreview, &c.CreatedAt, &c.UnreadCount,
+			&c.ID, &c.ListingID, &c.BuyerID, &c.SellerID, &c.OrganizationID, &c.AssignedTo, &c.ListingTitle, &c.ListingImage, &c.ListingPrice,
+			&c.LastMessageAt, &c.LastMessagePreview, &c.CreatedAt, &c.UnreadCount, &c.IsMuted,
 			&partID, &partName, &partAvatar,
 		)
 		if err != nil {
 			return nil, 0, err
 		}
+		c.IsArchived = archived
 
 		c.Participant = &domain.UserSummary{ID: partID, Name: partName, Avatar: partAvatar}
 		conversations = append(conversations, c)
@@ -240,66 +275,237 @@ func (r *postgresRepository) GetUserConversations(ctx context.Context, userID uu
 	return conversations, total, nil
 }
 
-func (r *postgresRepository) UpdateConversationLastMessage(ctx context.Context, id uuid.UUID, message string, senderID uuid.UUID) error {
-	// We need to know if sender is buyer or seller to increment OTHER's count
-	// Efficient update using CASE logic?
-	// Need to check roles first or do complex update.
-	// Update: Increment count for the recipient.
-
+func (r *postgresRepository) UpdateConversationLastMessage(ctx context.Context, id uuid.UUID, message string, fromBuyer bool) error {
+	// Increment the unread count of the side that did not send the message, and
+	// bring the conversation back to their inbox if they archived it
 	query := `
 		UPDATE conversations 
 		SET 
 			last_message_preview = $1,
 			last_message_at = NOW(),
-			buyer_unread_count = CASE WHEN seller_id = $3 THEN buyer_unread_count + 1 ELSE buyer_unread_count END,
-			seller_unread_count = CASE WHEN buyer_id = $3 THEN seller_unread_count + 1 ELSE seller_unread_count END
+			buyer_unread_count = CASE WHEN $3 THEN buyer_unread_count ELSE buyer_unread_count + 1 END,
+			seller_unread_count = CASE WHEN $3 THEN seller_unread_count + 1 ELSE seller_unread_count END,
+			is_archived_by_buyer = CASE WHEN $3 THEN is_archived_by_buyer ELSE FALSE END,
+			is_archived_by_seller = CASE WHEN $3 THEN FALSE ELSE is_archived_by_seller END
 		WHERE id = $2
 	`
-	_, err := r.db.Exec(ctx, query, message, id, senderID)
+	_, err := r.db.Exec(ctx, query, message, id, fromBuyer)
 	return err
 }
 
-func (r *postgresRepository) MarkConversationRead(ctx context.Context, id, userID uuid.UUID) error {
+func (r *postgresRepository) MarkConversationRead(ctx context.Context, id uuid.UUID, asBuyer bool) error {
 	query := `
 		UPDATE conversations
 		SET 
-			buyer_unread_count = CASE WHEN buyer_id = $2 THEN 0 ELSE buyer_unread_count END,
-			seller_unread_count = CASE WHEN seller_id = $2 THEN 0 ELSE seller_unread_count END
+			buyer_unread_count = CASE WHEN $2 THEN 0 ELSE buyer_unread_count END,
+			seller_unread_count = CASE WHEN $2 THEN seller_unread_count ELSE 0 END
 		WHERE id = $1
 	`
-	_, err := r.db.Exec(ctx, query, id, userID)
+	if _, err := r.db.Exec(ctx, query, id, asBuyer); err != nil {
+		return err
+	}
+
+	// Read receipts for the messages the other side sent
+	_, err := r.db.Exec(ctx, `
+		UPDATE messages m
+		SET is_read = TRUE, read_at = NOW()
+		FROM conversations c
+		WHERE c.id = $1 AND m.conversation_id = c.id AND m.is_read = FALSE
+		AND (m.sender_id = c.buyer_id) <> $2 AND m.moderation_status = 'visible'
+	`, id, asBuyer)
+	return err
+}
+
+func (r *postgresRepository) SetConversationArchived(ctx context.Context, id uuid.UUID, asBuyer, archived bool) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE conversations
+		SET
+			is_archived_by_buyer = CASE WHEN $2 THEN $3 ELSE is_archived_by_buyer END,
+			is_archived_by_seller = CASE WHEN $2 THEN is_archived_by_seller ELSE $3 END
+		WHERE id = $1
+	`, id, asBuyer, archived)
+	return err
+}
+
+func (r *postgresRepository) SetConversationMuted(ctx context.Context, id uuid.UUID, asBuyer, muted bool) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE conversations
+		SET
+			is_muted_by_buyer = CASE WHEN $2 THEN $3 ELSE is_muted_by_buyer END,
+			is_muted_by_seller = CASE WHEN $2 THEN is_muted_by_seller ELSE $3 END
+		WHERE id = $1
+	`, id, asBuyer, muted)
+	return err
+}
+
+// DeleteConversation hides the conversation and its history from one side.
+// The other side keeps it; nothing is removed.
+func (r *postgresRepository) DeleteConversation(ctx context.Context, id uuid.UUID, asBuyer bool) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE conversations
+		SET
+			deleted_by_buyer_at = CASE WHEN $2 THEN NOW() ELSE deleted_by_buyer_at END,
+			deleted_by_seller_at = CASE WHEN $2 THEN deleted_by_seller_at ELSE NOW() END,
+			buyer_unread_count = CASE WHEN $2 THEN 0 ELSE buyer_unread_count END,
+			seller_unread_count = CASE WHEN $2 THEN seller_unread_count ELSE 0 END
+		WHERE id = $1
+	`, id, asBuyer)
+	return err
+}
+
+// RefreshListingSnapshots re-caches the title, cover image and price of
+// listings whose price has changed since their conversations started.
+func (r *postgresRepository) RefreshListingSnapshots(ctx context.Context) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE conversations c
+		SET listing_price = cl.price,
+			listing_title = cl.title,
+			listing_image = (
+				SELECT li.image_url FROM listing_images li WHERE li.listing_id = cl.id
+				ORDER BY li.is_cover DESC, li.sort_order ASC LIMIT 1
+			)
+		FROM car_listings cl
+		WHERE c.listing_id = cl.id AND c.listing_price IS DISTINCT FROM cl.price
+	`)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ApplyOrganizationEvents brings conversations in line with dealer team
+// changes made in auth-service
+func (r *postgresRepository) ApplyOrganizationEvents(ctx context.Context, limit int) (int, error) {
+	return org.ApplyEvents(ctx, r.db, org.ConsumerConversations, limit, func(ctx context.Context, tx pgx.Tx, event org.Event) error {
+		var err error
+		switch event.Type {
+		case org.EventCreated:
+			// The owner's leads join the team, still assigned to them
+			_, err = tx.Exec(ctx, `
+				UPDATE conversations SET organization_id = $1, assigned_to = seller_id
+				WHERE seller_id = $2 AND organization_id IS NULL
+			`, event.OrganizationID, event.UserID)
+		case org.EventMemberRemoved:
+			_, err = tx.Exec(ctx, `
+				UPDATE conversations SET assigned_to = $3 WHERE organization_id = $1 AND assigned_to = $2
+			`, event.OrganizationID, event.UserID, event.OwnerID)
+		}
+		return err
+	})
+}
+
+func (r *postgresRepository) AssignConversation(ctx context.Context, id, assignedTo uuid.UUID) error {
+	_, err := r.db.Exec(ctx, "UPDATE conversations SET assigned_to = $1 WHERE id = $2", assignedTo, id)
 	return err
 }
 
 // Messages
 
+// CreateMessage saves a message and links the attachments the sender uploaded
+// to the conversation and hasn't sent yet.
 func (r *postgresRepository) CreateMessage(ctx context.Context, msg *domain.Message) (*domain.Message, error) {
-	err := r.db.QueryRow(ctx, `
-		INSERT INTO messages (conversation_id, sender_id, content, is_read, created_at)
-		VALUES ($1, $2, $3, $4, NOW())
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if msg.ModerationStatus == "" {
+		msg.ModerationStatus = domain.ModerationVisible
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO messages (
+			conversation_id, sender_id, message_type, content, is_read,
+			moderation_status, moderation_reason, masked, created_at, delivered_at
+		) VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, NOW(), CASE WHEN $6 = 'held' THEN NULL ELSE NOW() END)
 		RETURNING id, created_at
-	`, msg.ConversationID, msg.SenderID, msg.Content, msg.IsRead).Scan(&msg.ID, &msg.CreatedAt)
-	return msg, err
+	`, msg.ConversationID, msg.SenderID, msg.Type, msg.Content, msg.IsRead,
+		msg.ModerationStatus, msg.ModerationReason, msg.Masked).Scan(&msg.ID, &msg.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Held messages are filed with reports-service for an admin to review
+	if msg.ModerationStatus == domain.ModerationHeld {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO listing_reports (target_type, target_id, reason, details)
+			VALUES ('message', $1, 'spam', $2)
+		`, msg.ID, msg.ModerationReason)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(msg.AttachmentIDs) > 0 {
+		tag, err := tx.Exec(ctx, `
+			UPDATE message_attachments SET message_id = $1
+			WHERE id = ANY($2) AND conversation_id = $3 AND uploader_id = $4 AND message_id IS NULL
+		`, msg.ID, msg.AttachmentIDs, msg.ConversationID, msg.SenderID)
+		if err != nil {
+			return nil, err
+		}
+		if tag.RowsAffected() != int64(len(msg.AttachmentIDs)) {
+			return nil, fmt.Errorf("attachment not found")
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// GetMessageAttachments returns the attachments of the messages, in upload order
+func (r *postgresRepository) GetMessageAttachments(ctx context.Context, messageIDs []uuid.UUID) ([]domain.Attachment, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, message_id, kind, content_type, file_name, size_bytes, thumbnail_key IS NOT NULL
+		FROM message_attachments
+		WHERE message_id = ANY($1)
+		ORDER BY created_at
+	`, messageIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []domain.Attachment
+	for rows.Next() {
+		var a domain.Attachment
+		if err := rows.Scan(&a.ID, &a.MessageID, &a.Kind, &a.ContentType, &a.FileName, &a.SizeBytes, &a.HasThumbnail); err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
 }
 
-func (r *postgresRepository) GetMessagesByConversation(ctx context.Context, conversationID uuid.UUID, params domain.Pagination) ([]domain.Message, int64, error) {
+// GetMessagesByConversation pages through the messages, newest first. since
+// skips the history a participant deleted. Messages that screening kept back
+// are only returned to their sender.
+func (r *postgresRepository) GetMessagesByConversation(ctx context.Context, conversationID, viewerID uuid.UUID, since *time.Time, params pagination.Params) ([]domain.Message, int64, error) {
 	// Count
 	var total int64
-	err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM messages WHERE conversation_id = $1", conversationID).Scan(&total)
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM messages
+		WHERE conversation_id = $1 AND ($2::timestamp IS NULL OR created_at > $2)
+		AND (moderation_status = 'visible' OR sender_id = $3)
+	`, conversationID, since, viewerID).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	offset := (params.Page - 1) * params.Limit
+	offset := params.Offset()
 
 	rows, err := r.db.Query(ctx, `
-		SELECT m.id, m.conversation_id, m.sender_id, m.content, m.is_read, m.read_at, m.created_at, u.name
+		SELECT m.id, m.conversation_id, m.sender_id, m.message_type, m.offer_id, m.appointment_id, m.content, m.is_read, m.read_at,
+		m.moderation_status, m.masked, m.created_at, u.name
 		FROM messages m
 		JOIN users u ON m.sender_id = u.id
-		WHERE m.conversation_id = $1
+		WHERE m.conversation_id = $1 AND ($2::timestamp IS NULL OR m.created_at > $2)
+		AND (m.moderation_status = 'visible' OR m.sender_id = $5)
 		ORDER BY m.created_at DESC
-		LIMIT $2 OFFSET $3
-	`, conversationID, params.Limit, offset)
+		LIMIT $3 OFFSET $4
+	`, conversationID, since, params.Limit, offset, viewerID)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -309,7 +515,8 @@ func (r *postgresRepository) GetMessagesByConversation(ctx context.Context, conv
 	for rows.Next() {
 		var m domain.Message
 		err := rows.Scan(
-			&m.ID, &m.ConversationID, &m.SenderID, &m.Content, &m.IsRead, &m.ReadAt, &m.CreatedAt, &m.SenderName,
+			&m.ID, &m.ConversationID, &m.SenderID, &m.Type, &m.OfferID, &m.AppointmentID, &m.Content, &m.IsRead, &m.ReadAt,
+			&m.ModerationStatus, &m.Masked, &m.CreatedAt, &m.SenderName,
 		)
 		if err != nil {
 			return nil, 0, err
@@ -324,26 +531,27 @@ func (r *postgresRepository) GetMessagesByConversation(ctx context.Context, conv
 	return messages, total, nil
 }
 
-func (r *postgresRepository) GetTotalUnreadCount(ctx context.Context, userID uuid.UUID) (int, []domain.ConversationUnread, error) {
-	// Total Unread
+func (r *postgresRepository) GetTotalUnreadCount(ctx context.Context, viewer domain.Viewer) (int, []domain.ConversationUnread, error) {
+	// Total Unread; muted conversations don't count towards the badge
 	var total int
 	err := r.db.QueryRow(ctx, `
 		SELECT 
-			COALESCE(SUM(CASE WHEN buyer_id = $1 THEN buyer_unread_count ELSE seller_unread_count END), 0)
-		FROM conversations
-		WHERE buyer_id = $1 OR seller_id = $1
-	`, userID).Scan(&total)
+			COALESCE(SUM(CASE WHEN c.buyer_id = $1 THEN c.buyer_unread_count ELSE c.seller_unread_count END), 0)
+		FROM conversations c
+		WHERE (c.buyer_id = $1 OR `+sellerSide+`)
+		AND NOT COALESCE(CASE WHEN c.buyer_id = $1 THEN c.is_muted_by_buyer ELSE c.is_muted_by_seller END, FALSE)
+	`, viewer.UserID, viewer.OrganizationID, viewer.CanManage).Scan(&total)
 	if err != nil {
 		return 0, nil, err
 	}
 
 	// By Conversation
 	rows, err := r.db.Query(ctx, `
-		SELECT id, CASE WHEN buyer_id = $1 THEN buyer_unread_count ELSE seller_unread_count END
-		FROM conversations
-		WHERE (buyer_id = $1 OR seller_id = $1)
-		AND (CASE WHEN buyer_id = $1 THEN buyer_unread_count ELSE seller_unread_count END) > 0
-	`, userID)
+		SELECT c.id, CASE WHEN c.buyer_id = $1 THEN c.buyer_unread_count ELSE c.seller_unread_count END
+		FROM conversations c
+		WHERE (c.buyer_id = $1 OR `+sellerSide+`)
+		AND (CASE WHEN c.buyer_id = $1 THEN c.buyer_unread_count ELSE c.seller_unread_count END) > 0
+	`, viewer.UserID, viewer.OrganizationID, viewer.CanManage)
 	if err != nil {
 		return 0, nil, err
 	}
@@ -360,3 +568,182 @@ func (r *postgresRepository) GetTotalUnreadCount(ctx context.Context, userID uui
 
 	return total, details, nil
 }
+
+// Offers
+
+const offerColumns = `id, conversation_id, listing_id, buyer_id, seller_id, organization_id,
+	offered_by, from_buyer, amount, currency, expires_at, counter_to,
+	status, responded_by, responded_at, listing_status, created_at`
+
+func scanOffer(row pgx.Row) (*domain.Offer, error) {
+	var o domain.Offer
+	err := row.Scan(
+		&o.ID, &o.ConversationID, &o.ListingID, &o.BuyerID, &o.SellerID, &o.OrganizationID,
+		&o.OfferedBy, &o.FromBuyer, &o.Amount, &o.Currency, &o.ExpiresAt, &o.CounterTo,
+		&o.Status, &o.RespondedBy, &o.RespondedAt, &o.ListingStatus, &o.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// CreateOffer saves an offer and the message that carries it. A counter-offer
+// also marks the offer it answers as countered. Only one offer per
+// conversation can be pending; overdue ones are expired first.
+func (r *postgresRepository) CreateOffer(ctx context.Context, offer *domain.Offer, msg *domain.Message) (*domain.Message, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialises offers made in the same conversation
+	if _, err := tx.Exec(ctx, "SELECT id FROM conversations WHERE id = $1 FOR UPDATE", offer.ConversationID); err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(ctx, `
+		UPDATE offers SET status = 'expired'
+		WHERE conversation_id = $1 AND status = 'pending' AND expires_at <= NOW()
+	`, offer.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if offer.CounterTo != nil {
+		tag, err := tx.Exec(ctx, `
+			UPDATE offers SET status = 'countered', responded_by = $2, responded_at = NOW()
+			WHERE id = $1 AND status = 'pending'
+		`, *offer.CounterTo, offer.OfferedBy)
+		if err != nil {
+			return nil, err
+		}
+		if tag.RowsAffected() == 0 {
+			return nil, fmt.Errorf("offer is no longer pending")
+		}
+	} else {
+		var pending bool
+		err := tx.QueryRow(ctx,
+			"SELECT EXISTS (SELECT 1 FROM offers WHERE conversation_id = $1 AND status = 'pending')",
+			offer.ConversationID).Scan(&pending)
+		if err != nil {
+			return nil, err
+		}
+		if pending {
+			return nil, fmt.Errorf("an offer is already pending")
+		}
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO offers (
+			conversation_id, listing_id, buyer_id, seller_id, organization_id,
+			offered_by, from_buyer, amount, currency, expires_at, counter_to
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, status, created_at
+	`, offer.ConversationID, offer.ListingID, offer.BuyerID, offer.SellerID, offer.OrganizationID,
+		offer.OfferedBy, offer.FromBuyer, offer.Amount, offer.Currency, offer.ExpiresAt, offer.CounterTo,
+	).Scan(&offer.ID, &offer.Status, &offer.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	msg.OfferID = &offer.ID
+	err = tx.QueryRow(ctx, `
+		INSERT INTO messages (conversation_id, sender_id, message_type, offer_id, content, is_read, created_at)
+		VALUES ($1, $2, $3, $4, $5, FALSE, NOW())
+		RETURNING id, moderation_status, created_at
+	`, msg.ConversationID, msg.SenderID, msg.Type, msg.OfferID, msg.Content).Scan(&msg.ID, &msg.ModerationStatus, &msg.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	msg.Offer = offer
+	return msg, nil
+}
+
+func (r *postgresRepository) GetOffer(ctx context.Context, id uuid.UUID) (*domain.Offer, error) {
+	offer, err := scanOffer(r.db.QueryRow(ctx, "SELECT "+offerColumns+" FROM offers WHERE id = $1", id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return offer, err
+}
+
+// GetConversationOffers returns the conversation's offer history, oldest first
+func (r *postgresRepository) GetConversationOffers(ctx context.Context, conversationID uuid.UUID) ([]domain.Offer, error) {
+	rows, err := r.db.Query(ctx,
+		"SELECT "+offerColumns+" FROM offers WHERE conversation_id = $1 ORDER BY created_at", conversationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	offers := []domain.Offer{}
+	for rows.Next() {
+		offer, err := scanOffer(rows)
+		if err != nil {
+			return nil, err
+		}
+		offers = append(offers, *offer)
+	}
+	return offers, rows.Err()
+}
+
+// RespondToOffer accepts or rejects a pending offer that hasn't expired. It
+// returns nil if the offer is no longer open.
+func (r *postgresRepository) RespondToOffer(ctx context.Context, id, responderID uuid.UUID, status string, listingStatus *string) (*domain.Offer, error) {
+	offer, err := scanOffer(r.db.QueryRow(ctx, `
+		UPDATE offers
+		SET status = $2, responded_by = $3, responded_at = NOW(), listing_status = $4
+		WHERE id = $1 AND status = 'pending' AND expires_at > NOW()
+		RETURNING `+offerColumns, id, status, responderID, listingStatus))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return offer, err
+}
+
+// ExpireOffers closes pending offers whose expiry has passed
+func (r *postgresRepository) ExpireOffers(ctx context.Context) (int64, error) {
+	tag, err := r.db.Exec(ctx, "UPDATE offers SET status = 'expired' WHERE status = 'pending' AND expires_at <= NOW()")
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// Dealer organisations
+
+func (r *postgresRepository) GetMembership(ctx context.Context, userID uuid.UUID) (*org.Membership, error) {
+	return org.Lookup(ctx, r.db, userID)
+}
+
+func (r *postgresRepository) IsOrganizationMember(ctx context.Context, orgID, userID uuid.UUID) (bool, error) {
+	return org.IsMember(ctx, r.db, orgID, userID)
+}
+
+// GetOrganizationManagers returns the owner and managers, who see all of the
+// organisation's conversations.
+func (r *postgresRepository) GetOrganizationManagers(ctx context.Context, orgID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT user_id FROM organization_members
+		WHERE organization_id = $1 AND role = ANY($2)
+	`, orgID, []string{org.RoleOwner, org.RoleManager})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetSenderActivity counts the user's recent messages, leaving out
+// auto-replies, and works out whether their account is new or unverified,
+// from the users table owned by auth-service
+func (r *postgresRepository) GetSenderActivity(ctx context.Context, userID uuid.UUID) (*domain.SenderActivity, error) {
+	var a domain.SenderActivity
+	err := r.db.QueryRow(ctx, `
+		SELECT
+			u.created_at > NOW() - make_interval(secs => $2)
+				OR NOT (COALESCE(u.email_verified, FALSE) OR COALESCE(u.phone_verified, FALSE)),
+			(SELECT COUNT(*) FROM messages WHERE sender_id = u.id AND message_type <> 'auto_reply' AND created_at > NOW() - INTERVAL '1 minute'),
+			(SELECT COUNT(*) FROM messages WHERE sender_id = u.id AND message_type <> 'auto_reply' AND created_at > NOW() - INTERVAL '1 hour')
+		FROM users u
+		WHERE u.id = $1
+	`, userID, domain.RestrictedAccountAge.Seconds()).Scan(&a.Restricted, &a.LastMinute, &a.LastHour)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *postgresRepository) GetScamPhrases(ctx context.Context) ([]domain.ScamPhrase, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, phrase, created_by, created_at FROM scam_phrases ORDER BY LOWER(phrase)
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	phrases := []domain.ScamPhrase{}
+	for rows.Next() {
+		var p domain.ScamPhrase
+		if err := rows.Scan(&p.ID, &p.Phrase, &p.CreatedBy, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		phrases = append(phrases, p)
+	}
+	return phrases, rows.Err()
+}
+
+func (r *postgresRepository) CreateScamPhrase(ctx context.Context, phrase string, createdBy uuid.UUID) (*domain.ScamPhrase, error) {
+	p := domain.ScamPhrase{Phrase: phrase, CreatedBy: &createdBy}
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO scam_phrases (phrase, created_by) VALUES ($1, $2)
+		ON CONFLICT ((LOWER(phrase))) DO NOTHING
+		RETURNING id, created_at
+	`, phrase, createdBy).Scan(&p.ID, &p.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("scam phrase already exists")
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *postgresRepository) DeleteScamPhrase(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, "DELETE FROM scam_phrases WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("scam phrase not found")
+	}
+	return nil
+}
+
+// ClaimReleasedMessages marks a batch of messages approved in reports-service
+// as visible and returns them for delivery
+func (r *postgresRepository) ClaimReleasedMessages(ctx context.Context) ([]domain.Message, error) {
+	rows, err := r.db.Query(ctx, `
+		WITH released AS (
+			UPDATE messages SET moderation_status = 'visible', delivered_at = NOW()
+			WHERE id IN (
+				SELECT id FROM messages WHERE moderation_status = 'released'
+				ORDER BY created_at
+				LIMIT 100
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, conversation_id, sender_id, message_type, content, is_read, read_at,
+				moderation_status, masked, created_at
+		)
+		SELECT m.id, m.conversation_id, m.sender_id, m.message_type, m.content, m.is_read, m.read_at,
+			m.moderation_status, m.masked, m.created_at, u.name
+		FROM released m
+		JOIN users u ON m.sender_id = u.id
+		ORDER BY m.created_at
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []domain.Message
+	for rows.Next() {
+		var m domain.Message
+		err := rows.Scan(
+			&m.ID, &m.ConversationID, &m.SenderID, &m.Type, &m.Content, &m.IsRead, &m.ReadAt,
+			&m.ModerationStatus, &m.Masked, &m.CreatedAt, &m.SenderName,
+		)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
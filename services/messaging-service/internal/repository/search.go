@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+// messageVisibleToViewer hides messages from before the viewer's side deleted
+// the conversation, and messages screening held, except from their sender
+const messageVisibleToViewer = `m.created_at > COALESCE(CASE WHEN c.buyer_id = $1 THEN c.deleted_by_buyer_at ELSE c.deleted_by_seller_at END, '-infinity')
+		AND (m.moderation_status = 'visible' OR m.sender_id = $1)`
+
+// Highlighting for matched words; the service escapes the rest
+const (
+	snippetOptions = `StartSel=<mark>, StopSel=</mark>, MinWords=10, MaxWords=30, MaxFragments=2, FragmentDelimiter=" … "`
+	titleOptions   = `StartSel=<mark>, StopSel=</mark>, HighlightAll=true`
+)
+
+// SearchMessages finds messages in the viewer's conversations whose content
+// matches the query, newest first. A conversation whose listing matches is
+// found through its latest message, so it shows up once rather than once per
+// message. Fetches one row more than the limit.
+func (r *postgresRepository) SearchMessages(ctx context.Context, viewer domain.Viewer, query string, page pagination.CursorParams) ([]domain.SearchHit, error) {
+	sql := `
+		WITH q AS (
+			SELECT websearch_to_tsquery('english', $4) AS query
+		), mine AS (
+			SELECT c.* FROM conversations c
+			WHERE (c.buyer_id = $1 OR ` + sellerSide + `)
+			AND ` + visibleToViewer + `
+		), hits AS (
+			SELECT m.id, m.conversation_id, m.sender_id, m.content, m.created_at
+			FROM mine c
+			JOIN messages m ON m.conversation_id = c.id
+			CROSS JOIN q
+			WHERE m.search_vector @@ q.query
+			AND ` + messageVisibleToViewer + `
+			UNION
+			SELECT m.id, m.conversation_id, m.sender_id, m.content, m.created_at
+			FROM mine c
+			CROSS JOIN q
+			CROSS JOIN LATERAL (
+				SELECT m.id, m.conversation_id, m.sender_id, m.content, m.created_at
+				FROM messages m
+				WHERE m.conversation_id = c.id
+				AND ` + messageVisibleToViewer + `
+				ORDER BY m.created_at DESC
+				LIMIT 1
+			) m
+			WHERE c.search_vector @@ q.query
+		)
+		SELECT h.id, h.sender_id, u.name, ts_headline('english', h.content, q.query, '` + snippetOptions + `'), h.created_at,
+			c.id, c.listing_id, ts_headline('english', COALESCE(c.listing_title, ''), q.query, '` + titleOptions + `'), c.listing_image,
+			p.id, p.name
+		FROM hits h
+		CROSS JOIN q
+		JOIN conversations c ON c.id = h.conversation_id
+		JOIN users u ON u.id = h.sender_id
+		JOIN users p ON p.id = CASE WHEN c.buyer_id = $1 THEN c.seller_id ELSE c.buyer_id END
+		WHERE TRUE
+	`
+	args := []interface{}{viewer.UserID, viewer.OrganizationID, viewer.CanManage, query}
+	argIdx := 5
+
+	if cursor := page.Cursor; cursor != nil {
+		id, err := uuid.Parse(cursor.ID)
+		if err != nil {
+			return nil, pagination.ErrInvalidCursor
+		}
+		sql += fmt.Sprintf(" AND (h.created_at, h.id) < ($%d, $%d)", argIdx, argIdx+1)
+		args = append(args, cursor.CreatedAt, id)
+		argIdx += 2
+	}
+
+	// One extra row tells the caller whether there is another page
+	sql += fmt.Sprintf(" ORDER BY h.created_at DESC, h.id DESC LIMIT $%d", argIdx)
+	args = append(args, page.Limit+1)
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hits := []domain.SearchHit{}
+	for rows.Next() {
+		var h domain.SearchHit
+		err := rows.Scan(
+			&h.MessageID, &h.SenderID, &h.SenderName, &h.Snippet, &h.CreatedAt,
+			&h.Conversation.ID, &h.Conversation.ListingID, &h.Conversation.ListingTitle, &h.Conversation.ListingImage,
+			&h.Conversation.Participant.ID, &h.Conversation.Participant.Name,
+		)
+		if err != nil {
+			return nil, err
+		}
+		hits = append(hits, h)
+	}
+	return hits, rows.Err()
+}
@@ -0,0 +1,106 @@
+// Package screening checks messages before they are sent: send rate limits,
+// contact details to hide from new or unverified accounts, and scam phrases.
+package screening
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Limits caps how many messages a user may send
+type Limits struct {
+	PerMinute int
+	PerHour   int
+}
+
+// Accounts that are new or unverified get tighter limits; that is where
+// spammers come from
+var (
+	StandardLimits   = Limits{PerMinute: 20, PerHour: 300}
+	RestrictedLimits = Limits{PerMinute: 5, PerHour: 40}
+)
+
+// LimitsFor returns the limits of a sender
+func LimitsFor(restricted bool) Limits {
+	if restricted {
+		return RestrictedLimits
+	}
+	return StandardLimits
+}
+
+// Exceeded reports whether sending another message would go over the limits,
+// given the messages sent in the last minute and hour
+func (l Limits) Exceeded(lastMinute, lastHour int) bool {
+	return lastMinute >= l.PerMinute || lastHour >= l.PerHour
+}
+
+// Placeholders that replace hidden contact details
+const (
+	PhoneMask = "[phone number hidden]"
+	EmailMask = "[email hidden]"
+	LinkMask  = "[link hidden]"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`)
+	linkPattern  = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+|\b[a-z0-9\-]+(?:\.[a-z0-9\-]+)*\.(?:com|lk|net|org|info|biz|io|me|ly|link|app|xyz|online|site|co)(?:/\S*)?\b`)
+	// Local (07x…) and international (+94…, 0094…, 94…) numbers, with the
+	// spaces, dashes, dots and brackets people put in them
+	phonePattern = regexp.MustCompile(`(?:\+|\b00|\b0|\b94)\d[\d\s\-().]{6,}\d`)
+)
+
+// Phone numbers have between minPhoneDigits and maxPhoneDigits digits; shorter
+// runs are prices, years or mileage
+const (
+	minPhoneDigits = 9
+	maxPhoneDigits = 15
+)
+
+// MaskContacts replaces phone numbers, email addresses and links in content
+// with placeholders, and reports whether it replaced anything
+func MaskContacts(content string) (string, bool) {
+	masked := false
+	replace := func(pattern *regexp.Regexp, s string, mask func(string) string) string {
+		return pattern.ReplaceAllStringFunc(s, func(match string) string {
+			out := mask(match)
+			if out != match {
+				masked = true
+			}
+			return out
+		})
+	}
+
+	// Emails first, so their domains aren't taken for links
+	content = replace(emailPattern, content, func(string) string { return EmailMask })
+	content = replace(linkPattern, content, func(string) string { return LinkMask })
+	content = replace(phonePattern, content, func(match string) string {
+		digits := 0
+		for _, r := range match {
+			if r >= '0' && r <= '9' {
+				digits++
+			}
+		}
+		if digits < minPhoneDigits || digits > maxPhoneDigits {
+			return match
+		}
+		return PhoneMask
+	})
+	return content, masked
+}
+
+// MatchPhrase returns the first phrase found in content. Matching ignores case
+// and runs of whitespace.
+func MatchPhrase(content string, phrases []string) (string, bool) {
+	normalized := normalize(content)
+	for _, phrase := range phrases {
+		p := normalize(phrase)
+		if p != "" && strings.Contains(normalized, p) {
+			return phrase, true
+		}
+	}
+	return "", false
+}
+
+func normalize(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
@@ -0,0 +1,96 @@
+package screening
+
+import "testing"
+
+func TestLimitsExceeded(t *testing.T) {
+	tests := []struct {
+		name       string
+		restricted bool
+		lastMinute int
+		lastHour   int
+		want       bool
+	}{
+		{"standard, quiet", false, 0, 0, false},
+		{"standard, just under per minute", false, 19, 100, false},
+		{"standard, at per minute", false, 20, 100, true},
+		{"standard, at per hour", false, 0, 300, true},
+		{"restricted, just under per minute", true, 4, 10, false},
+		{"restricted, at per minute", true, 5, 10, true},
+		{"restricted, at per hour", true, 0, 40, true},
+		{"restricted is tighter than standard", true, 10, 50, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LimitsFor(tt.restricted).Exceeded(tt.lastMinute, tt.lastHour); got != tt.want {
+				t.Errorf("Exceeded(%d, %d) = %v, want %v", tt.lastMinute, tt.lastHour, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskContacts(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		want       string
+		wantMasked bool
+	}{
+		{"plain text", "Is the car still available?", "Is the car still available?", false},
+		{"local mobile", "Call me on 0771234567", "Call me on " + PhoneMask, true},
+		{"local mobile with spaces", "Call 077 123 4567 today", "Call " + PhoneMask + " today", true},
+		{"local mobile with dashes", "077-123-4567", PhoneMask, true},
+		{"international", "WhatsApp +94 77 123 4567", "WhatsApp " + PhoneMask, true},
+		{"international with 00", "0094771234567", PhoneMask, true},
+		{"international without plus", "94771234567 is my number", PhoneMask + " is my number", true},
+		{"price is not a phone", "Would you take 4500000 for it?", "Would you take 4500000 for it?", false},
+		{"price with separators is not a phone", "Rs. 4,500,000 final", "Rs. 4,500,000 final", false},
+		{"year and mileage are not phones", "2018 model with 45000 km", "2018 model with 45000 km", false},
+		{"email", "Mail kasun.perera@gmail.com please", "Mail " + EmailMask + " please", true},
+		{"email domain is not a link", "kasun@example.lk", EmailMask, true},
+		{"http link", "See https://example.com/car?id=1 for photos", "See " + LinkMask + " for photos", true},
+		{"www link", "Photos at www.cars.lk", "Photos at " + LinkMask, true},
+		{"bare domain", "check mycar.lk/photos", "check " + LinkMask, true},
+		{"everything", "0771234567 or a@b.com or www.x.lk", PhoneMask + " or " + EmailMask + " or " + LinkMask, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, masked := MaskContacts(tt.content)
+			if got != tt.want || masked != tt.wantMasked {
+				t.Errorf("MaskContacts(%q) = %q, %v; want %q, %v", tt.content, got, masked, tt.want, tt.wantMasked)
+			}
+		})
+	}
+}
+
+func TestMatchPhrase(t *testing.T) {
+	phrases := []string{"western union", "send the deposit", "  gift   card "}
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantOK  bool
+	}{
+		{"no match", "Can I see the car on Saturday?", "", false},
+		{"exact", "pay by western union", "western union", true},
+		{"ignores case", "Pay by WESTERN Union", "western union", true},
+		{"ignores whitespace", "please send   the\ndeposit first", "send the deposit", true},
+		{"phrase whitespace normalised", "I only take gift card payments", "  gift   card ", true},
+		{"first phrase wins", "gift card or western union", "western union", true},
+		{"partial phrase", "I can send the car over", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := MatchPhrase(tt.content, phrases)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("MatchPhrase(%q) = %q, %v; want %q, %v", tt.content, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestMatchPhraseSkipsEmpty(t *testing.T) {
+	if phrase, ok := MatchPhrase("anything", []string{"", "   "}); ok {
+		t.Errorf("MatchPhrase matched empty phrase %q", phrase)
+	}
+}
@@ -0,0 +1,553 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/calendar"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/domain"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/notifications"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/realtime"
+	"github.com/google/uuid"
+)
+
+// RequestAppointment lets the buyer propose times for a test drive or viewing.
+// Only one appointment per conversation can be open at a time.
+func (s *service) RequestAppointment(ctx context.Context, conversationID, userID uuid.UUID, req domain.RequestAppointmentRequest) (*domain.Message, error) {
+	conv, viewer, err := s.participant(ctx, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !viewer.IsBuyer(conv) {
+		return nil, fmt.Errorf("only the buyer can request an appointment")
+	}
+	if err := s.canMessage(ctx, conv); err != nil {
+		return nil, err
+	}
+
+	slots, err := s.checkSlots(ctx, conv, req.Slots, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.screenFields(ctx, conv, userID, &req.Location, &req.Notes); err != nil {
+		return nil, err
+	}
+
+	appointment := &domain.Appointment{
+		ConversationID: conv.ID,
+		ListingID:      conv.ListingID,
+		BuyerID:        conv.BuyerID,
+		SellerID:       conv.SellerID,
+		OrganizationID: conv.OrganizationID,
+		Kind:           req.Kind,
+		ProposedSlots:  slots,
+		ProposedBy:     userID,
+		FromBuyer:      true,
+		Location:       req.Location,
+		Notes:          req.Notes,
+	}
+
+	content := fmt.Sprintf("Requested a %s: %s", kindLabel(req.Kind), formatSlots(slots))
+	msg, err := s.repo.CreateAppointment(ctx, appointment, &domain.Message{
+		ConversationID: conv.ID,
+		SenderID:       userID,
+		Type:           domain.MessageTypeAppointment,
+		Content:        content,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.delivered(ctx, conv, viewer, msg, content); err != nil {
+		return nil, err
+	}
+	s.queueAppointment(ctx, conv, appointment, "appointment_requested", false, false)
+	return msg, nil
+}
+
+// RescheduleAppointment replaces the proposed or confirmed time with new
+// slots, which the other side then confirms
+func (s *service) RescheduleAppointment(ctx context.Context, appointmentID, userID uuid.UUID, req domain.RescheduleAppointmentRequest) (*domain.Message, error) {
+	appointment, conv, viewer, err := s.openAppointment(ctx, appointmentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.canMessage(ctx, conv); err != nil {
+		return nil, err
+	}
+
+	fromBuyer := viewer.IsBuyer(conv)
+	slots, err := s.checkSlots(ctx, conv, req.Slots, fromBuyer)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.screenFields(ctx, conv, userID, &req.Message); err != nil {
+		return nil, err
+	}
+
+	appointment.Status = domain.AppointmentRequested
+	appointment.ProposedSlots = slots
+	appointment.ProposedBy = userID
+	appointment.FromBuyer = fromBuyer
+	appointment.StartsAt = nil
+	appointment.EndsAt = nil
+
+	content := fmt.Sprintf("Proposed new times for the %s: %s", kindLabel(appointment.Kind), formatSlots(slots))
+	if req.Message != "" {
+		content = req.Message + "\n" + content
+	}
+	msg, err := s.saveAppointment(ctx, conv, viewer, appointment, content)
+	if err != nil {
+		return nil, err
+	}
+	s.queueAppointment(ctx, conv, appointment, "appointment_rescheduled", false, false)
+	return msg, nil
+}
+
+// ConfirmAppointment accepts one of the slots the other side proposed. Both
+// sides are sent a calendar invite.
+func (s *service) ConfirmAppointment(ctx context.Context, appointmentID, userID uuid.UUID, req domain.ConfirmAppointmentRequest) (*domain.Message, error) {
+	appointment, conv, viewer, err := s.openAppointment(ctx, appointmentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if appointment.Status != domain.AppointmentRequested {
+		return nil, fmt.Errorf("appointment is already confirmed")
+	}
+	if viewer.IsBuyer(conv) == appointment.FromBuyer {
+		return nil, fmt.Errorf("you cannot confirm your own proposal")
+	}
+	if err := s.canMessage(ctx, conv); err != nil {
+		return nil, err
+	}
+
+	var slot *domain.TimeSlot
+	for i := range appointment.ProposedSlots {
+		if appointment.ProposedSlots[i].Start.Equal(req.Start) {
+			slot = &appointment.ProposedSlots[i]
+			break
+		}
+	}
+	if slot == nil {
+		return nil, fmt.Errorf("start time is not one of the proposed slots")
+	}
+	if !slot.Start.After(time.Now()) {
+		return nil, fmt.Errorf("that slot has already passed")
+	}
+	if err := s.screenFields(ctx, conv, userID, &req.Location); err != nil {
+		return nil, err
+	}
+
+	appointment.Status = domain.AppointmentConfirmed
+	appointment.StartsAt = &slot.Start
+	appointment.EndsAt = &slot.End
+	appointment.InvitedStart = &slot.Start
+	appointment.InvitedEnd = &slot.End
+	if req.Location != "" {
+		appointment.Location = req.Location
+	}
+
+	content := fmt.Sprintf("Confirmed the %s for %s", kindLabel(appointment.Kind), formatTime(slot.Start))
+	msg, err := s.saveAppointment(ctx, conv, viewer, appointment, content)
+	if err != nil {
+		return nil, err
+	}
+	s.queueAppointment(ctx, conv, appointment, "appointment_confirmed", true, true)
+	return msg, nil
+}
+
+// CancelAppointment cancels an open appointment. If it was ever confirmed,
+// both sides are sent a calendar update that removes the invite, even if it
+// was being rescheduled.
+func (s *service) CancelAppointment(ctx context.Context, appointmentID, userID uuid.UUID, req domain.CancelAppointmentRequest) (*domain.Message, error) {
+	appointment, conv, viewer, err := s.openAppointment(ctx, appointmentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A reason is optional, so cancelling without one isn't rate limited
+	if strings.TrimSpace(req.Reason) != "" {
+		if err := s.screenFields(ctx, conv, userID, &req.Reason); err != nil {
+			return nil, err
+		}
+	}
+
+	appointment.Status = domain.AppointmentCancelled
+	appointment.CancelledBy = &userID
+	appointment.CancelReason = req.Reason
+
+	content := fmt.Sprintf("Cancelled the %s", kindLabel(appointment.Kind))
+	if appointment.CancelReason != "" {
+		content += ": " + appointment.CancelReason
+	}
+	msg, err := s.saveAppointment(ctx, conv, viewer, appointment, content)
+	if err != nil {
+		return nil, err
+	}
+	invited := appointment.InvitedStart != nil
+	s.queueAppointment(ctx, conv, appointment, "appointment_cancelled", invited, invited)
+	return msg, nil
+}
+
+// CompleteAppointment lets the seller side record whether a confirmed
+// appointment took place once its time has come
+func (s *service) CompleteAppointment(ctx context.Context, appointmentID, userID uuid.UUID, req domain.CompleteAppointmentRequest) (*domain.Appointment, error) {
+	appointment, conv, viewer, err := s.openAppointment(ctx, appointmentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !viewer.IsSeller(conv) {
+		return nil, fmt.Errorf("only the seller can complete an appointment")
+	}
+	if appointment.Status != domain.AppointmentConfirmed {
+		return nil, fmt.Errorf("appointment is not confirmed")
+	}
+	if appointment.StartsAt.After(time.Now()) {
+		return nil, fmt.Errorf("appointment hasn't started yet")
+	}
+
+	appointment.Status = domain.AppointmentCompleted
+	if req.NoShow {
+		appointment.Status = domain.AppointmentNoShow
+	}
+	if _, err := s.repo.UpdateAppointment(ctx, appointment, nil); err != nil {
+		return nil, err
+	}
+	s.publishAppointment(ctx, conv, appointment)
+	return appointment, nil
+}
+
+// GetAppointments returns the conversation's appointments, oldest first
+func (s *service) GetAppointments(ctx context.Context, conversationID, userID uuid.UUID) ([]domain.Appointment, error) {
+	if _, _, err := s.participant(ctx, conversationID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetConversationAppointments(ctx, conversationID)
+}
+
+// GetUserAppointments returns the appointments in all of the user's
+// conversations, on either side, soonest first
+func (s *service) GetUserAppointments(ctx context.Context, userID uuid.UUID, status string) ([]domain.Appointment, error) {
+	viewer, err := s.viewer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetUserAppointments(ctx, viewer, status)
+}
+
+// GetAvailability returns the weekly availability the user manages: their
+// dealer organisation's, or their own
+func (s *service) GetAvailability(ctx context.Context, userID uuid.UUID) ([]domain.AvailabilityWindow, error) {
+	viewer, err := s.viewer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ownerID := userID
+	if viewer.OrganizationID != nil {
+		ownerID = *viewer.OrganizationID
+	}
+	return s.repo.GetAvailability(ctx, ownerID)
+}
+
+// SetAvailability replaces the weekly availability the user manages. For a
+// dealer organisation only owners and managers can change it.
+func (s *service) SetAvailability(ctx context.Context, userID uuid.UUID, req domain.SetAvailabilityRequest) ([]domain.AvailabilityWindow, error) {
+	viewer, err := s.viewer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ownerID := userID
+	if viewer.OrganizationID != nil {
+		if !viewer.CanManage {
+			return nil, fmt.Errorf("only organisation owners and managers can change availability")
+		}
+		ownerID = *viewer.OrganizationID
+	}
+
+	if err := checkWindows("availability", req.Windows); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetAvailability(ctx, ownerID, req.Windows); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAvailability(ctx, ownerID)
+}
+
+// GetConversationAvailability returns the seller side's weekly availability,
+// for the buyer to pick slots from. It is empty if the seller hasn't set any.
+func (s *service) GetConversationAvailability(ctx context.Context, conversationID, userID uuid.UUID) ([]domain.AvailabilityWindow, error) {
+	conv, _, err := s.participant(ctx, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetAvailability(ctx, sellerOwner(conv))
+}
+
+// SendAppointmentReminders reminds both sides of confirmed appointments
+// starting within domain.AppointmentReminderLead
+func (s *service) SendAppointmentReminders(ctx context.Context) (int, error) {
+	appointments, err := s.repo.ClaimAppointmentReminders(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for i := range appointments {
+		conv, err := s.repo.GetConversationByID(ctx, appointments[i].ConversationID)
+		if err != nil || conv == nil {
+			log.Printf("Error loading conversation of appointment %s: %v", appointments[i].ID, err)
+			continue
+		}
+		s.queueAppointment(ctx, conv, &appointments[i], "appointment_reminder", true, false)
+	}
+	return len(appointments), nil
+}
+
+// openAppointment loads an appointment that is still to take place, in a
+// conversation the user takes part in
+func (s *service) openAppointment(ctx context.Context, appointmentID, userID uuid.UUID) (*domain.Appointment, *domain.Conversation, domain.Viewer, error) {
+	appointment, err := s.repo.GetAppointment(ctx, appointmentID)
+	if err != nil {
+		return nil, nil, domain.Viewer{}, err
+	}
+	if appointment == nil {
+		return nil, nil, domain.Viewer{}, fmt.Errorf("appointment not found")
+	}
+
+	conv, viewer, err := s.participant(ctx, appointment.ConversationID, userID)
+	if err != nil {
+		return nil, nil, domain.Viewer{}, err
+	}
+	if !appointment.IsOpen() {
+		return nil, nil, domain.Viewer{}, fmt.Errorf("appointment is no longer open")
+	}
+	return appointment, conv, viewer, nil
+}
+
+// saveAppointment saves a change with the message that records it, then
+// delivers the message and pushes the appointment's new state
+func (s *service) saveAppointment(ctx context.Context, conv *domain.Conversation, viewer domain.Viewer, appointment *domain.Appointment, content string) (*domain.Message, error) {
+	msg, err := s.repo.UpdateAppointment(ctx, appointment, &domain.Message{
+		ConversationID: conv.ID,
+		SenderID:       viewer.UserID,
+		Type:           domain.MessageTypeAppointment,
+		Content:        content,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.delivered(ctx, conv, viewer, msg, content); err != nil {
+		return nil, err
+	}
+	s.publishAppointment(ctx, conv, appointment)
+	return msg, nil
+}
+
+// checkSlots validates proposed slots and normalises them to UTC. Slots the
+// buyer proposes must also fall within the seller side's weekly
+// availability, if it has set any.
+func (s *service) checkSlots(ctx context.Context, conv *domain.Conversation, slots []domain.TimeSlot, fromBuyer bool) ([]domain.TimeSlot, error) {
+	var windows []domain.AvailabilityWindow
+	if fromBuyer {
+		var err error
+		windows, err = s.repo.GetAvailability(ctx, sellerOwner(conv))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	checked := make([]domain.TimeSlot, 0, len(slots))
+	for _, slot := range slots {
+		slot = domain.TimeSlot{Start: slot.Start.UTC(), End: slot.End.UTC()}
+		switch {
+		case !slot.End.After(slot.Start):
+			return nil, fmt.Errorf("slots must end after they start")
+		case slot.End.Sub(slot.Start) > domain.MaxAppointmentLength:
+			return nil, fmt.Errorf("slots can be at most 4 hours long")
+		case !slot.Start.After(now):
+			return nil, fmt.Errorf("slots must be in the future")
+		case slot.Start.After(now.Add(domain.MaxAppointmentLeadTime)):
+			return nil, fmt.Errorf("slots can be at most 90 days ahead")
+		}
+
+		if len(windows) > 0 {
+			available := false
+			for _, w := range windows {
+				if w.Contains(slot) {
+					available = true
+					break
+				}
+			}
+			if !available {
+				return nil, fmt.Errorf("slot on %s is outside the seller's availability", formatTime(slot.Start))
+			}
+		}
+		checked = append(checked, slot)
+	}
+	return checked, nil
+}
+
+// queueAppointment queues an email, and text where enabled, about the
+// appointment for the other side of the user who changed it, or both sides.
+// withInvite attaches a calendar file. Delivery is best effort.
+func (s *service) queueAppointment(ctx context.Context, conv *domain.Conversation, appointment *domain.Appointment, template string, bothSides, withInvite bool) {
+	buyers, sellers, err := s.sides(ctx, conv)
+	if err != nil {
+		log.Printf("Error loading recipients of conversation %s: %v", conv.ID, err)
+		return
+	}
+
+	recipients := append(append([]uuid.UUID{}, buyers...), sellers...)
+	if !bothSides {
+		// The side that didn't make the change
+		fromBuyer := appointment.FromBuyer
+		if appointment.CancelledBy != nil {
+			fromBuyer = *appointment.CancelledBy == conv.BuyerID
+		}
+		recipients = sellers
+		if !fromBuyer {
+			recipients = buyers
+		}
+	}
+
+	data := appointmentData(conv, appointment)
+	var attachments []notifications.Attachment
+	if withInvite && appointment.InvitedStart != nil {
+		attachments = []notifications.Attachment{{
+			FileName:    "appointment.ics",
+			ContentType: calendar.ContentType,
+			Content:     appointmentEvent(conv, appointment).ICS(time.Now()),
+		}}
+	}
+
+	queued := make([]notifications.Notification, 0, len(recipients))
+	for _, id := range recipients {
+		queued = append(queued, notifications.Notification{
+			UserID:      id,
+			Type:        template,
+			Data:        data,
+			Attachments: attachments,
+		})
+	}
+	if err := s.outbox.Enqueue(ctx, queued...); err != nil {
+		log.Printf("Error queueing %s notifications for appointment %s: %v", template, appointment.ID, err)
+	}
+}
+
+// publishAppointment pushes the current state of an appointment to both sides
+func (s *service) publishAppointment(ctx context.Context, conv *domain.Conversation, appointment *domain.Appointment) {
+	buyers, sellers, err := s.sides(ctx, conv)
+	if err != nil {
+		log.Printf("Error loading recipients of conversation %s: %v", conv.ID, err)
+		return
+	}
+	s.notify(ctx, append(append([]uuid.UUID{}, buyers...), sellers...), realtime.EventAppointmentUpdated, appointment)
+}
+
+// attachAppointments hydrates the appointment each appointment message refers to
+func (s *service) attachAppointments(ctx context.Context, conversationID uuid.UUID, messages []domain.Message) error {
+	hasAppointments := false
+	for _, m := range messages {
+		if m.AppointmentID != nil {
+			hasAppointments = true
+			break
+		}
+	}
+	if !hasAppointments {
+		return nil
+	}
+
+	appointments, err := s.repo.GetConversationAppointments(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	byID := make(map[uuid.UUID]*domain.Appointment, len(appointments))
+	for i := range appointments {
+		byID[appointments[i].ID] = &appointments[i]
+	}
+	for i := range messages {
+		if messages[i].AppointmentID != nil {
+			messages[i].Appointment = byID[*messages[i].AppointmentID]
+		}
+	}
+	return nil
+}
+
+// sellerOwner is who the seller side of a conversation belongs to: the dealer
+// organisation, or the seller. Weekly availability and blocks are theirs.
+func sellerOwner(conv *domain.Conversation) uuid.UUID {
+	if conv.OrganizationID != nil {
+		return *conv.OrganizationID
+	}
+	return conv.SellerID
+}
+
+func appointmentData(conv *domain.Conversation, appointment *domain.Appointment) map[string]interface{} {
+	proposedBy := "The seller"
+	if appointment.FromBuyer {
+		proposedBy = "The buyer"
+	}
+	location := appointment.Location
+	if location == "" {
+		location = "a place to be agreed"
+	}
+	data := map[string]interface{}{
+		"appointment_id":   appointment.ID.String(),
+		"conversation_id":  conv.ID.String(),
+		"appointment_kind": kindLabel(appointment.Kind),
+		"listing_title":    conv.ListingTitle,
+		"proposed_by":      proposedBy,
+		"proposed_slots":   formatSlots(appointment.ProposedSlots),
+		"location":         location,
+		"reason":           appointment.CancelReason,
+	}
+	if appointment.StartsAt != nil {
+		data["starts_at"] = formatTime(*appointment.StartsAt)
+	}
+	return data
+}
+
+// appointmentEvent is the calendar event for the slot an appointment was last
+// confirmed for. The UID stays the same, so later updates replace the event in
+// both calendars.
+func appointmentEvent(conv *domain.Conversation, appointment *domain.Appointment) calendar.Event {
+	label := kindLabel(appointment.Kind)
+	description := fmt.Sprintf("%s%s of %s, arranged on Exotics Lanka.", strings.ToUpper(label[:1]), label[1:], conv.ListingTitle)
+	if appointment.Notes != "" {
+		description += "\n\n" + appointment.Notes
+	}
+	return calendar.Event{
+		UID:         appointment.ID.String() + "@exoticslanka.lk",
+		Sequence:    appointment.Sequence,
+		Start:       *appointment.InvitedStart,
+		End:         *appointment.InvitedEnd,
+		Summary:     fmt.Sprintf("%s%s: %s", strings.ToUpper(label[:1]), label[1:], conv.ListingTitle),
+		Description: description,
+		Location:    appointment.Location,
+		Cancelled:   appointment.Status == domain.AppointmentCancelled,
+	}
+}
+
+func kindLabel(kind string) string {
+	if kind == domain.AppointmentTestDrive {
+		return "test drive"
+	}
+	return "viewing"
+}
+
+// formatTime shows a time as people in Sri Lanka read it
+func formatTime(t time.Time) string {
+	return t.In(domain.SriLankaTime).Format("Mon 2 Jan 2006, 3:04 PM")
+}
+
+func formatSlots(slots []domain.TimeSlot) string {
+	parts := make([]string, len(slots))
+	for i, slot := range slots {
+		parts[i] = formatTime(slot.Start) + " to " + slot.End.In(domain.SriLankaTime).Format("3:04 PM")
+	}
+	return strings.Join(parts, "; ")
+}
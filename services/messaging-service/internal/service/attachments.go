@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/signedurl"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+// attachmentLinkTTL is how long a signed attachment link works. Clients get
+// fresh links each time they load the conversation.
+const attachmentLinkTTL = 15 * time.Minute
+
+// AttachmentLinks signs the image-service links participants read attachments
+// through. image-service checks them with the same secret.
+type AttachmentLinks struct {
+	BaseURL string // image-service
+	Secret  []byte
+}
+
+func (l AttachmentLinks) sign(a *domain.Attachment, now time.Time) {
+	a.ExpiresAt = now.Add(attachmentLinkTTL)
+	base := strings.TrimSuffix(l.BaseURL, "/")
+	path := fmt.Sprintf("/api/attachments/%s", a.ID)
+	a.URL = base + signedurl.Sign(l.Secret, path+"/content", a.ExpiresAt)
+	if a.HasThumbnail {
+		a.ThumbnailURL = base + signedurl.Sign(l.Secret, path+"/thumbnail", a.ExpiresAt)
+	}
+}
+
+// attachFiles hydrates the attachments of attachment messages, with signed links
+func (s *service) attachFiles(ctx context.Context, messages []domain.Message) error {
+	var ids []uuid.UUID
+	for _, m := range messages {
+		if m.Type == domain.MessageTypeAttachment {
+			ids = append(ids, m.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	attachments, err := s.repo.GetMessageAttachments(ctx, ids)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	byMessage := make(map[uuid.UUID][]domain.Attachment, len(ids))
+	for _, a := range attachments {
+		s.links.sign(&a, now)
+		byMessage[a.MessageID] = append(byMessage[a.MessageID], a)
+	}
+	for i := range messages {
+		if files, ok := byMessage[messages[i].ID]; ok {
+			messages[i].Attachments = files
+		}
+	}
+	return nil
+}
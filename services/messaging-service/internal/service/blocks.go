@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+// BlockConversation blocks, or unblocks, the other side of the conversation.
+// Sellers on a dealer team block on behalf of the organisation, so the buyer
+// can't reach any of its members about any of its listings.
+func (s *service) BlockConversation(ctx context.Context, conversationID, userID uuid.UUID, blocked bool) error {
+	conv, viewer, err := s.participant(ctx, conversationID, userID)
+	if err != nil {
+		return err
+	}
+	owner, other := parties(conv, viewer)
+	if blocked {
+		return s.repo.BlockUser(ctx, owner, other, userID, &conv.ID)
+	}
+	return s.repo.UnblockUser(ctx, []uuid.UUID{owner}, other)
+}
+
+// GetBlockedUsers lists who the user blocked, and for dealer team members who
+// their organisation blocked
+func (s *service) GetBlockedUsers(ctx context.Context, userID uuid.UUID) ([]domain.BlockedUser, error) {
+	viewer, err := s.viewer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetBlockedUsers(ctx, blockOwners(viewer))
+}
+
+func (s *service) UnblockUser(ctx context.Context, userID, blockedID uuid.UUID) error {
+	viewer, err := s.viewer(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.repo.UnblockUser(ctx, blockOwners(viewer), blockedID)
+}
+
+// ReportConversation files a report against the other side with
+// reports-service, with the latest messages the reporter can see, and
+// optionally blocks them too
+func (s *service) ReportConversation(ctx context.Context, conversationID, userID uuid.UUID, req domain.ReportConversationRequest) (*domain.ConversationReport, error) {
+	conv, viewer, err := s.participant(ctx, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.repo.HasRecentConversationReport(ctx, userID, conv.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("you have already reported this conversation recently")
+	}
+
+	messages, _, err := s.repo.GetMessagesByConversation(ctx, conv.ID, userID, nil, pagination.Params{Page: 1, Limit: domain.ReportExcerptLength})
+	if err != nil {
+		return nil, err
+	}
+	// Messages come newest first; transcripts read oldest first
+	excerpt := make([]domain.ExcerptLine, 0, len(messages))
+	for i := len(messages) - 1; i >= 0; i-- {
+		m := &messages[i]
+		excerpt = append(excerpt, domain.ExcerptLine{
+			MessageID:  m.ID,
+			SenderID:   m.SenderID,
+			SenderName: m.SenderName,
+			Content:    messagePreview(m),
+			SentAt:     m.CreatedAt,
+		})
+	}
+
+	// On a dealer team's side, the member handling the conversation is reported
+	reported := conv.BuyerID
+	if viewer.IsBuyer(conv) {
+		reported = conv.SellerID
+		if conv.AssignedTo != nil {
+			reported = *conv.AssignedTo
+		}
+	}
+
+	report := &domain.ConversationReport{
+		ConversationID: conv.ID,
+		ReporterID:     userID,
+		ReportedUserID: reported,
+		Reason:         req.Reason,
+		Details:        req.Details,
+		Excerpt:        excerpt,
+	}
+	if err := s.repo.CreateConversationReport(ctx, report); err != nil {
+		return nil, err
+	}
+
+	if req.Block {
+		owner, other := parties(conv, viewer)
+		if err := s.repo.BlockUser(ctx, owner, other, userID, &conv.ID); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// canMessage checks that neither side of the conversation blocked the other
+func (s *service) canMessage(ctx context.Context, conv *domain.Conversation) error {
+	blocked, err := s.repo.IsBlocked(ctx, conv.BuyerID, sellerOwner(conv))
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return fmt.Errorf("you cannot message this user")
+	}
+	return nil
+}
+
+// parties returns the viewer's side of the conversation and the other side,
+// as blocks record them
+func parties(conv *domain.Conversation, viewer domain.Viewer) (owner, other uuid.UUID) {
+	if viewer.IsBuyer(conv) {
+		return conv.BuyerID, sellerOwner(conv)
+	}
+	return sellerOwner(conv), conv.BuyerID
+}
+
+// blockOwners is whose blocks the viewer manages: their own, and their
+// organisation's
+func blockOwners(viewer domain.Viewer) []uuid.UUID {
+	owners := []uuid.UUID{viewer.UserID}
+	if viewer.OrganizationID != nil {
+		owners = append(owners, *viewer.OrganizationID)
+	}
+	return owners
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/domain"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/realtime"
+	"github.com/google/uuid"
+)
+
+// MakeOffer posts a price offer in the conversation. Either side can make one,
+// but only one offer can be pending at a time.
+func (s *service) MakeOffer(ctx context.Context, conversationID, userID uuid.UUID, req domain.MakeOfferRequest) (*domain.Message, error) {
+	conv, viewer, err := s.participant(ctx, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.postOffer(ctx, conv, viewer, nil, req)
+}
+
+// CounterOffer answers a pending offer with a new amount
+func (s *service) CounterOffer(ctx context.Context, offerID, userID uuid.UUID, req domain.MakeOfferRequest) (*domain.Message, error) {
+	offer, conv, viewer, err := s.openOffer(ctx, offerID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.postOffer(ctx, conv, viewer, &offer.ID, req)
+}
+
+// AcceptOffer accepts a pending offer and records it on the listing. The
+// seller side can also reserve the listing or mark it as pending sale.
+func (s *service) AcceptOffer(ctx context.Context, offerID, userID uuid.UUID, req domain.AcceptOfferRequest) (*domain.Offer, error) {
+	_, conv, viewer, err := s.openOffer(ctx, offerID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var listingStatus *string
+	if req.ListingStatus != "" {
+		if !viewer.IsSeller(conv) {
+			return nil, fmt.Errorf("only the seller can change the listing status")
+		}
+		listingStatus = &req.ListingStatus
+	}
+
+	offer, err := s.respond(ctx, conv, offerID, userID, domain.OfferAccepted, listingStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	if offer.ListingID != nil {
+		if err := s.listings.RecordAcceptedOffer(ctx, offer); err != nil {
+			log.Printf("Error recording accepted offer %s on listing %s: %v", offer.ID, *offer.ListingID, err)
+		}
+	}
+	return offer, nil
+}
+
+func (s *service) RejectOffer(ctx context.Context, offerID, userID uuid.UUID) (*domain.Offer, error) {
+	_, conv, _, err := s.openOffer(ctx, offerID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.respond(ctx, conv, offerID, userID, domain.OfferRejected, nil)
+}
+
+// GetOffers returns the conversation's offer history, oldest first
+func (s *service) GetOffers(ctx context.Context, conversationID, userID uuid.UUID) ([]domain.Offer, error) {
+	if _, _, err := s.participant(ctx, conversationID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetConversationOffers(ctx, conversationID)
+}
+
+func (s *service) ExpireOffers(ctx context.Context) (int64, error) {
+	return s.repo.ExpireOffers(ctx)
+}
+
+// postOffer saves an offer, or a counter to counterTo, with the message that
+// carries it, then delivers the message like any other.
+func (s *service) postOffer(ctx context.Context, conv *domain.Conversation, viewer domain.Viewer, counterTo *uuid.UUID, req domain.MakeOfferRequest) (*domain.Message, error) {
+	if err := s.canMessage(ctx, conv); err != nil {
+		return nil, err
+	}
+	if err := s.screenFields(ctx, conv, viewer.UserID, &req.Message); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(domain.DefaultOfferTTL)
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) {
+			return nil, fmt.Errorf("offer expiry must be in the future")
+		}
+		if req.ExpiresAt.After(now.Add(domain.MaxOfferTTL)) {
+			return nil, fmt.Errorf("offer expiry cannot be more than 14 days away")
+		}
+		expiresAt = *req.ExpiresAt
+	}
+
+	currency := req.Currency
+	if currency == "" {
+		currency = domain.DefaultOfferCurrency
+	}
+
+	offer := &domain.Offer{
+		ConversationID: conv.ID,
+		ListingID:      conv.ListingID,
+		BuyerID:        conv.BuyerID,
+		SellerID:       conv.SellerID,
+		OrganizationID: conv.OrganizationID,
+		OfferedBy:      viewer.UserID,
+		FromBuyer:      viewer.IsBuyer(conv),
+		Amount:         req.Amount,
+		Currency:       currency,
+		ExpiresAt:      expiresAt,
+		CounterTo:      counterTo,
+	}
+
+	preview := "Offer: " + currency + " " + strconv.FormatFloat(req.Amount, 'f', -1, 64)
+	content := req.Message
+	if content == "" {
+		content = preview
+	}
+
+	msg, err := s.repo.CreateOffer(ctx, offer, &domain.Message{
+		ConversationID: conv.ID,
+		SenderID:       viewer.UserID,
+		Type:           domain.MessageTypeOffer,
+		Content:        content,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.delivered(ctx, conv, viewer, msg, preview); err != nil {
+		return nil, err
+	}
+	if counterTo != nil {
+		s.notifyOffer(ctx, conv, *counterTo)
+	}
+	return msg, nil
+}
+
+// openOffer loads an offer the user can respond to: it is pending and was
+// made by the other side of a conversation they take part in, which neither
+// side has blocked.
+func (s *service) openOffer(ctx context.Context, offerID, userID uuid.UUID) (*domain.Offer, *domain.Conversation, domain.Viewer, error) {
+	offer, err := s.repo.GetOffer(ctx, offerID)
+	if err != nil {
+		return nil, nil, domain.Viewer{}, err
+	}
+	if offer == nil {
+		return nil, nil, domain.Viewer{}, fmt.Errorf("offer not found")
+	}
+
+	conv, viewer, err := s.participant(ctx, offer.ConversationID, userID)
+	if err != nil {
+		return nil, nil, domain.Viewer{}, err
+	}
+	if viewer.IsBuyer(conv) == offer.FromBuyer {
+		return nil, nil, domain.Viewer{}, fmt.Errorf("you cannot respond to your own offer")
+	}
+	if err := s.canMessage(ctx, conv); err != nil {
+		return nil, nil, domain.Viewer{}, err
+	}
+
+	switch {
+	case offer.Status == domain.OfferExpired,
+		offer.Status == domain.OfferPending && !offer.ExpiresAt.After(time.Now()):
+		return nil, nil, domain.Viewer{}, fmt.Errorf("offer has expired")
+	case offer.Status != domain.OfferPending:
+		return nil, nil, domain.Viewer{}, fmt.Errorf("offer is no longer pending")
+	}
+	return offer, conv, viewer, nil
+}
+
+// respond closes an offer and pushes its new status to both sides
+func (s *service) respond(ctx context.Context, conv *domain.Conversation, offerID, userID uuid.UUID, status string, listingStatus *string) (*domain.Offer, error) {
+	offer, err := s.repo.RespondToOffer(ctx, offerID, userID, status, listingStatus)
+	if err != nil {
+		return nil, err
+	}
+	if offer == nil {
+		// Answered or expired since it was loaded
+		return nil, fmt.Errorf("offer is no longer pending")
+	}
+
+	s.publishOffer(ctx, conv, offer)
+	return offer, nil
+}
+
+// notifyOffer pushes the current state of an offer to both sides
+func (s *service) notifyOffer(ctx context.Context, conv *domain.Conversation, offerID uuid.UUID) {
+	offer, err := s.repo.GetOffer(ctx, offerID)
+	if err != nil || offer == nil {
+		log.Printf("Error loading offer %s: %v", offerID, err)
+		return
+	}
+	s.publishOffer(ctx, conv, offer)
+}
+
+func (s *service) publishOffer(ctx context.Context, conv *domain.Conversation, offer *domain.Offer) {
+	buyers, sellers, err := s.sides(ctx, conv)
+	if err != nil {
+		log.Printf("Error loading recipients of conversation %s: %v", conv.ID, err)
+		return
+	}
+	s.notify(ctx, append(append([]uuid.UUID{}, buyers...), sellers...), realtime.EventOfferUpdated, offer)
+}
+
+// attachOffers hydrates the offer carried by each offer message
+func (s *service) attachOffers(ctx context.Context, conversationID uuid.UUID, messages []domain.Message) error {
+	hasOffers := false
+	for _, m := range messages {
+		if m.OfferID != nil {
+			hasOffers = true
+			break
+		}
+	}
+	if !hasOffers {
+		return nil
+	}
+
+	offers, err := s.repo.GetConversationOffers(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	byID := make(map[uuid.UUID]*domain.Offer, len(offers))
+	for i := range offers {
+		byID[offers[i].ID] = &offers[i]
+	}
+	for i := range messages {
+		if messages[i].OfferID != nil {
+			messages[i].Offer = byID[*messages[i].OfferID]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/domain"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/replies"
+	"github.com/google/uuid"
+)
+
+func (s *service) GetQuickReplies(ctx context.Context, userID uuid.UUID) ([]domain.QuickReply, error) {
+	return s.repo.GetQuickReplies(ctx, userID)
+}
+
+func (s *service) CreateQuickReply(ctx context.Context, userID uuid.UUID, req domain.SaveQuickReplyRequest) (*domain.QuickReply, error) {
+	reply, err := quickReply(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateQuickReply(ctx, userID, reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *service) UpdateQuickReply(ctx context.Context, id, userID uuid.UUID, req domain.SaveQuickReplyRequest) (*domain.QuickReply, error) {
+	reply, err := quickReply(req)
+	if err != nil {
+		return nil, err
+	}
+	reply.ID = id
+	if err := s.repo.UpdateQuickReply(ctx, userID, reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *service) DeleteQuickReply(ctx context.Context, id, userID uuid.UUID) error {
+	return s.repo.DeleteQuickReply(ctx, userID, id)
+}
+
+// GetConversationQuickReplies returns the user's quick replies with their
+// placeholders filled in for the conversation, ready to send or edit
+func (s *service) GetConversationQuickReplies(ctx context.Context, conversationID, userID uuid.UUID) ([]domain.QuickReply, error) {
+	conv, _, err := s.participant(ctx, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	saved, err := s.repo.GetQuickReplies(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	values, err := s.replyValues(ctx, conv)
+	if err != nil {
+		return nil, err
+	}
+	for i := range saved {
+		saved[i].Content = replies.Render(saved[i].Content, values)
+	}
+	return saved, nil
+}
+
+// GetAutoReply returns the auto-reply of the seller side the user is on:
+// their dealer organisation's, or their own. It is off until set.
+func (s *service) GetAutoReply(ctx context.Context, userID uuid.UUID) (*domain.AutoReply, error) {
+	viewer, err := s.viewer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ownerID := userID
+	if viewer.OrganizationID != nil {
+		ownerID = *viewer.OrganizationID
+	}
+	reply, err := s.repo.GetAutoReply(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		reply = &domain.AutoReply{Mode: domain.AutoReplyOutsideHours, BusinessHours: []domain.AvailabilityWindow{}}
+	}
+	return reply, nil
+}
+
+// SetAutoReply replaces the user's auto-reply. For a dealer organisation only
+// owners and managers can change it.
+func (s *service) SetAutoReply(ctx context.Context, userID uuid.UUID, req domain.SetAutoReplyRequest) (*domain.AutoReply, error) {
+	viewer, err := s.viewer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ownerID := userID
+	if viewer.OrganizationID != nil {
+		if !viewer.CanManage {
+			return nil, fmt.Errorf("only organisation owners and managers can change the auto-reply")
+		}
+		ownerID = *viewer.OrganizationID
+	}
+
+	message := strings.TrimSpace(req.Message)
+	if req.Enabled && message == "" {
+		return nil, fmt.Errorf("auto-reply message is required")
+	}
+	if err := checkTemplate(message); err != nil {
+		return nil, err
+	}
+	if err := checkWindows("business hours", req.BusinessHours); err != nil {
+		return nil, err
+	}
+	if req.Enabled && req.Mode == domain.AutoReplyOutsideHours && len(req.BusinessHours) == 0 {
+		return nil, fmt.Errorf("business hours are required to reply outside them")
+	}
+
+	reply := &domain.AutoReply{
+		Enabled:       req.Enabled,
+		Mode:          req.Mode,
+		Message:       message,
+		BusinessHours: req.BusinessHours,
+		UpdatedBy:     &userID,
+	}
+	if reply.BusinessHours == nil {
+		reply.BusinessHours = []domain.AvailabilityWindow{}
+	}
+	if err := s.repo.SetAutoReply(ctx, ownerID, reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// autoReply answers the first message of a new conversation with the seller
+// side's auto-reply, when it is on and due. It is sent by the team member the
+// conversation is assigned to, or the seller.
+func (s *service) autoReply(ctx context.Context, conversationID uuid.UUID) error {
+	conv, err := s.repo.GetConversationByID(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	if conv == nil {
+		return fmt.Errorf("conversation not found")
+	}
+	reply, err := s.repo.GetAutoReply(ctx, sellerOwner(conv))
+	if err != nil || reply == nil || !reply.Due(conv.CreatedAt) {
+		return err
+	}
+
+	values, err := s.replyValues(ctx, conv)
+	if err != nil {
+		return err
+	}
+	sender := conv.SellerID
+	if conv.AssignedTo != nil {
+		sender = *conv.AssignedTo
+	}
+	msg, err := s.repo.CreateMessage(ctx, &domain.Message{
+		ConversationID: conv.ID,
+		SenderID:       sender,
+		Type:           domain.MessageTypeAutoReply,
+		Content:        replies.Render(reply.Message, values),
+	})
+	if err != nil {
+		return err
+	}
+	return s.delivered(ctx, conv, domain.Viewer{UserID: sender}, msg, msg.Content)
+}
+
+// replyValues are what placeholders are filled in with in the conversation
+func (s *service) replyValues(ctx context.Context, conv *domain.Conversation) (replies.Values, error) {
+	buyer, err := s.repo.GetUserName(ctx, conv.BuyerID)
+	if err != nil {
+		return replies.Values{}, err
+	}
+	return replies.Values{
+		ListingTitle: conv.ListingTitle,
+		Price:        conv.ListingPrice,
+		Currency:     domain.DefaultOfferCurrency,
+		BuyerName:    buyer,
+	}, nil
+}
+
+func quickReply(req domain.SaveQuickReplyRequest) (*domain.QuickReply, error) {
+	reply := &domain.QuickReply{
+		Title:   strings.TrimSpace(req.Title),
+		Content: strings.TrimSpace(req.Content),
+	}
+	if reply.Title == "" || reply.Content == "" {
+		return nil, fmt.Errorf("quick reply title and content are required")
+	}
+	if err := checkTemplate(reply.Content); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// checkTemplate rejects placeholders that can't be filled in, so typos aren't
+// sent to buyers as written
+func checkTemplate(template string) error {
+	if unknown := replies.Unknown(template); len(unknown) > 0 {
+		return fmt.Errorf("unknown placeholder %s; use %s, %s or %s", unknown[0], replies.ListingTitle, replies.Price, replies.BuyerName)
+	}
+	return nil
+}
+
+// checkWindows validates weekly windows, naming them in errors
+func checkWindows(name string, windows []domain.AvailabilityWindow) error {
+	for _, w := range windows {
+		start, err := time.Parse("15:04", w.Start)
+		if err != nil {
+			return fmt.Errorf("%s times must be HH:MM", name)
+		}
+		end, err := time.Parse("15:04", w.End)
+		if err != nil {
+			return fmt.Errorf("%s times must be HH:MM", name)
+		}
+		if !end.After(start) {
+			return fmt.Errorf("%s windows must end after they start", name)
+		}
+	}
+	return nil
+}
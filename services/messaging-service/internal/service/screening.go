@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/domain"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/screening"
+	"github.com/google/uuid"
+)
+
+// screen checks a message before it is saved. Senders over their rate limit
+// are turned away; new and unverified accounts have contact details masked,
+// and messages containing a scam phrase are held for review.
+func (s *service) screen(ctx context.Context, msg *domain.Message) error {
+	activity, err := s.repo.GetSenderActivity(ctx, msg.SenderID)
+	if err != nil {
+		return err
+	}
+	if screening.LimitsFor(activity.Restricted).Exceeded(activity.LastMinute, activity.LastHour) {
+		return fmt.Errorf("too many messages, try again later")
+	}
+	if msg.Content == "" {
+		return nil
+	}
+
+	original := msg.Content
+	if activity.Restricted {
+		msg.Content, msg.Masked = screening.MaskContacts(msg.Content)
+	}
+
+	phrases, err := s.repo.GetScamPhrases(ctx)
+	if err != nil {
+		return err
+	}
+	list := make([]string, len(phrases))
+	for i, p := range phrases {
+		list[i] = p.Phrase
+	}
+	// Match before masking, so a hidden link can't hide a phrase
+	if phrase, ok := screening.MatchPhrase(original, list); ok {
+		msg.ModerationStatus = domain.ModerationHeld
+		msg.ModerationReason = fmt.Sprintf("Message held by screening: matched scam phrase %q", phrase)
+	}
+	return nil
+}
+
+// screenFields screens the free text sent with an offer or appointment
+// before it is saved, emailed or put in a calendar invite. Restricted senders
+// have contact details masked; if the text contains a scam phrase it is taken
+// out of the fields and held for review as a message of its own.
+func (s *service) screenFields(ctx context.Context, conv *domain.Conversation, senderID uuid.UUID, fields ...*string) error {
+	var parts []string
+	for _, f := range fields {
+		*f = strings.TrimSpace(*f)
+		if *f != "" {
+			parts = append(parts, *f)
+		}
+	}
+	msg := &domain.Message{
+		ConversationID: conv.ID,
+		SenderID:       senderID,
+		Type:           domain.MessageTypeText,
+		Content:        strings.Join(parts, "\n"),
+	}
+	if err := s.screen(ctx, msg); err != nil {
+		return err
+	}
+
+	if msg.ModerationStatus == domain.ModerationHeld {
+		for _, f := range fields {
+			*f = ""
+		}
+		_, err := s.repo.CreateMessage(ctx, msg)
+		return err
+	}
+	if msg.Masked {
+		for _, f := range fields {
+			*f, _ = screening.MaskContacts(*f)
+		}
+	}
+	return nil
+}
+
+// messagePreview is what the conversation list shows for a message
+func messagePreview(msg *domain.Message) string {
+	if msg.Content == "" && msg.Type == domain.MessageTypeAttachment {
+		return "Sent an attachment"
+	}
+	return msg.Content
+}
+
+func (s *service) GetScamPhrases(ctx context.Context) ([]domain.ScamPhrase, error) {
+	return s.repo.GetScamPhrases(ctx)
+}
+
+func (s *service) AddScamPhrase(ctx context.Context, adminID uuid.UUID, req domain.CreateScamPhraseRequest) (*domain.ScamPhrase, error) {
+	phrase := strings.Join(strings.Fields(req.Phrase), " ")
+	if phrase == "" {
+		return nil, fmt.Errorf("phrase is required")
+	}
+	return s.repo.CreateScamPhrase(ctx, phrase, adminID)
+}
+
+func (s *service) DeleteScamPhrase(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteScamPhrase(ctx, id)
+}
+
+// DeliverReleasedMessages delivers held messages an admin approved in
+// reports-service, as if they had just been sent
+func (s *service) DeliverReleasedMessages(ctx context.Context) (int, error) {
+	messages, err := s.repo.ClaimReleasedMessages(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.attachFiles(ctx, messages); err != nil {
+		log.Printf("Error loading attachments of released messages: %v", err)
+	}
+	for i := range messages {
+		msg := &messages[i]
+		conv, err := s.repo.GetConversationByID(ctx, msg.ConversationID)
+		if err != nil || conv == nil {
+			log.Printf("Error loading conversation of message %s: %v", msg.ID, err)
+			continue
+		}
+		viewer, err := s.viewer(ctx, msg.SenderID)
+		if err != nil {
+			log.Printf("Error loading sender of message %s: %v", msg.ID, err)
+			continue
+		}
+		if err := s.delivered(ctx, conv, viewer, msg, messagePreview(msg)); err != nil {
+			log.Printf("Error delivering message %s: %v", msg.ID, err)
+		}
+	}
+	return len(messages), nil
+}
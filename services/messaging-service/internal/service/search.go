@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+// SearchMessages searches the messages of the conversations the user takes
+// part in, and the listings they are about
+func (s *service) SearchMessages(ctx context.Context, userID uuid.UUID, query string, page pagination.CursorParams) ([]domain.SearchHit, pagination.CursorPage, error) {
+	query = strings.TrimSpace(query)
+	if len(query) < domain.MinSearchQueryLength {
+		return nil, pagination.CursorPage{}, fmt.Errorf("search query must be at least %d characters", domain.MinSearchQueryLength)
+	}
+	if len(query) > domain.MaxSearchQueryLength {
+		return nil, pagination.CursorPage{}, fmt.Errorf("search query must be at most %d characters", domain.MaxSearchQueryLength)
+	}
+
+	viewer, err := s.viewer(ctx, userID)
+	if err != nil {
+		return nil, pagination.CursorPage{}, err
+	}
+	hits, err := s.repo.SearchMessages(ctx, viewer, query, page)
+	if err != nil {
+		return nil, pagination.CursorPage{}, err
+	}
+
+	meta, n := pagination.NewCursorPage(page, len(hits), func(i int) (time.Time, string) {
+		return hits[i].CreatedAt, hits[i].MessageID.String()
+	})
+	hits = hits[:n]
+	for i := range hits {
+		hits[i].Snippet = escapeHighlight(hits[i].Snippet)
+		hits[i].Conversation.ListingTitle = escapeHighlight(hits[i].Conversation.ListingTitle)
+	}
+	return hits, meta, nil
+}
+
+// escapeHighlight HTML-escapes text highlighted by ts_headline, keeping only
+// its <mark> tags, so clients can render snippets as HTML
+func escapeHighlight(s string) string {
+	return strings.NewReplacer("&lt;mark&gt;", "<mark>", "&lt;/mark&gt;", "</mark>").Replace(html.EscapeString(s))
+}
@@ -3,27 +3,90 @@ package service
 import (
 	"context"
 	"fmt"
+	"log"
+	"strings"
+	"time"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
 	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/domain"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/listings"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/notifications"
+	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/realtime"
 	"github.com/aselahemantha/exoticsLanka/services/messaging-service/internal/repository"
 	"github.com/google/uuid"
 )
 
 type Service interface {
 	CreateConversation(ctx context.Context, req domain.CreateConversationRequest, buyerID uuid.UUID) (*domain.ConversationResponse, error)
-	SendMessage(ctx context.Context, conversationID, senderID uuid.UUID, content string) (*domain.Message, error)
+	SendMessage(ctx context.Context, conversationID, senderID uuid.UUID, req domain.SendMessageRequest) (*domain.Message, error)
 	GetConversationByID(ctx context.Context, conversationID, userID uuid.UUID) (*domain.Conversation, []domain.Message, error)
-	GetUserConversations(ctx context.Context, userID uuid.UUID, page, limit int, archived bool) ([]domain.Conversation, *domain.Pagination, error)
+	GetUserConversations(ctx context.Context, userID uuid.UUID, params pagination.Params, archived bool) ([]domain.Conversation, *pagination.Pagination, error)
 	MarkConversationRead(ctx context.Context, conversationID, userID uuid.UUID) error
 	GetUnreadCount(ctx context.Context, userID uuid.UUID) (*domain.UnreadCountResponse, error)
+	SearchMessages(ctx context.Context, userID uuid.UUID, query string, page pagination.CursorParams) ([]domain.SearchHit, pagination.CursorPage, error)
+	AssignConversation(ctx context.Context, conversationID, userID uuid.UUID, req domain.AssignConversationRequest) error
+	ArchiveConversation(ctx context.Context, conversationID, userID uuid.UUID, archived bool) error
+	MuteConversation(ctx context.Context, conversationID, userID uuid.UUID, muted bool) error
+	DeleteConversation(ctx context.Context, conversationID, userID uuid.UUID) error
+	BlockConversation(ctx context.Context, conversationID, userID uuid.UUID, blocked bool) error
+	ReportConversation(ctx context.Context, conversationID, userID uuid.UUID, req domain.ReportConversationRequest) (*domain.ConversationReport, error)
+	GetBlockedUsers(ctx context.Context, userID uuid.UUID) ([]domain.BlockedUser, error)
+	UnblockUser(ctx context.Context, userID, blockedID uuid.UUID) error
+	RefreshListingSnapshots(ctx context.Context) (int64, error)
+	ApplyOrganizationEvents(ctx context.Context) (int, error)
+
+	// Screening
+	GetScamPhrases(ctx context.Context) ([]domain.ScamPhrase, error)
+	AddScamPhrase(ctx context.Context, adminID uuid.UUID, req domain.CreateScamPhraseRequest) (*domain.ScamPhrase, error)
+	DeleteScamPhrase(ctx context.Context, id uuid.UUID) error
+	DeliverReleasedMessages(ctx context.Context) (int, error)
+
+	// Quick replies and auto-replies
+	GetQuickReplies(ctx context.Context, userID uuid.UUID) ([]domain.QuickReply, error)
+	CreateQuickReply(ctx context.Context, userID uuid.UUID, req domain.SaveQuickReplyRequest) (*domain.QuickReply, error)
+	UpdateQuickReply(ctx context.Context, id, userID uuid.UUID, req domain.SaveQuickReplyRequest) (*domain.QuickReply, error)
+	DeleteQuickReply(ctx context.Context, id, userID uuid.UUID) error
+	GetConversationQuickReplies(ctx context.Context, conversationID, userID uuid.UUID) ([]domain.QuickReply, error)
+	GetAutoReply(ctx context.Context, userID uuid.UUID) (*domain.AutoReply, error)
+	SetAutoReply(ctx context.Context, userID uuid.UUID, req domain.SetAutoReplyRequest) (*domain.AutoReply, error)
+
+	// Offers
+	MakeOffer(ctx context.Context, conversationID, userID uuid.UUID, req domain.MakeOfferRequest) (*domain.Message, error)
+	CounterOffer(ctx context.Context, offerID, userID uuid.UUID, req domain.MakeOfferRequest) (*domain.Message, error)
+	AcceptOffer(ctx context.Context, offerID, userID uuid.UUID, req domain.AcceptOfferRequest) (*domain.Offer, error)
+	RejectOffer(ctx context.Context, offerID, userID uuid.UUID) (*domain.Offer, error)
+	GetOffers(ctx context.Context, conversationID, userID uuid.UUID) ([]domain.Offer, error)
+	ExpireOffers(ctx context.Context) (int64, error)
+
+	// Appointments
+	RequestAppointment(ctx context.Context, conversationID, userID uuid.UUID, req domain.RequestAppointmentRequest) (*domain.Message, error)
+	RescheduleAppointment(ctx context.Context, appointmentID, userID uuid.UUID, req domain.RescheduleAppointmentRequest) (*domain.Message, error)
+	ConfirmAppointment(ctx context.Context, appointmentID, userID uuid.UUID, req domain.ConfirmAppointmentRequest) (*domain.Message, error)
+	CancelAppointment(ctx context.Context, appointmentID, userID uuid.UUID, req domain.CancelAppointmentRequest) (*domain.Message, error)
+	CompleteAppointment(ctx context.Context, appointmentID, userID uuid.UUID, req domain.CompleteAppointmentRequest) (*domain.Appointment, error)
+	GetAppointments(ctx context.Context, conversationID, userID uuid.UUID) ([]domain.Appointment, error)
+	GetUserAppointments(ctx context.Context, userID uuid.UUID, status string) ([]domain.Appointment, error)
+	GetAvailability(ctx context.Context, userID uuid.UUID) ([]domain.AvailabilityWindow, error)
+	SetAvailability(ctx context.Context, userID uuid.UUID, req domain.SetAvailabilityRequest) ([]domain.AvailabilityWindow, error)
+	GetConversationAvailability(ctx context.Context, conversationID, userID uuid.UUID) ([]domain.AvailabilityWindow, error)
+	SendAppointmentReminders(ctx context.Context) (int, error)
+}
+
+// Notifier pushes events to users' connected devices
+type Notifier interface {
+	Publish(ctx context.Context, userIDs []uuid.UUID, event realtime.Event) error
 }
 
 type service struct {
-	repo repository.Repository
+	repo     repository.Repository
+	listings listings.Lookup
+	notifier Notifier
+	links    AttachmentLinks
+	outbox   notifications.Queue
 }
 
-func NewService(repo repository.Repository) Service {
-	return &service{repo: repo}
+func NewService(repo repository.Repository, listings listings.Lookup, notifier Notifier, links AttachmentLinks, outbox notifications.Queue) Service {
+	return &service{repo: repo, listings: listings, notifier: notifier, links: links, outbox: outbox}
 }
 
 func (s *service) CreateConversation(ctx context.Context, req domain.CreateConversationRequest, buyerID uuid.UUID) (*domain.ConversationResponse, error) {
@@ -31,6 +94,24 @@ func (s *service) CreateConversation(ctx context.Context, req domain.CreateConve
 		return nil, fmt.Errorf("cannot start conversation with yourself")
 	}
 
+	listing, err := s.resolveListing(ctx, req.ListingID, req.SellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Blocks cover every listing of the seller, or of their dealer organisation
+	sellerSide := req.SellerID
+	if listing.OrganizationID != nil {
+		sellerSide = *listing.OrganizationID
+	}
+	blocked, err := s.repo.IsBlocked(ctx, buyerID, sellerSide)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, fmt.Errorf("you cannot message this user")
+	}
+
 	// Check existing
 	existing, err := s.repo.GetConversationByParticipants(ctx, req.ListingID, buyerID, req.SellerID)
 	if err != nil {
@@ -39,7 +120,7 @@ func (s *service) CreateConversation(ctx context.Context, req domain.CreateConve
 
 	if existing != nil {
 		// Existing conversation found, just send the message
-		_, err := s.SendMessage(ctx, existing.ID, buyerID, req.InitialMessage)
+		_, err := s.SendMessage(ctx, existing.ID, buyerID, domain.SendMessageRequest{Content: req.InitialMessage})
 		if err != nil {
 			return nil, err
 		}
@@ -47,45 +128,54 @@ func (s *service) CreateConversation(ctx context.Context, req domain.CreateConve
 		return &domain.ConversationResponse{ID: existing.ID, IsNew: false}, nil
 	}
 
-	// Create new
-	// We need listing details. Assuming simplified logic where I assume details are fetched or passed.
-	// In a real microservice, we'd call `listings-service` here.
-	// For MVP/Monorepo, we might assume shared DB access to get listing details to cache.
-	// NOTE: The user's shared DB strategy (implied everywhere else) suggests we can read tables directly?
-	// But `repository.go` in `messaging-service` doesn't have `GetListing` logic implemented.
-	// I will optimistically insert with placeholders or basic data.
-	// Actually, the request didn't provide listing title/price/image.
-	// I should probably update `CreateConversationRequest` or implement a cross-service call/DB lookup.
-	// Given previous patterns, I'll stick to basic implementation and mock the "caching" or fetch it if possible.
-	// Let's assume we can fetch it via a helper repository method if needed, but for now, empty cache is better than failing.
-	// BETTER: The prompt said "Create this service also using same procedure".
-	// `favorites-service` accessed `car_listings`. I can do the same here if I add it to repository or just raw query.
-	// I will omit extra complexity for now and focus on flow. The cached fields will be null initially.
-
+	// Create new, with a snapshot of the listing in case it is later removed.
+	// The preview is set once the screened initial message is delivered.
 	newConv := &domain.Conversation{
-		ListingID:          &req.ListingID,
-		BuyerID:            buyerID,
-		SellerID:           req.SellerID,
-		ListingTitle:       "Listing Info", // Placeholder
-		LastMessagePreview: req.InitialMessage,
+		ListingID:    &req.ListingID,
+		BuyerID:      buyerID,
+		SellerID:     req.SellerID,
+		ListingTitle: listing.Title,
+		ListingImage: listing.Image,
+		ListingPrice: listing.Price,
 		// Unread counts init to 0, but creating message will increment seller's unread
 	}
 
+	// Leads on a dealer organisation's listing go to the member it is assigned to
+	if listing.OrganizationID != nil {
+		newConv.OrganizationID = listing.OrganizationID
+		newConv.AssignedTo = listing.AssignedTo
+		if listing.AssignedTo == nil {
+			newConv.AssignedTo = &req.SellerID
+		}
+	}
+
 	convID, err := s.repo.CreateConversation(ctx, newConv)
 	if err != nil {
 		return nil, err
 	}
 
 	// Send initial message (this will update last_message and increment seller unread count)
-	_, err = s.SendMessage(ctx, convID, buyerID, req.InitialMessage)
+	msg, err := s.SendMessage(ctx, convID, buyerID, domain.SendMessageRequest{Content: req.InitialMessage})
 	if err != nil {
 		return nil, err
 	}
 
+	// The seller side's auto-reply answers, unless screening held the message
+	if msg.ModerationStatus != domain.ModerationHeld {
+		if err := s.autoReply(ctx, convID); err != nil {
+			log.Printf("Error sending auto-reply in conversation %s: %v", convID, err)
+		}
+	}
+
 	return &domain.ConversationResponse{ID: convID, IsNew: true}, nil
 }
 
-func (s *service) SendMessage(ctx context.Context, conversationID, senderID uuid.UUID, content string) (*domain.Message, error) {
+func (s *service) SendMessage(ctx context.Context, conversationID, senderID uuid.UUID, req domain.SendMessageRequest) (*domain.Message, error) {
+	content := strings.TrimSpace(req.Content)
+	if content == "" && len(req.AttachmentIDs) == 0 {
+		return nil, fmt.Errorf("message content or an attachment is required")
+	}
+
 	// 1. Verify participation?
 	// We can trust `GetConversationByID` or similar.
 	// Actually `UpdateConversationLastMessage` checks IDs implicitly if we query carefully,
@@ -98,39 +188,84 @@ func (s *service) SendMessage(ctx context.Context, conversationID, senderID uuid
 		return nil, fmt.Errorf("conversation not found")
 	}
 
-	if conv.BuyerID != senderID && conv.SellerID != senderID {
+	viewer, err := s.viewer(ctx, senderID)
+	if err != nil {
+		return nil, err
+	}
+	if !viewer.IsParticipant(conv) {
 		return nil, fmt.Errorf("not a participant")
 	}
+	if err := s.canMessage(ctx, conv); err != nil {
+		return nil, err
+	}
 
-	// 2. Create Message
+	// 2. Create Message, with the attachments uploaded for it, once it has been screened
 	msg := &domain.Message{
 		ConversationID: conversationID,
 		SenderID:       senderID,
+		Type:           domain.MessageTypeText,
 		Content:        content,
 		IsRead:         false,
+		AttachmentIDs:  req.AttachmentIDs,
+	}
+	if len(req.AttachmentIDs) > 0 {
+		msg.Type = domain.MessageTypeAttachment
+	}
+	if err := s.screen(ctx, msg); err != nil {
+		return nil, err
 	}
 
 	createdMsg, err := s.repo.CreateMessage(ctx, msg)
 	if err != nil {
 		return nil, err
 	}
+	if len(req.AttachmentIDs) > 0 {
+		sent := []domain.Message{*createdMsg}
+		if err := s.attachFiles(ctx, sent); err != nil {
+			return nil, err
+		}
+		createdMsg = &sent[0]
+	}
 
-	// 3. Update Conversation (Last Message + Unread Count)
-	// We need to know who is who to increment correctly.
-	// Repo `UpdateConversationLastMessage` needs logic.
-	// I implemented it to blindly increment 'other' based on senderID check inside SQL OR passed params.
-	// Let's check repo impl again:
-	// `buyer_unread_count = CASE WHEN seller_id = $3 THEN buyer_unread_count + 1 ...`
-	// Yes, passing senderID `$3` handles it.
-
-	err = s.repo.UpdateConversationLastMessage(ctx, conversationID, content, senderID)
-	if err != nil {
+	// 3. Update the conversation and push the message. Held messages wait for review.
+	if createdMsg.ModerationStatus == domain.ModerationHeld {
+		return createdMsg, nil
+	}
+	if err := s.delivered(ctx, conv, viewer, createdMsg, messagePreview(createdMsg)); err != nil {
 		return nil, err
 	}
-
 	return createdMsg, nil
 }
 
+// delivered updates the conversation after a message is saved and pushes the
+// message to both sides' devices, including the sender's other devices.
+func (s *service) delivered(ctx context.Context, conv *domain.Conversation, viewer domain.Viewer, msg *domain.Message, preview string) error {
+	// Any member of a dealer team can reply, so pass the sender's side rather than their ID.
+	fromBuyer := viewer.IsBuyer(conv)
+	if err := s.repo.UpdateConversationLastMessage(ctx, conv.ID, preview, fromBuyer); err != nil {
+		return err
+	}
+
+	buyers, sellers, err := s.sides(ctx, conv)
+	if err != nil {
+		log.Printf("Error loading recipients of conversation %s: %v", conv.ID, err)
+		return nil
+	}
+	// A side that muted the conversation still gets the message, without an alert
+	senders, recipients := buyers, sellers
+	if !fromBuyer {
+		senders, recipients = sellers, buyers
+	}
+	s.notify(ctx, senders, realtime.EventMessageCreated, msg)
+	s.publish(ctx, recipients, realtime.Event{
+		Type:   realtime.EventMessageCreated,
+		Data:   msg,
+		Silent: conv.MutedBy(!fromBuyer),
+	})
+	s.notifyUnread(ctx, recipients)
+	return nil
+}
+
 func (s *service) GetConversationByID(ctx context.Context, conversationID, userID uuid.UUID) (*domain.Conversation, []domain.Message, error) {
 	// Reuse the user-aware getter
 	// We need to cast `repo` to struct or add method to interface (I added generic one to interface).
@@ -147,10 +282,28 @@ func (s *service) GetConversationByID(ctx context.Context, conversationID, userI
 		return nil, nil, fmt.Errorf("conversation not found")
 	}
 
-	if conv.BuyerID != userID && conv.SellerID != userID {
+	viewer, err := s.viewer(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !viewer.IsParticipant(conv) {
 		return nil, nil, fmt.Errorf("not a participant")
 	}
 
+	asBuyer := viewer.IsBuyer(conv)
+	if conv.HiddenFrom(asBuyer) {
+		return nil, nil, fmt.Errorf("conversation not found")
+	}
+	conv.IsMuted = conv.MutedBy(asBuyer)
+	conv.IsBlocked, err = s.repo.IsBlocked(ctx, conv.BuyerID, sellerOwner(conv))
+	if err != nil {
+		return nil, nil, err
+	}
+	conv.IsArchived = conv.ArchivedBySeller
+	if asBuyer {
+		conv.IsArchived = conv.ArchivedByBuyer
+	}
+
 	// Fix unread count mapping for response
 	if userID == conv.BuyerID {
 		// conv.UnreadCount was loaded with buyer's count in previous logic?
@@ -165,37 +318,36 @@ func (s *service) GetConversationByID(ctx context.Context, conversationID, userI
 		// User sees "UnreadCount"? Maybe not relevant for detail view, just list view.
 	}
 
-	messages, _, err := s.repo.GetMessagesByConversation(ctx, conversationID, domain.Pagination{Page: 1, Limit: 50}) // Default limit
+	messages, _, err := s.repo.GetMessagesByConversation(ctx, conversationID, userID, conv.DeletedBy(asBuyer), pagination.Params{Page: 1, Limit: 50}) // Default limit
 	if err != nil {
 		return nil, nil, err
 	}
+	if err := s.attachOffers(ctx, conversationID, messages); err != nil {
+		return nil, nil, err
+	}
+	if err := s.attachFiles(ctx, messages); err != nil {
+		return nil, nil, err
+	}
+	if err := s.attachAppointments(ctx, conversationID, messages); err != nil {
+		return nil, nil, err
+	}
 
 	return conv, messages, nil
 }
 
-func (s *service) GetUserConversations(ctx context.Context, userID uuid.UUID, page, limit int, archived bool) ([]domain.Conversation, *domain.Pagination, error) {
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 {
-		limit = 20
-	}
-
-	conversations, total, err := s.repo.GetUserConversations(ctx, userID, domain.Pagination{Page: page, Limit: limit}, archived)
+func (s *service) GetUserConversations(ctx context.Context, userID uuid.UUID, params pagination.Params, archived bool) ([]domain.Conversation, *pagination.Pagination, error) {
+	viewer, err := s.viewer(ctx, userID)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	pagination := &domain.Pagination{
-		Page:  page,
-		Limit: limit,
-		Total: total,
-	}
-	if limit > 0 {
-		pagination.TotalPages = int((total + int64(limit) - 1) / int64(limit))
+	conversations, total, err := s.repo.GetUserConversations(ctx, viewer, params, archived)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return conversations, pagination, nil
+	meta := pagination.New(params, total)
+	return conversations, &meta, nil
 }
 
 func (s *service) MarkConversationRead(ctx context.Context, conversationID, userID uuid.UUID) error {
@@ -205,15 +357,44 @@ func (s *service) MarkConversationRead(ctx context.Context, conversationID, user
 		return fmt.Errorf("conversation not found")
 	}
 
-	if conv.BuyerID != userID && conv.SellerID != userID {
+	viewer, err := s.viewer(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !viewer.IsParticipant(conv) {
 		return fmt.Errorf("not a participant")
 	}
 
-	return s.repo.MarkConversationRead(ctx, conversationID, userID)
+	if err := s.repo.MarkConversationRead(ctx, conversationID, viewer.IsBuyer(conv)); err != nil {
+		return err
+	}
+
+	// The other side sees the read receipt; the reader's side sees its unread count drop
+	buyers, sellers, err := s.sides(ctx, conv)
+	if err != nil {
+		log.Printf("Error loading recipients of conversation %s: %v", conversationID, err)
+		return nil
+	}
+	s.notify(ctx, append(append([]uuid.UUID{}, buyers...), sellers...), realtime.EventConversationRead, domain.ConversationReadEvent{
+		ConversationID: conversationID,
+		ReadBy:         userID,
+		ReadAt:         time.Now(),
+	})
+	if viewer.IsBuyer(conv) {
+		s.notifyUnread(ctx, buyers)
+	} else {
+		s.notifyUnread(ctx, sellers)
+	}
+	return nil
 }
 
 func (s *service) GetUnreadCount(ctx context.Context, userID uuid.UUID) (*domain.UnreadCountResponse, error) {
-	total, byConv, err := s.repo.GetTotalUnreadCount(ctx, userID)
+	viewer, err := s.viewer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	total, byConv, err := s.repo.GetTotalUnreadCount(ctx, viewer)
 	if err != nil {
 		return nil, err
 	}
@@ -223,3 +404,212 @@ func (s *service) GetUnreadCount(ctx context.Context, userID uuid.UUID) (*domain
 		ByConversation: byConv,
 	}, nil
 }
+
+// AssignConversation hands the seller side of a dealer organisation's
+// conversation to another team member. Owners, managers and the current
+// assignee can reassign it.
+func (s *service) AssignConversation(ctx context.Context, conversationID, userID uuid.UUID, req domain.AssignConversationRequest) error {
+	conv, err := s.repo.GetConversationByID(ctx, conversationID)
+	if err != nil || conv == nil {
+		return fmt.Errorf("conversation not found")
+	}
+
+	viewer, err := s.viewer(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if conv.OrganizationID == nil || !viewer.IsSeller(conv) {
+		return fmt.Errorf("not allowed to assign this conversation")
+	}
+
+	member, err := s.repo.IsOrganizationMember(ctx, *conv.OrganizationID, req.AssignedTo)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return fmt.Errorf("assignee is not a member of the organisation")
+	}
+
+	return s.repo.AssignConversation(ctx, conversationID, req.AssignedTo)
+}
+
+// ArchiveConversation moves the conversation out of, or back into, the
+// viewer's side's inbox. A new message from the other side unarchives it.
+func (s *service) ArchiveConversation(ctx context.Context, conversationID, userID uuid.UUID, archived bool) error {
+	conv, viewer, err := s.participant(ctx, conversationID, userID)
+	if err != nil {
+		return err
+	}
+	return s.repo.SetConversationArchived(ctx, conv.ID, viewer.IsBuyer(conv), archived)
+}
+
+// MuteConversation stops, or restarts, alerts and unread badge counts for the
+// viewer's side of the conversation.
+func (s *service) MuteConversation(ctx context.Context, conversationID, userID uuid.UUID, muted bool) error {
+	conv, viewer, err := s.participant(ctx, conversationID, userID)
+	if err != nil {
+		return err
+	}
+	asBuyer := viewer.IsBuyer(conv)
+	if err := s.repo.SetConversationMuted(ctx, conv.ID, asBuyer, muted); err != nil {
+		return err
+	}
+
+	// The badge count changes either way
+	buyers, sellers, err := s.sides(ctx, conv)
+	if err != nil {
+		log.Printf("Error loading recipients of conversation %s: %v", conversationID, err)
+		return nil
+	}
+	if asBuyer {
+		s.notifyUnread(ctx, buyers)
+	} else {
+		s.notifyUnread(ctx, sellers)
+	}
+	return nil
+}
+
+// DeleteConversation removes the conversation from the viewer's side only.
+// If the other side writes again it reappears, without the deleted history.
+func (s *service) DeleteConversation(ctx context.Context, conversationID, userID uuid.UUID) error {
+	conv, viewer, err := s.participant(ctx, conversationID, userID)
+	if err != nil {
+		return err
+	}
+	asBuyer := viewer.IsBuyer(conv)
+	if err := s.repo.DeleteConversation(ctx, conv.ID, asBuyer); err != nil {
+		return err
+	}
+
+	buyers, sellers, err := s.sides(ctx, conv)
+	if err != nil {
+		log.Printf("Error loading recipients of conversation %s: %v", conversationID, err)
+		return nil
+	}
+	if asBuyer {
+		s.notifyUnread(ctx, buyers)
+	} else {
+		s.notifyUnread(ctx, sellers)
+	}
+	return nil
+}
+
+// participant loads a conversation the user can act on and isn't hidden from them
+func (s *service) participant(ctx context.Context, conversationID, userID uuid.UUID) (*domain.Conversation, domain.Viewer, error) {
+	conv, err := s.repo.GetConversationByID(ctx, conversationID)
+	if err != nil {
+		return nil, domain.Viewer{}, err
+	}
+	if conv == nil {
+		return nil, domain.Viewer{}, fmt.Errorf("conversation not found")
+	}
+
+	viewer, err := s.viewer(ctx, userID)
+	if err != nil {
+		return nil, domain.Viewer{}, err
+	}
+	if !viewer.IsParticipant(conv) {
+		return nil, domain.Viewer{}, fmt.Errorf("not a participant")
+	}
+	if conv.HiddenFrom(viewer.IsBuyer(conv)) {
+		return nil, domain.Viewer{}, fmt.Errorf("conversation not found")
+	}
+	return conv, viewer, nil
+}
+
+// resolveListing checks that the listing can be enquired about and that
+// sellerID owns it: they listed it, or are on the dealer team that does.
+func (s *service) resolveListing(ctx context.Context, listingID, sellerID uuid.UUID) (*domain.ListingSnapshot, error) {
+	listing, err := s.listings.GetListing(ctx, listingID)
+	if err != nil {
+		return nil, err
+	}
+	if listing == nil {
+		return nil, fmt.Errorf("listing not found")
+	}
+	if listing.Status != domain.ListingStatusActive {
+		return nil, fmt.Errorf("listing is not active")
+	}
+
+	owns := listing.SellerID == sellerID
+	if !owns && listing.OrganizationID != nil {
+		owns, err = s.repo.IsOrganizationMember(ctx, *listing.OrganizationID, sellerID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !owns {
+		return nil, fmt.Errorf("seller does not own this listing")
+	}
+	return listing, nil
+}
+
+// RefreshListingSnapshots updates the cached listing details of conversations
+// whose listing has changed price
+func (s *service) RefreshListingSnapshots(ctx context.Context) (int64, error) {
+	return s.repo.RefreshListingSnapshots(ctx)
+}
+
+// organizationEventBatch is how many dealer team changes are applied per run
+const organizationEventBatch = 100
+
+// ApplyOrganizationEvents moves leads into a new dealer team, and hands a
+// removed member's leads to the owner
+func (s *service) ApplyOrganizationEvents(ctx context.Context) (int, error) {
+	return s.repo.ApplyOrganizationEvents(ctx, organizationEventBatch)
+}
+
+// sides returns the users on each side of a conversation: the buyer, and the
+// seller or the dealer team members who handle it.
+func (s *service) sides(ctx context.Context, conv *domain.Conversation) (buyers, sellers []uuid.UUID, err error) {
+	buyers = []uuid.UUID{conv.BuyerID}
+	if conv.OrganizationID == nil {
+		return buyers, []uuid.UUID{conv.SellerID}, nil
+	}
+
+	sellers, err = s.repo.GetOrganizationManagers(ctx, *conv.OrganizationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if conv.AssignedTo != nil {
+		sellers = append(sellers, *conv.AssignedTo)
+	}
+	return buyers, sellers, nil
+}
+
+// notify pushes an event to the users' devices. Delivery is best effort: the
+// change is already saved and clients resync when they reconnect.
+func (s *service) notify(ctx context.Context, userIDs []uuid.UUID, eventType string, data interface{}) {
+	s.publish(ctx, userIDs, realtime.Event{Type: eventType, Data: data})
+}
+
+func (s *service) publish(ctx context.Context, userIDs []uuid.UUID, event realtime.Event) {
+	if err := s.notifier.Publish(ctx, userIDs, event); err != nil {
+		log.Printf("Error publishing %s event: %v", event.Type, err)
+	}
+}
+
+// notifyUnread pushes each user's current unread counts to their devices
+func (s *service) notifyUnread(ctx context.Context, userIDs []uuid.UUID) {
+	for _, id := range userIDs {
+		counts, err := s.GetUnreadCount(ctx, id)
+		if err != nil {
+			log.Printf("Error counting unread messages for %s: %v", id, err)
+			continue
+		}
+		s.notify(ctx, []uuid.UUID{id}, realtime.EventUnreadUpdated, counts)
+	}
+}
+
+// viewer loads the user's dealer organisation membership, if any
+func (s *service) viewer(ctx context.Context, userID uuid.UUID) (domain.Viewer, error) {
+	membership, err := s.repo.GetMembership(ctx, userID)
+	if err != nil {
+		return domain.Viewer{}, err
+	}
+	return domain.Viewer{
+		UserID:         userID,
+		OrganizationID: membership.ID(),
+		CanManage:      membership.CanManage(),
+	}, nil
+}
@@ -0,0 +1,7 @@
+-- Conversations about a dealer organisation's listings are routed to the
+-- member the listing is assigned to, and can be handed to any team member.
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS organization_id UUID;
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS assigned_to UUID;
+
+CREATE INDEX IF NOT EXISTS idx_conversations_organization_id ON conversations(organization_id) WHERE organization_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_conversations_assigned_to ON conversations(assigned_to) WHERE assigned_to IS NOT NULL;
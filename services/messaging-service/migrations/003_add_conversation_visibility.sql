@@ -0,0 +1,7 @@
+-- Each side can mute a conversation or delete it from their inbox. Deleting
+-- hides the messages up to that point; a later message brings the
+-- conversation back with only the new history.
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS is_muted_by_buyer BOOLEAN DEFAULT FALSE;
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS is_muted_by_seller BOOLEAN DEFAULT FALSE;
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS deleted_by_buyer_at TIMESTAMP;
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS deleted_by_seller_at TIMESTAMP;
@@ -0,0 +1,34 @@
+-- Messages have a type; "offer" messages carry a price offer. A counter-offer
+-- is a new offer that points at the one it answers. Accepted offers are also
+-- what analytics-service counts as sales.
+CREATE TABLE IF NOT EXISTS offers (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    conversation_id     UUID NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    listing_id          UUID,          -- References car_listings(id) in shared DB
+    buyer_id            UUID NOT NULL, -- References users(id)
+    seller_id           UUID NOT NULL, -- The listing's owner, as on the conversation
+    organization_id     UUID,          -- References organizations(id)
+
+    offered_by          UUID NOT NULL, -- References users(id)
+    from_buyer          BOOLEAN NOT NULL,
+    amount              DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+    currency            CHAR(3) NOT NULL DEFAULT 'LKR',
+    expires_at          TIMESTAMP NOT NULL,
+    counter_to          UUID REFERENCES offers(id),
+
+    status              VARCHAR(20) NOT NULL DEFAULT 'pending'
+                        CHECK (status IN ('pending', 'accepted', 'rejected', 'countered', 'expired')),
+    responded_by        UUID,
+    responded_at        TIMESTAMP,
+    listing_status      VARCHAR(20), -- Status the listing was moved to on acceptance, if any
+
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_offers_conversation_id ON offers(conversation_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_offers_listing_id ON offers(listing_id);
+CREATE INDEX IF NOT EXISTS idx_offers_pending_expiry ON offers(expires_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_offers_accepted ON offers(seller_id, responded_at) WHERE status = 'accepted';
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS message_type VARCHAR(20) NOT NULL DEFAULT 'text';
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS offer_id UUID REFERENCES offers(id);
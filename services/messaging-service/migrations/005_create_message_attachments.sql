@@ -0,0 +1,21 @@
+-- Photos and PDFs sent in conversations. image-service stores the file and
+-- inserts the row on upload; the row is linked to its message when the
+-- message is sent. Files are read through signed, expiring links.
+CREATE TABLE IF NOT EXISTS message_attachments (
+    id                  UUID PRIMARY KEY,
+    conversation_id     UUID NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    message_id          UUID REFERENCES messages(id) ON DELETE CASCADE,
+    uploader_id         UUID NOT NULL, -- References users(id)
+
+    kind                VARCHAR(20) NOT NULL CHECK (kind IN ('image', 'document')),
+    content_type        VARCHAR(100) NOT NULL,
+    file_name           VARCHAR(255) NOT NULL,
+    size_bytes          BIGINT NOT NULL,
+    storage_key         TEXT NOT NULL,
+    thumbnail_key       TEXT,
+
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_attachments_message_id ON message_attachments(message_id);
+CREATE INDEX IF NOT EXISTS idx_message_attachments_conversation_id ON message_attachments(conversation_id);
@@ -0,0 +1,52 @@
+-- Test drives and viewings arranged in conversations. One side proposes time
+-- slots and the other confirms one; "appointment" messages record each step.
+-- Times are absolute and shown in Sri Lanka time.
+CREATE TABLE IF NOT EXISTS appointments (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    conversation_id     UUID NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    listing_id          UUID,          -- References car_listings(id) in shared DB
+    buyer_id            UUID NOT NULL, -- References users(id)
+    seller_id           UUID NOT NULL, -- The listing's owner, as on the conversation
+    organization_id     UUID,          -- References organizations(id)
+
+    kind                VARCHAR(20) NOT NULL CHECK (kind IN ('test_drive', 'viewing')),
+    status              VARCHAR(20) NOT NULL DEFAULT 'requested'
+                        CHECK (status IN ('requested', 'confirmed', 'cancelled', 'completed', 'no_show')),
+    proposed_slots      JSONB NOT NULL, -- [{"start": ..., "end": ...}]
+    proposed_by         UUID NOT NULL,
+    from_buyer          BOOLEAN NOT NULL,
+    starts_at           TIMESTAMP WITH TIME ZONE, -- Set once confirmed
+    ends_at             TIMESTAMP WITH TIME ZONE,
+    location            VARCHAR(255) NOT NULL DEFAULT '',
+    notes               TEXT NOT NULL DEFAULT '',
+    cancelled_by        UUID,
+    cancel_reason       TEXT NOT NULL DEFAULT '',
+    sequence            INT NOT NULL DEFAULT 0, -- Bumped on every change; the calendar invite's SEQUENCE
+    reminder_sent_at    TIMESTAMP WITH TIME ZONE,
+
+    created_at          TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_appointments_conversation_id ON appointments(conversation_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_appointments_buyer_id ON appointments(buyer_id, starts_at);
+CREATE INDEX IF NOT EXISTS idx_appointments_seller_id ON appointments(seller_id, starts_at);
+CREATE INDEX IF NOT EXISTS idx_appointments_organization_id ON appointments(organization_id, starts_at);
+CREATE INDEX IF NOT EXISTS idx_appointments_reminders ON appointments(starts_at) WHERE status = 'confirmed' AND reminder_sent_at IS NULL;
+-- Only one appointment per conversation can be open
+CREATE UNIQUE INDEX IF NOT EXISTS idx_appointments_open ON appointments(conversation_id) WHERE status IN ('requested', 'confirmed');
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS appointment_id UUID REFERENCES appointments(id);
+
+-- Weekly windows when a seller, or a dealer organisation, takes appointments.
+-- owner_id is the organisation for dealer teams and the seller otherwise.
+CREATE TABLE IF NOT EXISTS availability_windows (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id            UUID NOT NULL,
+    weekday             SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6), -- 0 is Sunday
+    start_time          TIME NOT NULL,
+    end_time            TIME NOT NULL CHECK (end_time > start_time),
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_availability_windows_owner_id ON availability_windows(owner_id, weekday);
@@ -0,0 +1,23 @@
+-- Message screening. Messages matching a scam phrase are held: only the sender
+-- sees them until an admin resolves the report filed in listing_reports
+-- (reports-service), which marks them released or removed. The job delivers
+-- released messages and marks them visible.
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS moderation_status VARCHAR(20) NOT NULL DEFAULT 'visible'
+    CHECK (moderation_status IN ('visible', 'held', 'released', 'removed'));
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS moderation_reason TEXT;
+-- Contact details in the content were hidden because the sender's account is new or unverified
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS masked BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_messages_moderation ON messages(moderation_status) WHERE moderation_status <> 'visible';
+-- Send rate limits count the sender's recent messages
+CREATE INDEX IF NOT EXISTS idx_messages_sender_created ON messages(sender_id, created_at DESC);
+
+-- Admin-editable list of phrases that hold a message for review, matched case-insensitively
+CREATE TABLE IF NOT EXISTS scam_phrases (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    phrase      TEXT NOT NULL,
+    created_by  UUID,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_scam_phrases_phrase ON scam_phrases(LOWER(phrase));
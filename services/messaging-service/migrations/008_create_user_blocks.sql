@@ -0,0 +1,14 @@
+-- Blocks stop a user messaging, or starting conversations with, the side that
+-- blocked them. Sellers on a dealer team block on behalf of the organisation,
+-- so owner_id and blocked_id are the organisation for dealer teams and the
+-- user otherwise.
+CREATE TABLE IF NOT EXISTS user_blocks (
+    owner_id            UUID NOT NULL,
+    blocked_id          UUID NOT NULL,
+    blocked_by          UUID NOT NULL, -- The user who blocked; a team member for organisations
+    conversation_id     UUID REFERENCES conversations(id) ON DELETE SET NULL, -- Where the block was made
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (owner_id, blocked_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_blocks_blocked_id ON user_blocks(blocked_id);
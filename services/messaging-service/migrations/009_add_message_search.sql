@@ -0,0 +1,9 @@
+-- Full-text search over what was said and the listing it was about. Messages
+-- match on their content, conversations on their listing snapshot.
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('english', COALESCE(content, ''))) STORED;
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('english', COALESCE(listing_title, ''))) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_messages_search ON messages USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_conversations_search ON conversations USING GIN (search_vector);
@@ -0,0 +1,24 @@
+-- Reply templates saved by each user. Placeholders such as {listing_title}
+-- are filled in from the conversation a template is used in.
+CREATE TABLE IF NOT EXISTS quick_replies (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id             UUID NOT NULL, -- References users(id)
+    title               VARCHAR(100) NOT NULL,
+    content             TEXT NOT NULL,
+    created_at          TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_quick_replies_user_id ON quick_replies(user_id);
+
+-- The seller side's auto-reply to the first message of a new conversation.
+-- owner_id is the organisation for dealer teams and the seller otherwise.
+CREATE TABLE IF NOT EXISTS auto_replies (
+    owner_id            UUID PRIMARY KEY,
+    enabled             BOOLEAN NOT NULL DEFAULT FALSE,
+    mode                VARCHAR(20) NOT NULL DEFAULT 'outside_hours' CHECK (mode IN ('always', 'outside_hours')),
+    message             TEXT NOT NULL DEFAULT '',
+    business_hours      JSONB NOT NULL DEFAULT '[]', -- [{"weekday": 1, "start": "09:00", "end": "17:30"}], Sri Lanka time
+    updated_by          UUID,
+    updated_at          TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,7 @@
+-- Files of attachments removed from message_attachments, e.g. when
+-- auth-service erases an account. image-service deletes each file from
+-- storage in the background, then its row.
+CREATE TABLE IF NOT EXISTS attachment_deletions (
+    storage_key         TEXT PRIMARY KEY,
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,8 @@
+-- The slot the last calendar invite was sent for. Rescheduling clears
+-- starts_at until the new time is confirmed, but the invite stays in both
+-- calendars, so a cancellation still has to remove it.
+ALTER TABLE appointments ADD COLUMN IF NOT EXISTS invited_starts_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE appointments ADD COLUMN IF NOT EXISTS invited_ends_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE appointments SET invited_starts_at = starts_at, invited_ends_at = ends_at
+WHERE invited_starts_at IS NULL AND starts_at IS NOT NULL;
@@ -0,0 +1,11 @@
+-- A conversation reaches the seller side's inbox once a message in it is
+-- delivered. Until then last_message_at is NULL, e.g. while screening holds
+-- the first message, and only the buyer who started it sees it.
+ALTER TABLE conversations ALTER COLUMN last_message_at DROP DEFAULT;
+
+UPDATE conversations c SET last_message_at = NULL
+WHERE c.last_message_at IS NOT NULL
+AND NOT EXISTS (
+    SELECT 1 FROM messages m
+    WHERE m.conversation_id = c.id AND m.moderation_status IN ('visible', 'released')
+);
@@ -0,0 +1,9 @@
+-- When the other side received the message: when it was sent, or when it was
+-- released from review. NULL while held. Response times are measured from it
+-- (analytics-service).
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS delivered_at TIMESTAMP;
+
+UPDATE messages SET delivered_at = created_at
+WHERE delivered_at IS NULL AND moderation_status = 'visible';
+
+ALTER TABLE messages ALTER COLUMN delivered_at SET DEFAULT CURRENT_TIMESTAMP;
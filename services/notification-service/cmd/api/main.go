@@ -9,8 +9,15 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/audit"
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/cors"
+	"github.com/aselahemantha/exoticsLanka/pkg/jwks"
+	"github.com/aselahemantha/exoticsLanka/pkg/migrate"
+	"github.com/aselahemantha/exoticsLanka/pkg/rbac"
 	"github.com/aselahemantha/exoticsLanka/services/notification-service/internal/config"
 	"github.com/aselahemantha/exoticsLanka/services/notification-service/internal/handler"
+	"github.com/aselahemantha/exoticsLanka/services/notification-service/internal/jobs"
 	"github.com/aselahemantha/exoticsLanka/services/notification-service/internal/provider"
 	"github.com/aselahemantha/exoticsLanka/services/notification-service/internal/repository"
 	"github.com/aselahemantha/exoticsLanka/services/notification-service/internal/service"
@@ -29,7 +36,9 @@ func main() {
 	defer dbPool.Close()
 
 	// Run Migrations
-	repository.RunMigrations(dbPool)
+	if err := migrate.Run(context.Background(), dbPool, "migrations"); err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
+	}
 
 	// Providers
 	emailProvider := provider.NewSendGridProvider(cfg.SendGridAPIKey, cfg.EmailFrom, cfg.EmailFromName)
@@ -39,31 +48,33 @@ func main() {
 	repo := repository.NewRepository(dbPool)
 	svc := service.NewService(repo, emailProvider, smsProvider)
 	h := handler.NewHandler(svc)
-	auth := handler.NewAuthMiddleware(cfg.JWTSecret)
+
+	// Delivers notifications other services queue in the shared database
+	jobs.NewJobScheduler(svc).Start()
+	auditSink := audit.NewPostgresSink(dbPool)
+	authMW := auth.NewMiddleware(
+		jwks.NewClient(cfg.JWKSURL, cfg.JWTPublicKeyFile).Keyfunc,
+		auth.WithTokenCheck(auth.AccountStatusCheck(dbPool)),
+		auth.WithImpersonationAudit(auditSink, "notification-service"),
+	)
+
+	// Role to permission mapping shared by all services
+	authz := rbac.MustLoadAuthorizer(cfg.RBACPolicyFile, auditSink, "notification-service")
 
 	// Router
 	r := gin.Default()
 
-	// CORS (Basic)
-	r.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-		c.Next()
-	})
+	r.Use(cors.New())
 
 	api := r.Group("/api/notifications")
-	api.Use(auth.RequireAuth())
+	api.Use(authMW.Required())
 	{
 		api.GET("/preferences", h.GetPreferences)
 		api.PUT("/preferences", h.UpdatePreferences)
 
 		// Internal Trigger Endpoint
-		api.POST("/send", h.SendNotification)
+		api.POST("/send", authz.Require(rbac.NotificationSend), h.SendNotification)
+		api.POST("/sms", authz.Require(rbac.NotificationSend), h.SendSMS)
 	}
 
 	// Server
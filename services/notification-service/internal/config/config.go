@@ -10,7 +10,9 @@ import (
 type Config struct {
 	Port                    string
 	DatabaseURL             string
-	JWTSecret               string
+	JWKSURL                 string
+	JWTPublicKeyFile        string
+	RBACPolicyFile          string
 	SendGridAPIKey          string
 	EmailFrom               string
 	EmailFromName           string
@@ -29,7 +31,9 @@ func LoadConfig() *Config {
 	return &Config{
 		Port:                    getEnv("PORT", "8092"),
 		DatabaseURL:             getEnv("DATABASE_URL", ""),
-		JWTSecret:               getEnv("JWT_SECRET", ""),
+		JWKSURL:                 getEnv("JWKS_URL", "http://localhost:8081/.well-known/jwks.json"),
+		JWTPublicKeyFile:        getEnv("JWT_PUBLIC_KEY_FILE", ""),
+		RBACPolicyFile:          getEnv("RBAC_POLICY_FILE", ""),
 		SendGridAPIKey:          getEnv("SENDGRID_API_KEY", ""),
 		EmailFrom:               getEnv("EMAIL_FROM", "noreply@exotics.lk"),
 		EmailFromName:           getEnv("EMAIL_FROM_NAME", "Exotics Lanka"),
@@ -27,8 +27,45 @@ type NotificationLog struct {
 }
 
 type NotificationRequest struct {
-	UserID  string                 `json:"user_id"`
-	Type    string                 `json:"type"`    // "welcome", "listing_approved", etc.
-	Channel string                 `json:"channel"` // "email", "sms", "push" (optional, if empty send to all enabled)
-	Data    map[string]interface{} `json:"data"`
+	UserID      string                 `json:"user_id"`
+	Type        string                 `json:"type"`    // "welcome", "listing_approved", etc.
+	Channel     string                 `json:"channel"` // "email", "sms", "push" (optional, if empty send to all enabled)
+	Data        map[string]interface{} `json:"data"`
+	Attachments []Attachment           `json:"attachments,omitempty"` // Email only
+}
+
+// Attachment is a file attached to an email, e.g. an ICS calendar invite.
+// Content is base64 in JSON.
+type Attachment struct {
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Content     []byte `json:"content"`
+}
+
+// OutboxNotification is a notification queued by another service in the
+// notification_outbox table
+type OutboxNotification struct {
+	ID                string
+	Attempts          int
+	DeliveredChannels []string // Channels already sent on by an earlier attempt
+	NotificationRequest
+}
+
+// Delivered reports whether an earlier attempt already sent the notification on channel
+func (n *OutboxNotification) Delivered(channel string) bool {
+	for _, c := range n.DeliveredChannels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// SMSRequest sends a text to a number that need not be verified yet (e.g. a one-time code).
+// It bypasses preferences, so it is only for transactional messages.
+type SMSRequest struct {
+	UserID      string                 `json:"user_id"`
+	PhoneNumber string                 `json:"phone_number" binding:"required"`
+	Type        string                 `json:"type" binding:"required"` // "phone_otp"
+	Data        map[string]interface{} `json:"data"`
 }
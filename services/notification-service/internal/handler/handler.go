@@ -3,6 +3,8 @@ package handler
 import (
 	"net/http"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
 	"github.com/aselahemantha/exoticsLanka/services/notification-service/internal/domain"
 	"github.com/aselahemantha/exoticsLanka/services/notification-service/internal/service"
 	"github.com/gin-gonic/gin"
@@ -17,29 +19,37 @@ func NewHandler(service *service.Service) *Handler {
 }
 
 func (h *Handler) GetPreferences(c *gin.Context) {
-	userID := c.GetString("userID")
-	prefs, err := h.service.GetPreferences(c.Request.Context(), userID)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	prefs, err := h.service.GetPreferences(c.Request.Context(), userID.String())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": prefs})
 }
 
 func (h *Handler) UpdatePreferences(c *gin.Context) {
-	userID := c.GetString("userID")
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
 	var prefs domain.NotificationPreference
 	if err := c.ShouldBindJSON(&prefs); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// Enforce UserID from token
-	prefs.UserID = userID
+	prefs.UserID = userID.String()
 
-	err := h.service.UpdatePreferences(c.Request.Context(), &prefs)
+	err = h.service.UpdatePreferences(c.Request.Context(), &prefs)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Preferences updated"})
@@ -54,16 +64,32 @@ func (h *Handler) SendNotification(c *gin.Context) {
 
 	var req domain.NotificationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// Attempt to send
 	err := h.service.SendNotification(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Notification dispatched"})
 }
+
+// SendSMS is called by auth-service to deliver phone verification codes
+func (h *Handler) SendSMS(c *gin.Context) {
+	var req domain.SMSRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.service.SendSMS(c.Request.Context(), &req); err != nil {
+		response.Error(c, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "SMS dispatched"})
+}
@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/services/notification-service/internal/service"
+)
+
+// outboxInterval bounds how long a queued notification waits to be sent
+const outboxInterval = 15 * time.Second
+
+type JobScheduler struct {
+	svc *service.Service
+}
+
+func NewJobScheduler(svc *service.Service) *JobScheduler {
+	return &JobScheduler{svc: svc}
+}
+
+func (s *JobScheduler) Start() {
+	go s.runOutbox()
+}
+
+func (s *JobScheduler) runOutbox() {
+	ticker := time.NewTicker(outboxInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if n, err := s.svc.DeliverOutbox(context.Background()); err != nil {
+			log.Printf("Error delivering queued notifications: %v", err)
+		} else if n > 0 {
+			log.Printf("Delivered %d queued notifications", n)
+		}
+	}
+}
@@ -1,9 +1,11 @@
 package provider
 
 import (
+	"encoding/base64"
 	"fmt"
 	"log"
 
+	"github.com/aselahemantha/exoticsLanka/services/notification-service/internal/domain"
 	"github.com/sendgrid/sendgrid-go"
 	"github.com/sendgrid/sendgrid-go/helpers/mail"
 	"github.com/twilio/twilio-go"
@@ -11,7 +13,7 @@ import (
 )
 
 type EmailProvider interface {
-	SendEmail(to, subject, htmlContent string) (string, error)
+	SendEmail(to, subject, htmlContent string, attachments []domain.Attachment) (string, error)
 }
 
 type SMSProvider interface {
@@ -33,15 +35,23 @@ func NewSendGridProvider(apiKey, fromEmail, fromName string) *SendGridProvider {
 	}
 }
 
-func (p *SendGridProvider) SendEmail(to, subject, htmlContent string) (string, error) {
+func (p *SendGridProvider) SendEmail(to, subject, htmlContent string, attachments []domain.Attachment) (string, error) {
 	from := mail.NewEmail(p.fromName, p.fromEmail)
 	if p.client == nil || p.fromEmail == "test@example.com" { // Mock mode if key invalid or test
-		log.Printf("[MOCK EMAIL] To: %s, Subject: %s\n", to, subject)
+		log.Printf("[MOCK EMAIL] To: %s, Subject: %s, Attachments: %d\n", to, subject, len(attachments))
 		return "mock-message-id", nil
 	}
 
 	toEmail := mail.NewEmail("", to)
 	message := mail.NewSingleEmail(from, subject, toEmail, " ", htmlContent) // Content passed as HTML
+	for _, a := range attachments {
+		attachment := mail.NewAttachment()
+		attachment.SetFilename(a.FileName)
+		attachment.SetType(a.ContentType)
+		attachment.SetContent(base64.StdEncoding.EncodeToString(a.Content))
+		attachment.SetDisposition("attachment")
+		message.AddAttachment(attachment)
+	}
 
 	response, err := p.client.Send(message)
 	if err != nil {
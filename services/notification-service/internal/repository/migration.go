@@ -1,23 +0,0 @@
-package repository
-
-import (
-	"context"
-	"log"
-	"os"
-
-	"github.com/jackc/pgx/v5/pgxpool"
-)
-
-func RunMigrations(db *pgxpool.Pool) {
-	sql, err := os.ReadFile("migrations/001_initial_schema.sql")
-	if err != nil {
-		log.Fatalf("Failed to read migration file: %v", err)
-	}
-
-	_, err = db.Exec(context.Background(), string(sql))
-	if err != nil {
-		log.Fatalf("Failed to run migrations: %v", err)
-	}
-
-	log.Println("Migrations completed successfully")
-}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aselahemantha/exoticsLanka/services/notification-service/internal/domain"
+)
+
+// outboxLease is how long a claimed notification is held by one replica
+// before another may pick it up again
+const outboxLease = 5 * time.Minute
+
+// ClaimOutbox takes up to limit due notifications from the outbox and leases
+// them to the caller, so replicas don't deliver the same one twice
+func (r *Repository) ClaimOutbox(ctx context.Context, limit int) ([]domain.OutboxNotification, error) {
+	query := `
+		UPDATE notification_outbox
+		SET attempts = attempts + 1, send_after = NOW() + $2 * INTERVAL '1 second'
+		WHERE id IN (
+			SELECT id FROM notification_outbox
+			WHERE status = 'pending' AND send_after <= NOW()
+			ORDER BY send_after
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, user_id, type, channel, data, attachments, attempts, delivered_channels
+	`
+	rows, err := r.db.Query(ctx, query, limit, outboxLease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []domain.OutboxNotification
+	for rows.Next() {
+		var n domain.OutboxNotification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Type, &n.Channel, &n.Data, &n.Attachments, &n.Attempts, &n.DeliveredChannels); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox notification: %w", err)
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, rows.Err()
+}
+
+func (r *Repository) MarkOutboxSent(ctx context.Context, id string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE notification_outbox SET status = 'sent', sent_at = NOW(), last_error = NULL WHERE id = $1
+	`, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox notification sent: %w", err)
+	}
+	return nil
+}
+
+// MarkOutboxFailed records a failed delivery and the channels that did go
+// out. The notification is retried on the others after retryAfter, or given
+// up on if retryAfter is zero.
+func (r *Repository) MarkOutboxFailed(ctx context.Context, id string, delivered []string, deliveryErr error, retryAfter time.Duration) error {
+	status := "pending"
+	if retryAfter == 0 {
+		status = "failed"
+	}
+	_, err := r.db.Exec(ctx, `
+		UPDATE notification_outbox
+		SET status = $2, last_error = $3, send_after = NOW() + $4 * INTERVAL '1 second', delivered_channels = $5
+		WHERE id = $1
+	`, id, status, deliveryErr.Error(), retryAfter.Seconds(), delivered)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox notification failed: %w", err)
+	}
+	return nil
+}
@@ -88,10 +88,10 @@ func (r *Repository) GetUserEmail(ctx context.Context, userID string) (string, e
 	return email, nil
 }
 
-// GetUserPhone fetches user phone from the shared 'users' table
+// GetUserPhone fetches the user's verified phone from the shared 'users' table
 func (r *Repository) GetUserPhone(ctx context.Context, userID string) (string, error) {
 	var phone *string
-	query := `SELECT phone_number FROM users WHERE id = $1`
+	query := `SELECT CASE WHEN phone_verified THEN phone_number END FROM users WHERE id = $1`
 	err := r.db.QueryRow(ctx, query, userID).Scan(&phone)
 	if err != nil {
 		return "", fmt.Errorf("failed to get user phone: %w", err)
@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"html"
 	"strings"
+	"time"
 
 	"github.com/aselahemantha/exoticsLanka/services/notification-service/internal/domain"
 	"github.com/aselahemantha/exoticsLanka/services/notification-service/internal/provider"
@@ -59,7 +62,7 @@ func (s *Service) SendNotification(ctx context.Context, req *domain.Notification
 		email, err := s.repo.GetUserEmail(ctx, req.UserID)
 		if err == nil && email != "" {
 			subject, body := s.renderEmailTemplate(req.Type, req.Data)
-			msgID, err := s.emailProvider.SendEmail(email, subject, body)
+			msgID, err := s.emailProvider.SendEmail(email, subject, body, req.Attachments)
 			status := "sent"
 			errMsg := ""
 			if err != nil {
@@ -115,6 +118,91 @@ func (s *Service) SendNotification(ctx context.Context, req *domain.Notification
 	return nil
 }
 
+// SendSMS sends a transactional text straight to req.PhoneNumber
+func (s *Service) SendSMS(ctx context.Context, req *domain.SMSRequest) error {
+	body := s.renderSMSTemplate(req.Type, req.Data)
+	sid, err := s.smsProvider.SendSMS(req.PhoneNumber, body)
+	status := "sent"
+	errMsg := ""
+	if err != nil {
+		status = "failed"
+		errMsg = err.Error()
+	}
+
+	// Log without the data; for one-time codes it holds the code itself
+	_ = s.repo.LogNotification(ctx, &domain.NotificationLog{
+		UserID:       req.UserID,
+		Type:         req.Type,
+		Provider:     "twilio",
+		ExternalID:   sid,
+		Status:       status,
+		ErrorMessage: errMsg,
+	})
+
+	if err != nil {
+		return fmt.Errorf("sms failed: %w", err)
+	}
+	return nil
+}
+
+// Outbox delivery: each run sends up to outboxBatchSize notifications, and a
+// failed one is retried with a growing delay up to outboxMaxAttempts times
+const (
+	outboxBatchSize   = 50
+	outboxMaxAttempts = 5
+)
+
+// outboxChannels are what a notification queued for all enabled channels is
+// sent on, one at a time so each is only retried until it succeeds
+var outboxChannels = []string{"email", "sms"}
+
+// DeliverOutbox sends the notifications other services queued in the
+// outbox and returns how many were sent
+func (s *Service) DeliverOutbox(ctx context.Context) (int, error) {
+	notifications, err := s.repo.ClaimOutbox(ctx, outboxBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, n := range notifications {
+		channels := []string{n.Channel}
+		if n.Channel == "" {
+			channels = outboxChannels
+		}
+
+		var errs []string
+		for _, channel := range channels {
+			if n.Delivered(channel) {
+				continue
+			}
+			req := n.NotificationRequest
+			req.Channel = channel
+			if err := s.SendNotification(ctx, &req); err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			n.DeliveredChannels = append(n.DeliveredChannels, channel)
+		}
+
+		if len(errs) > 0 {
+			retryAfter := time.Duration(n.Attempts*n.Attempts) * time.Minute
+			if n.Attempts >= outboxMaxAttempts {
+				retryAfter = 0
+			}
+			if err := s.repo.MarkOutboxFailed(ctx, n.ID, n.DeliveredChannels, errors.New(strings.Join(errs, "; ")), retryAfter); err != nil {
+				return sent, err
+			}
+			continue
+		}
+		if err := s.repo.MarkOutboxSent(ctx, n.ID); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
 // Simple Template Engine
 func (s *Service) renderEmailTemplate(templateType string, data map[string]interface{}) (string, string) {
 	subject := "Notification from Exotics Lanka"
@@ -136,11 +224,37 @@ func (s *Service) renderEmailTemplate(templateType string, data map[string]inter
 	case "new_lead":
 		subject = "New Lead!"
 		body = fmt.Sprintf("<p>New inquiry for %v from %v.</p>", data["listing_title"], data["buyer_name"])
+	case "appointment_requested":
+		subject = fmt.Sprintf("New %v request for %v", data["appointment_kind"], data["listing_title"])
+		body = fmt.Sprintf("<p>%v proposed these times for a %v of <b>%v</b>:</p><p>%v</p><p>Confirm one in your messages.</p>",
+			escape(data["proposed_by"]), escape(data["appointment_kind"]), escape(data["listing_title"]), escape(data["proposed_slots"]))
+	case "appointment_rescheduled":
+		subject = fmt.Sprintf("New times proposed for your %v", data["appointment_kind"])
+		body = fmt.Sprintf("<p>%v proposed new times for the %v of <b>%v</b>:</p><p>%v</p><p>Confirm one in your messages.</p>",
+			escape(data["proposed_by"]), escape(data["appointment_kind"]), escape(data["listing_title"]), escape(data["proposed_slots"]))
+	case "appointment_confirmed":
+		subject = fmt.Sprintf("Your %v is confirmed for %v", data["appointment_kind"], data["starts_at"])
+		body = fmt.Sprintf("<p>Your %v of <b>%v</b> is confirmed for %v at %v. The calendar invite is attached.</p>",
+			escape(data["appointment_kind"]), escape(data["listing_title"]), escape(data["starts_at"]), escape(data["location"]))
+	case "appointment_cancelled":
+		subject = fmt.Sprintf("Your %v has been cancelled", data["appointment_kind"])
+		body = fmt.Sprintf("<p>The %v of <b>%v</b> was cancelled. Reason: %v</p>",
+			escape(data["appointment_kind"]), escape(data["listing_title"]), escape(data["reason"]))
+	case "appointment_reminder":
+		subject = fmt.Sprintf("Reminder: %v on %v", data["appointment_kind"], data["starts_at"])
+		body = fmt.Sprintf("<p>This is a reminder of your %v of <b>%v</b> on %v at %v.</p>",
+			escape(data["appointment_kind"]), escape(data["listing_title"]), escape(data["starts_at"]), escape(data["location"]))
 	}
 
 	return subject, body
 }
 
+// escape renders a template value for an HTML email body. Appointment values
+// include text the other party typed, such as the location or cancel reason.
+func escape(value interface{}) string {
+	return html.EscapeString(fmt.Sprint(value))
+}
+
 func (s *Service) renderSMSTemplate(templateType string, data map[string]interface{}) string {
 	body := "Exotics Lanka Notification"
 
@@ -153,6 +267,14 @@ func (s *Service) renderSMSTemplate(templateType string, data map[string]interfa
 		body = fmt.Sprintf("New message from %v about %v.", data["sender_name"], data["listing_title"])
 	case "new_lead":
 		body = fmt.Sprintf("New lead for %v from %v.", data["listing_title"], data["buyer_name"])
+	case "appointment_confirmed":
+		body = fmt.Sprintf("Your %v of %v is confirmed for %v.", data["appointment_kind"], data["listing_title"], data["starts_at"])
+	case "appointment_cancelled":
+		body = fmt.Sprintf("Your %v of %v has been cancelled.", data["appointment_kind"], data["listing_title"])
+	case "appointment_reminder":
+		body = fmt.Sprintf("Reminder: your %v of %v is on %v at %v.", data["appointment_kind"], data["listing_title"], data["starts_at"], data["location"])
+	case "phone_otp":
+		body = fmt.Sprintf("Your Exotics Lanka verification code is %v. It expires in %v minutes.", data["code"], data["expires_in_minutes"])
 	}
 
 	return body
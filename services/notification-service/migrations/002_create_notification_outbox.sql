@@ -0,0 +1,20 @@
+-- Notifications queued by other services in the shared database, for services
+-- that can't call the /send endpoint. Rows are delivered in the background and
+-- retried with backoff until max attempts.
+CREATE TABLE IF NOT EXISTS notification_outbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    type VARCHAR(50) NOT NULL, -- Template, e.g. 'appointment_confirmed'
+    channel VARCHAR(20) NOT NULL DEFAULT '', -- 'email', 'sms', or '' for all enabled
+    data JSONB NOT NULL DEFAULT '{}',
+    attachments JSONB NOT NULL DEFAULT '[]', -- Email attachments: file_name, content_type, content (base64)
+    source VARCHAR(50) NOT NULL, -- Service that queued it
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    send_after TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_outbox_pending ON notification_outbox(send_after) WHERE status = 'pending';
@@ -0,0 +1,3 @@
+-- Channels a notification for all enabled channels has already gone out on,
+-- so a retry after one channel fails doesn't resend the others
+ALTER TABLE notification_outbox ADD COLUMN IF NOT EXISTS delivered_channels TEXT[] NOT NULL DEFAULT '{}';
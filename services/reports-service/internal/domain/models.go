@@ -6,10 +6,30 @@ import (
 	"github.com/google/uuid"
 )
 
+// Report targets
+const (
+	TargetListing = "listing"
+	// TargetMessage reports are raised by messaging-service for messages its
+	// screening held; resolving them releases or removes the message
+	TargetMessage = "message"
+	// TargetConversation reports are filed by a participant from
+	// messaging-service against the other side, with a transcript excerpt
+	TargetConversation = "conversation"
+)
+
+// Actions taken on message reports
+const (
+	ActionMessageReleased = "message_released"
+	ActionMessageRemoved  = "message_removed"
+)
+
 type Report struct {
 	ID          uuid.UUID       `json:"id"`
-	ListingID   uuid.UUID       `json:"listingId"`
+	TargetType  string          `json:"targetType"`
+	TargetID    uuid.UUID       `json:"targetId"`
+	ListingID   *uuid.UUID      `json:"listingId,omitempty"`
 	Listing     *ListingSummary `json:"listing,omitempty"` // For expanded view
+	Message     *MessageSummary `json:"message,omitempty"`
 	ReporterID  *uuid.UUID      `json:"reporterId,omitempty"`
 	Reporter    *UserSummary    `json:"reporter,omitempty"`
 	Reason      string          `json:"reason"`
@@ -22,6 +42,11 @@ type Report struct {
 	Resolver    *UserSummary    `json:"resolver,omitempty"`
 	CreatedAt   time.Time       `json:"createdAt"`
 	UpdatedAt   time.Time       `json:"updatedAt"`
+
+	// Conversation reports name the user reported and keep the latest messages
+	ReportedUserID *uuid.UUID    `json:"reportedUserId,omitempty"`
+	ReportedUser   *UserSummary  `json:"reportedUser,omitempty"`
+	Excerpt        []ExcerptLine `json:"excerpt,omitempty"`
 }
 
 type ListingSummary struct {
@@ -33,6 +58,24 @@ type ListingSummary struct {
 	User       UserSummary `json:"user"`
 }
 
+type MessageSummary struct {
+	ID               uuid.UUID   `json:"id"`
+	ConversationID   uuid.UUID   `json:"conversationId"`
+	Content          string      `json:"content"`
+	ModerationStatus string      `json:"moderationStatus"`
+	CreatedAt        time.Time   `json:"createdAt"`
+	Sender           UserSummary `json:"sender"`
+}
+
+// ExcerptLine is a message copied into a conversation report
+type ExcerptLine struct {
+	MessageID  uuid.UUID `json:"messageId"`
+	SenderID   uuid.UUID `json:"senderId"`
+	SenderName string    `json:"senderName"`
+	Content    string    `json:"content"`
+	SentAt     time.Time `json:"sentAt"`
+}
+
 type UserSummary struct {
 	ID    uuid.UUID `json:"id"`
 	Name  string    `json:"name"`
@@ -57,12 +100,5 @@ type CreateReportRequest struct {
 type ResolveReportRequest struct {
 	Status      string `json:"status" binding:"required,oneof=pending reviewing resolved dismissed"`
 	AdminNotes  string `json:"adminNotes"`
-	ActionTaken string `json:"actionTaken"` // 'listing_removed', 'user_suspended', etc.
-}
-
-type Pagination struct {
-	Page       int   `json:"page"`
-	Limit      int   `json:"limit"`
-	Total      int64 `json:"total"`
-	TotalPages int   `json:"totalPages"`
+	ActionTaken string `json:"actionTaken"` // 'listing_removed', 'user_suspended', 'message_released', 'message_removed'
 }
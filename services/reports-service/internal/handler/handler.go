@@ -2,8 +2,10 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
 	"github.com/aselahemantha/exoticsLanka/services/reports-service/internal/domain"
 	"github.com/aselahemantha/exoticsLanka/services/reports-service/internal/service"
 	"github.com/gin-gonic/gin"
@@ -20,25 +22,25 @@ func NewHandler(service service.Service) *Handler {
 
 // POST /api/reports
 func (h *Handler) SubmitReport(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	var req domain.CreateReportRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	report, err := h.service.SubmitReport(c.Request.Context(), req, userID)
 	if err != nil {
 		if err.Error() == "you have already reported this listing recently" {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			response.Error(c, http.StatusConflict, err.Error())
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -51,14 +53,14 @@ func (h *Handler) SubmitReport(c *gin.Context) {
 
 // GET /api/reports (Admin)
 func (h *Handler) GetReports(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	params := pagination.FromQuery(c, 20)
+	targetType := c.Query("targetType")
 	status := c.Query("status")
 	reason := c.Query("reason")
 
-	reports, pagination, err := h.service.GetReports(c.Request.Context(), status, reason, page, limit)
+	reports, meta, err := h.service.GetReports(c.Request.Context(), targetType, status, reason, params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -66,7 +68,7 @@ func (h *Handler) GetReports(c *gin.Context) {
 		"success": true,
 		"data": gin.H{
 			"reports":    reports,
-			"pagination": pagination,
+			"pagination": meta,
 		},
 	})
 }
@@ -76,17 +78,17 @@ func (h *Handler) GetReport(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid report ID")
 		return
 	}
 
 	report, err := h.service.GetReport(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 	if report == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Report not found"})
+		response.Error(c, http.StatusNotFound, "Report not found")
 		return
 	}
 
@@ -95,28 +97,36 @@ func (h *Handler) GetReport(c *gin.Context) {
 
 // PUT /api/reports/:id (Admin)
 func (h *Handler) ResolveReport(c *gin.Context) {
-	adminID, err := GetUserID(c)
+	adminID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid report ID")
 		return
 	}
 
 	var req domain.ResolveReportRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	report, err := h.service.ResolveReport(c.Request.Context(), id, adminID, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		switch err.Error() {
+		case "report not found":
+			response.Error(c, http.StatusNotFound, "Report not found")
+			return
+		case "action does not apply to this report":
+			response.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -131,7 +141,7 @@ func (h *Handler) ResolveReport(c *gin.Context) {
 func (h *Handler) GetStats(c *gin.Context) {
 	stats, err := h.service.GetStats(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
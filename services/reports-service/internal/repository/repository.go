@@ -2,8 +2,11 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
 	"github.com/aselahemantha/exoticsLanka/services/reports-service/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -12,7 +15,7 @@ import (
 
 type Repository interface {
 	CreateReport(ctx context.Context, report *domain.Report) (*domain.Report, error)
-	GetReports(ctx context.Context, status, reason string, page, limit int) ([]domain.Report, int64, error)
+	GetReports(ctx context.Context, targetType, status, reason string, params pagination.Params) ([]domain.Report, int64, error)
 	GetReportByID(ctx context.Context, id uuid.UUID) (*domain.Report, error)
 	UpdateReport(ctx context.Context, report *domain.Report) error
 	GetReportStats(ctx context.Context) (*domain.ReportStats, error)
@@ -23,7 +26,8 @@ type Repository interface {
 
 	// Cross-Domain (Shared DB)
 	UpdateListingStatus(ctx context.Context, listingID uuid.UUID, status string) error
-	UpdateUserStatus(ctx context.Context, userID uuid.UUID, status string) error
+	UpdateMessageModeration(ctx context.Context, messageID uuid.UUID, status string) error
+	SuspendUser(ctx context.Context, userID, adminID uuid.UUID, reason string) error
 }
 
 type postgresRepository struct {
@@ -36,29 +40,37 @@ func NewPostgresRepository(db *pgxpool.Pool) Repository {
 
 func (r *postgresRepository) CreateReport(ctx context.Context, report *domain.Report) (*domain.Report, error) {
 	err := r.db.QueryRow(ctx, `
-		INSERT INTO listing_reports (listing_id, reporter_id, reason, details)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO listing_reports (target_type, target_id, listing_id, reporter_id, reason, details)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING id, status, created_at, updated_at
-	`, report.ListingID, report.ReporterID, report.Reason, report.Details).Scan(
+	`, report.TargetType, report.TargetID, report.ListingID, report.ReporterID, report.Reason, report.Details).Scan(
 		&report.ID, &report.Status, &report.CreatedAt, &report.UpdatedAt,
 	)
 	return report, err
 }
 
-func (r *postgresRepository) GetReports(ctx context.Context, status, reason string, page, limit int) ([]domain.Report, int64, error) {
+func (r *postgresRepository) GetReports(ctx context.Context, targetType, status, reason string, params pagination.Params) ([]domain.Report, int64, error) {
 	// Base Query
 	query := `
-		SELECT r.id, r.listing_id, r.reporter_id, r.reason, r.details, r.status, 
+		SELECT r.id, r.target_type, r.target_id, r.listing_id, r.reporter_id, r.reported_user_id, r.reason, r.details, r.status, 
                r.admin_notes, r.action_taken, r.resolved_at, r.resolved_by, r.created_at,
-               cl.title, u.email as reporter_email
+               cl.title, m.conversation_id, m.content, m.moderation_status, m.sender_id,
+               u.email as reporter_email
 		FROM listing_reports r
-		JOIN car_listings cl ON r.listing_id = cl.id
+		LEFT JOIN car_listings cl ON r.listing_id = cl.id
+		LEFT JOIN messages m ON r.target_type = 'message' AND r.target_id = m.id
 		LEFT JOIN users u ON r.reporter_id = u.id
 		WHERE 1=1
 	`
 	args := []interface{}{}
 	argIdx := 1
 
+	if targetType != "" {
+		query += fmt.Sprintf(" AND r.target_type = $%d", argIdx)
+		args = append(args, targetType)
+		argIdx++
+	}
+
 	if status != "" {
 		query += fmt.Sprintf(" AND r.status = $%d", argIdx)
 		args = append(args, status)
@@ -82,6 +94,11 @@ func (r *postgresRepository) GetReports(ctx context.Context, status, reason stri
 	cArgs := []interface{}{}
 	cArgIdx := 1
 	cQuery := "SELECT COUNT(*) FROM listing_reports r WHERE 1=1"
+	if targetType != "" {
+		cQuery += fmt.Sprintf(" AND r.target_type = $%d", cArgIdx)
+		cArgs = append(cArgs, targetType)
+		cArgIdx++
+	}
 	if status != "" {
 		cQuery += fmt.Sprintf(" AND r.status = $%d", cArgIdx)
 		cArgs = append(cArgs, status)
@@ -100,7 +117,7 @@ func (r *postgresRepository) GetReports(ctx context.Context, status, reason stri
 
 	// Pagination
 	query += fmt.Sprintf(" ORDER BY r.created_at DESC LIMIT $%d OFFSET $%d", argIdx, argIdx+1)
-	args = append(args, limit, (page-1)*limit)
+	args = append(args, params.Limit, params.Offset())
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -112,19 +129,33 @@ func (r *postgresRepository) GetReports(ctx context.Context, status, reason stri
 	for rows.Next() {
 		var rpt domain.Report
 		var reporterEmail *string
-		var listingTitle string
+		var listingTitle *string
+		var msgConversationID, msgSenderID *uuid.UUID
+		var msgContent, msgStatus *string
 
 		err := rows.Scan(
-			&rpt.ID, &rpt.ListingID, &rpt.ReporterID, &rpt.Reason, &rpt.Details, &rpt.Status,
+			&rpt.ID, &rpt.TargetType, &rpt.TargetID, &rpt.ListingID, &rpt.ReporterID, &rpt.ReportedUserID, &rpt.Reason, &rpt.Details, &rpt.Status,
 			&rpt.AdminNotes, &rpt.ActionTaken, &rpt.ResolvedAt, &rpt.ResolvedBy, &rpt.CreatedAt,
-			&listingTitle, &reporterEmail,
+			&listingTitle, &msgConversationID, &msgContent, &msgStatus, &msgSenderID,
+			&reporterEmail,
 		)
 		if err != nil {
 			return nil, 0, err
 		}
 
 		// Map extra fields to summary structs
-		rpt.Listing = &domain.ListingSummary{ID: rpt.ListingID, Title: listingTitle}
+		if rpt.ListingID != nil && listingTitle != nil {
+			rpt.Listing = &domain.ListingSummary{ID: *rpt.ListingID, Title: *listingTitle}
+		}
+		if msgConversationID != nil {
+			rpt.Message = &domain.MessageSummary{
+				ID:               rpt.TargetID,
+				ConversationID:   *msgConversationID,
+				Content:          *msgContent,
+				ModerationStatus: *msgStatus,
+				Sender:           domain.UserSummary{ID: *msgSenderID},
+			}
+		}
 		if rpt.ReporterID != nil && reporterEmail != nil {
 			rpt.Reporter = &domain.UserSummary{ID: *rpt.ReporterID, Email: *reporterEmail}
 		}
@@ -137,30 +168,46 @@ func (r *postgresRepository) GetReports(ctx context.Context, status, reason stri
 
 func (r *postgresRepository) GetReportByID(ctx context.Context, id uuid.UUID) (*domain.Report, error) {
 	query := `
-		SELECT r.id, r.listing_id, r.reporter_id, r.reason, r.details, r.status, 
+		SELECT r.id, r.target_type, r.target_id, r.listing_id, r.reporter_id, r.reason, r.details, r.status, 
                r.admin_notes, r.action_taken, r.resolved_at, r.resolved_by, r.created_at, r.updated_at,
+               r.reported_user_id, ru.name, ru.email, r.excerpt,
                cl.title, cl.price, cl.status,
                u.id as reporter_id, u.email as reporter_email,
-               s.id as listing_owner_id, s.name as listing_owner_name, s.email as listing_owner_email
+               s.id as listing_owner_id, s.name as listing_owner_name, s.email as listing_owner_email,
+               m.conversation_id, m.content, m.moderation_status, m.created_at,
+               ms.id as sender_id, ms.name as sender_name, ms.email as sender_email
 		FROM listing_reports r
-		JOIN car_listings cl ON r.listing_id = cl.id
-		JOIN users s ON cl.user_id = s.id
+		LEFT JOIN car_listings cl ON r.listing_id = cl.id
+		LEFT JOIN users s ON cl.user_id = s.id
+		LEFT JOIN messages m ON r.target_type = 'message' AND r.target_id = m.id
+		LEFT JOIN users ms ON m.sender_id = ms.id
+		LEFT JOIN users ru ON r.reported_user_id = ru.id
 		LEFT JOIN users u ON r.reporter_id = u.id
 		WHERE r.id = $1
 	`
 	var rpt domain.Report
-	var lListing domain.ListingSummary
-	var lOwner domain.UserSummary
+	var lTitle, lStatus *string
+	var lPrice *float64
+	var lOwnerID *uuid.UUID
+	var lOwnerName, lOwnerEmail *string
 	var rReporter domain.UserSummary
 	var rReporterID *uuid.UUID
 	var rReporterEmail *string
+	var mConversationID, mSenderID *uuid.UUID
+	var mContent, mStatus, mSenderName, mSenderEmail *string
+	var mCreatedAt *time.Time
+	var ruName, ruEmail *string
+	var excerpt []byte
 
 	err := r.db.QueryRow(ctx, query, id).Scan(
-		&rpt.ID, &rpt.ListingID, &rpt.ReporterID, &rpt.Reason, &rpt.Details, &rpt.Status,
+		&rpt.ID, &rpt.TargetType, &rpt.TargetID, &rpt.ListingID, &rpt.ReporterID, &rpt.Reason, &rpt.Details, &rpt.Status,
 		&rpt.AdminNotes, &rpt.ActionTaken, &rpt.ResolvedAt, &rpt.ResolvedBy, &rpt.CreatedAt, &rpt.UpdatedAt,
-		&lListing.Title, &lListing.Price, &lListing.Status,
+		&rpt.ReportedUserID, &ruName, &ruEmail, &excerpt,
+		&lTitle, &lPrice, &lStatus,
 		&rReporterID, &rReporterEmail,
-		&lOwner.ID, &lOwner.Name, &lOwner.Email,
+		&lOwnerID, &lOwnerName, &lOwnerEmail,
+		&mConversationID, &mContent, &mStatus, &mCreatedAt,
+		&mSenderID, &mSenderName, &mSenderEmail,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -170,9 +217,34 @@ func (r *postgresRepository) GetReportByID(ctx context.Context, id uuid.UUID) (*
 	}
 
 	// Reconstruct complex objects
-	lListing.ID = rpt.ListingID
-	lListing.User = lOwner
-	rpt.Listing = &lListing
+	if rpt.ListingID != nil && lTitle != nil {
+		rpt.Listing = &domain.ListingSummary{
+			ID:     *rpt.ListingID,
+			Title:  *lTitle,
+			Price:  *lPrice,
+			Status: *lStatus,
+			User:   domain.UserSummary{ID: *lOwnerID, Name: *lOwnerName, Email: *lOwnerEmail},
+		}
+	}
+	if mConversationID != nil && mSenderID != nil {
+		rpt.Message = &domain.MessageSummary{
+			ID:               rpt.TargetID,
+			ConversationID:   *mConversationID,
+			Content:          *mContent,
+			ModerationStatus: *mStatus,
+			CreatedAt:        *mCreatedAt,
+			Sender:           domain.UserSummary{ID: *mSenderID, Name: *mSenderName, Email: *mSenderEmail},
+		}
+	}
+
+	if rpt.ReportedUserID != nil && ruEmail != nil {
+		rpt.ReportedUser = &domain.UserSummary{ID: *rpt.ReportedUserID, Name: *ruName, Email: *ruEmail}
+	}
+	if len(excerpt) > 0 {
+		if err := json.Unmarshal(excerpt, &rpt.Excerpt); err != nil {
+			return nil, err
+		}
+	}
 
 	if rReporterID != nil && rReporterEmail != nil {
 		rReporter.ID = *rReporterID
@@ -260,11 +332,25 @@ func (r *postgresRepository) UpdateListingStatus(ctx context.Context, listingID
 	return err
 }
 
-func (r *postgresRepository) UpdateUserStatus(ctx context.Context, userID uuid.UUID, status string) error {
-	// Assuming 'users' table has a 'status' or similar field (often 'is_active' or 'role').
-	// If the schema doesn't have status, we might need to check other services.
-	// For now, let's assume standard 'status' field for moderation.
-	// If it fails, we catch it.
-	_, err := r.db.Exec(ctx, "UPDATE users SET status = $1 WHERE id = $2", status, userID)
+// UpdateMessageModeration releases or removes a message held by messaging-service
+// screening. Released messages are delivered by messaging-service's jobs.
+func (r *postgresRepository) UpdateMessageModeration(ctx context.Context, messageID uuid.UUID, status string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE messages SET moderation_status = $1
+		WHERE id = $2 AND moderation_status = 'held'
+	`, status, messageID)
+	return err
+}
+
+// SuspendUser suspends the account indefinitely in the shared users table. Every
+// service rejects the user's tokens from the next request; admins lift it in auth-service.
+func (r *postgresRepository) SuspendUser(ctx context.Context, userID, adminID uuid.UUID, reason string) error {
+	query := `
+		UPDATE users SET
+			status = 'suspended', suspended_reason = $1, suspended_until = NULL,
+			suspended_at = NOW(), suspended_by = $2, updated_at = NOW()
+		WHERE id = $3 AND deleted_at IS NULL
+	`
+	_, err := r.db.Exec(ctx, query, reason, adminID, userID)
 	return err
 }
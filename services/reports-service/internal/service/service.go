@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
 	"github.com/aselahemantha/exoticsLanka/services/reports-service/internal/domain"
 	"github.com/aselahemantha/exoticsLanka/services/reports-service/internal/repository"
 	"github.com/google/uuid"
@@ -12,7 +13,7 @@ import (
 
 type Service interface {
 	SubmitReport(ctx context.Context, req domain.CreateReportRequest, reporterID uuid.UUID) (*domain.Report, error)
-	GetReports(ctx context.Context, status, reason string, page, limit int) ([]domain.Report, domain.Pagination, error)
+	GetReports(ctx context.Context, targetType, status, reason string, params pagination.Params) ([]domain.Report, pagination.Pagination, error)
 	GetReport(ctx context.Context, id uuid.UUID) (*domain.Report, error)
 	ResolveReport(ctx context.Context, id uuid.UUID, adminID uuid.UUID, req domain.ResolveReportRequest) (*domain.Report, error)
 	GetStats(ctx context.Context) (*domain.ReportStats, error)
@@ -39,7 +40,9 @@ func (s *service) SubmitReport(ctx context.Context, req domain.CreateReportReque
 
 	// 2. Create Report
 	report := &domain.Report{
-		ListingID:  req.ListingID,
+		TargetType: domain.TargetListing,
+		TargetID:   req.ListingID,
+		ListingID:  &req.ListingID,
 		ReporterID: &reporterID,
 		Reason:     req.Reason,
 		Details:    req.Details,
@@ -58,22 +61,13 @@ func (s *service) SubmitReport(ctx context.Context, req domain.CreateReportReque
 	return createdReport, nil
 }
 
-func (s *service) GetReports(ctx context.Context, status, reason string, page, limit int) ([]domain.Report, domain.Pagination, error) {
-	reports, total, err := s.repo.GetReports(ctx, status, reason, page, limit)
+func (s *service) GetReports(ctx context.Context, targetType, status, reason string, params pagination.Params) ([]domain.Report, pagination.Pagination, error) {
+	reports, total, err := s.repo.GetReports(ctx, targetType, status, reason, params)
 	if err != nil {
-		return nil, domain.Pagination{}, err
+		return nil, pagination.Pagination{}, err
 	}
 
-	pagination := domain.Pagination{
-		Page:  page,
-		Limit: limit,
-		Total: total,
-	}
-	if limit > 0 {
-		pagination.TotalPages = int((total + int64(limit) - 1) / int64(limit))
-	}
-
-	return reports, pagination, nil
+	return reports, pagination.New(params, total), nil
 }
 
 func (s *service) GetReport(ctx context.Context, id uuid.UUID) (*domain.Report, error) {
@@ -88,6 +82,9 @@ func (s *service) ResolveReport(ctx context.Context, id uuid.UUID, adminID uuid.
 	if report == nil {
 		return nil, fmt.Errorf("report not found")
 	}
+	if err := validateAction(report, req.ActionTaken); err != nil {
+		return nil, err
+	}
 
 	now := time.Now()
 	report.Status = req.Status
@@ -101,8 +98,15 @@ func (s *service) ResolveReport(ctx context.Context, id uuid.UUID, adminID uuid.
 	}
 
 	// Take Action
+	switch report.TargetType {
+	case domain.TargetMessage:
+		return report, s.moderateMessage(ctx, report, adminID, req)
+	case domain.TargetConversation:
+		return report, s.moderateConversation(ctx, report, adminID, req)
+	}
+
 	if req.ActionTaken == "listing_removed" {
-		if err := s.repo.UpdateListingStatus(ctx, report.ListingID, "rejected"); err != nil {
+		if err := s.repo.UpdateListingStatus(ctx, *report.ListingID, "rejected"); err != nil {
 			return nil, fmt.Errorf("report resolved but failed to remove listing: %v", err)
 		}
 	}
@@ -110,7 +114,11 @@ func (s *service) ResolveReport(ctx context.Context, id uuid.UUID, adminID uuid.
 	if req.ActionTaken == "user_suspended" {
 		// Need owner ID. We fetched it in GetReportByID inside Listing struct
 		if report.Listing != nil {
-			if err := s.repo.UpdateUserStatus(ctx, report.Listing.User.ID, "suspended"); err != nil {
+			reason := "Report " + report.ID.String()
+			if req.AdminNotes != "" {
+				reason += ": " + req.AdminNotes
+			}
+			if err := s.repo.SuspendUser(ctx, report.Listing.User.ID, adminID, reason); err != nil {
 				return nil, fmt.Errorf("report resolved but failed to suspend user: %v", err)
 			}
 		}
@@ -119,6 +127,67 @@ func (s *service) ResolveReport(ctx context.Context, id uuid.UUID, adminID uuid.
 	return report, nil
 }
 
+// moderateMessage settles a held message. Dismissing the report or releasing the
+// message lets messaging-service deliver it; removing it, or suspending its
+// sender, keeps it from the recipient for good.
+func (s *service) moderateMessage(ctx context.Context, report *domain.Report, adminID uuid.UUID, req domain.ResolveReportRequest) error {
+	status := ""
+	switch {
+	case req.ActionTaken == domain.ActionMessageReleased || req.Status == "dismissed":
+		status = "released"
+	case req.ActionTaken == domain.ActionMessageRemoved || req.ActionTaken == "user_suspended":
+		status = "removed"
+	}
+	if status != "" {
+		if err := s.repo.UpdateMessageModeration(ctx, report.TargetID, status); err != nil {
+			return fmt.Errorf("report resolved but failed to update message: %v", err)
+		}
+	}
+
+	if req.ActionTaken == "user_suspended" && report.Message != nil {
+		reason := "Report " + report.ID.String()
+		if req.AdminNotes != "" {
+			reason += ": " + req.AdminNotes
+		}
+		if err := s.repo.SuspendUser(ctx, report.Message.Sender.ID, adminID, reason); err != nil {
+			return fmt.Errorf("report resolved but failed to suspend user: %v", err)
+		}
+	}
+	return nil
+}
+
+// moderateConversation acts on the user a conversation report is about.
+// Warnings are recorded on the report only.
+func (s *service) moderateConversation(ctx context.Context, report *domain.Report, adminID uuid.UUID, req domain.ResolveReportRequest) error {
+	if req.ActionTaken != "user_suspended" || report.ReportedUserID == nil {
+		return nil
+	}
+	reason := "Report " + report.ID.String()
+	if req.AdminNotes != "" {
+		reason += ": " + req.AdminNotes
+	}
+	if err := s.repo.SuspendUser(ctx, *report.ReportedUserID, adminID, reason); err != nil {
+		return fmt.Errorf("report resolved but failed to suspend user: %v", err)
+	}
+	return nil
+}
+
+// validateAction rejects actions that don't apply to the report's target.
+// Other actions, such as user_warned, are only recorded.
+func validateAction(report *domain.Report, action string) error {
+	switch action {
+	case "listing_removed":
+		if report.TargetType != domain.TargetListing {
+			return fmt.Errorf("action does not apply to this report")
+		}
+	case domain.ActionMessageReleased, domain.ActionMessageRemoved:
+		if report.TargetType != domain.TargetMessage {
+			return fmt.Errorf("action does not apply to this report")
+		}
+	}
+	return nil
+}
+
 func (s *service) GetStats(ctx context.Context) (*domain.ReportStats, error) {
 	return s.repo.GetReportStats(ctx)
 }
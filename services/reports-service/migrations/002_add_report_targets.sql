@@ -0,0 +1,12 @@
+-- Reports can target things other than listings. target_id is the reported
+-- listing or message; listing_id stays set for listing reports.
+ALTER TABLE listing_reports ADD COLUMN IF NOT EXISTS target_type VARCHAR(20) NOT NULL DEFAULT 'listing';
+ALTER TABLE listing_reports ADD COLUMN IF NOT EXISTS target_id UUID;
+UPDATE listing_reports SET target_id = listing_id WHERE target_id IS NULL;
+ALTER TABLE listing_reports ALTER COLUMN target_id SET NOT NULL;
+ALTER TABLE listing_reports ALTER COLUMN listing_id DROP NOT NULL;
+
+-- The allowed target types are defined by the latest migration that adds
+-- one (003); migrations re-run on every start, so it is not set here.
+
+CREATE INDEX IF NOT EXISTS idx_listing_reports_target ON listing_reports(target_type, target_id);
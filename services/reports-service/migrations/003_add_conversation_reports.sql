@@ -0,0 +1,9 @@
+-- Conversation reports are filed from messaging-service. They name the user
+-- being reported and keep an excerpt of the transcript, since either side can
+-- later delete the conversation.
+ALTER TABLE listing_reports ADD COLUMN IF NOT EXISTS reported_user_id UUID;
+ALTER TABLE listing_reports ADD COLUMN IF NOT EXISTS excerpt JSONB;
+
+ALTER TABLE listing_reports DROP CONSTRAINT IF EXISTS listing_reports_target_type_check;
+ALTER TABLE listing_reports ADD CONSTRAINT listing_reports_target_type_check
+    CHECK (target_type IN ('listing', 'message', 'conversation'));
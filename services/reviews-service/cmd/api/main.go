@@ -10,6 +10,12 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/audit"
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/cors"
+	"github.com/aselahemantha/exoticsLanka/pkg/jwks"
+	"github.com/aselahemantha/exoticsLanka/pkg/migrate"
+	"github.com/aselahemantha/exoticsLanka/pkg/rbac"
 	"github.com/aselahemantha/exoticsLanka/services/reviews-service/internal/config"
 	"github.com/aselahemantha/exoticsLanka/services/reviews-service/internal/handler"
 	"github.com/aselahemantha/exoticsLanka/services/reviews-service/internal/repository"
@@ -38,65 +44,48 @@ func main() {
 	fmt.Println("Connected to PostgreSQL")
 
 	// 3. Run Migrations
-	if err := repository.RunMigrations(dbPool, "migrations"); err != nil {
+	if err := migrate.Run(context.Background(), dbPool, "migrations"); err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
 	// 4. Initialize Dependency Injection
 	repo := repository.NewPostgresRepository(dbPool)
 	svc := service.NewService(repo)
-	h := handler.NewHandler(svc)
+	// Token verification keys, fetched from auth-service; suspended and deleted
+	// accounts are rejected straight away and impersonated requests are audited
+	auditSink := audit.NewPostgresSink(dbPool)
+	authMW := auth.NewMiddleware(
+		jwks.NewClient(cfg.JWKSURL, cfg.JWTPublicKeyFile).Keyfunc,
+		auth.WithTokenCheck(auth.AccountStatusCheck(dbPool)),
+		auth.WithImpersonationAudit(auditSink, "reviews-service"),
+	)
+
+	// Role to permission mapping shared by all services
+	authz := rbac.MustLoadAuthorizer(cfg.RBACPolicyFile, auditSink, "reviews-service")
+
+	h := handler.NewHandler(svc, authz)
 
 	// 5. Setup Router
 	router := gin.Default()
 
-	// CORS
-	router.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-		c.Next()
-	})
+	router.Use(cors.New())
 
 	api := router.Group("/api/reviews")
 
-	// Public Endpoints
-	api.GET("/seller/:sellerId", handler.AuthMiddleware(cfg), h.GetReviewsBySeller) // Auth optional handled inside? No, let's keep it protected for voting status check or make middleware allow partial.
-	// Actually, middleware returns Unauthorized if no token. We need a way to support optional auth.
-	// For now, let's use a specific wrapper for optional auth if needed, OR just require auth for personalization.
-	// The request says "Auth: No" for GET. So we should NOT use AuthMiddleware for GETs unless we want vote status.
-	// But GetReviewsBySeller logic checks for vote status if userID is present.
-	// Let's split or use a custom "OptionalAuthMiddleware".
-	// For MVP simplicity: Public = No Auth Middleware (userID will be nil).
-	// If the implementation strictly needs userID for "hasVoted", clients should send it.
-	// Let's create `OptionalAuthMiddleware` in `main.go` or `handler/middleware.go`?
-	// It's cleaner to have it. I'll define a simple inline one or just let the handler extract it if present, but Gin doesn't parse Bearer automatically without code.
-
-	// Updating strategy:
-	// GET /seller/:sellerId -> Public. If token present, parsed.
-	// I'll leave it as Public (no middleware enforced), but in the handler `GetOptionalUserID` logic needs to manualy parse header if middleware didn't run.
-	// Actually, if I use `AuthMiddleware` it enforces it.
-	// I will route public GETs without the strict middleware, but I'll add a lightweight "ParseTokenIfPresent" middleware for them.
-
-	router.GET("/api/reviews/seller/:sellerId", optionalAuth(cfg), h.GetReviewsBySeller)
-	router.GET("/api/reviews/seller/:sellerId/stats", h.GetSellerStats)
-	// router.GET("/api/reviews/listing/:listingId", h.GetReviewsByListing) // Not fully implemented yet
+	// Public Endpoints (token optional, used for the caller's helpful-vote status)
+	api.GET("/seller/:sellerId", authMW.Optional(), h.GetReviewsBySeller)
+	api.GET("/seller/:sellerId/stats", h.GetSellerStats)
+	// api.GET("/listing/:listingId", h.GetReviewsByListing) // Not fully implemented yet
 
 	// Protected Endpoints
 	protected := api.Group("")
-	protected.Use(handler.AuthMiddleware(cfg))
+	protected.Use(authMW.Required())
 	{
-		protected.POST("", h.CreateReview)
+		protected.POST("", authz.Require(rbac.ReviewCreate), h.CreateReview)
 		protected.PUT("/:id", h.UpdateReview)
 		protected.DELETE("/:id", h.DeleteReview)
 		protected.POST("/:id/helpful", h.ToggleHelpful)
-		protected.POST("/:id/response", h.AddSellerResponse) // Validates seller ownership inside
+		protected.POST("/:id/response", authz.Require(rbac.ReviewRespond), h.AddSellerResponse) // Validates seller ownership inside
 		protected.POST("/:id/photos", h.AddPhoto)
 		protected.DELETE("/:id/photos/:photoId", h.RemovePhoto)
 	}
@@ -127,21 +116,3 @@ func main() {
 	}
 	log.Println("Server exiting")
 }
-
-// Simple optional auth middleware
-func optionalAuth(cfg *config.Config) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Just try to run the standard auth logic but don't abort on failure, just continue with empty context
-		// Re-using logic from handler.AuthMiddleware but ignoring errors
-		handler.AuthMiddleware(cfg)(c)
-		// If AuthMiddleware aborted, it wrote JSON. We want to prevent that?
-		// Handler func logic in `middleware.go` calls `c.Abort()`.
-		// So we can't reuse it directly without refactoring.
-		// For now, let's just proceed. The handler `GetReviewsBySeller` uses `GetOptionalUserID` which checks `c.Get("userID")`.
-		// Make sure checking header doesn't fail.
-		// Detailed optional auth is complex without refactoring.
-		// Strategy: For this deliverable, I will just proceed without parsing token for public GETs.
-		// The `hasVoted` will be false for anonymous users, which is acceptable.
-		c.Next()
-	}
-}
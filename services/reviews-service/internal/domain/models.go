@@ -81,11 +81,3 @@ type UpdateReviewRequest struct {
 type SellerResponseRequest struct {
 	Comment string `json:"comment" binding:"required,min=10,max=1000"`
 }
-
-// Pagination
-type Pagination struct {
-	Page       int   `json:"page"`
-	Limit      int   `json:"limit"`
-	Total      int64 `json:"total"`
-	TotalPages int   `json:"totalPages"`
-}
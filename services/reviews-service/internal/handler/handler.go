@@ -2,8 +2,11 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/auth"
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
+	"github.com/aselahemantha/exoticsLanka/pkg/rbac"
+	"github.com/aselahemantha/exoticsLanka/pkg/response"
 	"github.com/aselahemantha/exoticsLanka/services/reviews-service/internal/domain"
 	"github.com/aselahemantha/exoticsLanka/services/reviews-service/internal/service"
 	"github.com/gin-gonic/gin"
@@ -12,10 +15,11 @@ import (
 
 type Handler struct {
 	service service.Service
+	authz   *rbac.Authorizer
 }
 
-func NewHandler(service service.Service) *Handler {
-	return &Handler{service: service}
+func NewHandler(service service.Service, authz *rbac.Authorizer) *Handler {
+	return &Handler{service: service, authz: authz}
 }
 
 // GET /api/reviews/seller/:sellerId
@@ -23,18 +27,17 @@ func (h *Handler) GetReviewsBySeller(c *gin.Context) {
 	sellerIDStr := c.Param("sellerId")
 	sellerID, err := uuid.Parse(sellerIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid seller ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid seller ID")
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	params := pagination.FromQuery(c, 10)
 
-	userID := GetOptionalUserID(c) // For checking 'hasVotedHelpful'
+	userID := auth.GetOptionalUserID(c) // For checking 'hasVotedHelpful'
 
-	reviews, pagination, err := h.service.GetReviewsBySeller(c.Request.Context(), sellerID, page, limit, userID)
+	reviews, meta, err := h.service.GetReviewsBySeller(c.Request.Context(), sellerID, params, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -42,7 +45,7 @@ func (h *Handler) GetReviewsBySeller(c *gin.Context) {
 		"success": true,
 		"data": gin.H{
 			"reviews":    reviews,
-			"pagination": pagination,
+			"pagination": meta,
 		},
 	})
 }
@@ -52,13 +55,13 @@ func (h *Handler) GetSellerStats(c *gin.Context) {
 	sellerIDStr := c.Param("sellerId")
 	sellerID, err := uuid.Parse(sellerIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid seller ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid seller ID")
 		return
 	}
 
 	stats, err := h.service.GetSellerStats(c.Request.Context(), sellerID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -67,22 +70,22 @@ func (h *Handler) GetSellerStats(c *gin.Context) {
 
 // POST /api/reviews
 func (h *Handler) CreateReview(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	var req domain.CreateReviewRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	review, err := h.service.CreateReview(c.Request.Context(), req, userID)
 	if err != nil {
 		// Could handle 409 Conflict slightly better if error type checked
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -91,28 +94,28 @@ func (h *Handler) CreateReview(c *gin.Context) {
 
 // PUT /api/reviews/:id
 func (h *Handler) UpdateReview(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	reviewIDStr := c.Param("id")
 	reviewID, err := uuid.Parse(reviewIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid review ID")
 		return
 	}
 
 	var req domain.UpdateReviewRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	review, err := h.service.UpdateReview(c.Request.Context(), reviewID, userID, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -121,26 +124,25 @@ func (h *Handler) UpdateReview(c *gin.Context) {
 
 // DELETE /api/reviews/:id
 func (h *Handler) DeleteReview(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	reviewIDStr := c.Param("id")
 	reviewID, err := uuid.Parse(reviewIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid review ID")
 		return
 	}
 
-	// Check if admin? For now assume only user delete.
-	userRole := c.GetString("userRole")
-	isAdmin := userRole == "admin" // Basic check
+	// Moderators may delete any review, everyone else only their own
+	isAdmin := h.authz.Can(c, rbac.ReviewModerate)
 
 	err = h.service.DeleteReview(c.Request.Context(), reviewID, userID, isAdmin)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -149,22 +151,22 @@ func (h *Handler) DeleteReview(c *gin.Context) {
 
 // POST /api/reviews/:id/helpful
 func (h *Handler) ToggleHelpful(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	reviewIDStr := c.Param("id")
 	reviewID, err := uuid.Parse(reviewIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid review ID")
 		return
 	}
 
 	count, hasVoted, err := h.service.ToggleHelpful(c.Request.Context(), reviewID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -185,28 +187,28 @@ func (h *Handler) ToggleHelpful(c *gin.Context) {
 
 // POST /api/reviews/:id/response
 func (h *Handler) AddSellerResponse(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	reviewIDStr := c.Param("id")
 	reviewID, err := uuid.Parse(reviewIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid review ID")
 		return
 	}
 
 	var req domain.SellerResponseRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	review, err := h.service.AddSellerResponse(c.Request.Context(), reviewID, userID, req.Comment)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -219,16 +221,16 @@ func (h *Handler) AddSellerResponse(c *gin.Context) {
 
 // POST /api/reviews/:id/photos
 func (h *Handler) AddPhoto(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	reviewIDStr := c.Param("id")
 	reviewID, err := uuid.Parse(reviewIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid review ID")
 		return
 	}
 
@@ -240,13 +242,13 @@ func (h *Handler) AddPhoto(c *gin.Context) {
 	}
 	var req PhotoRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	err = h.service.AddPhoto(c.Request.Context(), reviewID, userID, req.URL)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -255,29 +257,29 @@ func (h *Handler) AddPhoto(c *gin.Context) {
 
 // DELETE /api/reviews/:id/photos/:photoId
 func (h *Handler) RemovePhoto(c *gin.Context) {
-	userID, err := GetUserID(c)
+	userID, err := auth.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
 	reviewIDStr := c.Param("id")
 	reviewID, err := uuid.Parse(reviewIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid review ID")
 		return
 	}
 
 	photoIDStr := c.Param("photoId")
 	photoID, err := uuid.Parse(photoIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid photo ID"})
+		response.Error(c, http.StatusBadRequest, "Invalid photo ID")
 		return
 	}
 
 	err = h.service.RemovePhoto(c.Request.Context(), reviewID, userID, photoID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
 	"github.com/aselahemantha/exoticsLanka/services/reviews-service/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -12,8 +13,8 @@ import (
 
 type Repository interface {
 	CreateReview(ctx context.Context, review *domain.Review) (*domain.Review, error)
-	GetReviewsBySeller(ctx context.Context, sellerID uuid.UUID, params domain.Pagination, userID *uuid.UUID) ([]domain.Review, int64, error)
-	GetReviewsByListing(ctx context.Context, listingID uuid.UUID, params domain.Pagination) ([]domain.Review, int64, error)
+	GetReviewsBySeller(ctx context.Context, sellerID uuid.UUID, params pagination.Params, userID *uuid.UUID) ([]domain.Review, int64, error)
+	GetReviewsByListing(ctx context.Context, listingID uuid.UUID, params pagination.Params) ([]domain.Review, int64, error)
 	GetReviewByID(ctx context.Context, id uuid.UUID) (*domain.Review, error)
 	UpdateReview(ctx context.Context, review *domain.Review) (*domain.Review, error)
 	DeleteReview(ctx context.Context, id uuid.UUID) error
@@ -72,7 +73,7 @@ func (r *postgresRepository) CreateReview(ctx context.Context, review *domain.Re
 	return review, nil
 }
 
-func (r *postgresRepository) GetReviewsBySeller(ctx context.Context, sellerID uuid.UUID, params domain.Pagination, userID *uuid.UUID) ([]domain.Review, int64, error) {
+func (r *postgresRepository) GetReviewsBySeller(ctx context.Context, sellerID uuid.UUID, params pagination.Params, userID *uuid.UUID) ([]domain.Review, int64, error) {
 	// Total Count
 	var total int64
 	err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM reviews WHERE seller_id = $1", sellerID).Scan(&total)
@@ -80,7 +81,7 @@ func (r *postgresRepository) GetReviewsBySeller(ctx context.Context, sellerID uu
 		return nil, 0, err
 	}
 
-	offset := (params.Page - 1) * params.Limit
+	offset := params.Offset()
 
 	// Complex Query with Helpers check
 	// NOTE: We're not doing a complex join for User/Listing details here to keep it simple as we don't have access to other service tables easily (unless shared DB assumption holds fully).
@@ -151,7 +152,7 @@ func (r *postgresRepository) GetReviewsBySeller(ctx context.Context, sellerID uu
 	return reviews, total, nil
 }
 
-func (r *postgresRepository) GetReviewsByListing(ctx context.Context, listingID uuid.UUID, params domain.Pagination) ([]domain.Review, int64, error) {
+func (r *postgresRepository) GetReviewsByListing(ctx context.Context, listingID uuid.UUID, params pagination.Params) ([]domain.Review, int64, error) {
 	// Implementation similar to GetReviewsBySeller but filtered by listing
 	return nil, 0, nil // Placeholder for brevity as user verified existing logic implies generic getter patterns
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
 	"github.com/aselahemantha/exoticsLanka/services/reviews-service/internal/domain"
 	"github.com/aselahemantha/exoticsLanka/services/reviews-service/internal/repository"
 	"github.com/google/uuid"
@@ -11,8 +12,8 @@ import (
 
 type Service interface {
 	CreateReview(ctx context.Context, req domain.CreateReviewRequest, buyerID uuid.UUID) (*domain.Review, error)
-	GetReviewsBySeller(ctx context.Context, sellerID uuid.UUID, page, limit int, userID *uuid.UUID) ([]domain.Review, *domain.Pagination, error)
-	GetReviewsByListing(ctx context.Context, listingID uuid.UUID, page, limit int) ([]domain.Review, *domain.Pagination, error)
+	GetReviewsBySeller(ctx context.Context, sellerID uuid.UUID, params pagination.Params, userID *uuid.UUID) ([]domain.Review, *pagination.Pagination, error)
+	GetReviewsByListing(ctx context.Context, listingID uuid.UUID, params pagination.Params) ([]domain.Review, *pagination.Pagination, error)
 	GetSellerStats(ctx context.Context, sellerID uuid.UUID) (*domain.SellerStats, error)
 
 	UpdateReview(ctx context.Context, reviewID, userID uuid.UUID, req domain.UpdateReviewRequest) (*domain.Review, error)
@@ -62,30 +63,17 @@ func (s *service) CreateReview(ctx context.Context, req domain.CreateReviewReque
 	return s.repo.CreateReview(ctx, review)
 }
 
-func (s *service) GetReviewsBySeller(ctx context.Context, sellerID uuid.UUID, page, limit int, userID *uuid.UUID) ([]domain.Review, *domain.Pagination, error) {
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 {
-		limit = 10
-	}
-
-	pagination := &domain.Pagination{Page: page, Limit: limit}
-
-	reviews, total, err := s.repo.GetReviewsBySeller(ctx, sellerID, *pagination, userID)
+func (s *service) GetReviewsBySeller(ctx context.Context, sellerID uuid.UUID, params pagination.Params, userID *uuid.UUID) ([]domain.Review, *pagination.Pagination, error) {
+	reviews, total, err := s.repo.GetReviewsBySeller(ctx, sellerID, params, userID)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	pagination.Total = total
-	if limit > 0 {
-		pagination.TotalPages = int((total + int64(limit) - 1) / int64(limit))
-	}
-
-	return reviews, pagination, nil
+	meta := pagination.New(params, total)
+	return reviews, &meta, nil
 }
 
-func (s *service) GetReviewsByListing(ctx context.Context, listingID uuid.UUID, page, limit int) ([]domain.Review, *domain.Pagination, error) {
+func (s *service) GetReviewsByListing(ctx context.Context, listingID uuid.UUID, params pagination.Params) ([]domain.Review, *pagination.Pagination, error) {
 	// Placeholder implementation reusing seller pattern
 	return nil, nil, fmt.Errorf("not implemented yet")
 }
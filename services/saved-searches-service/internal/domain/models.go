@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
 	"github.com/google/uuid"
 )
 
@@ -69,8 +70,8 @@ type CheckMatchesResponse struct {
 }
 
 type RunSearchResponse struct {
-	Listings   []ListingSummary `json:"listings"`
-	Pagination Pagination       `json:"pagination"`
+	Listings   []ListingSummary      `json:"listings"`
+	Pagination pagination.Pagination `json:"pagination"`
 }
 
 type ListingSummary struct {
@@ -85,13 +86,6 @@ type ListingSummary struct {
 	CreatedAt  time.Time `json:"createdAt"`
 }
 
-type Pagination struct {
-	Page       int   `json:"page"`
-	Limit      int   `json:"limit"`
-	Total      int64 `json:"total"`
-	TotalPages int   `json:"totalPages"`
-}
-
 type NewMatchesResponse struct {
 	TotalNewMatches int           `json:"totalNewMatches"`
 	BySearch        []SearchStats `json:"bySearch"`
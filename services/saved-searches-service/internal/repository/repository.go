@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
 	"github.com/aselahemantha/exoticsLanka/services/saved-searches-service/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -23,7 +24,7 @@ type Repository interface {
 	// Matching Logic
 	CountMatchingListings(ctx context.Context, filters domain.SearchFilters) (int, error)
 	GetNewListingsSince(ctx context.Context, filters domain.SearchFilters, since time.Time) ([]domain.ListingSummary, error)
-	RunSearch(ctx context.Context, filters domain.SearchFilters, page, limit int) ([]domain.ListingSummary, int64, error)
+	RunSearch(ctx context.Context, filters domain.SearchFilters, page pagination.Params) ([]domain.ListingSummary, int64, error)
 
 	// Internal / Background
 	UpdateMatchStats(ctx context.Context, id uuid.UUID, newCount, totalCount int) error
@@ -260,7 +261,7 @@ func (r *postgresRepository) GetNewListingsSince(ctx context.Context, filters do
 	return listings, nil
 }
 
-func (r *postgresRepository) RunSearch(ctx context.Context, filters domain.SearchFilters, page, limit int) ([]domain.ListingSummary, int64, error) {
+func (r *postgresRepository) RunSearch(ctx context.Context, filters domain.SearchFilters, page pagination.Params) ([]domain.ListingSummary, int64, error) {
 	// Count first
 	countQuery := "SELECT COUNT(*) FROM car_listings WHERE status = 'active'"
 	cParams := []interface{}{}
@@ -283,7 +284,7 @@ func (r *postgresRepository) RunSearch(ctx context.Context, filters domain.Searc
 	query, params, paramIdx = buildFilterQuery(query, filters, params, paramIdx)
 
 	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", paramIdx, paramIdx+1)
-	params = append(params, limit, (page-1)*limit)
+	params = append(params, page.Limit, page.Offset())
 
 	rows, err := r.db.Query(ctx, query, params...)
 	if err != nil {
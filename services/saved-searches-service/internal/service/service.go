@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/aselahemantha/exoticsLanka/pkg/pagination"
 	"github.com/aselahemantha/exoticsLanka/services/saved-searches-service/internal/domain"
 	"github.com/aselahemantha/exoticsLanka/services/saved-searches-service/internal/repository"
 	"github.com/google/uuid"
@@ -17,7 +18,7 @@ type Service interface {
 	DeleteSavedSearch(ctx context.Context, id, userID uuid.UUID) error
 
 	CheckForNewMatches(ctx context.Context, id, userID uuid.UUID) (*domain.CheckMatchesResponse, error)
-	RunSearch(ctx context.Context, id, userID uuid.UUID, page, limit int) (*domain.RunSearchResponse, error)
+	RunSearch(ctx context.Context, id, userID uuid.UUID, params pagination.Params) (*domain.RunSearchResponse, error)
 
 	UpdateAlertSettings(ctx context.Context, id, userID uuid.UUID, req domain.UpdateAlertsRequest) error
 	GetTotalNewMatches(ctx context.Context, userID uuid.UUID) (*domain.NewMatchesResponse, error)
@@ -163,29 +164,20 @@ func (s *service) CheckForNewMatches(ctx context.Context, id, userID uuid.UUID)
 	}, nil
 }
 
-func (s *service) RunSearch(ctx context.Context, id, userID uuid.UUID, page, limit int) (*domain.RunSearchResponse, error) {
+func (s *service) RunSearch(ctx context.Context, id, userID uuid.UUID, params pagination.Params) (*domain.RunSearchResponse, error) {
 	ss, err := s.GetSavedSearch(ctx, id, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	listings, total, err := s.repo.RunSearch(ctx, ss.Filters, page, limit)
+	listings, total, err := s.repo.RunSearch(ctx, ss.Filters, params)
 	if err != nil {
 		return nil, err
 	}
 
-	pagination := domain.Pagination{
-		Page:  page,
-		Limit: limit,
-		Total: total,
-	}
-	if limit > 0 {
-		pagination.TotalPages = int((total + int64(limit) - 1) / int64(limit))
-	}
-
 	return &domain.RunSearchResponse{
 		Listings:   listings,
-		Pagination: pagination,
+		Pagination: pagination.New(params, total),
 	}, nil
 }
 